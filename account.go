@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+// writeScope is what's known of whether the token may make changes.
+type writeScope int
+
+const (
+	writeUnknown writeScope = iota
+	writeAllowed
+	writeRefused
+)
+
+// accountMsg reports who the token belongs to and whether it can create
+// Droplets.
+type accountMsg struct {
+	account *godo.Account
+	scope   writeScope
+	err     error
+}
+
+// checkAccount looks up the token's account and, if checkScope is set,
+// checks it has write scope.
+func checkAccount(client *godo.Client, checkScope bool) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+
+		account, _, err := client.Account.Get(ctx)
+		if err != nil {
+			return accountMsg{err: err}
+		}
+
+		if !checkScope {
+			return accountMsg{account: account}
+		}
+
+		// An empty create request is always rejected, so nothing is created:
+		// as invalid if the token may create Droplets, and as forbidden
+		// before it's validated if the token is read-only.
+		_, resp, _ := client.Droplets.Create(ctx, &godo.DropletCreateRequest{})
+		scope := writeUnknown
+		switch {
+		case resp == nil:
+		case resp.StatusCode == http.StatusForbidden:
+			scope = writeRefused
+		case resp.StatusCode == http.StatusUnprocessableEntity:
+			scope = writeAllowed
+		}
+
+		return accountMsg{account: account, scope: scope}
+	}
+}
+
+// isForbidden reports whether the API refused the request with a 403.
+func isForbidden(err error) bool {
+	var errResp *godo.ErrorResponse
+	return errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusForbidden
+}
+
+// accountView describes the token's account for the header.
+func (m model) accountView() string {
+	switch {
+	case m.account.err != nil:
+		return errorStyle.Render("token invalid: " + m.account.err.Error())
+	case m.account.account == nil:
+		return placeholderStyle.Render("checking token...")
+	case m.account.scope == writeRefused:
+		return focusedStyle.Render(m.account.account.Email) + " " + errorStyle.Render("(read-only token)")
+	case m.readOnly:
+		return focusedStyle.Render(m.account.account.Email) + " " + placeholderStyle.Render("(read-only mode)")
+	case m.account.scope == writeUnknown:
+		return focusedStyle.Render(m.account.account.Email) + " " + placeholderStyle.Render("(write access unknown)")
+	default:
+		return focusedStyle.Render(m.account.account.Email) + " " + placeholderStyle.Render("(read/write)")
+	}
+}
+
+// readOnlyReason explains why nothing may be changed on the account, or
+// returns an empty string if changes are allowed.
+func (m model) readOnlyReason() string {
+	switch {
+	case m.readOnly:
+		return "read-only mode is on"
+	case m.account.scope == writeRefused:
+		return "the token is read-only"
+	}
+	return ""
+}
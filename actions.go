@@ -0,0 +1,251 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+	"github.com/digitalocean/godo/util"
+)
+
+var actionsKey = key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "actions"))
+
+// dropletAction is one of the actions offered in the list's actions menu.
+type dropletAction struct {
+	name        string
+	description string
+	// doing and done describe the action in the list while it's running and
+	// once it's finished, e.g. "powering off" and "Powered off".
+	doing string
+	done  string
+	// when reports whether the action applies to a Droplet in the status.
+	when func(status string) bool
+	run  func(godo.DropletActionsService, context.Context, int) (*godo.Action, *godo.Response, error)
+	// open is set instead of run for actions that need more to go on,
+	// taking over the list until they're started.
+	open func(model, godo.Droplet) (model, tea.Cmd)
+}
+
+func isActive(status string) bool { return status == "active" }
+func isOff(status string) bool    { return status == "off" }
+
+// isSettled reports whether the Droplet is on or off, rather than still
+// being created or archived.
+func isSettled(status string) bool { return isActive(status) || isOff(status) }
+
+var dropletActions = []dropletAction{
+	{
+		name:        "Shut down",
+		description: "shut the OS down cleanly, then power off",
+		doing:       "shutting down",
+		done:        "Shut down",
+		when:        isActive,
+		run:         godo.DropletActionsService.Shutdown,
+	},
+	{
+		name:        "Reboot",
+		description: "restart the OS cleanly",
+		doing:       "rebooting",
+		done:        "Rebooted",
+		when:        isActive,
+		run:         godo.DropletActionsService.Reboot,
+	},
+	{
+		name:        "Power off",
+		description: "cut the power, like pulling the plug, which may lose data",
+		doing:       "powering off",
+		done:        "Powered off",
+		when:        isActive,
+		run:         godo.DropletActionsService.PowerOff,
+	},
+	{
+		name:        "Power on",
+		description: "start the Droplet up again",
+		doing:       "powering on",
+		done:        "Powered on",
+		when:        isOff,
+		run:         godo.DropletActionsService.PowerOn,
+	},
+	{
+		name:        "Resize",
+		description: "move to a bigger or smaller size, powering off meanwhile",
+		when:        isSettled,
+		open:        model.openResize,
+	},
+	{
+		name:        "Rebuild",
+		description: "reinstall from an image, erasing the disk",
+		when:        isSettled,
+		open:        model.openRebuild,
+	},
+	{
+		name:        "Backups",
+		description: "browse its backups and restore one, overwriting the disk",
+		when:        isSettled,
+		open:        model.openBackups,
+	},
+	{
+		name:        "Volumes",
+		description: "attach a volume in its region, or detach one of its own",
+		when:        isSettled,
+		open:        model.openDropletVolumes,
+	},
+	{
+		name:        "Snapshot",
+		description: "save an image of its disk, to restore or create Droplets from",
+		when:        isSettled,
+		open:        model.openSnapshot,
+	},
+	{
+		name:        "Change kernel",
+		description: "choose the kernel an older Droplet boots, if it uses an external one",
+		when:        isSettled,
+		open:        model.openKernels,
+	},
+	{
+		name:        "Reset password",
+		description: "email a new root password, for when its SSH keys are lost",
+		when:        isActive,
+		open:        model.openPasswordReset,
+	},
+	{
+		name:        "Rename",
+		description: "change its name, or press n in the list",
+		open:        model.openRename,
+	},
+}
+
+// actionItem lists an action in the actions menu.
+type actionItem struct {
+	dropletAction
+}
+
+func (a actionItem) Title() string       { return a.name }
+func (a actionItem) Description() string { return a.description }
+func (a actionItem) FilterValue() string { return a.name }
+func (a actionItem) Value() string       { return a.name }
+
+// dropletActionMsg reports that an action on a Droplet has finished. verb
+// and done describe it, e.g. "reboot" and "Rebooted".
+type dropletActionMsg struct {
+	id   int
+	name string
+	verb string
+	done string
+	err  error
+}
+
+// runDropletAction starts the action and waits for it to complete.
+func runDropletAction(client *godo.Client, id int, name string, a dropletAction) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+
+		action, _, err := a.run(client.DropletActions, ctx, id)
+		if err == nil {
+			err = waitForAction(ctx, client, action)
+		}
+		return dropletActionMsg{id: id, name: name, verb: strings.ToLower(a.name), done: a.done, err: err}
+	}
+}
+
+// waitForAction waits for an action that was just started to complete.
+func waitForAction(ctx context.Context, client *godo.Client, action *godo.Action) error {
+	return util.WaitForActive(ctx, client, fmt.Sprintf("v2/actions/%d", action.ID))
+}
+
+// openActions shows the actions that apply to the Droplet under the cursor.
+func (m model) openActions() (model, tea.Cmd) {
+	if m.blockChanges("Droplets", "changed") {
+		return m, nil
+	}
+
+	l := m.list
+	droplet, ok := l.selected()
+	if !ok {
+		return m, nil
+	}
+	var opts []option
+	for _, a := range dropletActions {
+		if a.when == nil || a.when(droplet.Status) {
+			opts = append(opts, actionItem{a})
+		}
+	}
+	if len(opts) == 0 {
+		m.formErr = fmt.Sprintf("there's nothing to do with %s while it's %s", droplet.Name, droplet.Status)
+		return m, nil
+	}
+
+	l.target = droplet
+	l.actions = newSelectField("", "Actions for "+droplet.Name, "")
+	l.actions.SetSize(m.width, m.height-1)
+	cmd := l.actions.SetOptions(opts)
+	l.actions.Open()
+	m.notice = ""
+	m.formErr = ""
+	return m, cmd
+}
+
+// updateActions handles the actions menu, starting the chosen action.
+func (m model) updateActions(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.list
+	if msg.String() == "ctrl+c" {
+		return m, m.quit()
+	}
+
+	_, cmd := l.actions.Update(msg)
+	if l.actions.Opened() {
+		return m, cmd
+	}
+	a, ok := l.actions.selected.(actionItem)
+	l.actions = nil
+	if !ok {
+		return m, cmd
+	}
+	if a.open != nil {
+		m, openCmd := a.open(m, l.target)
+		return m, tea.Batch(cmd, openCmd)
+	}
+
+	l.busy[l.target.ID] = a.doing
+	l.setRows()
+	return m, tea.Batch(cmd, runDropletAction(m.client, l.target.ID, l.target.Name, a.dropletAction))
+}
+
+// actionDone refreshes the list once an action has finished, to show the
+// Droplet's new status.
+func (m model) actionDone(msg dropletActionMsg) (tea.Model, tea.Cmd) {
+	if m.list != nil {
+		delete(m.list.busy, msg.id)
+		m.list.setRows()
+	}
+	if msg.err != nil {
+		m.formErr = fmt.Sprintf("couldn't %s %s: %s", msg.verb, msg.name, msg.err)
+		return m, nil
+	}
+
+	cmd := m.toast(fmt.Sprintf("%s %s", msg.done, msg.name))
+	if m.list == nil {
+		return m, cmd
+	}
+	m.list.loading = true
+	return m, tea.Batch(cmd, fetchDroplets(m.client, m.list.page))
+}
+
+// busyView describes what the list is waiting on, if anything.
+func (m model) busyView() string {
+	l := m.list
+	var busy []string
+	for _, d := range l.droplets {
+		if s, ok := l.busy[d.ID]; ok {
+			busy = append(busy, fmt.Sprintf("%s %s", s, d.Name))
+		}
+	}
+	if len(busy) == 0 {
+		return ""
+	}
+	s := strings.Join(busy, ", ")
+	return fmt.Sprintf("%s  %s\n\n", m.spinner.View(), placeholderStyle.Render(strings.ToUpper(s[:1])+s[1:]+"..."))
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+var createAlertKey = key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "create"))
+
+// alertsPerPage is how many alert policies the alerts screen fetches at a
+// time.
+const alertsPerPage = 20
+
+// alertListMsg carries a page of the account's alert policies, along with
+// how many there are in all.
+type alertListMsg struct {
+	page     int
+	policies []godo.AlertPolicy
+	total    int
+	err      error
+}
+
+// fetchAlertList lists a page of the account's alert policies, counting
+// from 1.
+func fetchAlertList(client *godo.Client, page int) tea.Cmd {
+	return func() tea.Msg {
+		policies, resp, err := client.Monitoring.ListAlertPolicies(context.Background(), &godo.ListOptions{Page: page, PerPage: alertsPerPage})
+		if err != nil {
+			return alertListMsg{page: page, err: err}
+		}
+
+		total := len(policies)
+		if resp.Meta != nil {
+			total = resp.Meta.Total
+		}
+		return alertListMsg{page: page, policies: policies, total: total}
+	}
+}
+
+// alertList is the screen listing the account's monitoring alert policies,
+// a page at a time.
+type alertList struct {
+	table    table.Model
+	policies []godo.AlertPolicy
+	page     int
+	total    int
+	loading  bool
+	err      error
+	// create is set while creating an alert policy.
+	create *alertWizard
+	// creating holds the descriptions of the policies being created.
+	creating []string
+}
+
+func newAlertList(width, height int) *alertList {
+	l := &alertList{
+		page: 1,
+		table: newListTable([]table.Column{
+			{Title: "Description", Width: 28},
+			{Title: "Metric", Width: 8},
+			{Title: "Condition", Width: 20},
+			{Title: "Applies to", Width: 24},
+			{Title: "Notifies", Width: 28},
+			{Title: "Enabled", Width: 8},
+		}),
+	}
+	l.SetSize(width, height)
+	return l
+}
+
+// SetSize fits the table to the screen, under the title and above the help.
+func (l *alertList) SetSize(width, height int) {
+	setTableSize(&l.table, width, height)
+}
+
+// pages returns how many pages of alert policies there are.
+func (l *alertList) pages() int {
+	return pageCount(l.total, alertsPerPage)
+}
+
+// setAlertList shows a page of alert policies once it's been fetched.
+func (m model) setAlertList(msg alertListMsg) (tea.Model, tea.Cmd) {
+	l := m.alerts
+	if l == nil {
+		return m, nil
+	}
+	// The last page was emptied, e.g. by deleting elsewhere, so go back one.
+	if msg.err == nil && len(msg.policies) == 0 && msg.page > 1 {
+		return m, fetchAlertList(m.client, msg.page-1)
+	}
+
+	l.loading = false
+	l.err = msg.err
+	if msg.err != nil {
+		return m, nil
+	}
+	l.page, l.policies, l.total = msg.page, msg.policies, msg.total
+	l.setRows()
+	return m, nil
+}
+
+// setRows fills the table in from the alert policies.
+func (l *alertList) setRows() {
+	rows := make([]table.Row, len(l.policies))
+	for i, p := range l.policies {
+		rows[i] = table.Row{p.Description, alertMetricName(p.Type), alertCondition(p), alertScopeLabel(p), alertNotifiesLabel(p.Alerts), yesNoLabel(p.Enabled)}
+	}
+	l.table.SetRows(rows)
+	if l.table.Cursor() >= len(rows) {
+		l.table.GotoTop()
+	}
+}
+
+// alertCondition describes when a policy alerts, e.g. "above 80% for 5m".
+func alertCondition(p godo.AlertPolicy) string {
+	comp := "above"
+	if p.Compare == godo.LessThan {
+		comp = "below"
+	}
+	return fmt.Sprintf("%s %s for %s", comp, formatThreshold(p.Value), p.Window)
+}
+
+// alertScopeLabel describes the Droplets a policy applies to.
+func alertScopeLabel(p godo.AlertPolicy) string {
+	var parts []string
+	if len(p.Entities) > 0 {
+		parts = append(parts, pluralize(len(p.Entities), "Droplet"))
+	}
+	for _, tag := range p.Tags {
+		parts = append(parts, "tag:"+tag)
+	}
+	if len(parts) == 0 {
+		return "all Droplets"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// alertNotifiesLabel describes where a policy's alerts are sent.
+func alertNotifiesLabel(a godo.Alerts) string {
+	parts := append([]string(nil), a.Email...)
+	for _, s := range a.Slack {
+		parts = append(parts, "Slack "+s.Channel)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// yesNoLabel returns "yes" or "no".
+func yesNoLabel(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// openAlerts shows the list of alert policies, fetching its first page.
+func (m model) openAlerts() (model, tea.Cmd) {
+	m.alerts = newAlertList(m.width, m.height-1)
+	m.alerts.loading = true
+	m.notice = ""
+	m.formErr = ""
+	return m, tea.Batch(fetchAlertList(m.client, 1), m.spinner.Tick)
+}
+
+func (m model) updateAlerts(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.alerts
+	if l.create != nil {
+		return m.updateAlertWizard(msg)
+	}
+
+	switch {
+	case msg.String() == "ctrl+c":
+		return m, m.quit()
+	case key.Matches(msg, closeListKeys):
+		return m.closeScreen()
+	case key.Matches(msg, screensKey):
+		return m.openScreens()
+	case key.Matches(msg, createAlertKey):
+		return m.openAlertWizard()
+	case l.loading:
+		return m, nil
+	case key.Matches(msg, refreshListKey):
+		l.loading = true
+		return m, fetchAlertList(m.client, l.page)
+	case key.Matches(msg, nextPageKey) && l.page < l.pages():
+		l.loading = true
+		return m, fetchAlertList(m.client, l.page+1)
+	case key.Matches(msg, prevPageKey) && l.page > 1:
+		l.loading = true
+		return m, fetchAlertList(m.client, l.page-1)
+	}
+
+	var cmd tea.Cmd
+	l.table, cmd = l.table.Update(msg)
+	return m, cmd
+}
+
+func (m model) alertsView() string {
+	l := m.alerts
+	if v, ok := m.alertWizardPickerView(); ok {
+		return v
+	}
+
+	var b strings.Builder
+
+	title := focusedStyle.Render("Alert policies")
+	if l.total > 0 {
+		count := fmt.Sprintf("%d policies", l.total)
+		if l.total == 1 {
+			count = "1 policy"
+		}
+		title += placeholderStyle.Render(fmt.Sprintf("  %s · page %d of %d", count, l.page, l.pages()))
+	}
+	b.WriteString(title + "\n\n")
+
+	switch {
+	case l.loading && l.policies == nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading alert policies..."))
+	case l.err != nil && l.policies == nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the alert policies: "+l.err.Error()))
+	case len(l.policies) == 0:
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("There are no alert policies in this account yet."))
+	default:
+		fmt.Fprintf(&b, "%s\n\n", l.table.View())
+	}
+
+	switch {
+	case l.loading && l.policies != nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading..."))
+	case l.err != nil && l.policies != nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the alert policies: "+l.err.Error()))
+	}
+	if len(l.creating) > 0 {
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Creating "+strings.Join(l.creating, ", ")+"..."))
+	}
+	if m.notice != "" {
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render(m.notice))
+	}
+	if m.formErr != "" {
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(m.formErr))
+	}
+
+	if l.create != nil {
+		b.WriteString(m.alertWizardView())
+		return b.String()
+	}
+
+	help := []string{"↑/↓: move", "c: create"}
+	if l.pages() > 1 {
+		help = append(help, "←/→: page")
+	}
+	help = append(help, "r: refresh", "tab: screens", m.closeScreenHelp())
+	b.WriteString(helpStyle.Render(strings.Join(help, " • ")))
+
+	return b.String()
+}
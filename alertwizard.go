@@ -0,0 +1,697 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+// alertStep is a step of the alert policy wizard, in the order they're
+// taken.
+type alertStep int
+
+const (
+	alertStepMetric alertStep = iota
+	alertStepThreshold
+	alertStepWindow
+	alertStepScope
+	alertStepDroplets
+	alertStepTag
+	alertStepEmails
+	alertStepSlack
+	alertStepChannel
+	alertStepDescription
+	alertStepReview
+)
+
+// alertMetric is a Droplet metric a policy can alert on, as a percentage
+// used.
+type alertMetric struct {
+	typ, name, description string
+}
+
+// alertMetrics are the metrics offered, in the order they're listed.
+var alertMetrics = []alertMetric{
+	{godo.DropletCPUUtilizationPercent, "CPU", "the percentage of CPU in use, across all cores"},
+	{godo.DropletMemoryUtilizationPercent, "Memory", "the percentage of memory in use"},
+	{godo.DropletDiskUtilizationPercent, "Disk", "the percentage of disk space in use"},
+}
+
+func (a alertMetric) Title() string       { return a.name }
+func (a alertMetric) Description() string { return a.description }
+func (a alertMetric) FilterValue() string { return a.name }
+func (a alertMetric) Value() string       { return a.typ }
+
+// alertMetricName returns the name of the metric a policy alerts on, or its
+// type if it's not one that's offered.
+func alertMetricName(typ string) string {
+	for _, a := range alertMetrics {
+		if a.typ == typ {
+			return a.name
+		}
+	}
+	return strings.TrimPrefix(typ, "v1/insights/droplet/")
+}
+
+// alertWindows are how long a metric must stay past its threshold before
+// the API alerts, as it takes them.
+var alertWindows = []string{"5m", "10m", "30m", "1h"}
+
+// alertWindow is a window to choose for a policy.
+type alertWindow string
+
+func (w alertWindow) Title() string {
+	if strings.HasSuffix(string(w), "h") {
+		n, _ := strconv.Atoi(strings.TrimSuffix(string(w), "h"))
+		return pluralize(n, "hour")
+	}
+	n, _ := strconv.Atoi(strings.TrimSuffix(string(w), "m"))
+	return pluralize(n, "minute")
+}
+func (w alertWindow) Description() string {
+	return "alerts once it's been over the threshold this long"
+}
+func (w alertWindow) FilterValue() string { return w.Title() }
+func (w alertWindow) Value() string       { return string(w) }
+
+// alertScope is which Droplets a new policy applies to.
+type alertScope int
+
+const (
+	alertScopeAll alertScope = iota
+	alertScopeDroplets
+	alertScopeTag
+)
+
+func (s alertScope) Title() string {
+	switch s {
+	case alertScopeDroplets:
+		return "Droplets"
+	case alertScopeTag:
+		return "A tag"
+	}
+	return "All Droplets"
+}
+
+func (s alertScope) Description() string {
+	switch s {
+	case alertScopeDroplets:
+		return "watches the Droplets chosen now"
+	case alertScopeTag:
+		return "watches every Droplet with the tag, including ones tagged later"
+	}
+	return "watches every Droplet on the account, including ones created later"
+}
+
+func (s alertScope) FilterValue() string { return s.Title() }
+func (s alertScope) Value() string       { return s.Title() }
+
+// alertWizard walks through creating an alert policy a step at a time. Esc
+// goes back a step.
+type alertWizard struct {
+	step alertStep
+
+	metrics     *selectField
+	threshold   *textField
+	windows     *selectField
+	scopes      *selectField
+	droplets    *multiSelectField
+	tag         *textField
+	emails      *textField
+	slack       *textField
+	channel     *textField
+	description *textField
+
+	// The answers so far, parsed.
+	value     float32
+	addresses []string
+	// dropletNames is the names of the chosen Droplets, for the review.
+	dropletNames []string
+	// err is set if the Droplets couldn't be fetched.
+	err error
+}
+
+// alertDropletsMsg carries the account's Droplets for an alert policy to
+// watch.
+type alertDropletsMsg struct {
+	droplets []godo.Droplet
+	err      error
+}
+
+func fetchAlertDroplets(client *godo.Client) tea.Cmd {
+	return func() tea.Msg {
+		droplets, _, err := client.Droplets.List(context.Background(), &godo.ListOptions{PerPage: 200})
+		return alertDropletsMsg{droplets: droplets, err: err}
+	}
+}
+
+// openAlertWizard starts creating an alert policy, choosing its metric
+// first.
+func (m model) openAlertWizard() (model, tea.Cmd) {
+	if m.blockChanges("alert policies", "created") {
+		return m, nil
+	}
+
+	w := &alertWizard{metrics: newSelectField("", "Alert on", "")}
+	w.metrics.SetSize(m.width, m.height-1)
+	opts := make([]option, len(alertMetrics))
+	for i, a := range alertMetrics {
+		opts[i] = a
+	}
+	cmd := w.metrics.SetOptions(opts)
+	w.metrics.Open()
+	m.alerts.create = w
+	m.notice = ""
+	m.formErr = ""
+	return m, cmd
+}
+
+// setAlertDroplets offers the account's Droplets once they've been fetched.
+func (m model) setAlertDroplets(msg alertDropletsMsg) (tea.Model, tea.Cmd) {
+	if m.alerts == nil || m.alerts.create == nil || m.alerts.create.droplets == nil {
+		return m, nil
+	}
+	w := m.alerts.create
+	if msg.err == nil && len(msg.droplets) == 0 {
+		msg.err = errors.New("there are no Droplets in this account; press esc to choose a tag instead")
+	}
+	if msg.err != nil {
+		// Fetched again if the step's come back to.
+		w.droplets = nil
+		w.err = msg.err
+		return m, nil
+	}
+
+	opts := make([]option, len(msg.droplets))
+	for i, d := range msg.droplets {
+		opts[i] = dropletOption{d}
+	}
+	cmd := w.droplets.SetOptions(opts)
+	if w.step == alertStepDroplets {
+		w.droplets.Open()
+	}
+	return m, cmd
+}
+
+func (m model) updateAlertWizard(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.alerts
+	w := l.create
+	if msg.String() == "ctrl+c" {
+		return m, m.quit()
+	}
+
+	switch w.step {
+	case alertStepMetric:
+		_, cmd := w.metrics.Update(msg)
+		if w.metrics.Opened() {
+			return m, cmd
+		}
+		if msg.String() == "esc" || w.metrics.selected == nil {
+			return m.alertWizardBack()
+		}
+		return m.alertWizardStep(alertStepThreshold)
+
+	case alertStepWindow:
+		_, cmd := w.windows.Update(msg)
+		if w.windows.Opened() {
+			return m, cmd
+		}
+		if msg.String() == "esc" || w.windows.selected == nil {
+			return m.alertWizardBack()
+		}
+		return m.alertWizardStep(alertStepScope)
+
+	case alertStepScope:
+		_, cmd := w.scopes.Update(msg)
+		if w.scopes.Opened() {
+			return m, cmd
+		}
+		scope, ok := w.scopes.selected.(alertScope)
+		if msg.String() == "esc" || !ok {
+			return m.alertWizardBack()
+		}
+		switch scope {
+		case alertScopeDroplets:
+			return m.alertWizardStep(alertStepDroplets)
+		case alertScopeTag:
+			return m.alertWizardStep(alertStepTag)
+		}
+		return m.alertWizardStep(alertStepEmails)
+
+	case alertStepDroplets:
+		if msg.String() == "esc" && (w.droplets == nil || w.droplets.list.FilterState() == list.Unfiltered) {
+			return m.alertWizardBack()
+		}
+		if w.droplets == nil || !w.droplets.Opened() {
+			// Waiting for the Droplets.
+			return m, nil
+		}
+		_, cmd := w.droplets.Update(msg)
+		if w.droplets.Opened() {
+			return m, cmd
+		}
+		var names []string
+		for _, o := range w.droplets.options {
+			if w.droplets.checked[o.Value()] {
+				names = append(names, o.Title())
+			}
+		}
+		if len(names) == 0 {
+			m.formErr = "choose at least one Droplet with space, or press esc to choose a tag instead"
+			w.droplets.Open()
+			return m, cmd
+		}
+		m.formErr = ""
+		w.dropletNames = names
+		return m.alertWizardStep(alertStepEmails)
+
+	case alertStepReview:
+		switch msg.String() {
+		case "esc":
+			return m.alertWizardBack()
+		case "enter":
+			req := w.request()
+			l.create = nil
+			m.formErr = ""
+			l.creating = append(l.creating, req.Description)
+			return m, createAlertPolicy(m.client, req)
+		}
+		return m, nil
+	}
+
+	// The rest of the steps are typed.
+	f := w.field()
+	switch msg.String() {
+	case "esc":
+		return m.alertWizardBack()
+	case "enter":
+		if err := w.answer(); err != "" {
+			m.formErr = err
+			return m, nil
+		}
+		m.formErr = ""
+		return m.alertWizardStep(w.next())
+	}
+
+	_, cmd := f.Update(msg)
+	return m, cmd
+}
+
+// field returns the text field of the current step.
+func (w *alertWizard) field() *textField {
+	switch w.step {
+	case alertStepThreshold:
+		return w.threshold
+	case alertStepTag:
+		return w.tag
+	case alertStepEmails:
+		return w.emails
+	case alertStepSlack:
+		return w.slack
+	case alertStepChannel:
+		return w.channel
+	case alertStepDescription:
+		return w.description
+	}
+	return nil
+}
+
+// answer checks and keeps the answer typed at the current step, returning
+// an error message if it's no good.
+func (w *alertWizard) answer() string {
+	switch w.step {
+	case alertStepThreshold:
+		value, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(w.threshold.Value()), "%"), 32)
+		if err != nil || value <= 0 || value >= 100 {
+			return "the threshold is a percentage between 0 and 100, e.g. 80"
+		}
+		w.value = float32(value)
+	case alertStepTag:
+		tag := strings.TrimSpace(w.tag.Value())
+		if tag == "" {
+			return "type the tag whose Droplets it watches"
+		}
+		if invalidTagChars.MatchString(tag) {
+			return fmt.Sprintf("tag %q can only have letters, numbers, colons, dashes and underscores", tag)
+		}
+	case alertStepEmails:
+		addresses := splitList(w.emails.Value())
+		for _, address := range addresses {
+			if _, err := mail.ParseAddress(address); err != nil {
+				return fmt.Sprintf("%q isn't an email address", address)
+			}
+		}
+		w.addresses = addresses
+	case alertStepSlack:
+		hook := strings.TrimSpace(w.slack.Value())
+		if hook == "" {
+			if len(w.addresses) == 0 {
+				return "the policy needs an email address or a Slack webhook to alert; press esc to add an address"
+			}
+			return ""
+		}
+		if u, err := url.Parse(hook); err != nil || u.Scheme != "https" || u.Host == "" {
+			return "the webhook is an https URL, e.g. https://hooks.slack.com/services/…"
+		}
+	case alertStepChannel:
+		if strings.TrimSpace(w.channel.Value()) == "" {
+			return "type the Slack channel to post alerts to"
+		}
+	case alertStepDescription:
+		if strings.TrimSpace(w.description.Value()) == "" {
+			return "the policy needs a description"
+		}
+	}
+	return ""
+}
+
+// next returns the step after the current typed one.
+func (w *alertWizard) next() alertStep {
+	switch w.step {
+	case alertStepThreshold:
+		return alertStepWindow
+	case alertStepTag:
+		return alertStepEmails
+	case alertStepEmails:
+		return alertStepSlack
+	case alertStepSlack:
+		if w.hasSlack() {
+			return alertStepChannel
+		}
+		return alertStepDescription
+	case alertStepChannel:
+		return alertStepDescription
+	}
+	return alertStepReview
+}
+
+// hasSlack reports whether a Slack webhook was given.
+func (w *alertWizard) hasSlack() bool {
+	return w.slack != nil && strings.TrimSpace(w.slack.Value()) != ""
+}
+
+// scope returns the Droplets the policy applies to.
+func (w *alertWizard) scope() alertScope {
+	scope, _ := w.scopes.selected.(alertScope)
+	return scope
+}
+
+// alertWizardStep moves the wizard on to the step, setting up its field the
+// first time it's reached.
+func (m model) alertWizardStep(step alertStep) (tea.Model, tea.Cmd) {
+	w := m.alerts.create
+	w.step = step
+	w.err = nil
+
+	switch step {
+	case alertStepMetric:
+		w.metrics.Open()
+		return m, nil
+
+	case alertStepThreshold:
+		if w.threshold == nil {
+			w.threshold = newTextField("Alert above (%): ", "80")
+			w.threshold.CharLimit = 6
+		}
+		return m, w.threshold.Focus()
+
+	case alertStepWindow:
+		if w.windows == nil {
+			w.windows = newSelectField("", "Alert once it's been over the threshold for", "")
+			w.windows.SetSize(m.width, m.height-1)
+			opts := make([]option, len(alertWindows))
+			for i, window := range alertWindows {
+				opts[i] = alertWindow(window)
+			}
+			cmd := w.windows.SetOptions(opts)
+			w.windows.Open()
+			return m, cmd
+		}
+		w.windows.Open()
+		return m, nil
+
+	case alertStepScope:
+		if w.scopes == nil {
+			w.scopes = newSelectField("", "Watch", "")
+			w.scopes.SetSize(m.width, m.height-1)
+			cmd := w.scopes.SetOptions([]option{alertScopeAll, alertScopeDroplets, alertScopeTag})
+			w.scopes.Open()
+			return m, cmd
+		}
+		w.scopes.Open()
+		return m, nil
+
+	case alertStepDroplets:
+		if w.droplets == nil {
+			w.droplets = newMultiSelectField("", "Choose the Droplets to watch")
+			w.droplets.SetSize(m.width, m.height-1)
+			return m, fetchAlertDroplets(m.client)
+		}
+		w.droplets.Open()
+		return m, nil
+
+	case alertStepTag:
+		if w.tag == nil {
+			w.tag = newOptionalTextField("Tag: ", "e.g. web")
+			w.tag.CharLimit = 255
+		}
+		return m, w.tag.Focus()
+
+	case alertStepEmails:
+		if w.emails == nil {
+			w.emails = newOptionalTextField("Email (optional): ", "addresses to alert, separated by commas")
+			w.emails.CharLimit = 1024
+			// The account's own address is the usual one to alert.
+			if a := m.account.account; a != nil && a.Email != "" {
+				w.emails.SetValue(a.Email)
+			}
+		}
+		return m, w.emails.Focus()
+
+	case alertStepSlack:
+		if w.slack == nil {
+			w.slack = newOptionalTextField("Slack webhook (optional): ", "https://hooks.slack.com/services/…")
+			w.slack.CharLimit = 1024
+		}
+		return m, w.slack.Focus()
+
+	case alertStepChannel:
+		if w.channel == nil {
+			w.channel = newOptionalTextField("Slack channel: ", "e.g. #alerts")
+			w.channel.CharLimit = 255
+		}
+		return m, w.channel.Focus()
+
+	case alertStepDescription:
+		// Describe the policy unless another description was typed.
+		def := w.defaultDescription()
+		if w.description == nil {
+			w.description = newOptionalTextField("Description: ", "what the alert's titled")
+			w.description.CharLimit = 255
+		}
+		if v := w.description.Value(); v == "" || v == w.description.Placeholder {
+			w.description.SetValue(def)
+		}
+		w.description.Placeholder = def
+		return m, w.description.Focus()
+	}
+	return m, nil
+}
+
+// defaultDescription describes the policy from its answers, e.g. "CPU
+// above 80% on web".
+func (w *alertWizard) defaultDescription() string {
+	desc := fmt.Sprintf("%s above %s", w.metrics.selected.Title(), formatThreshold(w.value))
+	switch w.scope() {
+	case alertScopeDroplets:
+		if len(w.dropletNames) == 1 {
+			return desc + " on " + w.dropletNames[0]
+		}
+		return desc + " on " + pluralize(len(w.dropletNames), "Droplet")
+	case alertScopeTag:
+		return desc + " on " + strings.TrimSpace(w.tag.Value())
+	}
+	return desc
+}
+
+// alertWizardBack goes back a step, or stops creating the policy from the
+// first.
+func (m model) alertWizardBack() (tea.Model, tea.Cmd) {
+	w := m.alerts.create
+	m.formErr = ""
+
+	switch w.step {
+	case alertStepMetric:
+		m.alerts.create = nil
+		return m, nil
+	case alertStepDroplets, alertStepTag:
+		return m.alertWizardStep(alertStepScope)
+	case alertStepEmails:
+		switch w.scope() {
+		case alertScopeDroplets:
+			return m.alertWizardStep(alertStepDroplets)
+		case alertScopeTag:
+			return m.alertWizardStep(alertStepTag)
+		}
+		return m.alertWizardStep(alertStepScope)
+	case alertStepDescription:
+		if !w.hasSlack() {
+			return m.alertWizardStep(alertStepSlack)
+		}
+	}
+	return m.alertWizardStep(w.step - 1)
+}
+
+// request returns the alert policy described by the wizard's answers.
+func (w *alertWizard) request() *godo.AlertPolicyCreateRequest {
+	enabled := true
+	req := &godo.AlertPolicyCreateRequest{
+		Type:        w.metrics.Value(),
+		Description: strings.TrimSpace(w.description.Value()),
+		Compare:     godo.GreaterThan,
+		Value:       w.value,
+		Window:      w.windows.Value(),
+		Entities:    []string{},
+		Tags:        []string{},
+		Alerts:      godo.Alerts{Email: w.addresses, Slack: []godo.SlackDetails{}},
+		Enabled:     &enabled,
+	}
+	if req.Alerts.Email == nil {
+		req.Alerts.Email = []string{}
+	}
+	if w.hasSlack() {
+		req.Alerts.Slack = append(req.Alerts.Slack, godo.SlackDetails{
+			URL:     strings.TrimSpace(w.slack.Value()),
+			Channel: strings.TrimSpace(w.channel.Value()),
+		})
+	}
+
+	switch w.scope() {
+	case alertScopeDroplets:
+		req.Entities = w.droplets.Values()
+	case alertScopeTag:
+		req.Tags = []string{strings.TrimSpace(w.tag.Value())}
+	}
+	return req
+}
+
+// formatThreshold formats a percentage without trailing zeros, e.g. "80%".
+func formatThreshold(v float32) string {
+	return strconv.FormatFloat(float64(v), 'f', -1, 32) + "%"
+}
+
+// alertPolicyCreatedMsg reports that an alert policy has been created, or
+// couldn't be.
+type alertPolicyCreatedMsg struct {
+	description string
+	policy      *godo.AlertPolicy
+	err         error
+}
+
+func createAlertPolicy(client *godo.Client, req *godo.AlertPolicyCreateRequest) tea.Cmd {
+	return func() tea.Msg {
+		p, _, err := client.Monitoring.CreateAlertPolicy(context.Background(), req)
+		return alertPolicyCreatedMsg{description: req.Description, policy: p, err: err}
+	}
+}
+
+// alertPolicyCreated refreshes the list once a policy's been created.
+func (m model) alertPolicyCreated(msg alertPolicyCreatedMsg) (tea.Model, tea.Cmd) {
+	l := m.alerts
+	if l != nil {
+		for i, desc := range l.creating {
+			if desc == msg.description {
+				l.creating = append(l.creating[:i:i], l.creating[i+1:]...)
+				break
+			}
+		}
+	}
+	if msg.err != nil {
+		m.formErr = fmt.Sprintf("couldn't create %s: %s", msg.description, msg.err)
+		return m, nil
+	}
+
+	cmd := m.toast(fmt.Sprintf("Created %s; it alerts %s", msg.description, alertNotifiesLabel(msg.policy.Alerts)))
+	if l == nil {
+		return m, cmd
+	}
+	l.loading = true
+	return m, tea.Batch(cmd, fetchAlertList(m.client, l.page))
+}
+
+// alertWizardPickerView returns the list being chosen from, if the wizard's
+// at a step that's chosen from one.
+func (m model) alertWizardPickerView() (string, bool) {
+	w := m.alerts.create
+	switch {
+	case w == nil:
+		return "", false
+	case w.step == alertStepMetric && w.metrics.Opened():
+		return w.metrics.View(), true
+	case w.step == alertStepWindow && w.windows.Opened():
+		return w.windows.View(), true
+	case w.step == alertStepScope && w.scopes.Opened():
+		return w.scopes.View(), true
+	case w.step == alertStepDroplets && w.droplets != nil && w.droplets.Opened():
+		return w.droplets.View(), true
+	}
+	return "", false
+}
+
+func (m model) alertWizardView() string {
+	w := m.alerts.create
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s\n", focusedStyle.Render("New alert policy"))
+	if w.step > alertStepMetric {
+		fmt.Fprintf(&b, "  Metric: %s\n", w.metrics.selected.Title())
+	}
+	if w.step > alertStepWindow {
+		fmt.Fprintf(&b, "  Condition: above %s for %s\n", formatThreshold(w.value), w.windows.selected.Title())
+	}
+	if w.step > alertStepTag {
+		switch w.scope() {
+		case alertScopeDroplets:
+			fmt.Fprintf(&b, "  Watches: %s\n", strings.Join(w.dropletNames, ", "))
+		case alertScopeTag:
+			fmt.Fprintf(&b, "  Watches: Droplets tagged %s\n", strings.TrimSpace(w.tag.Value()))
+		default:
+			fmt.Fprintf(&b, "  Watches: all Droplets\n")
+		}
+	}
+	if w.step > alertStepEmails && len(w.addresses) > 0 {
+		fmt.Fprintf(&b, "  Email: %s\n", strings.Join(w.addresses, ", "))
+	}
+	if w.step > alertStepChannel && w.hasSlack() {
+		fmt.Fprintf(&b, "  Slack: %s\n", strings.TrimSpace(w.channel.Value()))
+	}
+	if w.step == alertStepReview {
+		fmt.Fprintf(&b, "  Description: %s\n", strings.TrimSpace(w.description.Value()))
+	}
+	b.WriteString("\n")
+
+	switch {
+	case w.err != nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(w.err.Error()))
+		b.WriteString(helpStyle.Render("esc: back"))
+	case w.step == alertStepDroplets:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading Droplets..."))
+		b.WriteString(helpStyle.Render("esc: back"))
+	case w.step == alertStepReview:
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("The metrics agent must be installed on the Droplets for the policy to see them."))
+		b.WriteString(helpStyle.Render("enter: create • esc: back"))
+	case w.field() != nil:
+		fmt.Fprintf(&b, "%s\n\n", w.field().View())
+		b.WriteString(helpStyle.Render("enter: next • esc: back"))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+)
+
+// ansibleGroupChars matches the characters Ansible doesn't allow in group
+// names.
+var ansibleGroupChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// inventoryMsg reports that the Ansible inventory has been written.
+type inventoryMsg struct {
+	path string
+	err  error
+}
+
+// ansibleHost is a host's variables in an Ansible inventory.
+type ansibleHost struct {
+	Host string `yaml:"ansible_host"`
+	User string `yaml:"ansible_user"`
+	Port int    `yaml:"ansible_port,omitempty"`
+}
+
+// ansibleGroup is a group in a YAML Ansible inventory.
+type ansibleGroup struct {
+	Hosts    map[string]*ansibleHost  `yaml:"hosts,omitempty"`
+	Children map[string]*ansibleGroup `yaml:"children,omitempty"`
+}
+
+// inventoryHosts returns the created Droplets as inventory hosts, and the
+// groups they belong to, named after their tags.
+func (m model) inventoryHosts() (map[string]*ansibleHost, map[string][]string) {
+	user := m.defaults.SSHUser
+	if user == "" {
+		user = defaultSSHUser
+	}
+	port := 0
+	if p := m.sshPort(); p != defaultSSHPort {
+		port = p
+	}
+
+	hosts := make(map[string]*ansibleHost)
+	groups := make(map[string][]string)
+	for _, d := range m.createdDroplets() {
+		ip, _ := d.PublicIPv4()
+		if ip == "" {
+			continue
+		}
+		hosts[d.Name] = &ansibleHost{Host: ip, User: user, Port: port}
+		for _, t := range d.Tags {
+			group := ansibleGroupChars.ReplaceAllString(t, "_")
+			groups[group] = append(groups[group], d.Name)
+		}
+	}
+	return hosts, groups
+}
+
+// ansibleExport renders the created Droplets as a YAML Ansible inventory,
+// grouped by tag.
+func (m model) ansibleExport() string {
+	hosts, groups := m.inventoryHosts()
+
+	all := &ansibleGroup{Hosts: hosts}
+	if len(groups) > 0 {
+		all.Children = make(map[string]*ansibleGroup)
+	}
+	for name, members := range groups {
+		g := &ansibleGroup{Hosts: make(map[string]*ansibleHost)}
+		for _, h := range members {
+			// Refer to the host without repeating its variables.
+			g.Hosts[h] = nil
+		}
+		all.Children[name] = g
+	}
+
+	var b strings.Builder
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	if err := enc.Encode(map[string]*ansibleGroup{"all": all}); err != nil {
+		return err.Error()
+	}
+	return strings.ReplaceAll(b.String(), ": null\n", ":\n")
+}
+
+// ansibleINI renders the created Droplets as an INI Ansible inventory,
+// grouped by tag.
+func (m model) ansibleINI() string {
+	hosts, groups := m.inventoryHosts()
+
+	var b strings.Builder
+	names := make([]string, 0, len(hosts))
+	for name := range hosts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		h := hosts[name]
+		fmt.Fprintf(&b, "%s ansible_host=%s ansible_user=%s", name, h.Host, h.User)
+		if h.Port != 0 {
+			fmt.Fprintf(&b, " ansible_port=%d", h.Port)
+		}
+		b.WriteRune('\n')
+	}
+
+	groupNames := make([]string, 0, len(groups))
+	for name := range groups {
+		groupNames = append(groupNames, name)
+	}
+	sort.Strings(groupNames)
+	for _, name := range groupNames {
+		fmt.Fprintf(&b, "\n[%s]\n%s\n", name, strings.Join(groups[name], "\n"))
+	}
+
+	return b.String()
+}
+
+// writeInventory writes the created Droplets to an Ansible inventory at
+// path, as INI if it ends in .ini and YAML otherwise.
+func (m model) writeInventory(path string) tea.Cmd {
+	inventory := m.ansibleExport()
+	if filepath.Ext(path) == ".ini" {
+		inventory = m.ansibleINI()
+	}
+
+	return func() tea.Msg {
+		if err := os.WriteFile(path, []byte(inventory), 0o644); err != nil {
+			return inventoryMsg{err: err}
+		}
+		return inventoryMsg{path: path}
+	}
+}
@@ -0,0 +1,462 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+	"gopkg.in/yaml.v3"
+)
+
+// appPollInterval is how often a deployment is checked on while it's
+// followed.
+const appPollInterval = 5 * time.Second
+
+// defaultAppSpecPath is where doctl and the App Platform GitHub action look
+// for an app spec.
+const defaultAppSpecPath = ".do/app.yaml"
+
+// readAppSpec reads an app spec from the file, as YAML or JSON. Unknown
+// fields are an error, as for the form's spec files.
+func readAppSpec(path string) (*godo.AppSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// The spec's fields are only tagged for JSON, so the YAML is converted.
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if raw == nil {
+		return nil, fmt.Errorf("%s is empty", path)
+	}
+	converted, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	var spec godo.AppSpec
+	dec := json.NewDecoder(bytes.NewReader(converted))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if spec.Name == "" {
+		return nil, fmt.Errorf("%s doesn't name the app", path)
+	}
+	return &spec, nil
+}
+
+// appDeployFlow deploys an app spec: it asks for the spec's path, validates
+// it, and then confirms creating the app, or updating it if there's one of
+// the same name already.
+type appDeployFlow struct {
+	path *textField
+	// proposing is set while the spec is read and validated, and proposal
+	// once it has been.
+	proposing bool
+	spec      *godo.AppSpec
+	app       *godo.App
+	proposal  *godo.AppProposeResponse
+}
+
+// appDeployment is a deployment being followed until it's live.
+type appDeployment struct {
+	name       string
+	deployment *godo.Deployment
+}
+
+// openAppDeploy starts deploying an app spec, asking for its path.
+func (m model) openAppDeploy() (model, tea.Cmd) {
+	if m.blockChanges("apps", "deployed") {
+		return m, nil
+	}
+
+	path := newTextField("App spec: ", defaultAppSpecPath)
+	path.CharLimit = 4096
+	m.apps.deploy = &appDeployFlow{path: path}
+	m.notice = ""
+	m.formErr = ""
+	return m, path.Focus()
+}
+
+// appProposedMsg carries an app spec once it's been validated, along with
+// the app of the same name it updates, if there is one.
+type appProposedMsg struct {
+	spec     *godo.AppSpec
+	app      *godo.App
+	proposal *godo.AppProposeResponse
+	err      error
+}
+
+// proposeApp reads the app spec at path and validates it, as an update to
+// the app of the same name if there is one.
+func proposeApp(client *godo.Client, path string) tea.Cmd {
+	return func() tea.Msg {
+		spec, err := readAppSpec(path)
+		if err != nil {
+			return appProposedMsg{err: err}
+		}
+
+		ctx := context.Background()
+		apps, _, err := client.Apps.List(ctx, &godo.ListOptions{PerPage: 200})
+		if err != nil {
+			return appProposedMsg{err: err}
+		}
+		var existing *godo.App
+		for _, a := range apps {
+			if appSpecName(a) == spec.Name {
+				existing = a
+			}
+		}
+
+		req := &godo.AppProposeRequest{Spec: spec}
+		if existing != nil {
+			req.AppID = existing.ID
+		}
+		proposal, _, err := client.Apps.Propose(ctx, req)
+		if err != nil {
+			return appProposedMsg{err: err}
+		}
+		return appProposedMsg{spec: spec, app: existing, proposal: proposal}
+	}
+}
+
+func (m model) updateAppDeploy(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.apps
+	d := l.deploy
+	switch msg.String() {
+	case "ctrl+c":
+		return m, m.quit()
+	case "esc":
+		l.deploy = nil
+		m.formErr = ""
+		return m, nil
+	case "enter":
+		switch {
+		case d.proposing:
+			return m, nil
+		case d.proposal != nil:
+			l.deploy = nil
+			m.formErr = ""
+			l.deploying[appDeployKey(d.spec, d.app)] = &appDeployment{name: d.spec.Name}
+			return m, deployApp(m.client, d.spec, d.app)
+		}
+		path, err := expandHome(strings.TrimSpace(d.path.Value()))
+		if err != nil {
+			m.formErr = err.Error()
+			return m, nil
+		}
+		d.proposing = true
+		m.formErr = ""
+		return m, proposeApp(m.client, path)
+	}
+
+	if d.proposing || d.proposal != nil {
+		return m, nil
+	}
+	_, cmd := d.path.Update(msg)
+	return m, cmd
+}
+
+// appDeployKey returns what a deployment's followed by until the app it
+// creates has an ID.
+func appDeployKey(spec *godo.AppSpec, existing *godo.App) string {
+	if existing != nil {
+		return existing.ID
+	}
+	return "new:" + spec.Name
+}
+
+// setAppProposal shows what deploying the spec does once it's been
+// validated.
+func (m model) setAppProposal(msg appProposedMsg) (tea.Model, tea.Cmd) {
+	if m.apps == nil || m.apps.deploy == nil {
+		return m, nil
+	}
+	d := m.apps.deploy
+	d.proposing = false
+	if msg.err != nil {
+		m.formErr = "couldn't validate the app spec: " + msg.err.Error()
+		return m, nil
+	}
+	if msg.app == nil && !msg.proposal.AppNameAvailable {
+		m.formErr = fmt.Sprintf("the app name %s is taken", msg.spec.Name)
+		if msg.proposal.AppNameSuggestion != "" {
+			m.formErr += fmt.Sprintf("; try %s", msg.proposal.AppNameSuggestion)
+		}
+		return m, nil
+	}
+	d.spec, d.app, d.proposal = msg.spec, msg.app, msg.proposal
+	return m, nil
+}
+
+// appDeployStartedMsg reports that an app has been created or updated, and
+// the deployment that's rolling it out.
+type appDeployStartedMsg struct {
+	key          string
+	name         string
+	app          *godo.App
+	deploymentID string
+	err          error
+}
+
+// deployApp creates the app from the spec, or updates the existing app with
+// it, and finds the deployment that started.
+func deployApp(client *godo.Client, spec *godo.AppSpec, existing *godo.App) tea.Cmd {
+	key := appDeployKey(spec, existing)
+
+	return func() tea.Msg {
+		ctx := context.Background()
+		var app *godo.App
+		var err error
+		if existing == nil {
+			app, _, err = client.Apps.Create(ctx, &godo.AppCreateRequest{Spec: spec})
+		} else {
+			app, _, err = client.Apps.Update(ctx, existing.ID, &godo.AppUpdateRequest{Spec: spec})
+		}
+		if err != nil {
+			return appDeployStartedMsg{key: key, name: spec.Name, err: err}
+		}
+
+		for _, d := range []*godo.Deployment{app.InProgressDeployment, app.PendingDeployment} {
+			if d != nil {
+				return appDeployStartedMsg{key: key, name: spec.Name, app: app, deploymentID: d.ID}
+			}
+		}
+		deployments, _, err := client.Apps.ListDeployments(ctx, app.ID, &godo.ListOptions{PerPage: 1})
+		if err != nil {
+			return appDeployStartedMsg{key: key, name: spec.Name, app: app, err: err}
+		}
+		if len(deployments) == 0 {
+			return appDeployStartedMsg{key: key, name: spec.Name, app: app, err: errors.New("no deployment was started")}
+		}
+		return appDeployStartedMsg{key: key, name: spec.Name, app: app, deploymentID: deployments[0].ID}
+	}
+}
+
+// appDeployStarted follows the deployment of an app that's been created or
+// updated.
+func (m model) appDeployStarted(msg appDeployStartedMsg) (tea.Model, tea.Cmd) {
+	l := m.apps
+	if l != nil {
+		delete(l.deploying, msg.key)
+	}
+	if msg.err != nil {
+		m.formErr = fmt.Sprintf("couldn't deploy %s: %s", msg.name, msg.err)
+		return m, nil
+	}
+	if l == nil {
+		return m, nil
+	}
+
+	l.deploying[msg.app.ID] = &appDeployment{name: msg.name}
+	l.loading = true
+	return m, tea.Batch(followDeployment(m.client, msg.app.ID, msg.name, msg.deploymentID, 0), fetchAppList(m.client, l.page))
+}
+
+// appDeploymentMsg carries the latest state of a deployment being followed,
+// and the app's live URL once it's active.
+type appDeploymentMsg struct {
+	appID      string
+	name       string
+	deployment *godo.Deployment
+	liveURL    string
+	err        error
+}
+
+// followDeployment checks on the deployment after waiting for wait.
+func followDeployment(client *godo.Client, appID, name, deploymentID string, wait time.Duration) tea.Cmd {
+	return func() tea.Msg {
+		time.Sleep(wait)
+
+		ctx := context.Background()
+		deployment, _, err := client.Apps.GetDeployment(ctx, appID, deploymentID)
+		if err != nil {
+			return appDeploymentMsg{appID: appID, name: name, err: err}
+		}
+		if deployment.Phase != godo.DeploymentPhase_Active {
+			return appDeploymentMsg{appID: appID, name: name, deployment: deployment}
+		}
+		app, _, err := client.Apps.Get(ctx, appID)
+		if err != nil {
+			return appDeploymentMsg{appID: appID, name: name, deployment: deployment, err: err}
+		}
+		return appDeploymentMsg{appID: appID, name: name, deployment: deployment, liveURL: app.LiveURL}
+	}
+}
+
+// appDeploymentUpdated shows a deployment's progress, following it until
+// it's live or has failed.
+func (m model) appDeploymentUpdated(msg appDeploymentMsg) (tea.Model, tea.Cmd) {
+	l := m.apps
+	if l == nil {
+		return m, nil
+	}
+	d, ok := l.deploying[msg.appID]
+	if !ok {
+		// Left the screen since, so it's no longer shown.
+		return m, nil
+	}
+
+	if msg.err != nil {
+		delete(l.deploying, msg.appID)
+		l.setRows()
+		m.formErr = fmt.Sprintf("couldn't follow the deployment of %s: %s", msg.name, msg.err)
+		return m, nil
+	}
+
+	d.deployment = msg.deployment
+	switch msg.deployment.Phase {
+	case godo.DeploymentPhase_Active:
+		delete(l.deploying, msg.appID)
+		// Left up, rather than toasted, to be copied.
+		m.notice = fmt.Sprintf("%s is live at %s", msg.name, msg.liveURL)
+	case godo.DeploymentPhase_Error, godo.DeploymentPhase_Canceled, godo.DeploymentPhase_Superseded:
+		delete(l.deploying, msg.appID)
+		m.formErr = fmt.Sprintf("couldn't deploy %s: the deployment %s", msg.name, deploymentFailure(msg.deployment))
+	default:
+		l.setRows()
+		return m, followDeployment(m.client, msg.appID, msg.name, msg.deployment.ID, appPollInterval)
+	}
+
+	l.loading = true
+	l.setRows()
+	return m, fetchAppList(m.client, l.page)
+}
+
+// deploymentFailure describes how a deployment ended without going live,
+// e.g. "failed at build: the build failed".
+func deploymentFailure(d *godo.Deployment) string {
+	switch d.Phase {
+	case godo.DeploymentPhase_Canceled:
+		return "was canceled"
+	case godo.DeploymentPhase_Superseded:
+		return "was superseded by a newer one"
+	}
+	if d.Progress != nil {
+		if s := findStep(d.Progress.Steps, godo.DeploymentProgressStepStatus_Error); s != nil {
+			failure := "failed at " + stepLabel(s)
+			if s.Reason != nil && s.Reason.Message != "" {
+				failure += ": " + s.Reason.Message
+			}
+			return failure
+		}
+	}
+	return "failed"
+}
+
+// findStep returns the innermost step with the status, searching the steps
+// in order.
+func findStep(steps []*godo.DeploymentProgressStep, status godo.DeploymentProgressStepStatus) *godo.DeploymentProgressStep {
+	for _, s := range steps {
+		if s.Status != status {
+			continue
+		}
+		if inner := findStep(s.Steps, status); inner != nil {
+			return inner
+		}
+		return s
+	}
+	return nil
+}
+
+// stepLabel names a deployment step along with its component, if it's for
+// one, e.g. "web build".
+func stepLabel(s *godo.DeploymentProgressStep) string {
+	name := strings.ReplaceAll(s.Name, "_", " ")
+	if s.ComponentName != "" {
+		return s.ComponentName + " " + name
+	}
+	return name
+}
+
+// appDeployingView lists the deployments being followed with their
+// progress.
+func (m model) appDeployingView() string {
+	l := m.apps
+	var lines []string
+	for _, d := range l.deploying {
+		line := "Deploying " + d.name
+		if dep := d.deployment; dep != nil {
+			line += ": " + phaseLabel(dep.Phase)
+			if p := dep.Progress; p != nil && p.TotalSteps > 0 {
+				line += fmt.Sprintf(", %d of %d steps done", p.SuccessSteps, p.TotalSteps)
+				if s := findStep(p.Steps, godo.DeploymentProgressStepStatus_Running); s != nil {
+					line += " · " + stepLabel(s)
+				}
+			}
+		}
+		lines = append(lines, line)
+	}
+	sort.Strings(lines)
+
+	var b strings.Builder
+	for _, line := range lines {
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render(line+"..."))
+	}
+	return b.String()
+}
+
+func (m model) appDeployView() string {
+	d := m.apps.deploy
+	var b strings.Builder
+
+	if d.proposal == nil {
+		fmt.Fprintf(&b, "%s\n", focusedStyle.Render("Deploy an app spec"))
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("It creates the app, or updates the app of the same name."))
+		fmt.Fprintf(&b, "%s\n\n", d.path.View())
+		if d.proposing {
+			fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Validating the spec..."))
+		}
+		b.WriteString(helpStyle.Render("enter: validate • esc: cancel"))
+		return b.String()
+	}
+
+	verb := "Create"
+	if d.app != nil {
+		verb = "Update"
+	}
+	fmt.Fprintf(&b, "%s\n", focusedStyle.Render(fmt.Sprintf("%s %s?", verb, d.spec.Name)))
+	var components []string
+	for _, c := range []struct {
+		n    int
+		noun string
+	}{
+		{len(d.spec.Services), "service"},
+		{len(d.spec.StaticSites), "static site"},
+		{len(d.spec.Workers), "worker"},
+		{len(d.spec.Jobs), "job"},
+		{len(d.spec.Functions), "functions component"},
+		{len(d.spec.Databases), "database"},
+	} {
+		if c.n > 0 {
+			components = append(components, pluralize(c.n, c.noun))
+		}
+	}
+	if len(components) > 0 {
+		fmt.Fprintf(&b, "  Components: %s\n", strings.Join(components, ", "))
+	}
+	if d.spec.Region != "" {
+		fmt.Fprintf(&b, "  Region: %s\n", d.spec.Region)
+	}
+	if d.proposal.AppCost > 0 {
+		fmt.Fprintf(&b, "  Cost: $%.2f/mo\n", d.proposal.AppCost)
+	} else if d.proposal.AppIsStatic {
+		fmt.Fprintf(&b, "  Cost: free, as a static site\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("enter: " + strings.ToLower(verb) + " and deploy • esc: cancel"))
+
+	return b.String()
+}
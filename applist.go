@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+var deployAppKey = key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "deploy a spec"))
+
+// appsPerPage is how many apps the apps screen fetches at a time.
+const appsPerPage = 20
+
+// appListMsg carries a page of the account's App Platform apps, along with
+// how many there are in all.
+type appListMsg struct {
+	page  int
+	apps  []*godo.App
+	total int
+	err   error
+}
+
+// fetchAppList lists a page of the account's apps, counting from 1.
+func fetchAppList(client *godo.Client, page int) tea.Cmd {
+	return func() tea.Msg {
+		apps, resp, err := client.Apps.List(context.Background(), &godo.ListOptions{Page: page, PerPage: appsPerPage})
+		if err != nil {
+			return appListMsg{page: page, err: err}
+		}
+
+		total := len(apps)
+		if resp.Meta != nil {
+			total = resp.Meta.Total
+		}
+		return appListMsg{page: page, apps: apps, total: total}
+	}
+}
+
+// appList is the screen listing the account's App Platform apps, a page at
+// a time.
+type appList struct {
+	table   table.Model
+	apps    []*godo.App
+	page    int
+	total   int
+	loading bool
+	err     error
+	// deploy is set while deploying an app spec, from asking for its path
+	// until it's been created or updated.
+	deploy *appDeployFlow
+	// deploying holds the latest state of the deployments being followed,
+	// by app ID, until they're live or have failed.
+	deploying map[string]*appDeployment
+}
+
+func newAppList(width, height int) *appList {
+	l := &appList{
+		page:      1,
+		deploying: make(map[string]*appDeployment),
+		table: newListTable([]table.Column{
+			{Title: "Name", Width: 24},
+			{Title: "Region", Width: 8},
+			{Title: "Live URL", Width: 44},
+			{Title: "Deployment", Width: 16},
+		}),
+	}
+	l.SetSize(width, height)
+	return l
+}
+
+// SetSize fits the table to the screen, under the title and above the help.
+func (l *appList) SetSize(width, height int) {
+	setTableSize(&l.table, width, height)
+}
+
+// pages returns how many pages of apps there are.
+func (l *appList) pages() int {
+	return pageCount(l.total, appsPerPage)
+}
+
+// setAppList shows a page of apps once it's been fetched.
+func (m model) setAppList(msg appListMsg) (tea.Model, tea.Cmd) {
+	l := m.apps
+	if l == nil {
+		return m, nil
+	}
+	// The last page was emptied, e.g. by deleting elsewhere, so go back one.
+	if msg.err == nil && len(msg.apps) == 0 && msg.page > 1 {
+		return m, fetchAppList(m.client, msg.page-1)
+	}
+
+	l.loading = false
+	l.err = msg.err
+	if msg.err != nil {
+		return m, nil
+	}
+	l.page, l.apps, l.total = msg.page, msg.apps, msg.total
+	l.setRows()
+	return m, nil
+}
+
+// setRows fills the table in from the apps.
+func (l *appList) setRows() {
+	rows := make([]table.Row, len(l.apps))
+	for i, a := range l.apps {
+		region := ""
+		if a.Region != nil {
+			region = a.Region.Slug
+		}
+		rows[i] = table.Row{appSpecName(a), region, strings.TrimPrefix(a.LiveURL, "https://"), l.deploymentLabel(a)}
+	}
+	l.table.SetRows(rows)
+	if l.table.Cursor() >= len(rows) {
+		l.table.GotoTop()
+	}
+}
+
+// appSpecName returns the name the app's spec gives it.
+func appSpecName(a *godo.App) string {
+	if a.Spec == nil {
+		return a.ID
+	}
+	return a.Spec.Name
+}
+
+// deploymentLabel describes the app's latest deployment, e.g. "building…"
+// while one is being followed.
+func (l *appList) deploymentLabel(a *godo.App) string {
+	if d, ok := l.deploying[a.ID]; ok && d.deployment != nil {
+		return phaseLabel(d.deployment.Phase) + "…"
+	}
+	for _, d := range []*godo.Deployment{a.InProgressDeployment, a.PendingDeployment, a.ActiveDeployment} {
+		if d != nil {
+			return phaseLabel(d.Phase)
+		}
+	}
+	return ""
+}
+
+// phaseLabel returns a deployment phase in lower case with spaces, e.g.
+// "pending build".
+func phaseLabel(phase godo.DeploymentPhase) string {
+	return strings.ReplaceAll(strings.ToLower(string(phase)), "_", " ")
+}
+
+// openApps shows the list of apps, fetching its first page.
+func (m model) openApps() (model, tea.Cmd) {
+	m.apps = newAppList(m.width, m.height-1)
+	m.apps.loading = true
+	m.notice = ""
+	m.formErr = ""
+	return m, tea.Batch(fetchAppList(m.client, 1), m.spinner.Tick)
+}
+
+func (m model) updateApps(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.apps
+	if l.deploy != nil {
+		return m.updateAppDeploy(msg)
+	}
+
+	switch {
+	case msg.String() == "ctrl+c":
+		return m, m.quit()
+	case key.Matches(msg, closeListKeys):
+		return m.closeScreen()
+	case key.Matches(msg, screensKey):
+		return m.openScreens()
+	case key.Matches(msg, deployAppKey):
+		return m.openAppDeploy()
+	case l.loading:
+		return m, nil
+	case key.Matches(msg, refreshListKey):
+		l.loading = true
+		return m, fetchAppList(m.client, l.page)
+	case key.Matches(msg, nextPageKey) && l.page < l.pages():
+		l.loading = true
+		return m, fetchAppList(m.client, l.page+1)
+	case key.Matches(msg, prevPageKey) && l.page > 1:
+		l.loading = true
+		return m, fetchAppList(m.client, l.page-1)
+	}
+
+	var cmd tea.Cmd
+	l.table, cmd = l.table.Update(msg)
+	return m, cmd
+}
+
+func (m model) appsView() string {
+	l := m.apps
+	var b strings.Builder
+
+	title := focusedStyle.Render("Apps")
+	if l.total > 0 {
+		title += placeholderStyle.Render(fmt.Sprintf("  %s · page %d of %d", pluralize(l.total, "app"), l.page, l.pages()))
+	}
+	b.WriteString(title + "\n\n")
+
+	switch {
+	case l.loading && l.apps == nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading apps..."))
+	case l.err != nil && l.apps == nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the apps: "+l.err.Error()))
+	case len(l.apps) == 0:
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("There are no apps in this account yet."))
+	default:
+		fmt.Fprintf(&b, "%s\n\n", l.table.View())
+	}
+
+	switch {
+	case l.loading && l.apps != nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading..."))
+	case l.err != nil && l.apps != nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the apps: "+l.err.Error()))
+	}
+	b.WriteString(m.appDeployingView())
+	if m.notice != "" {
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render(m.notice))
+	}
+	if m.formErr != "" {
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(m.formErr))
+	}
+
+	if l.deploy != nil {
+		b.WriteString(m.appDeployView())
+		return b.String()
+	}
+
+	help := []string{"↑/↓: move", "c: deploy a spec"}
+	if l.pages() > 1 {
+		help = append(help, "←/→: page")
+	}
+	help = append(help, "r: refresh", "tab: screens", m.closeScreenHelp())
+	b.WriteString(helpStyle.Render(strings.Join(help, " • ")))
+
+	return b.String()
+}
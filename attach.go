@@ -0,0 +1,429 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+var attachKey = key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "attach/detach"))
+
+// volumeAttachMsg reports that a volume has been attached to a Droplet, or
+// detached from it if attach isn't set.
+type volumeAttachMsg struct {
+	volume      godo.Volume
+	dropletID   int
+	dropletName string
+	attach      bool
+	err         error
+}
+
+// attachVolume attaches the volume to the Droplet, or detaches it, and waits
+// for the action to complete.
+func attachVolume(client *godo.Client, volume godo.Volume, dropletID int, dropletName string, attach bool) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+
+		var action *godo.Action
+		var err error
+		if attach {
+			action, _, err = client.StorageActions.Attach(ctx, volume.ID, dropletID)
+		} else {
+			action, _, err = client.StorageActions.DetachByDropletID(ctx, volume.ID, dropletID)
+		}
+		if err == nil {
+			err = waitForAction(ctx, client, action)
+		}
+		return volumeAttachMsg{volume: volume, dropletID: dropletID, dropletName: dropletName, attach: attach, err: err}
+	}
+}
+
+// mountHint explains how to mount the volume once it's attached.
+func mountHint(volume string) string {
+	dir := "/mnt/" + mountName(volume)
+	return fmt.Sprintf("It's at /dev/disk/by-id/scsi-0DO_Volume_%s; mount it with sudo mkdir -p %s && sudo mount -o discard,defaults /dev/disk/by-id/scsi-0DO_Volume_%s %s", volume, dir, volume, dir)
+}
+
+// volumeAttached refreshes whichever screen the volume was attached or
+// detached from once it's done.
+func (m model) volumeAttached(msg volumeAttachMsg) (tea.Model, tea.Cmd) {
+	if m.volumes != nil {
+		delete(m.volumes.busy, msg.volume.ID)
+		m.volumes.setRows()
+	}
+	if m.list != nil {
+		delete(m.list.busy, msg.dropletID)
+		m.list.setRows()
+	}
+
+	verb, prep := "detach", "from"
+	if msg.attach {
+		verb, prep = "attach", "to"
+	}
+	if msg.err != nil {
+		m.formErr = fmt.Sprintf("couldn't %s %s %s %s: %s", verb, msg.volume.Name, prep, msg.dropletName, msg.err)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	if msg.attach {
+		// Left up, rather than toasted, to be copied.
+		m.notice = fmt.Sprintf("Attached %s to %s. %s", msg.volume.Name, msg.dropletName, mountHint(msg.volume.Name))
+	} else {
+		cmd = m.toast(fmt.Sprintf("Detached %s from %s", msg.volume.Name, msg.dropletName))
+	}
+	switch {
+	case m.volumes != nil:
+		m.volumes.loading = true
+		cmd = tea.Batch(cmd, fetchVolumes(m.client, m.volumes.page))
+	case m.list != nil:
+		m.list.loading = true
+		cmd = tea.Batch(cmd, fetchDroplets(m.client, m.list.page))
+	}
+	return m, cmd
+}
+
+// dropletOption offers a Droplet to choose, e.g. to attach a volume to. Its
+// value is the Droplet's ID, since names needn't be unique.
+type dropletOption struct {
+	godo.Droplet
+}
+
+func (d dropletOption) Title() string { return d.Name }
+
+func (d dropletOption) Description() string {
+	ip, _ := d.PublicIPv4()
+	return strings.Join([]string{d.SizeSlug, d.Status, ip}, " · ")
+}
+
+func (d dropletOption) FilterValue() string { return d.Name }
+func (d dropletOption) Value() string       { return strconv.Itoa(d.ID) }
+
+// volumeAttachFlow attaches a volume to a Droplet from the volumes screen,
+// or detaches it from its Droplet after confirming.
+type volumeAttachFlow struct {
+	volume godo.Volume
+	// droplets lists the Droplets it can be attached to, unless it's
+	// already attached, when detach is set instead.
+	droplets *selectField
+	detach   bool
+	err      error
+}
+
+// attachDropletsMsg carries the Droplets a volume can be attached to.
+type attachDropletsMsg struct {
+	volumeID string
+	droplets []godo.Droplet
+	err      error
+}
+
+// fetchAttachDroplets lists the Droplets in the volume's region, which are
+// the only ones it can be attached to.
+func fetchAttachDroplets(client *godo.Client, volume godo.Volume) tea.Cmd {
+	return func() tea.Msg {
+		region := ""
+		if volume.Region != nil {
+			region = volume.Region.Slug
+		}
+		droplets, err := dropletsInRegion(context.Background(), client, region)
+		return attachDropletsMsg{volumeID: volume.ID, droplets: droplets, err: err}
+	}
+}
+
+// dropletsInRegion lists the account's Droplets in the region.
+func dropletsInRegion(ctx context.Context, client *godo.Client, region string) ([]godo.Droplet, error) {
+	droplets, _, err := client.Droplets.List(ctx, &godo.ListOptions{PerPage: 200})
+	if err != nil {
+		return nil, err
+	}
+
+	var inRegion []godo.Droplet
+	for _, d := range droplets {
+		if d.Region != nil && d.Region.Slug == region {
+			inRegion = append(inRegion, d)
+		}
+	}
+	return inRegion, nil
+}
+
+// openVolumeAttach attaches the volume under the cursor to a Droplet, or
+// detaches it if it's attached.
+func (m model) openVolumeAttach() (model, tea.Cmd) {
+	if m.blockChanges("volumes", "attached or detached") {
+		return m, nil
+	}
+
+	l := m.volumes
+	volume, ok := l.selected()
+	if !ok {
+		return m, nil
+	}
+	m.notice = ""
+	m.formErr = ""
+	if len(volume.DropletIDs) > 0 {
+		l.attach = &volumeAttachFlow{volume: volume, detach: true}
+		return m, nil
+	}
+
+	droplets := newSelectField("", "Attach "+volume.Name+" to", "")
+	droplets.SetSize(m.width, m.height-1)
+	l.attach = &volumeAttachFlow{volume: volume, droplets: droplets}
+	return m, fetchAttachDroplets(m.client, volume)
+}
+
+// setAttachDroplets offers the Droplets once they've been fetched.
+func (m model) setAttachDroplets(msg attachDropletsMsg) (tea.Model, tea.Cmd) {
+	if m.volumes == nil || m.volumes.attach == nil || m.volumes.attach.volume.ID != msg.volumeID {
+		return m, nil
+	}
+	a := m.volumes.attach
+	if msg.err == nil && len(msg.droplets) == 0 {
+		msg.err = errors.New("there are no Droplets in its region")
+	}
+	if msg.err != nil {
+		a.err = msg.err
+		return m, nil
+	}
+
+	opts := make([]option, len(msg.droplets))
+	for i, d := range msg.droplets {
+		opts[i] = dropletOption{d}
+	}
+	cmd := a.droplets.SetOptions(opts)
+	a.droplets.Open()
+	return m, cmd
+}
+
+func (m model) updateVolumeAttach(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.volumes
+	a := l.attach
+	if msg.String() == "ctrl+c" {
+		return m, m.quit()
+	}
+
+	switch {
+	case a.detach:
+		switch msg.String() {
+		case "y", "Y":
+			l.attach = nil
+			l.busy[a.volume.ID] = "detaching"
+			l.setRows()
+			id := a.volume.DropletIDs[0]
+			return m, attachVolume(m.client, a.volume, id, l.dropletName(id), false)
+		case "n", "N", "esc":
+			l.attach = nil
+		}
+		return m, nil
+
+	case a.err != nil || !a.droplets.Loaded():
+		if msg.String() == "esc" {
+			l.attach = nil
+		}
+		return m, nil
+	}
+
+	_, cmd := a.droplets.Update(msg)
+	if a.droplets.Opened() {
+		return m, cmd
+	}
+	l.attach = nil
+	d, ok := a.droplets.selected.(dropletOption)
+	if !ok {
+		return m, cmd
+	}
+	l.busy[a.volume.ID] = "attaching"
+	l.setRows()
+	return m, tea.Batch(cmd, attachVolume(m.client, a.volume, d.ID, d.Name, true))
+}
+
+func (m model) volumeAttachView() string {
+	a := m.volumes.attach
+	var b strings.Builder
+
+	switch {
+	case a.detach:
+		fmt.Fprintf(&b, "%s\n", focusedStyle.Render(fmt.Sprintf("Detach %s from %s?", a.volume.Name, m.volumes.attachedTo(a.volume))))
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("Unmount it on the Droplet first, or anything being written to it may be lost."))
+		b.WriteString(helpStyle.Render("y: detach • n: cancel"))
+	case a.err != nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(fmt.Sprintf("can't attach %s: %s", a.volume.Name, a.err)))
+		b.WriteString(helpStyle.Render("esc: back"))
+	case !a.droplets.Loaded():
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading Droplets..."))
+		b.WriteString(helpStyle.Render("esc: cancel"))
+	}
+
+	return b.String()
+}
+
+// dropletVolumesFlow attaches one of the volumes in a Droplet's region to it
+// from the Droplets list, or detaches one of its own after confirming.
+type dropletVolumesFlow struct {
+	droplet godo.Droplet
+	volumes *selectField
+	// chosen is set once a volume attached to it has been chosen, while
+	// confirming detaching it.
+	chosen *godo.Volume
+	err    error
+}
+
+// volumeOption offers a volume to attach to a Droplet, or detach from it.
+type volumeOption struct {
+	godo.Volume
+	attached bool
+}
+
+func (v volumeOption) Title() string { return v.Name }
+
+func (v volumeOption) Description() string {
+	if v.attached {
+		return fmt.Sprintf("%d GB · attached, choose it to detach it", v.SizeGigaBytes)
+	}
+	return fmt.Sprintf("%d GB · available, choose it to attach it", v.SizeGigaBytes)
+}
+
+func (v volumeOption) FilterValue() string { return v.Name }
+func (v volumeOption) Value() string       { return v.ID }
+
+// dropletVolumesMsg carries the volumes that can be attached to or detached
+// from a Droplet.
+type dropletVolumesMsg struct {
+	dropletID int
+	volumes   []volumeOption
+	err       error
+}
+
+// fetchDropletVolumes lists the volumes in the Droplet's region that are
+// either attached to it or to nothing.
+func fetchDropletVolumes(client *godo.Client, droplet godo.Droplet) tea.Cmd {
+	return func() tea.Msg {
+		params := &godo.ListVolumeParams{ListOptions: &godo.ListOptions{PerPage: 200}}
+		if droplet.Region != nil {
+			params.Region = droplet.Region.Slug
+		}
+		volumes, _, err := client.Storage.ListVolumes(context.Background(), params)
+		if err != nil {
+			return dropletVolumesMsg{dropletID: droplet.ID, err: err}
+		}
+
+		var opts []volumeOption
+		for _, v := range volumes {
+			switch {
+			case contains(droplet.VolumeIDs, v.ID):
+				opts = append(opts, volumeOption{Volume: v, attached: true})
+			case len(v.DropletIDs) == 0:
+				opts = append(opts, volumeOption{Volume: v})
+			}
+		}
+		return dropletVolumesMsg{dropletID: droplet.ID, volumes: opts}
+	}
+}
+
+// openDropletVolumes offers the volumes to attach to or detach from the
+// Droplet, fetching them.
+func (m model) openDropletVolumes(droplet godo.Droplet) (model, tea.Cmd) {
+	volumes := newSelectField("", "Volumes for "+droplet.Name, "")
+	volumes.SetSize(m.width, m.height-1)
+	m.list.volumes = &dropletVolumesFlow{droplet: droplet, volumes: volumes}
+	return m, fetchDropletVolumes(m.client, droplet)
+}
+
+// setDropletVolumes offers the volumes once they've been fetched.
+func (m model) setDropletVolumes(msg dropletVolumesMsg) (tea.Model, tea.Cmd) {
+	if m.list == nil || m.list.volumes == nil || m.list.volumes.droplet.ID != msg.dropletID {
+		return m, nil
+	}
+	v := m.list.volumes
+	if msg.err == nil && len(msg.volumes) == 0 {
+		msg.err = errors.New("there are no volumes in its region to attach; create one on the Volumes screen")
+	}
+	if msg.err != nil {
+		v.err = msg.err
+		return m, nil
+	}
+
+	opts := make([]option, len(msg.volumes))
+	for i, o := range msg.volumes {
+		opts[i] = o
+	}
+	cmd := v.volumes.SetOptions(opts)
+	v.volumes.Open()
+	return m, cmd
+}
+
+func (m model) updateDropletVolumes(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.list
+	v := l.volumes
+	if msg.String() == "ctrl+c" {
+		return m, m.quit()
+	}
+
+	switch {
+	case v.err != nil || !v.volumes.Loaded():
+		if msg.String() == "esc" {
+			l.volumes = nil
+		}
+		return m, nil
+
+	case v.chosen != nil:
+		switch msg.String() {
+		case "y", "Y":
+			l.volumes = nil
+			l.busy[v.droplet.ID] = "detaching"
+			l.setRows()
+			return m, attachVolume(m.client, *v.chosen, v.droplet.ID, v.droplet.Name, false)
+		case "n", "N", "esc":
+			// Back to the volumes.
+			v.chosen = nil
+			v.volumes.selected = nil
+			v.volumes.Open()
+		}
+		return m, nil
+	}
+
+	_, cmd := v.volumes.Update(msg)
+	if v.volumes.Opened() {
+		return m, cmd
+	}
+	o, ok := v.volumes.selected.(volumeOption)
+	if !ok {
+		l.volumes = nil
+		return m, cmd
+	}
+	if o.attached {
+		v.chosen = &o.Volume
+		return m, cmd
+	}
+	l.volumes = nil
+	l.busy[v.droplet.ID] = "attaching"
+	l.setRows()
+	return m, tea.Batch(cmd, attachVolume(m.client, o.Volume, v.droplet.ID, v.droplet.Name, true))
+}
+
+func (m model) dropletVolumesView() string {
+	v := m.list.volumes
+	var b strings.Builder
+
+	switch {
+	case v.err != nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(fmt.Sprintf("can't attach a volume to %s: %s", v.droplet.Name, v.err)))
+		b.WriteString(helpStyle.Render("esc: back"))
+	case !v.volumes.Loaded():
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading volumes..."))
+		b.WriteString(helpStyle.Render("esc: cancel"))
+	case v.volumes.Opened():
+		return v.volumes.View()
+	case v.chosen != nil:
+		fmt.Fprintf(&b, "%s\n", focusedStyle.Render(fmt.Sprintf("Detach %s from %s?", v.chosen.Name, v.droplet.Name)))
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("Unmount it on the Droplet first, or anything being written to it may be lost."))
+		b.WriteString(helpStyle.Render("y: detach • n: back"))
+	}
+
+	return b.String()
+}
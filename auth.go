@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/digitalocean/godo"
+	"golang.org/x/oauth2"
+)
+
+// tokenEnvVars are the environment variables a token is read from, in order
+// of precedence. The latter two are the ones doctl and Terraform use.
+var tokenEnvVars = []string{"DO_TOKEN", "DIGITALOCEAN_TOKEN", "DIGITALOCEAN_ACCESS_TOKEN"}
+
+// resolveContext works out which token to use. In order of precedence that's:
+//
+//  1. the doctl auth context given by name, i.e. --context
+//  2. the first of tokenEnvVars that's set
+//  3. the token saved in the system keyring
+//  4. doctl's current auth context
+//
+// It also returns every context that can be switched to. The active context
+// has no token if none was found.
+func resolveContext(name string) ([]authContext, authContext, error) {
+	contexts, current, doctlErr := doctlContexts()
+	if name != "" {
+		if doctlErr != nil {
+			return nil, authContext{}, doctlErr
+		}
+		c, ok := findContext(contexts, name)
+		if !ok {
+			return contexts, authContext{}, fmt.Errorf("doctl has no auth context named %q", name)
+		}
+		return contexts, c, nil
+	}
+
+	var active authContext
+	if name, token := envToken(); token != "" {
+		active = authContext{name: name, source: "environment", token: token}
+	} else if token := keyringToken(); token != "" {
+		active = authContext{name: "keyring", source: "system keyring", token: token}
+	}
+	if active.token != "" {
+		return append([]authContext{active}, contexts...), active, nil
+	}
+
+	if doctlErr != nil {
+		return nil, authContext{}, doctlErr
+	}
+	if c, ok := findContext(contexts, current); ok {
+		return contexts, c, nil
+	}
+	if len(contexts) > 0 {
+		return contexts, authContext{}, fmt.Errorf("doctl's current context %q has no token", current)
+	}
+	return nil, authContext{}, nil
+}
+
+// envToken returns the name of the first of tokenEnvVars that's set, along
+// with its token.
+func envToken() (string, string) {
+	for _, v := range tokenEnvVars {
+		if token := os.Getenv(v); token != "" {
+			return v, token
+		}
+	}
+	return "", ""
+}
+
+// newClient returns an API client for the token. If apiURL is set, requests
+// are sent there instead of the public API, e.g. to a mock or a proxy.
+func newClient(token, apiURL string) (*godo.Client, error) {
+	if apiURL == "" {
+		return godo.NewFromToken(token), nil
+	}
+	if !strings.HasSuffix(apiURL, "/") {
+		apiURL += "/"
+	}
+
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: strings.TrimSpace(token)})
+	return godo.New(oauth2.NewClient(context.Background(), ts), godo.SetBaseURL(apiURL))
+}
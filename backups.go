@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+// backupsFlow browses a Droplet's backups from the list, restoring one after
+// typing the Droplet's name to confirm, since its disk is overwritten.
+type backupsFlow struct {
+	droplet godo.Droplet
+	backups *selectField
+	// name is set once a backup has been chosen, asking for the Droplet's
+	// name to confirm.
+	backup backupItem
+	name   *textField
+	err    error
+}
+
+// backupItem lists a backup in the backups screen.
+type backupItem struct {
+	godo.Image
+}
+
+func (b backupItem) Title() string { return b.Name }
+
+func (b backupItem) Description() string {
+	taken := b.Created
+	if t, err := time.Parse(time.RFC3339, b.Created); err == nil {
+		taken = t.Local().Format("2006-01-02 15:04")
+	}
+	return fmt.Sprintf("taken %s · %.1f GB · %d", taken, b.SizeGigaBytes, b.ID)
+}
+
+func (b backupItem) FilterValue() string { return b.Name }
+func (b backupItem) Value() string       { return strconv.Itoa(b.ID) }
+
+// dropletBackupsMsg carries a Droplet's backups.
+type dropletBackupsMsg struct {
+	id      int
+	backups []godo.Image
+	err     error
+}
+
+// fetchBackups lists the Droplet's backups.
+func fetchBackups(client *godo.Client, id int) tea.Cmd {
+	return func() tea.Msg {
+		backups, _, err := client.Droplets.Backups(context.Background(), id, &godo.ListOptions{PerPage: 200})
+		return dropletBackupsMsg{id: id, backups: backups, err: err}
+	}
+}
+
+// openBackups shows the Droplet's backups, fetching them.
+func (m model) openBackups(droplet godo.Droplet) (model, tea.Cmd) {
+	backups := newSelectField("", "Backups of "+droplet.Name, "")
+	backups.SetSize(m.width, m.height-1)
+	m.list.backups = &backupsFlow{droplet: droplet, backups: backups}
+	return m, fetchBackups(m.client, droplet.ID)
+}
+
+// setBackups opens the backups screen once they've been fetched.
+func (m model) setBackups(msg dropletBackupsMsg) (tea.Model, tea.Cmd) {
+	if m.list == nil || m.list.backups == nil || m.list.backups.droplet.ID != msg.id {
+		return m, nil
+	}
+	b := m.list.backups
+	if msg.err == nil && len(msg.backups) == 0 {
+		if contains(b.droplet.Features, "backups") {
+			msg.err = errors.New("it has no backups yet; the first is taken within a week of enabling them")
+		} else {
+			msg.err = errors.New("it has no backups, and they aren't enabled")
+		}
+	}
+	if msg.err != nil {
+		b.err = msg.err
+		return m, nil
+	}
+
+	opts := make([]option, len(msg.backups))
+	for i, backup := range msg.backups {
+		opts[i] = backupItem{backup}
+	}
+	cmd := b.backups.SetOptions(opts)
+	b.backups.Open()
+	return m, cmd
+}
+
+func (m model) updateBackups(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.list
+	b := l.backups
+	if msg.String() == "ctrl+c" {
+		return m, m.quit()
+	}
+
+	switch {
+	case b.err != nil || !b.backups.Loaded():
+		if msg.String() == "esc" {
+			l.backups = nil
+		}
+		return m, nil
+
+	case b.backups.Opened():
+		_, cmd := b.backups.Update(msg)
+		if b.backups.Opened() {
+			return m, cmd
+		}
+		backup, ok := b.backups.selected.(backupItem)
+		if !ok {
+			l.backups = nil
+			return m, cmd
+		}
+		b.backup = backup
+		b.name = newOptionalTextField("Type its name to restore it: ", b.droplet.Name)
+		b.name.CharLimit = 255
+		return m, tea.Batch(cmd, b.name.Focus())
+
+	case b.name != nil:
+		switch msg.String() {
+		case "esc":
+			// Back to the backups.
+			b.name = nil
+			b.backups.selected = nil
+			b.backups.Open()
+			m.formErr = ""
+			return m, nil
+		case "enter":
+			if strings.TrimSpace(b.name.Model.Value()) != b.droplet.Name {
+				m.formErr = "the name doesn't match; type it exactly, or press esc to go back"
+				return m, nil
+			}
+			l.backups = nil
+			m.formErr = ""
+			l.busy[b.droplet.ID] = "restoring"
+			l.setRows()
+			return m, restoreDroplet(m.client, b.droplet, b.backup)
+		}
+		_, cmd := b.name.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+// restoreDroplet restores the Droplet from the backup and waits for the
+// action to complete.
+func restoreDroplet(client *godo.Client, droplet godo.Droplet, backup backupItem) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+
+		action, _, err := client.DropletActions.Restore(ctx, droplet.ID, backup.ID)
+		if err == nil {
+			err = waitForAction(ctx, client, action)
+		}
+		return dropletActionMsg{id: droplet.ID, name: fmt.Sprintf("%s from %s", droplet.Name, backup.Name), verb: "restore", done: "Restored", err: err}
+	}
+}
+
+func (m model) backupsView() string {
+	b := m.list.backups
+	var s strings.Builder
+
+	switch {
+	case b.err != nil:
+		fmt.Fprintf(&s, "%s\n\n", errorStyle.Render(fmt.Sprintf("can't restore %s: %s", b.droplet.Name, b.err)))
+		s.WriteString(helpStyle.Render("esc: back"))
+	case !b.backups.Loaded():
+		fmt.Fprintf(&s, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading backups..."))
+		s.WriteString(helpStyle.Render("esc: cancel"))
+	case b.backups.Opened():
+		return b.backups.View()
+	case b.name != nil:
+		fmt.Fprintf(&s, "%s\n", focusedStyle.Render(fmt.Sprintf("Restore %s from %s?", b.droplet.Name, b.backup.Name)))
+		fmt.Fprintf(&s, "%s\n\n", errorStyle.Render("This overwrites its disk with the backup, losing anything written since, and can't be undone."))
+		if m.formErr != "" {
+			fmt.Fprintf(&s, "%s\n\n", errorStyle.Render(m.formErr))
+		}
+		fmt.Fprintf(&s, "%s\n\n", b.name.View())
+		s.WriteString(helpStyle.Render("enter: restore • esc: back"))
+	}
+
+	return s.String()
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// bootstrapSSHOptions keep SSH from prompting, since the script runs without
+// a terminal.
+var bootstrapSSHOptions = []string{"-o", "BatchMode=yes"}
+
+// bootstrapRun is the bootstrap script running on the new Droplets, with its
+// output so far.
+type bootstrapRun struct {
+	script string
+	output viewport.Model
+	lines  []string
+	// out carries the output lines and exit of every run, and running is
+	// how many haven't exited yet.
+	out     chan tea.Msg
+	running int
+}
+
+// bootstrapLineMsg is a line of output from the bootstrap script on the
+// Droplet at index.
+type bootstrapLineMsg struct {
+	index int
+	line  string
+}
+
+// bootstrapDoneMsg reports that the bootstrap script on the Droplet at index
+// has exited.
+type bootstrapDoneMsg struct {
+	index int
+	err   error
+}
+
+// startBootstrap runs the bootstrap script on every Droplet that was
+// created, streaming the output to the screen.
+func (m model) startBootstrap() (model, tea.Cmd) {
+	run := &bootstrapRun{
+		script: m.defaults.Bootstrap,
+		output: viewport.New(m.width, m.bootstrapHeight()),
+		out:    make(chan tea.Msg, 64),
+	}
+
+	var cmds []tea.Cmd
+	for i, p := range m.pending {
+		if !p.settingUp() {
+			continue
+		}
+		m.pending[i].bootstrapped = true
+		run.running++
+		cmds = append(cmds, m.runBootstrap(i, run.out))
+	}
+	m.bootstrap = run
+
+	return m, tea.Batch(append(cmds, run.listen())...)
+}
+
+// runBootstrap pipes the script to a shell on the Droplet at index over SSH,
+// sending each line of output to out as it arrives.
+func (m model) runBootstrap(index int, out chan<- tea.Msg) tea.Cmd {
+	droplet := m.pending[index].droplet
+	script := m.defaults.Bootstrap
+
+	return func() tea.Msg {
+		done := func(err error) tea.Msg {
+			out <- bootstrapDoneMsg{index: index, err: err}
+			return nil
+		}
+
+		f, err := os.Open(script)
+		if err != nil {
+			return done(err)
+		}
+		defer f.Close()
+
+		cmd, err := m.sshCommand(droplet, bootstrapSSHOptions, "sh", "-s")
+		if err != nil {
+			return done(err)
+		}
+		r, w := io.Pipe()
+		cmd.Stdin = f
+		cmd.Stdout = w
+		cmd.Stderr = w
+		if err := cmd.Start(); err != nil {
+			return done(err)
+		}
+		go func() {
+			w.CloseWithError(cmd.Wait())
+		}()
+
+		s := bufio.NewScanner(r)
+		for s.Scan() {
+			out <- bootstrapLineMsg{index: index, line: s.Text()}
+		}
+		return done(s.Err())
+	}
+}
+
+// listen waits for the next message from the running scripts.
+func (r *bootstrapRun) listen() tea.Cmd {
+	return func() tea.Msg {
+		return <-r.out
+	}
+}
+
+// bootstrapHeight is how many lines of output fit on the screen under the
+// title and above the help.
+func (m model) bootstrapHeight() int {
+	if h := m.height - 6; h > 0 {
+		return h
+	}
+	return 10
+}
+
+func (m model) updateBootstrap(msg tea.Msg) (tea.Model, tea.Cmd) {
+	run := m.bootstrap
+
+	switch msg := msg.(type) {
+	case bootstrapLineMsg:
+		line := msg.line
+		if len(m.pending) > 1 {
+			line = placeholderStyle.Render(m.pending[msg.index].droplet.Name+": ") + line
+		}
+		follow := run.output.AtBottom()
+		run.lines = append(run.lines, line)
+		run.output.SetContent(strings.Join(run.lines, "\n"))
+		if follow {
+			run.output.GotoBottom()
+		}
+		return m, run.listen()
+
+	case bootstrapDoneMsg:
+		m.pending[msg.index].bootstrapErr = msg.err
+		run.running--
+		if run.running > 0 {
+			return m, run.listen()
+		}
+		return m.runSetup(healthStep)
+
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		run.output.Width = msg.Width
+		run.output.Height = m.bootstrapHeight()
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return m.quitWith(createSummary(m.pending))
+		case "enter", "esc", "q":
+			if run.running == 0 {
+				m.bootstrap = nil
+				return m, nil
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	run.output, cmd = run.output.Update(msg)
+	return m, cmd
+}
+
+func (m model) bootstrapView() string {
+	var b strings.Builder
+
+	run := m.bootstrap
+	name := filepath.Base(run.script)
+	if run.running > 0 {
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Running "+name+"..."))
+	} else {
+		fmt.Fprintf(&b, "%s\n\n", focusedStyle.Render("Finished running "+name))
+	}
+	fmt.Fprintf(&b, "%s\n\n", run.output.View())
+
+	if run.running > 0 {
+		b.WriteString(helpStyle.Render("↑/↓: scroll • ctrl+c: quit"))
+	} else {
+		b.WriteString(helpStyle.Render("↑/↓: scroll • enter: continue"))
+	}
+
+	return b.String()
+}
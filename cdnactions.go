@@ -0,0 +1,502 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+// cdnCreateFlow creates an endpoint, asking for the bucket it serves and
+// then how long to cache its files. The buckets are listed to choose from
+// when there's a Spaces access key, and typed otherwise.
+type cdnCreateFlow struct {
+	origins *selectField
+	origin  *textField
+	ttls    *selectField
+	// choosingTTL is set once the origin's been given, and originsErr if
+	// the buckets couldn't be listed.
+	choosingTTL bool
+	originsErr  error
+}
+
+// picker returns the list the current step is chosen from, if it's chosen
+// from one.
+func (f *cdnCreateFlow) picker() *selectField {
+	if f.choosingTTL {
+		return f.ttls
+	}
+	return f.origins
+}
+
+// originValue returns the origin that's been chosen or typed.
+func (f *cdnCreateFlow) originValue() string {
+	if f.origins != nil {
+		return f.origins.Value()
+	}
+	return strings.TrimSpace(f.origin.Value())
+}
+
+// cdnOriginsMsg carries the buckets an endpoint can serve.
+type cdnOriginsMsg struct {
+	origins []option
+	err     error
+}
+
+// fetchCDNOrigins lists the buckets in every Spaces region by their
+// hostnames.
+func fetchCDNOrigins(c *spacesClient) tea.Cmd {
+	list := fetchSpacesList(c)
+	return func() tea.Msg {
+		msg := list().(spacesListMsg)
+		if len(msg.buckets) == 0 {
+			for _, err := range msg.errs {
+				return cdnOriginsMsg{err: err}
+			}
+			return cdnOriginsMsg{err: errors.New("there are no Spaces buckets to serve yet; create one on the Spaces screen first")}
+		}
+
+		opts := make([]option, len(msg.buckets))
+		for i, b := range msg.buckets {
+			opts[i] = valueOption(b.origin())
+		}
+		return cdnOriginsMsg{origins: opts}
+	}
+}
+
+// openCDNCreate starts creating an endpoint.
+func (m model) openCDNCreate() (model, tea.Cmd) {
+	if m.blockChanges("CDN endpoints", "created") {
+		return m, nil
+	}
+
+	f := &cdnCreateFlow{ttls: newSelectField("", "How long should the edge cache files?", "")}
+	ttls := make([]option, len(cdnTTLs))
+	for i, ttl := range cdnTTLs {
+		ttls[i] = cdnTTL(ttl)
+	}
+	cmds := []tea.Cmd{f.ttls.SetOptions(ttls)}
+	f.ttls.Select(strconv.Itoa(defaultCDNTTL))
+	f.ttls.SetSize(m.width, m.height-1)
+
+	if c := newSpacesClient(m.defaults); c != nil {
+		f.origins = newSelectField("", "Choose the bucket to serve", "")
+		f.origins.SetSize(m.width, m.height-1)
+		cmds = append(cmds, fetchCDNOrigins(c))
+	} else {
+		f.origin = newOptionalTextField("Origin: ", "e.g. my-assets.nyc3.digitaloceanspaces.com")
+		f.origin.CharLimit = 255
+		cmds = append(cmds, f.origin.Focus())
+	}
+
+	m.cdns.create = f
+	m.notice = ""
+	m.formErr = ""
+	return m, tea.Batch(cmds...)
+}
+
+// setCDNOrigins offers the buckets once they've been listed.
+func (m model) setCDNOrigins(msg cdnOriginsMsg) (tea.Model, tea.Cmd) {
+	if m.cdns == nil || m.cdns.create == nil || m.cdns.create.origins == nil {
+		return m, nil
+	}
+	f := m.cdns.create
+	if msg.err != nil {
+		f.originsErr = msg.err
+		return m, nil
+	}
+	cmd := f.origins.SetOptions(msg.origins)
+	f.origins.Open()
+	return m, cmd
+}
+
+func (m model) updateCDNCreate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.cdns
+	f := l.create
+	if msg.String() == "ctrl+c" {
+		return m, m.quit()
+	}
+
+	if f.choosingTTL {
+		_, cmd := f.ttls.Update(msg)
+		if f.ttls.Opened() {
+			return m, cmd
+		}
+		if msg.String() == "esc" || f.ttls.selected == nil {
+			// Back to the origin.
+			f.choosingTTL = false
+			if f.origins != nil {
+				f.origins.Open()
+				return m, cmd
+			}
+			return m, tea.Batch(cmd, f.origin.Focus())
+		}
+		ttl, _ := strconv.Atoi(f.ttls.Value())
+		origin := f.originValue()
+		l.create = nil
+		m.formErr = ""
+		l.creating = append(l.creating, origin)
+		return m, tea.Batch(cmd, createCDN(m.client, origin, uint32(ttl)))
+	}
+
+	if f.origins != nil {
+		if !f.origins.Opened() {
+			// Waiting for the buckets, or they couldn't be listed.
+			if msg.String() == "esc" {
+				l.create = nil
+			}
+			return m, nil
+		}
+		_, cmd := f.origins.Update(msg)
+		if f.origins.Opened() {
+			return m, cmd
+		}
+		if msg.String() == "esc" || f.origins.selected == nil {
+			l.create = nil
+			return m, cmd
+		}
+		f.choosingTTL = true
+		f.ttls.Open()
+		return m, cmd
+	}
+
+	switch msg.String() {
+	case "esc":
+		l.create = nil
+		m.formErr = ""
+		return m, nil
+	case "enter":
+		if problem := checkCDNOrigin(f.originValue()); problem != "" {
+			m.formErr = problem
+			return m, nil
+		}
+		m.formErr = ""
+		f.origin.Blur()
+		f.choosingTTL = true
+		f.ttls.Open()
+		return m, nil
+	}
+	_, cmd := f.origin.Update(msg)
+	return m, cmd
+}
+
+// checkCDNOrigin returns why origin can't be served by a CDN endpoint, or an
+// empty string if it can. Endpoints serve Spaces buckets, by their
+// hostnames.
+func checkCDNOrigin(origin string) string {
+	if origin == "" {
+		return "type the bucket's hostname, e.g. my-assets.nyc3.digitaloceanspaces.com"
+	}
+	parts := strings.Split(origin, ".")
+	if len(parts) != 4 || !strings.HasSuffix(origin, ".digitaloceanspaces.com") {
+		return fmt.Sprintf("%q isn't a bucket's hostname, like my-assets.nyc3.digitaloceanspaces.com", origin)
+	}
+	if !contains(spacesRegions, parts[1]) {
+		return fmt.Sprintf("%s isn't a Spaces region; choose one of %s", parts[1], strings.Join(spacesRegions, ", "))
+	}
+	return checkBucketName(parts[0])
+}
+
+func createCDN(client *godo.Client, origin string, ttl uint32) tea.Cmd {
+	return func() tea.Msg {
+		_, _, err := client.CDNs.Create(context.Background(), &godo.CDNCreateRequest{Origin: origin, TTL: ttl})
+		return cdnCreatedMsg{origin: origin, err: err}
+	}
+}
+
+// cdnCreatedMsg reports that an endpoint has been created, or couldn't be.
+type cdnCreatedMsg struct {
+	origin string
+	err    error
+}
+
+// cdnCreated refreshes the list once an endpoint's been created.
+func (m model) cdnCreated(msg cdnCreatedMsg) (tea.Model, tea.Cmd) {
+	l := m.cdns
+	if l != nil {
+		for i, origin := range l.creating {
+			if origin == msg.origin {
+				l.creating = append(l.creating[:i:i], l.creating[i+1:]...)
+				break
+			}
+		}
+	}
+	if msg.err != nil {
+		m.formErr = fmt.Sprintf("couldn't create an endpoint for %s: %s", msg.origin, msg.err)
+		return m, nil
+	}
+
+	cmd := m.toast("Created an endpoint for " + msg.origin)
+	if l == nil {
+		return m, cmd
+	}
+	l.loading = true
+	return m, tea.Batch(cmd, fetchCDNList(m.client, l.page))
+}
+
+func (m model) cdnCreateView() string {
+	f := m.cdns.create
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s\n\n", focusedStyle.Render("New CDN endpoint"))
+	switch {
+	case f.origin != nil:
+		fmt.Fprintf(&b, "%s\n\n", f.origin.View())
+		b.WriteString(helpStyle.Render("enter: next • esc: cancel"))
+	case f.originsErr != nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the buckets: "+f.originsErr.Error()))
+		b.WriteString(helpStyle.Render("esc: cancel"))
+	default:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading buckets..."))
+		b.WriteString(helpStyle.Render("esc: cancel"))
+	}
+
+	return b.String()
+}
+
+// cdnFlushPrompt asks which paths to flush from an endpoint's cache.
+type cdnFlushPrompt struct {
+	cdn   godo.CDN
+	paths *textField
+}
+
+// promptCDNFlush asks what to flush from the endpoint under the cursor,
+// starting with everything.
+func (m model) promptCDNFlush() (model, tea.Cmd) {
+	if m.blockChanges("CDN endpoints", "flushed") {
+		return m, nil
+	}
+
+	l := m.cdns
+	c, ok := l.selected()
+	if !ok {
+		return m, nil
+	}
+	paths := newOptionalTextField("Paths: ", "e.g. assets/*, index.html")
+	paths.CharLimit = 4096
+	paths.SetValue("*")
+	l.flush = &cdnFlushPrompt{cdn: c, paths: paths}
+	m.notice = ""
+	m.formErr = ""
+	return m, paths.Focus()
+}
+
+func (m model) updateCDNFlush(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.cdns
+	p := l.flush
+	switch msg.String() {
+	case "ctrl+c":
+		return m, m.quit()
+	case "esc":
+		l.flush = nil
+		m.formErr = ""
+		return m, nil
+	case "enter":
+		var files []string
+		for _, path := range strings.Split(p.paths.Value(), ",") {
+			if path = strings.TrimSpace(path); path != "" {
+				files = append(files, strings.TrimPrefix(path, "/"))
+			}
+		}
+		if len(files) == 0 {
+			m.formErr = "type the paths to flush, or * for everything"
+			return m, nil
+		}
+		l.flush = nil
+		m.formErr = ""
+		l.busy[p.cdn.ID] = "flushing"
+		l.setRows()
+		return m, flushCDN(m.client, p.cdn, files)
+	}
+
+	_, cmd := p.paths.Update(msg)
+	return m, cmd
+}
+
+// flushCDN drops the files from the endpoint's cache, so they're fetched
+// from the bucket again.
+func flushCDN(client *godo.Client, c godo.CDN, files []string) tea.Cmd {
+	return func() tea.Msg {
+		_, err := client.CDNs.FlushCache(context.Background(), c.ID, &godo.CDNFlushCacheRequest{Files: files})
+		return cdnDoneMsg{id: c.ID, origin: c.Origin, verb: "flush", done: "Flushed", err: err}
+	}
+}
+
+func (m model) cdnFlushView() string {
+	p := m.cdns.flush
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s\n", focusedStyle.Render("Flush "+p.cdn.Origin))
+	fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("Separate paths with commas; * flushes everything, and assets/* everything under assets."))
+	fmt.Fprintf(&b, "%s\n\n", p.paths.View())
+	b.WriteString(helpStyle.Render("enter: flush • esc: cancel"))
+
+	return b.String()
+}
+
+// cdnDomainFlow sets an endpoint's custom domain, asking for the domain and
+// then the certificate to serve it with.
+type cdnDomainFlow struct {
+	cdn          godo.CDN
+	domain       *textField
+	certificates *selectField
+	// choosing is set once the domain's been given, and certificatesErr if
+	// no certificate could be offered for it.
+	choosing        bool
+	certificatesErr error
+}
+
+// openCDNDomain starts setting the custom domain of the endpoint under the
+// cursor.
+func (m model) openCDNDomain() (model, tea.Cmd) {
+	if m.blockChanges("CDN endpoints", "changed") {
+		return m, nil
+	}
+
+	l := m.cdns
+	c, ok := l.selected()
+	if !ok {
+		return m, nil
+	}
+	domain := newOptionalTextField("Custom domain: ", "e.g. assets.example.com")
+	domain.CharLimit = 253
+	domain.SetValue(c.CustomDomain)
+	d := &cdnDomainFlow{cdn: c, domain: domain}
+	d.certificates = newSelectField("", "Choose a certificate", "")
+	d.certificates.SetSize(m.width, m.height-1)
+	l.domain = d
+	m.notice = ""
+	m.formErr = ""
+	return m, domain.Focus()
+}
+
+// setCDNCertificates offers the certificates that cover the custom domain
+// once they've been fetched.
+func (m model) setCDNCertificates(msg certificatesMsg) (tea.Model, tea.Cmd) {
+	if m.cdns == nil || m.cdns.domain == nil || !m.cdns.domain.choosing {
+		return m, nil
+	}
+	d := m.cdns.domain
+	domain := strings.TrimSpace(d.domain.Value())
+	var opts []option
+	for _, c := range msg.certificates {
+		if certificateCovers(c, domain) {
+			opts = append(opts, certificateItem{c})
+		}
+	}
+	if msg.err == nil && len(opts) == 0 {
+		msg.err = fmt.Errorf("none of the account's certificates cover %s; add one for it first", domain)
+	}
+	if msg.err != nil {
+		d.certificatesErr = msg.err
+		return m, nil
+	}
+
+	cmd := d.certificates.SetOptions(opts)
+	d.certificates.Select(d.cdn.CertificateID)
+	d.certificates.Open()
+	return m, cmd
+}
+
+func (m model) updateCDNDomain(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.cdns
+	d := l.domain
+	if msg.String() == "ctrl+c" {
+		return m, m.quit()
+	}
+
+	if d.choosing {
+		if !d.certificates.Opened() {
+			// Waiting for the certificates, or there are none to choose.
+			if msg.String() == "esc" {
+				d.choosing = false
+				d.certificatesErr = nil
+				return m, d.domain.Focus()
+			}
+			return m, nil
+		}
+		_, cmd := d.certificates.Update(msg)
+		if d.certificates.Opened() {
+			return m, cmd
+		}
+		if msg.String() == "esc" || d.certificates.selected == nil {
+			d.choosing = false
+			return m, tea.Batch(cmd, d.domain.Focus())
+		}
+		l.domain = nil
+		l.busy[d.cdn.ID] = "updating"
+		l.setRows()
+		return m, tea.Batch(cmd, setCDNDomain(m.client, d.cdn, strings.TrimSpace(d.domain.Value()), d.certificates.Value()))
+	}
+
+	switch msg.String() {
+	case "esc":
+		l.domain = nil
+		m.formErr = ""
+		return m, nil
+	case "enter":
+		domain := strings.TrimSpace(d.domain.Value())
+		if domain == "" {
+			if d.cdn.CustomDomain == "" {
+				m.formErr = "type the domain to serve the endpoint from"
+				return m, nil
+			}
+			// Emptied, so take the custom domain off.
+			l.domain = nil
+			m.formErr = ""
+			l.busy[d.cdn.ID] = "updating"
+			l.setRows()
+			return m, setCDNDomain(m.client, d.cdn, "", "")
+		}
+		if problem := checkDomainName(domain); problem != "" {
+			m.formErr = problem
+			return m, nil
+		}
+		m.formErr = ""
+		d.domain.Blur()
+		d.choosing = true
+		return m, fetchCertificates(m.client)
+	}
+	_, cmd := d.domain.Update(msg)
+	return m, cmd
+}
+
+// setCDNDomain serves the endpoint from the domain with the certificate, or
+// only from its own hostname if domain is empty.
+func setCDNDomain(client *godo.Client, c godo.CDN, domain, certificateID string) tea.Cmd {
+	return func() tea.Msg {
+		_, _, err := client.CDNs.UpdateCustomDomain(context.Background(), c.ID, &godo.CDNUpdateCustomDomainRequest{
+			CustomDomain:  domain,
+			CertificateID: certificateID,
+		})
+		if domain == "" {
+			return cdnDoneMsg{id: c.ID, origin: c.Origin, verb: "remove the custom domain of", done: "Removed the custom domain of", err: err}
+		}
+		return cdnDoneMsg{id: c.ID, origin: c.Origin, verb: "set the custom domain of", done: "Set the custom domain of", err: err}
+	}
+}
+
+func (m model) cdnDomainView() string {
+	d := m.cdns.domain
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s\n", focusedStyle.Render("Custom domain for "+d.cdn.Origin))
+	fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("Point a CNAME record for it at "+d.cdn.Endpoint+"; leave it empty to take it off."))
+	fmt.Fprintf(&b, "%s\n\n", d.domain.View())
+	switch {
+	case d.certificatesErr != nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't offer a certificate: "+d.certificatesErr.Error()))
+		b.WriteString(helpStyle.Render("esc: back"))
+	case d.choosing:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading certificates..."))
+		b.WriteString(helpStyle.Render("esc: back"))
+	default:
+		b.WriteString(helpStyle.Render("enter: next • esc: cancel"))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,335 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+var (
+	createCDNKey    = key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "create"))
+	flushCDNKey     = key.NewBinding(key.WithKeys("f"), key.WithHelp("f", "flush"))
+	customCDNKey    = key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "custom domain"))
+	copyEndpointKey = key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "copy hostname"))
+)
+
+// cdnsPerPage is how many endpoints the CDN screen fetches at a time.
+const cdnsPerPage = 20
+
+// cdnTTLs are the cache lifetimes the API allows for an endpoint, in
+// seconds.
+var cdnTTLs = []uint32{60, 600, 3600, 86400, 604800}
+
+// defaultCDNTTL is offered first, and is the control panel's default.
+const defaultCDNTTL = 3600
+
+// cdnTTL is a cache lifetime to choose for an endpoint.
+type cdnTTL uint32
+
+func (t cdnTTL) Title() string {
+	switch {
+	case t >= 86400:
+		return pluralize(int(t/86400), "day")
+	case t >= 3600:
+		return pluralize(int(t/3600), "hour")
+	}
+	return pluralize(int(t/60), "minute")
+}
+func (t cdnTTL) Description() string { return strconv.Itoa(int(t)) + " seconds" }
+func (t cdnTTL) FilterValue() string { return t.Title() }
+func (t cdnTTL) Value() string       { return strconv.Itoa(int(t)) }
+
+// cdnListMsg carries a page of the account's CDN endpoints, along with how
+// many there are in all.
+type cdnListMsg struct {
+	page  int
+	cdns  []godo.CDN
+	total int
+	err   error
+}
+
+// fetchCDNList lists a page of the account's CDN endpoints, counting from 1.
+func fetchCDNList(client *godo.Client, page int) tea.Cmd {
+	return func() tea.Msg {
+		cdns, resp, err := client.CDNs.List(context.Background(), &godo.ListOptions{Page: page, PerPage: cdnsPerPage})
+		if err != nil {
+			return cdnListMsg{page: page, err: err}
+		}
+
+		total := len(cdns)
+		if resp.Meta != nil {
+			total = resp.Meta.Total
+		}
+		return cdnListMsg{page: page, cdns: cdns, total: total}
+	}
+}
+
+// cdnDoneMsg reports that a change to an endpoint has finished. verb and
+// done describe it, e.g. "flush" and "Flushed".
+type cdnDoneMsg struct {
+	id     string
+	origin string
+	verb   string
+	done   string
+	err    error
+}
+
+// cdnList is the screen listing the account's CDN endpoints, which serve
+// Spaces buckets from the edge, a page at a time.
+type cdnList struct {
+	table   table.Model
+	cdns    []godo.CDN
+	page    int
+	total   int
+	loading bool
+	err     error
+	// create is set while creating an endpoint, flush while choosing what
+	// to flush from one, and domain while setting one's custom domain.
+	create *cdnCreateFlow
+	flush  *cdnFlushPrompt
+	domain *cdnDomainFlow
+	// creating holds the origins of the endpoints being created, and busy
+	// the status to show for the endpoints being changed, by ID.
+	creating []string
+	busy     map[string]string
+}
+
+func newCDNList(width, height int) *cdnList {
+	l := &cdnList{
+		page: 1,
+		busy: make(map[string]string),
+		table: newListTable([]table.Column{
+			{Title: "Origin", Width: 40},
+			{Title: "Custom domain", Width: 28},
+			{Title: "TTL", Width: 10},
+			{Title: "Status", Width: 12},
+		}),
+	}
+	l.SetSize(width, height)
+	return l
+}
+
+// SetSize fits the table to the screen, under the title and above the help.
+func (l *cdnList) SetSize(width, height int) {
+	setTableSize(&l.table, width, height)
+}
+
+// pages returns how many pages of endpoints there are.
+func (l *cdnList) pages() int {
+	return pageCount(l.total, cdnsPerPage)
+}
+
+// setCDNList shows a page of endpoints once it's been fetched.
+func (m model) setCDNList(msg cdnListMsg) (tea.Model, tea.Cmd) {
+	l := m.cdns
+	if l == nil {
+		return m, nil
+	}
+	// The last page was emptied, e.g. by deleting elsewhere, so go back one.
+	if msg.err == nil && len(msg.cdns) == 0 && msg.page > 1 {
+		return m, fetchCDNList(m.client, msg.page-1)
+	}
+
+	l.loading = false
+	l.err = msg.err
+	if msg.err != nil {
+		return m, nil
+	}
+	l.page, l.cdns, l.total = msg.page, msg.cdns, msg.total
+	l.setRows()
+	return m, nil
+}
+
+// setRows fills the table in from the endpoints.
+func (l *cdnList) setRows() {
+	rows := make([]table.Row, len(l.cdns))
+	for i, c := range l.cdns {
+		status := ""
+		if s, ok := l.busy[c.ID]; ok {
+			status = s + "…"
+		}
+		rows[i] = table.Row{c.Origin, c.CustomDomain, cdnTTL(c.TTL).Title(), status}
+	}
+	l.table.SetRows(rows)
+	if l.table.Cursor() >= len(rows) {
+		l.table.GotoTop()
+	}
+}
+
+// selected returns the endpoint under the cursor, unless there are none or
+// it's busy.
+func (l *cdnList) selected() (godo.CDN, bool) {
+	i := l.table.Cursor()
+	if i < 0 || i >= len(l.cdns) {
+		return godo.CDN{}, false
+	}
+	_, busy := l.busy[l.cdns[i].ID]
+	return l.cdns[i], !busy
+}
+
+// openCDNs shows the list of CDN endpoints, fetching its first page.
+func (m model) openCDNs() (model, tea.Cmd) {
+	m.cdns = newCDNList(m.width, m.height-1)
+	m.cdns.loading = true
+	m.notice = ""
+	m.formErr = ""
+	return m, tea.Batch(fetchCDNList(m.client, 1), m.spinner.Tick)
+}
+
+func (m model) updateCDNs(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.cdns
+	switch {
+	case l.create != nil:
+		return m.updateCDNCreate(msg)
+	case l.flush != nil:
+		return m.updateCDNFlush(msg)
+	case l.domain != nil:
+		return m.updateCDNDomain(msg)
+	}
+
+	switch {
+	case msg.String() == "ctrl+c":
+		return m, m.quit()
+	case key.Matches(msg, closeListKeys):
+		return m.closeScreen()
+	case key.Matches(msg, screensKey):
+		return m.openScreens()
+	case key.Matches(msg, createCDNKey):
+		return m.openCDNCreate()
+	case l.loading:
+		return m, nil
+	case key.Matches(msg, flushCDNKey):
+		return m.promptCDNFlush()
+	case key.Matches(msg, customCDNKey):
+		return m.openCDNDomain()
+	case key.Matches(msg, copyEndpointKey):
+		if c, ok := l.selected(); ok {
+			host := c.Endpoint
+			if c.CustomDomain != "" {
+				host = c.CustomDomain
+			}
+			return m, copyToClipboard(host)
+		}
+		return m, nil
+	case key.Matches(msg, refreshListKey):
+		l.loading = true
+		return m, fetchCDNList(m.client, l.page)
+	case key.Matches(msg, nextPageKey) && l.page < l.pages():
+		l.loading = true
+		return m, fetchCDNList(m.client, l.page+1)
+	case key.Matches(msg, prevPageKey) && l.page > 1:
+		l.loading = true
+		return m, fetchCDNList(m.client, l.page-1)
+	}
+
+	var cmd tea.Cmd
+	l.table, cmd = l.table.Update(msg)
+	return m, cmd
+}
+
+// cdnDone refreshes the list once a change to an endpoint has finished.
+func (m model) cdnDone(msg cdnDoneMsg) (tea.Model, tea.Cmd) {
+	l := m.cdns
+	if l != nil {
+		delete(l.busy, msg.id)
+		l.setRows()
+	}
+	if msg.err != nil {
+		m.formErr = fmt.Sprintf("couldn't %s %s: %s", msg.verb, msg.origin, msg.err)
+		return m, nil
+	}
+
+	cmd := m.toast(fmt.Sprintf("%s %s", msg.done, msg.origin))
+	if l == nil {
+		return m, cmd
+	}
+	l.loading = true
+	return m, tea.Batch(cmd, fetchCDNList(m.client, l.page))
+}
+
+// cdnPickerView returns the list being chosen from, if the screen's
+// choosing from one.
+func (m model) cdnPickerView() (string, bool) {
+	l := m.cdns
+	if f := l.create; f != nil {
+		if p := f.picker(); p != nil && p.Opened() {
+			return p.View(), true
+		}
+	}
+	if d := l.domain; d != nil && d.certificates.Opened() {
+		return d.certificates.View(), true
+	}
+	return "", false
+}
+
+func (m model) cdnsView() string {
+	l := m.cdns
+	if v, ok := m.cdnPickerView(); ok {
+		return v
+	}
+
+	var b strings.Builder
+
+	title := focusedStyle.Render("CDN endpoints")
+	if l.total > 0 {
+		title += placeholderStyle.Render(fmt.Sprintf("  %s · page %d of %d", pluralize(l.total, "endpoint"), l.page, l.pages()))
+	}
+	b.WriteString(title + "\n\n")
+
+	switch {
+	case l.loading && l.cdns == nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading CDN endpoints..."))
+	case l.err != nil && l.cdns == nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the CDN endpoints: "+l.err.Error()))
+	case len(l.cdns) == 0:
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("There are no CDN endpoints in this account yet."))
+	default:
+		fmt.Fprintf(&b, "%s\n\n", l.table.View())
+	}
+
+	switch {
+	case l.loading && l.cdns != nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading..."))
+	case l.err != nil && l.cdns != nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the CDN endpoints: "+l.err.Error()))
+	}
+	if len(l.creating) > 0 {
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Creating "+strings.Join(l.creating, ", ")+"..."))
+	}
+	if m.notice != "" {
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render(m.notice))
+	}
+	if m.formErr != "" {
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(m.formErr))
+	}
+
+	switch {
+	case l.create != nil:
+		b.WriteString(m.cdnCreateView())
+		return b.String()
+	case l.flush != nil:
+		b.WriteString(m.cdnFlushView())
+		return b.String()
+	case l.domain != nil:
+		b.WriteString(m.cdnDomainView())
+		return b.String()
+	}
+
+	if c, ok := l.selected(); ok {
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("Served from "+c.Endpoint))
+	}
+	help := []string{"↑/↓: move", "c: create", "f: flush", "u: custom domain", "y: copy hostname"}
+	if l.pages() > 1 {
+		help = append(help, "←/→: page")
+	}
+	help = append(help, "r: refresh", "tab: screens", m.closeScreenHelp())
+	b.WriteString(helpStyle.Render(strings.Join(help, " • ")))
+
+	return b.String()
+}
@@ -0,0 +1,390 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+// certificatePollInterval is how often a certificate requested from Let's
+// Encrypt is checked on until it's issued.
+const certificatePollInterval = 10 * time.Second
+
+// certificateUpload asks for the PEM files of a certificate, its private key
+// and the chain of certificates that signed it, and then what to call it.
+// Each file is read as it's given, so mistakes show up straight away.
+type certificateUpload struct {
+	// step is the index in fields of the field being typed.
+	step   int
+	fields []*textField
+	// The files read so far.
+	leaf, key, chain string
+	// dnsNames are the names the certificate's for, for the review.
+	dnsNames []string
+}
+
+// The steps of certificateUpload, in the order they're taken.
+const (
+	certUploadLeaf = iota
+	certUploadKey
+	certUploadChain
+	certUploadName
+)
+
+// openCertificateUpload starts uploading a certificate.
+func (m model) openCertificateUpload() (model, tea.Cmd) {
+	if m.blockChanges("certificates", "uploaded") {
+		return m, nil
+	}
+
+	u := &certificateUpload{fields: []*textField{
+		newOptionalTextField("Certificate: ", "PEM file, e.g. ~/certs/example.com.crt"),
+		newOptionalTextField("Private key: ", "PEM file, e.g. ~/certs/example.com.key"),
+		newOptionalTextField("Chain (optional): ", "PEM file of the intermediate certificates"),
+		newOptionalTextField("Name: ", "what to call it on the account"),
+	}}
+	for _, f := range u.fields {
+		f.CharLimit = 4096
+	}
+	u.fields[certUploadName].CharLimit = 255
+	m.certificates.upload = u
+	m.notice = ""
+	m.formErr = ""
+	return m, u.fields[certUploadLeaf].Focus()
+}
+
+func (m model) updateCertificateUpload(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.certificates
+	u := l.upload
+	f := u.fields[u.step]
+	switch msg.String() {
+	case "ctrl+c":
+		return m, m.quit()
+	case "esc":
+		m.formErr = ""
+		if u.step == certUploadLeaf {
+			l.upload = nil
+			return m, nil
+		}
+		f.Blur()
+		u.step--
+		return m, u.fields[u.step].Focus()
+	case "enter":
+		if err := u.answer(); err != nil {
+			m.formErr = err.Error()
+			return m, nil
+		}
+		m.formErr = ""
+		if u.step == certUploadName {
+			req := &godo.CertificateRequest{
+				Name:             strings.TrimSpace(f.Value()),
+				Type:             "custom",
+				LeafCertificate:  u.leaf,
+				PrivateKey:       u.key,
+				CertificateChain: u.chain,
+			}
+			l.upload = nil
+			l.creating = append(l.creating, req.Name)
+			return m, createCertificate(m.client, req)
+		}
+		f.Blur()
+		u.step++
+		return m, u.fields[u.step].Focus()
+	}
+
+	_, cmd := f.Update(msg)
+	return m, cmd
+}
+
+// answer reads and checks the file given at the current step, or the name.
+func (u *certificateUpload) answer() error {
+	value := strings.TrimSpace(u.fields[u.step].Value())
+	if u.step == certUploadName {
+		if value == "" {
+			return errors.New("the certificate needs a name")
+		}
+		return nil
+	}
+	if value == "" && u.step == certUploadChain {
+		u.chain = ""
+		return nil
+	}
+	if value == "" {
+		return errors.New("type the path of the PEM file")
+	}
+
+	path, err := expandHome(value)
+	if err != nil {
+		return err
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	contents := strings.TrimSpace(string(b))
+
+	switch u.step {
+	case certUploadLeaf:
+		block, _ := pem.Decode(b)
+		if block == nil || block.Type != "CERTIFICATE" {
+			return fmt.Errorf("%s isn't a PEM certificate", value)
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return fmt.Errorf("%s isn't a certificate: %s", value, err)
+		}
+		u.leaf = contents
+	case certUploadKey:
+		pair, err := tls.X509KeyPair([]byte(u.leaf), b)
+		if err != nil {
+			return fmt.Errorf("%s isn't the certificate's private key: %s", value, err)
+		}
+		leaf, err := x509.ParseCertificate(pair.Certificate[0])
+		if err != nil {
+			return err
+		}
+		if time.Now().After(leaf.NotAfter) {
+			return fmt.Errorf("the certificate expired on %s", leaf.NotAfter.Format("2006-01-02"))
+		}
+		u.key = contents
+		u.dnsNames = leaf.DNSNames
+		if name := u.fields[certUploadName]; name.Value() == "" {
+			switch {
+			case len(leaf.DNSNames) > 0:
+				name.SetValue(strings.ReplaceAll(leaf.DNSNames[0], "*", "wildcard"))
+			case leaf.Subject.CommonName != "":
+				name.SetValue(leaf.Subject.CommonName)
+			}
+		}
+	case certUploadChain:
+		if block, _ := pem.Decode(b); block == nil || block.Type != "CERTIFICATE" {
+			return fmt.Errorf("%s isn't a PEM certificate chain", value)
+		}
+		u.chain = contents
+	}
+	return nil
+}
+
+func (m model) certificateUploadView() string {
+	u := m.certificates.upload
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s\n\n", focusedStyle.Render("Upload a certificate"))
+	for i := 0; i <= u.step; i++ {
+		fmt.Fprintf(&b, "%s\n", u.fields[i].View())
+	}
+	if len(u.dnsNames) > 0 && u.step > certUploadKey {
+		fmt.Fprintf(&b, "%s\n", placeholderStyle.Render("  For "+strings.Join(u.dnsNames, ", ")))
+	}
+	b.WriteString("\n")
+
+	back := "esc: back"
+	if u.step == certUploadLeaf {
+		back = "esc: cancel"
+	}
+	next := "enter: next"
+	if u.step == certUploadName {
+		next = "enter: upload"
+	}
+	b.WriteString(helpStyle.Render(next + " • " + back))
+
+	return b.String()
+}
+
+// certificateRequest asks for the domains to request a certificate for from
+// Let's Encrypt, and then what to call it.
+type certificateRequest struct {
+	domains *textField
+	// name is set once the domains have been given.
+	name     *textField
+	dnsNames []string
+}
+
+// openCertificateRequest starts requesting a certificate from Let's Encrypt.
+func (m model) openCertificateRequest() (model, tea.Cmd) {
+	if m.blockChanges("certificates", "requested") {
+		return m, nil
+	}
+
+	domains := newOptionalTextField("Domains: ", "e.g. example.com, www.example.com or *.example.com")
+	domains.CharLimit = 4096
+	m.certificates.request = &certificateRequest{domains: domains}
+	m.notice = ""
+	m.formErr = ""
+	return m, domains.Focus()
+}
+
+func (m model) updateCertificateRequest(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.certificates
+	r := l.request
+	if msg.String() == "ctrl+c" {
+		return m, m.quit()
+	}
+
+	if r.name != nil {
+		switch msg.String() {
+		case "esc":
+			// Back to the domains.
+			r.name = nil
+			m.formErr = ""
+			return m, r.domains.Focus()
+		case "enter":
+			name := strings.TrimSpace(r.name.Value())
+			if name == "" {
+				m.formErr = "the certificate needs a name"
+				return m, nil
+			}
+			req := &godo.CertificateRequest{Name: name, Type: "lets_encrypt", DNSNames: r.dnsNames}
+			l.request = nil
+			m.formErr = ""
+			l.creating = append(l.creating, name)
+			return m, createCertificate(m.client, req)
+		}
+		_, cmd := r.name.Update(msg)
+		return m, cmd
+	}
+
+	switch msg.String() {
+	case "esc":
+		l.request = nil
+		m.formErr = ""
+		return m, nil
+	case "enter":
+		names := splitList(r.domains.Value())
+		if len(names) == 0 {
+			m.formErr = "type the domains the certificate is for"
+			return m, nil
+		}
+		for _, name := range names {
+			if problem := checkDomainName(strings.TrimPrefix(name, "*.")); problem != "" {
+				m.formErr = problem
+				return m, nil
+			}
+		}
+		m.formErr = ""
+		r.dnsNames = names
+		r.domains.Blur()
+		r.name = newOptionalTextField("Name: ", "what to call it on the account")
+		r.name.CharLimit = 255
+		r.name.SetValue(strings.ReplaceAll(names[0], "*", "wildcard"))
+		return m, r.name.Focus()
+	}
+	_, cmd := r.domains.Update(msg)
+	return m, cmd
+}
+
+func (m model) certificateRequestView() string {
+	r := m.certificates.request
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s\n", focusedStyle.Render("Request a certificate from Let's Encrypt"))
+	fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("Its domains need their DNS managed here, so they can be verified; Let's Encrypt renews it."))
+	fmt.Fprintf(&b, "%s\n", r.domains.View())
+	if r.name != nil {
+		fmt.Fprintf(&b, "%s\n\n", r.name.View())
+		b.WriteString(helpStyle.Render("enter: request • esc: back"))
+	} else {
+		b.WriteString("\n" + helpStyle.Render("enter: next • esc: cancel"))
+	}
+
+	return b.String()
+}
+
+// certificateCreatedMsg reports that a certificate has been uploaded or
+// requested, or couldn't be.
+type certificateCreatedMsg struct {
+	name        string
+	certificate *godo.Certificate
+	err         error
+}
+
+func createCertificate(client *godo.Client, req *godo.CertificateRequest) tea.Cmd {
+	return func() tea.Msg {
+		c, _, err := client.Certificates.Create(context.Background(), req)
+		return certificateCreatedMsg{name: req.Name, certificate: c, err: err}
+	}
+}
+
+// certificateCreated refreshes the list once a certificate's been added,
+// following it until it's issued if it's from Let's Encrypt.
+func (m model) certificateCreated(msg certificateCreatedMsg) (tea.Model, tea.Cmd) {
+	l := m.certificates
+	if l != nil {
+		for i, name := range l.creating {
+			if name == msg.name {
+				l.creating = append(l.creating[:i:i], l.creating[i+1:]...)
+				break
+			}
+		}
+	}
+	if msg.err != nil {
+		m.formErr = fmt.Sprintf("couldn't add %s: %s", msg.name, msg.err)
+		return m, nil
+	}
+
+	var cmds []tea.Cmd
+	if c := msg.certificate; c.State == "pending" {
+		cmds = append(cmds, m.toast(fmt.Sprintf("Requested %s; it's issued once Let's Encrypt has verified its domains", msg.name)))
+		cmds = append(cmds, waitForCertificate(m.client, c.ID, msg.name))
+	} else {
+		cmds = append(cmds, m.toast("Uploaded "+msg.name))
+	}
+	if l != nil {
+		l.loading = true
+		cmds = append(cmds, fetchCertificateList(m.client, l.page))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// certificateIssuedMsg reports that a certificate requested from Let's
+// Encrypt has been issued, or couldn't be.
+type certificateIssuedMsg struct {
+	name string
+	err  error
+}
+
+// waitForCertificate checks on a certificate requested from Let's Encrypt
+// until it's no longer pending.
+func waitForCertificate(client *godo.Client, id, name string) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		for {
+			time.Sleep(certificatePollInterval)
+			c, _, err := client.Certificates.Get(ctx, id)
+			if err != nil {
+				return certificateIssuedMsg{name: name, err: err}
+			}
+			switch c.State {
+			case "verified":
+				return certificateIssuedMsg{name: name}
+			case "error":
+				return certificateIssuedMsg{name: name, err: errors.New("Let's Encrypt couldn't verify its domains; check their DNS is managed here")}
+			}
+		}
+	}
+}
+
+// certificateIssued refreshes the list once Let's Encrypt has issued a
+// certificate.
+func (m model) certificateIssued(msg certificateIssuedMsg) (tea.Model, tea.Cmd) {
+	l := m.certificates
+	if msg.err != nil {
+		m.formErr = fmt.Sprintf("couldn't issue %s: %s", msg.name, msg.err)
+		return m, nil
+	}
+
+	cmd := m.toast("Let's Encrypt issued " + msg.name)
+	if l == nil {
+		return m, cmd
+	}
+	l.loading = true
+	return m, tea.Batch(cmd, fetchCertificateList(m.client, l.page))
+}
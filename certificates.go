@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+type certificateItem struct {
+	godo.Certificate
+}
+
+func (c certificateItem) Title() string { return c.Name }
+func (c certificateItem) Description() string {
+	kind := "custom"
+	if c.Type == "lets_encrypt" {
+		kind = "Let's Encrypt"
+	}
+	return kind + " · " + strings.Join(c.DNSNames, ", ")
+}
+func (c certificateItem) FilterValue() string {
+	return c.Name + " " + strings.Join(c.DNSNames, " ")
+}
+func (c certificateItem) Value() string { return c.ID }
+
+// certificatesMsg carries the account's certificates.
+type certificatesMsg struct {
+	certificates []godo.Certificate
+	err          error
+}
+
+// fetchCertificates lists the account's certificates, for choosing one.
+func fetchCertificates(client *godo.Client) tea.Cmd {
+	return func() tea.Msg {
+		certs, _, err := client.Certificates.List(context.Background(), &godo.ListOptions{PerPage: 200})
+		return certificatesMsg{certificates: certs, err: err}
+	}
+}
+
+// setCertificates offers the certificates to whichever of the load balancer
+// wizard and the CDN screen is choosing one.
+func (m model) setCertificates(msg certificatesMsg) (tea.Model, tea.Cmd) {
+	if l := m.loadBalancers; l != nil && l.create != nil && l.create.certificates != nil {
+		return m.setLBCertificates(msg)
+	}
+	return m.setCDNCertificates(msg)
+}
+
+// certificateCovers reports whether the certificate is valid for the host,
+// either by name or by a wildcard one level up.
+func certificateCovers(c godo.Certificate, host string) bool {
+	for _, name := range c.DNSNames {
+		if strings.EqualFold(name, host) {
+			return true
+		}
+		if strings.HasPrefix(name, "*.") {
+			if i := strings.Index(host, "."); i > 0 && strings.EqualFold(name[2:], host[i+1:]) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+var (
+	uploadCertificateKey  = key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "upload"))
+	requestCertificateKey = key.NewBinding(key.WithKeys("l"), key.WithHelp("l", "Let's Encrypt"))
+)
+
+// certificatesPerPage is how many certificates the certificates screen
+// fetches at a time.
+const certificatesPerPage = 20
+
+// certificateListMsg carries a page of the account's certificates, along
+// with how many there are in all.
+type certificateListMsg struct {
+	page         int
+	certificates []godo.Certificate
+	total        int
+	err          error
+}
+
+// fetchCertificateList lists a page of the account's certificates, counting
+// from 1.
+func fetchCertificateList(client *godo.Client, page int) tea.Cmd {
+	return func() tea.Msg {
+		certs, resp, err := client.Certificates.List(context.Background(), &godo.ListOptions{Page: page, PerPage: certificatesPerPage})
+		if err != nil {
+			return certificateListMsg{page: page, err: err}
+		}
+
+		total := len(certs)
+		if resp.Meta != nil {
+			total = resp.Meta.Total
+		}
+		return certificateListMsg{page: page, certificates: certs, total: total}
+	}
+}
+
+// certificateList is the screen listing the certificates load balancers
+// and CDN endpoints can serve, a page at a time.
+type certificateList struct {
+	table        table.Model
+	certificates []godo.Certificate
+	page         int
+	total        int
+	loading      bool
+	err          error
+	// upload is set while uploading a certificate, and request while
+	// requesting one from Let's Encrypt.
+	upload  *certificateUpload
+	request *certificateRequest
+	// creating holds the names of the certificates being uploaded or
+	// requested.
+	creating []string
+}
+
+func newCertificateList(width, height int) *certificateList {
+	l := &certificateList{
+		page: 1,
+		table: newListTable([]table.Column{
+			{Title: "Name", Width: 24},
+			{Title: "Type", Width: 14},
+			{Title: "Domains", Width: 36},
+			{Title: "Expires", Width: 12},
+			{Title: "State", Width: 10},
+		}),
+	}
+	l.SetSize(width, height)
+	return l
+}
+
+// SetSize fits the table to the screen, under the title and above the help.
+func (l *certificateList) SetSize(width, height int) {
+	setTableSize(&l.table, width, height)
+}
+
+// pages returns how many pages of certificates there are.
+func (l *certificateList) pages() int {
+	return pageCount(l.total, certificatesPerPage)
+}
+
+// setCertificateList shows a page of certificates once it's been fetched.
+func (m model) setCertificateList(msg certificateListMsg) (tea.Model, tea.Cmd) {
+	l := m.certificates
+	if l == nil {
+		return m, nil
+	}
+	// The last page was emptied, e.g. by deleting elsewhere, so go back one.
+	if msg.err == nil && len(msg.certificates) == 0 && msg.page > 1 {
+		return m, fetchCertificateList(m.client, msg.page-1)
+	}
+
+	l.loading = false
+	l.err = msg.err
+	if msg.err != nil {
+		return m, nil
+	}
+	l.page, l.certificates, l.total = msg.page, msg.certificates, msg.total
+	l.setRows()
+	return m, nil
+}
+
+// setRows fills the table in from the certificates.
+func (l *certificateList) setRows() {
+	rows := make([]table.Row, len(l.certificates))
+	for i, c := range l.certificates {
+		kind := "custom"
+		if c.Type == "lets_encrypt" {
+			kind = "Let's Encrypt"
+		}
+		expires := c.NotAfter
+		if len(expires) > 10 {
+			expires = expires[:10]
+		}
+		rows[i] = table.Row{c.Name, kind, strings.Join(c.DNSNames, ", "), expires, c.State}
+	}
+	l.table.SetRows(rows)
+	if l.table.Cursor() >= len(rows) {
+		l.table.GotoTop()
+	}
+}
+
+// openCertificates shows the list of certificates, fetching its first page.
+func (m model) openCertificates() (model, tea.Cmd) {
+	m.certificates = newCertificateList(m.width, m.height-1)
+	m.certificates.loading = true
+	m.notice = ""
+	m.formErr = ""
+	return m, tea.Batch(fetchCertificateList(m.client, 1), m.spinner.Tick)
+}
+
+func (m model) updateCertificates(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.certificates
+	if l.upload != nil {
+		return m.updateCertificateUpload(msg)
+	}
+	if l.request != nil {
+		return m.updateCertificateRequest(msg)
+	}
+
+	switch {
+	case msg.String() == "ctrl+c":
+		return m, m.quit()
+	case key.Matches(msg, closeListKeys):
+		return m.closeScreen()
+	case key.Matches(msg, screensKey):
+		return m.openScreens()
+	case key.Matches(msg, uploadCertificateKey):
+		return m.openCertificateUpload()
+	case key.Matches(msg, requestCertificateKey):
+		return m.openCertificateRequest()
+	case l.loading:
+		return m, nil
+	case key.Matches(msg, refreshListKey):
+		l.loading = true
+		return m, fetchCertificateList(m.client, l.page)
+	case key.Matches(msg, nextPageKey) && l.page < l.pages():
+		l.loading = true
+		return m, fetchCertificateList(m.client, l.page+1)
+	case key.Matches(msg, prevPageKey) && l.page > 1:
+		l.loading = true
+		return m, fetchCertificateList(m.client, l.page-1)
+	}
+
+	var cmd tea.Cmd
+	l.table, cmd = l.table.Update(msg)
+	return m, cmd
+}
+
+func (m model) certificatesView() string {
+	l := m.certificates
+	var b strings.Builder
+
+	title := focusedStyle.Render("Certificates")
+	if l.total > 0 {
+		title += placeholderStyle.Render(fmt.Sprintf("  %s · page %d of %d", pluralize(l.total, "certificate"), l.page, l.pages()))
+	}
+	b.WriteString(title + "\n\n")
+
+	switch {
+	case l.loading && l.certificates == nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading certificates..."))
+	case l.err != nil && l.certificates == nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the certificates: "+l.err.Error()))
+	case len(l.certificates) == 0:
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("There are no certificates in this account yet."))
+	default:
+		fmt.Fprintf(&b, "%s\n\n", l.table.View())
+	}
+
+	switch {
+	case l.loading && l.certificates != nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading..."))
+	case l.err != nil && l.certificates != nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the certificates: "+l.err.Error()))
+	}
+	if len(l.creating) > 0 {
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Adding "+strings.Join(l.creating, ", ")+"..."))
+	}
+	if m.notice != "" {
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render(m.notice))
+	}
+	if m.formErr != "" {
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(m.formErr))
+	}
+
+	switch {
+	case l.upload != nil:
+		b.WriteString(m.certificateUploadView())
+		return b.String()
+	case l.request != nil:
+		b.WriteString(m.certificateRequestView())
+		return b.String()
+	}
+
+	help := []string{"↑/↓: move", "c: upload", "l: Let's Encrypt"}
+	if l.pages() > 1 {
+		help = append(help, "←/→: page")
+	}
+	help = append(help, "r: refresh", "tab: screens", m.closeScreenHelp())
+	b.WriteString(helpStyle.Render(strings.Join(help, " • ")))
+
+	return b.String()
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// fieldFlags set the form's fields from the command line, named like the
+// spec's fields. Those given override the spec read with -f.
+type fieldFlags struct {
+	values   map[string]*string
+	toggles  map[string]*bool
+	userData *string
+}
+
+// fieldFlagNames maps the flags to the fields they set, and says what they
+// are.
+var fieldFlagNames = []struct {
+	name  string
+	field int
+	usage string
+}{
+	{"name", nameField, "the Droplet's name, e.g. web-%02d with --count"},
+	{"count", countField, "how many Droplets to create"},
+	{"region", regionField, "the region slug, e.g. nyc3"},
+	{"size", sizeField, "the size slug, e.g. s-1vcpu-1gb"},
+	{"image", imageField, "the image slug or ID"},
+	{"ssh-keys", keysField, "comma-separated SSH keys, by name, fingerprint or ID"},
+	{"tags", tagsField, "comma-separated tags"},
+	{"vpc", vpcField, "the VPC's ID, instead of the region's default"},
+	{"project", projectField, "the project's ID, instead of the default"},
+	{"domain", domainField, "create an A record for each Droplet in this domain"},
+	{"reserved-ip", reservedIPField, "assign this reserved IP, or new to reserve one"},
+	{"firewalls", firewallsField, "comma-separated cloud firewalls to add the Droplets to, by name or ID"},
+	{"load-balancer", loadBalancerField, "add the Droplets to this load balancer, by ID"},
+	{"volume-name", volumeNameField, "create and attach a volume with this name"},
+	{"volume-size", volumeSizeField, "the volume's size in GB"},
+}
+
+// fieldToggleNames maps the flags for the toggles to their fields.
+var fieldToggleNames = []struct {
+	name  string
+	field int
+	usage string
+}{
+	{"backups", backupsField, "enable backups"},
+	{"monitoring", monitoringField, "install the metrics agent"},
+	{"ipv6", ipv6Field, "enable IPv6"},
+}
+
+func newFieldFlags() *fieldFlags {
+	f := &fieldFlags{values: make(map[string]*string), toggles: make(map[string]*bool)}
+	for _, n := range fieldFlagNames {
+		f.values[n.name] = flag.String(n.name, "", n.usage)
+	}
+	for _, n := range fieldToggleNames {
+		f.toggles[n.name] = flag.Bool(n.name, false, n.usage)
+	}
+	f.userData = flag.String("user-data", "", "read the cloud-init user data from this file")
+	return f
+}
+
+// apply fills in the fields whose flags were given on the command line.
+func (f *fieldFlags) apply(m *model) error {
+	var err error
+	flag.Visit(func(fl *flag.Flag) {
+		for _, n := range fieldFlagNames {
+			if n.name != fl.Name {
+				continue
+			}
+			v := *f.values[n.name]
+			switch field := m.fields[n.field].(type) {
+			case *textField:
+				field.SetValue(v)
+			case *multiSelectField:
+				field.CheckMatching(splitList(v))
+			case interface{ Select(string) }:
+				field.Select(v)
+			}
+		}
+		for _, n := range fieldToggleNames {
+			if n.name == fl.Name {
+				m.fields[n.field].(*toggleField).checked = *f.toggles[n.name]
+			}
+		}
+		if fl.Name == "user-data" && err == nil {
+			msg := loadUserData(*f.userData)().(userDataMsg)
+			if msg.err != nil {
+				err = fmt.Errorf("reading the user data: %w", msg.err)
+				return
+			}
+			m.fields[userDataField].(*userDataEditor).editor.SetValue(msg.data)
+		}
+	})
+	return err
+}
+
+// splitList splits a comma-separated flag value, allowing spaces within the
+// items, e.g. in SSH key names.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"errors"
+	"os"
+
+	"github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+var copyIPKey = key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "copy IP"))
+
+// copiedMsg reports that text was copied to the clipboard.
+type copiedMsg struct {
+	text string
+	err  error
+}
+
+// copyToClipboard copies text to the system clipboard. Where there's no
+// clipboard to talk to, such as over SSH, the terminal is asked to with an
+// OSC 52 escape sequence instead.
+func copyToClipboard(text string) tea.Cmd {
+	return func() tea.Msg {
+		if err := clipboard.WriteAll(text); err == nil {
+			return copiedMsg{text: text}
+		}
+
+		seq := osc52.New(text)
+		if os.Getenv("TMUX") != "" {
+			seq = seq.Tmux()
+		}
+		if _, err := seq.WriteTo(os.Stderr); err != nil {
+			return copiedMsg{err: err}
+		}
+		return copiedMsg{text: text}
+	}
+}
+
+// copyIP copies the Droplet's public IPv4 address to the clipboard.
+func copyIP(droplet *godo.Droplet) tea.Cmd {
+	ip, _ := droplet.PublicIPv4()
+	if ip == "" {
+		return func() tea.Msg {
+			return copiedMsg{err: errors.New("the Droplet has no public IPv4 address")}
+		}
+	}
+	return copyToClipboard(ip)
+}
+
+// copySecret copies a password or the like to the clipboard, reporting it
+// as what was copied rather than echoing it.
+func copySecret(text, what string) tea.Cmd {
+	cmd := copyToClipboard(text)
+	return func() tea.Msg {
+		msg := cmd().(copiedMsg)
+		msg.text = what
+		return msg
+	}
+}
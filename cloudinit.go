@@ -0,0 +1,166 @@
+package main
+
+import (
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var libraryKey = key.NewBinding(key.WithKeys("ctrl+l"), key.WithHelp("ctrl+l", "template library"))
+
+// cloudInitTemplate is one of the cloud-config files in the library, with
+// placeholders filled in from the form when it's chosen, see fillCloudInit.
+type cloudInitTemplate struct {
+	name, description, body string
+}
+
+func (t cloudInitTemplate) Title() string       { return t.name }
+func (t cloudInitTemplate) Description() string { return t.description }
+func (t cloudInitTemplate) FilterValue() string { return t.name }
+func (t cloudInitTemplate) Value() string       { return t.name }
+
+// cloudInitMsg asks for the user data to be replaced by the named template
+// from the library.
+type cloudInitMsg string
+
+var cloudInitTemplates = []cloudInitTemplate{
+	{
+		name:        "docker host",
+		description: "Docker Engine and Compose, usable by the SSH user without sudo",
+		body: `#cloud-config
+package_update: true
+packages:
+  - docker.io
+  - docker-compose-v2
+runcmd:
+  - systemctl enable --now docker
+  - usermod -aG docker {user}
+`,
+	},
+	{
+		name:        "nginx static site",
+		description: "nginx serving /var/www/site for the Droplets' DNS records",
+		body: `#cloud-config
+package_update: true
+packages:
+  - nginx
+write_files:
+  - path: /etc/nginx/sites-available/site
+    content: |
+      server {
+          listen 80 default_server;
+          listen [::]:80 default_server;
+          server_name {server-names};
+          root /var/www/site;
+          index index.html;
+          location / {
+              try_files $uri $uri/ =404;
+          }
+      }
+  - path: /var/www/site/index.html
+    content: |
+      <!doctype html>
+      <title>It works</title>
+      <p>Upload the site to /var/www/site.</p>
+runcmd:
+  - rm -f /etc/nginx/sites-enabled/default
+  - ln -s /etc/nginx/sites-available/site /etc/nginx/sites-enabled/site
+  - chown -R www-data:www-data /var/www/site
+  - systemctl reload nginx
+`,
+	},
+	{
+		name:        "tailscale node",
+		description: "Join the tailnet with TS_AUTHKEY and allow SSH over it",
+		body: `#cloud-config
+runcmd:
+  - curl -fsSL https://tailscale.com/install.sh | sh
+  - tailscale up --ssh --authkey={tailscale-authkey} --hostname="$(hostname)"
+`,
+	},
+	{
+		name:        "unattended-upgrades baseline",
+		description: "Daily security updates, rebooting at 04:00 when needed",
+		body: `#cloud-config
+package_update: true
+package_upgrade: true
+packages:
+  - unattended-upgrades
+  - apt-listchanges
+write_files:
+  - path: /etc/apt/apt.conf.d/20auto-upgrades
+    content: |
+      APT::Periodic::Update-Package-Lists "1";
+      APT::Periodic::Unattended-Upgrade "1";
+      APT::Periodic::AutocleanInterval "7";
+  - path: /etc/apt/apt.conf.d/52unattended-upgrades-local
+    content: |
+      Unattended-Upgrade::Remove-Unused-Dependencies "true";
+      Unattended-Upgrade::Automatic-Reboot "true";
+      Unattended-Upgrade::Automatic-Reboot-Time "04:00";
+runcmd:
+  - systemctl enable --now unattended-upgrades
+`,
+	},
+}
+
+func newCloudInitLibrary() *selectField {
+	f := newSelectField("", "Choose a cloud-config template", "")
+	opts := make([]option, len(cloudInitTemplates))
+	for i, t := range cloudInitTemplates {
+		opts[i] = t
+	}
+	f.SetOptions(opts)
+	return f
+}
+
+// cloudInitPlaceholder matches the placeholders left in a template once it's
+// been filled in.
+var cloudInitPlaceholder = regexp.MustCompile(`\{[a-z-]+\}`)
+
+// fillCloudInit returns the named template with its placeholders filled in
+// from the form:
+//
+//	{user}               the SSH user, see ssh-user
+//	{server-names}       the names of the Droplets' DNS records, or _
+//	{tailscale-authkey}  $TS_AUTHKEY
+//
+// Placeholders that can't be filled in are left for editing, and listed.
+func (m model) fillCloudInit(name string) (string, []string) {
+	var body string
+	for _, t := range cloudInitTemplates {
+		if t.name == name {
+			body = t.body
+		}
+	}
+
+	user := m.defaults.SSHUser
+	if user == "" {
+		user = defaultSSHUser
+	}
+	serverNames := "_"
+	if domain := m.fields[domainField].Value(); domain != "" {
+		if names, err := dropletNames(m.fields[nameField].Value(), m.fields[countField].Value()); err == nil {
+			for i, n := range names {
+				names[i] = recordFQDN(recordName(n, domain), domain)
+			}
+			serverNames = strings.Join(names, " ")
+		}
+	}
+
+	pairs := []string{"{user}", user, "{server-names}", serverNames}
+	if key := os.Getenv("TS_AUTHKEY"); key != "" {
+		pairs = append(pairs, "{tailscale-authkey}", key)
+	}
+	body = strings.NewReplacer(pairs...).Replace(body)
+
+	return body, cloudInitPlaceholder.FindAllString(body, -1)
+}
+
+// pickCloudInit asks for the chosen template to be put in the editor.
+func pickCloudInit(name string) tea.Cmd {
+	return func() tea.Msg { return cloudInitMsg(name) }
+}
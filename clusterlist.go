@@ -0,0 +1,384 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+var createClusterKey = key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "create"))
+
+// clustersPerPage is how many Kubernetes clusters the clusters screen
+// fetches at a time.
+const clustersPerPage = 20
+
+// clusterListMsg carries a page of the account's Kubernetes clusters, along
+// with how many there are in all.
+type clusterListMsg struct {
+	page     int
+	clusters []*godo.KubernetesCluster
+	total    int
+	err      error
+}
+
+// fetchClusterList lists a page of the account's Kubernetes clusters,
+// counting from 1.
+func fetchClusterList(client *godo.Client, page int) tea.Cmd {
+	return func() tea.Msg {
+		clusters, resp, err := client.Kubernetes.List(context.Background(), &godo.ListOptions{Page: page, PerPage: clustersPerPage})
+		if err != nil {
+			return clusterListMsg{page: page, err: err}
+		}
+
+		total := len(clusters)
+		if resp.Meta != nil {
+			total = resp.Meta.Total
+		}
+		return clusterListMsg{page: page, clusters: clusters, total: total}
+	}
+}
+
+// clusterDoneMsg reports that a change to a Kubernetes cluster has
+// finished. For one that's been created, cluster is it once it's running.
+type clusterDoneMsg struct {
+	id      string
+	name    string
+	verb    string
+	done    string
+	cluster *godo.KubernetesCluster
+	err     error
+}
+
+// clusterList is the screen listing the account's Kubernetes clusters, a
+// page at a time.
+type clusterList struct {
+	table    table.Model
+	clusters []*godo.KubernetesCluster
+	page     int
+	total    int
+	loading  bool
+	err      error
+	// create is set while walking through creating a cluster, kubeconfig
+	// while asking where to save one's kubeconfig, and confirmDelete while
+	// asking to confirm deleting one.
+	create        *clusterWizard
+	kubeconfig    *kubeconfigPrompt
+	confirmDelete *clusterDeletePrompt
+	// busy holds the status to show for the clusters that are starting up
+	// or being deleted, by ID, and creating the names of those being
+	// created, until they're done.
+	busy     map[string]string
+	creating []string
+}
+
+func newClusterList(width, height int) *clusterList {
+	l := &clusterList{
+		page: 1,
+		busy: make(map[string]string),
+		table: newListTable([]table.Column{
+			{Title: "Name", Width: 24},
+			{Title: "Region", Width: 8},
+			{Title: "Version", Width: 14},
+			{Title: "Nodes", Width: 24},
+			{Title: "Endpoint", Width: 20},
+			{Title: "Status", Width: 14},
+		}),
+	}
+	l.SetSize(width, height)
+	return l
+}
+
+// SetSize fits the table to the screen, under the title and above the help.
+func (l *clusterList) SetSize(width, height int) {
+	setTableSize(&l.table, width, height)
+}
+
+// pages returns how many pages of clusters there are.
+func (l *clusterList) pages() int {
+	return pageCount(l.total, clustersPerPage)
+}
+
+// setClusterList shows a page of clusters once it's been fetched.
+func (m model) setClusterList(msg clusterListMsg) (tea.Model, tea.Cmd) {
+	l := m.clusters
+	if l == nil {
+		return m, nil
+	}
+	// The last page was emptied, e.g. by deleting, so go back one.
+	if msg.err == nil && len(msg.clusters) == 0 && msg.page > 1 {
+		return m, fetchClusterList(m.client, msg.page-1)
+	}
+
+	l.loading = false
+	l.err = msg.err
+	if msg.err != nil {
+		return m, nil
+	}
+	l.page, l.clusters, l.total = msg.page, msg.clusters, msg.total
+	l.setRows()
+	return m, nil
+}
+
+// setRows fills the table in from the clusters.
+func (l *clusterList) setRows() {
+	rows := make([]table.Row, len(l.clusters))
+	for i, c := range l.clusters {
+		status := ""
+		if c.Status != nil {
+			status = string(c.Status.State)
+		}
+		if s, ok := l.busy[c.ID]; ok {
+			status = s + "…"
+		}
+		rows[i] = table.Row{c.Name, c.RegionSlug, c.VersionSlug, nodesLabel(c.NodePools), strings.TrimPrefix(c.Endpoint, "https://"), status}
+	}
+	l.table.SetRows(rows)
+	if l.table.Cursor() >= len(rows) {
+		l.table.GotoTop()
+	}
+}
+
+// nodesLabel describes a cluster's node pools briefly, e.g. "3 × s-2vcpu-4gb".
+func nodesLabel(pools []*godo.KubernetesNodePool) string {
+	labels := make([]string, len(pools))
+	for i, p := range pools {
+		labels[i] = fmt.Sprintf("%d × %s", p.Count, p.Size)
+	}
+	return strings.Join(labels, ", ")
+}
+
+// selected returns the cluster under the cursor, unless there are none or
+// it's busy.
+func (l *clusterList) selected() (*godo.KubernetesCluster, bool) {
+	i := l.table.Cursor()
+	if i < 0 || i >= len(l.clusters) {
+		return nil, false
+	}
+	_, busy := l.busy[l.clusters[i].ID]
+	return l.clusters[i], !busy
+}
+
+// openClusters shows the list of Kubernetes clusters, fetching its first
+// page.
+func (m model) openClusters() (model, tea.Cmd) {
+	m.clusters = newClusterList(m.width, m.height-1)
+	m.clusters.loading = true
+	m.notice = ""
+	m.formErr = ""
+	return m, tea.Batch(fetchClusterList(m.client, 1), m.spinner.Tick)
+}
+
+func (m model) updateClusters(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.clusters
+	if l.create != nil {
+		return m.updateClusterWizard(msg)
+	}
+	if l.kubeconfig != nil {
+		return m.updateKubeconfig(msg)
+	}
+	if l.confirmDelete != nil {
+		return m.updateClusterDelete(msg)
+	}
+
+	switch {
+	case msg.String() == "ctrl+c":
+		return m, m.quit()
+	case key.Matches(msg, closeListKeys):
+		return m.closeScreen()
+	case key.Matches(msg, screensKey):
+		return m.openScreens()
+	case key.Matches(msg, createClusterKey):
+		return m.openClusterWizard()
+	case l.loading:
+		return m, nil
+	case key.Matches(msg, kubeconfigKey):
+		return m.promptKubeconfig()
+	case key.Matches(msg, deleteKey):
+		return m.promptClusterDelete()
+	case key.Matches(msg, refreshListKey):
+		l.loading = true
+		return m, fetchClusterList(m.client, l.page)
+	case key.Matches(msg, nextPageKey) && l.page < l.pages():
+		l.loading = true
+		return m, fetchClusterList(m.client, l.page+1)
+	case key.Matches(msg, prevPageKey) && l.page > 1:
+		l.loading = true
+		return m, fetchClusterList(m.client, l.page-1)
+	}
+
+	var cmd tea.Cmd
+	l.table, cmd = l.table.Update(msg)
+	return m, cmd
+}
+
+// clusterDone refreshes the list once a change to a cluster has finished.
+func (m model) clusterDone(msg clusterDoneMsg) (tea.Model, tea.Cmd) {
+	l := m.clusters
+	if l != nil {
+		delete(l.busy, msg.id)
+		l.setRows()
+	}
+	if msg.err != nil {
+		m.formErr = fmt.Sprintf("couldn't %s %s: %s", msg.verb, msg.name, msg.err)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	if msg.cluster != nil {
+		// Left up, rather than toasted, to be copied.
+		m.notice = fmt.Sprintf("%s is running at %s", msg.name, msg.cluster.Endpoint)
+	} else {
+		cmd = m.toast(fmt.Sprintf("%s %s", msg.done, msg.name))
+	}
+	if l == nil {
+		return m, cmd
+	}
+	l.loading = true
+	return m, tea.Batch(cmd, fetchClusterList(m.client, l.page))
+}
+
+func (m model) clustersView() string {
+	l := m.clusters
+	if v, ok := m.clusterWizardPickerView(); ok {
+		return v
+	}
+
+	var b strings.Builder
+
+	title := focusedStyle.Render("Kubernetes clusters")
+	if l.total > 0 {
+		title += placeholderStyle.Render(fmt.Sprintf("  %s · page %d of %d", pluralize(l.total, "cluster"), l.page, l.pages()))
+	}
+	b.WriteString(title + "\n\n")
+
+	switch {
+	case l.loading && l.clusters == nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading clusters..."))
+	case l.err != nil && l.clusters == nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the clusters: "+l.err.Error()))
+	case len(l.clusters) == 0:
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("There are no Kubernetes clusters in this account yet."))
+	default:
+		fmt.Fprintf(&b, "%s\n\n", l.table.View())
+	}
+
+	switch {
+	case l.loading && l.clusters != nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading..."))
+	case l.err != nil && l.clusters != nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the clusters: "+l.err.Error()))
+	}
+	if len(l.creating) > 0 {
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Creating "+strings.Join(l.creating, ", ")+"..."))
+	}
+	if m.notice != "" {
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render(m.notice))
+	}
+	if m.formErr != "" {
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(m.formErr))
+	}
+
+	switch {
+	case l.create != nil:
+		b.WriteString(m.clusterWizardView())
+		return b.String()
+	case l.kubeconfig != nil:
+		b.WriteString(m.kubeconfigView())
+		b.WriteString(helpStyle.Render("enter: save • esc: cancel"))
+		return b.String()
+	case l.confirmDelete != nil:
+		b.WriteString(m.clusterDeleteView())
+		b.WriteString(helpStyle.Render("enter: delete • esc: cancel"))
+		return b.String()
+	}
+
+	help := []string{"↑/↓: move", "c: create", "k: save kubeconfig", "d: delete"}
+	if l.pages() > 1 {
+		help = append(help, "←/→: page")
+	}
+	help = append(help, "r: refresh", "tab: screens", m.closeScreenHelp())
+	b.WriteString(helpStyle.Render(strings.Join(help, " • ")))
+
+	return b.String()
+}
+
+// clusterDeletePrompt asks for a cluster's name before deleting it.
+type clusterDeletePrompt struct {
+	cluster *godo.KubernetesCluster
+	name    *textField
+}
+
+// promptClusterDelete asks to confirm deleting the cluster under the
+// cursor.
+func (m model) promptClusterDelete() (model, tea.Cmd) {
+	if m.blockChanges("clusters", "deleted") {
+		return m, nil
+	}
+
+	l := m.clusters
+	c, ok := l.selected()
+	if !ok {
+		return m, nil
+	}
+	name := newOptionalTextField("Type its name to delete it: ", c.Name)
+	name.CharLimit = 255
+	l.confirmDelete = &clusterDeletePrompt{cluster: c, name: name}
+	m.notice = ""
+	m.formErr = ""
+	return m, name.Focus()
+}
+
+func (m model) updateClusterDelete(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.clusters
+	p := l.confirmDelete
+	switch msg.String() {
+	case "ctrl+c":
+		return m, m.quit()
+	case "esc":
+		l.confirmDelete = nil
+		m.formErr = ""
+		return m, nil
+	case "enter":
+		if strings.TrimSpace(p.name.Value()) != p.cluster.Name {
+			m.formErr = "the name doesn't match; type it exactly, or press esc to cancel"
+			return m, nil
+		}
+		l.confirmDelete = nil
+		m.formErr = ""
+		l.busy[p.cluster.ID] = "deleting"
+		l.setRows()
+		return m, deleteCluster(m.client, p.cluster)
+	}
+
+	_, cmd := p.name.Update(msg)
+	return m, cmd
+}
+
+// deleteCluster deletes the cluster along with its nodes. Load balancers
+// and volumes its workloads created are left for deleting separately.
+func deleteCluster(client *godo.Client, c *godo.KubernetesCluster) tea.Cmd {
+	return func() tea.Msg {
+		_, err := client.Kubernetes.Delete(context.Background(), c.ID)
+		return clusterDoneMsg{id: c.ID, name: c.Name, verb: "delete", done: "Deleted", err: err}
+	}
+}
+
+func (m model) clusterDeleteView() string {
+	p := m.clusters.confirmDelete
+	var b strings.Builder
+
+	nodes := 0
+	for _, pool := range p.cluster.NodePools {
+		nodes += pool.Count
+	}
+	fmt.Fprintf(&b, "%s\n", focusedStyle.Render(fmt.Sprintf("Delete %s?", p.cluster.Name)))
+	fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(fmt.Sprintf("Its %s and everything running on them are destroyed. Load balancers and volumes it created are kept.", pluralize(nodes, "node"))))
+	fmt.Fprintf(&b, "%s\n\n", p.name.View())
+
+	return b.String()
+}
@@ -0,0 +1,433 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+// clusterPollInterval is how often a new cluster is checked on while it's
+// provisioned, which takes several minutes.
+const clusterPollInterval = 10 * time.Second
+
+// defaultNodeSize is the node size the wizard starts on, the smallest the
+// control panel recommends for a node pool.
+const defaultNodeSize = "s-2vcpu-4gb"
+
+// clusterStep is a step of the cluster wizard, in the order they're taken.
+type clusterStep int
+
+const (
+	clusterStepName clusterStep = iota
+	clusterStepRegion
+	clusterStepVersion
+	clusterStepSize
+	clusterStepCount
+	clusterStepReview
+)
+
+type clusterRegionOption struct {
+	*godo.KubernetesRegion
+}
+
+func (o clusterRegionOption) Title() string       { return o.Name }
+func (o clusterRegionOption) Description() string { return o.Slug }
+func (o clusterRegionOption) FilterValue() string { return o.Name + " " + o.Slug }
+func (o clusterRegionOption) Value() string       { return o.Slug }
+
+type clusterVersionOption struct {
+	*godo.KubernetesVersion
+}
+
+func (o clusterVersionOption) Title() string {
+	return "Kubernetes " + o.KubernetesVersion.KubernetesVersion
+}
+func (o clusterVersionOption) Description() string { return o.Slug }
+func (o clusterVersionOption) FilterValue() string { return o.Slug }
+func (o clusterVersionOption) Value() string       { return o.Slug }
+
+type clusterSizeOption struct {
+	*godo.KubernetesNodeSize
+}
+
+func (o clusterSizeOption) Title() string { return o.Name }
+func (o clusterSizeOption) Description() string {
+	if o.Name == o.Slug {
+		return ""
+	}
+	return o.Slug
+}
+func (o clusterSizeOption) FilterValue() string { return o.Name + " " + o.Slug }
+func (o clusterSizeOption) Value() string       { return o.Slug }
+
+// clusterWizard walks through creating a Kubernetes cluster with a single
+// node pool, a step at a time. Esc goes back a step.
+type clusterWizard struct {
+	step clusterStep
+
+	name     *textField
+	regions  *selectField
+	versions *selectField
+	sizes    *selectField
+	count    *textField
+
+	// loaded is set once the regions, versions and sizes have been fetched,
+	// and err if they couldn't be.
+	loaded bool
+	err    error
+}
+
+// clusterOptionsMsg carries the regions, versions and node sizes clusters
+// can be created with.
+type clusterOptionsMsg struct {
+	options *godo.KubernetesOptions
+	err     error
+}
+
+func fetchClusterOptions(client *godo.Client) tea.Cmd {
+	return func() tea.Msg {
+		options, _, err := client.Kubernetes.GetOptions(context.Background())
+		return clusterOptionsMsg{options: options, err: err}
+	}
+}
+
+// openClusterWizard starts creating a cluster, asking for its name while
+// the regions, versions and sizes are fetched.
+func (m model) openClusterWizard() (model, tea.Cmd) {
+	if m.blockChanges("clusters", "created") {
+		return m, nil
+	}
+
+	w := &clusterWizard{name: newOptionalTextField("Name: ", "e.g. prod-cluster")}
+	w.name.CharLimit = 255
+	w.regions = newSelectField("", "Choose a region for the cluster", "")
+	w.versions = newSelectField("", "Choose a Kubernetes version", "")
+	w.sizes = newSelectField("", "Choose the size of the cluster's nodes", "")
+	for _, f := range []*selectField{w.regions, w.versions, w.sizes} {
+		f.SetSize(m.width, m.height-1)
+	}
+	m.clusters.create = w
+	m.notice = ""
+	m.formErr = ""
+	return m, tea.Batch(w.name.Focus(), fetchClusterOptions(m.client))
+}
+
+// setClusterOptions offers the regions, versions and sizes once they've
+// been fetched, starting on the form's region, the newest version and a
+// small node size.
+func (m model) setClusterOptions(msg clusterOptionsMsg) (tea.Model, tea.Cmd) {
+	if m.clusters == nil || m.clusters.create == nil {
+		return m, nil
+	}
+	w := m.clusters.create
+	if msg.err != nil {
+		w.err = msg.err
+		return m, nil
+	}
+
+	var regions, versions, sizes []option
+	for _, r := range msg.options.Regions {
+		regions = append(regions, clusterRegionOption{r})
+	}
+	for _, v := range msg.options.Versions {
+		versions = append(versions, clusterVersionOption{v})
+	}
+	for _, s := range msg.options.Sizes {
+		sizes = append(sizes, clusterSizeOption{s})
+	}
+	cmds := []tea.Cmd{
+		w.regions.SetOptions(regions),
+		w.versions.SetOptions(versions),
+		w.sizes.SetOptions(sizes),
+	}
+	w.regions.Select(m.fields[regionField].Value())
+	w.sizes.Select(defaultNodeSize)
+	for _, f := range []*selectField{w.regions, w.versions, w.sizes} {
+		f.selected = nil
+	}
+	w.loaded = true
+	if f := w.picker(); f != nil {
+		f.Open()
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// picker returns the list the current step is chosen from, if it's chosen
+// from one.
+func (w *clusterWizard) picker() *selectField {
+	switch w.step {
+	case clusterStepRegion:
+		return w.regions
+	case clusterStepVersion:
+		return w.versions
+	case clusterStepSize:
+		return w.sizes
+	}
+	return nil
+}
+
+func (m model) updateClusterWizard(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.clusters
+	w := l.create
+	if msg.String() == "ctrl+c" {
+		return m, m.quit()
+	}
+
+	if f := w.picker(); f != nil {
+		if !f.Opened() {
+			// Waiting for the options.
+			if msg.String() == "esc" {
+				return m.clusterWizardBack()
+			}
+			return m, nil
+		}
+		_, cmd := f.Update(msg)
+		if f.Opened() {
+			return m, cmd
+		}
+		if f.selected == nil {
+			return m.clusterWizardBack()
+		}
+		return m.clusterWizardStep(w.step + 1)
+	}
+
+	switch w.step {
+	case clusterStepReview:
+		switch msg.String() {
+		case "esc":
+			return m.clusterWizardBack()
+		case "enter":
+			req := w.request()
+			l.create = nil
+			m.formErr = ""
+			l.creating = append(l.creating, req.Name)
+			return m, createCluster(m.client, req)
+		}
+		return m, nil
+
+	case clusterStepName:
+		switch msg.String() {
+		case "esc":
+			return m.clusterWizardBack()
+		case "enter":
+			name := strings.TrimSpace(w.name.Value())
+			if name == "" {
+				m.formErr = "the cluster needs a name"
+				return m, nil
+			}
+			if err := checkHostname(name); err != "" {
+				m.formErr = err
+				return m, nil
+			}
+			m.formErr = ""
+			return m.clusterWizardStep(clusterStepRegion)
+		}
+		_, cmd := w.name.Update(msg)
+		return m, cmd
+
+	case clusterStepCount:
+		switch msg.String() {
+		case "esc":
+			return m.clusterWizardBack()
+		case "enter":
+			if _, err := w.nodeCount(); err != nil {
+				m.formErr = err.Error()
+				return m, nil
+			}
+			m.formErr = ""
+			return m.clusterWizardStep(clusterStepReview)
+		}
+		_, cmd := w.count.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+// nodeCount returns the number of nodes typed for the node pool.
+func (w *clusterWizard) nodeCount() (int, error) {
+	s := strings.TrimSpace(w.count.Value())
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("the node count %q isn't a whole number of at least 1", s)
+	}
+	return n, nil
+}
+
+// clusterWizardStep moves the wizard on to the step.
+func (m model) clusterWizardStep(step clusterStep) (tea.Model, tea.Cmd) {
+	w := m.clusters.create
+	w.step = step
+
+	switch step {
+	case clusterStepName:
+		return m, w.name.Focus()
+	case clusterStepCount:
+		if w.count == nil {
+			w.count = newTextField("Nodes: ", "3")
+			w.count.CharLimit = 4
+		}
+		return m, w.count.Focus()
+	}
+	if f := w.picker(); f != nil && w.loaded {
+		f.Open()
+	}
+	return m, nil
+}
+
+// clusterWizardBack goes back a step, or stops creating the cluster from
+// the first.
+func (m model) clusterWizardBack() (tea.Model, tea.Cmd) {
+	w := m.clusters.create
+	m.formErr = ""
+	if w.step == clusterStepName {
+		m.clusters.create = nil
+		return m, nil
+	}
+	return m.clusterWizardStep(w.step - 1)
+}
+
+// request returns the cluster described by the wizard's answers, with a
+// single node pool named after it.
+func (w *clusterWizard) request() *godo.KubernetesClusterCreateRequest {
+	name := strings.TrimSpace(w.name.Value())
+	count, _ := w.nodeCount()
+	return &godo.KubernetesClusterCreateRequest{
+		Name:        name,
+		RegionSlug:  w.regions.Value(),
+		VersionSlug: w.versions.Value(),
+		NodePools: []*godo.KubernetesNodePoolCreateRequest{{
+			Name:  name + "-default-pool",
+			Size:  w.sizes.Value(),
+			Count: count,
+		}},
+	}
+}
+
+// clusterCreatedMsg reports that a cluster has been created, though it's
+// still being provisioned.
+type clusterCreatedMsg struct {
+	name    string
+	cluster *godo.KubernetesCluster
+	err     error
+}
+
+func createCluster(client *godo.Client, req *godo.KubernetesClusterCreateRequest) tea.Cmd {
+	return func() tea.Msg {
+		cluster, _, err := client.Kubernetes.Create(context.Background(), req)
+		return clusterCreatedMsg{name: req.Name, cluster: cluster, err: err}
+	}
+}
+
+// clusterCreated lists a new cluster while waiting for it to be
+// provisioned.
+func (m model) clusterCreated(msg clusterCreatedMsg) (tea.Model, tea.Cmd) {
+	l := m.clusters
+	if l != nil {
+		for i, name := range l.creating {
+			if name == msg.name {
+				l.creating = append(l.creating[:i:i], l.creating[i+1:]...)
+				break
+			}
+		}
+	}
+	if msg.err != nil {
+		m.formErr = fmt.Sprintf("couldn't create %s: %s", msg.name, msg.err)
+		return m, nil
+	}
+
+	cmd := waitForCluster(m.client, msg.cluster.ID, msg.name)
+	if l == nil {
+		return m, cmd
+	}
+	l.busy[msg.cluster.ID] = "provisioning"
+	l.loading = true
+	return m, tea.Batch(cmd, fetchClusterList(m.client, l.page))
+}
+
+// waitForCluster checks on a new cluster until it's running.
+func waitForCluster(client *godo.Client, id, name string) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		for {
+			cluster, _, err := client.Kubernetes.Get(ctx, id)
+			if err != nil {
+				return clusterDoneMsg{id: id, name: name, verb: "create", err: err}
+			}
+			if cluster.Status != nil {
+				switch cluster.Status.State {
+				case godo.KubernetesClusterStatusRunning:
+					return clusterDoneMsg{id: id, name: name, verb: "create", done: "Created", cluster: cluster}
+				case godo.KubernetesClusterStatusError, godo.KubernetesClusterStatusInvalid:
+					err := errors.New("it failed while being provisioned")
+					if cluster.Status.Message != "" {
+						err = errors.New(cluster.Status.Message)
+					}
+					return clusterDoneMsg{id: id, name: name, verb: "create", err: err}
+				}
+			}
+			time.Sleep(clusterPollInterval)
+		}
+	}
+}
+
+// clusterWizardPickerView returns the list being chosen from, if the
+// wizard's at a step that's chosen from one.
+func (m model) clusterWizardPickerView() (string, bool) {
+	w := m.clusters.create
+	if w == nil {
+		return "", false
+	}
+	if f := w.picker(); f != nil && f.Opened() {
+		return f.View(), true
+	}
+	return "", false
+}
+
+func (m model) clusterWizardView() string {
+	w := m.clusters.create
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s\n", focusedStyle.Render("New Kubernetes cluster"))
+	if w.step > clusterStepName {
+		fmt.Fprintf(&b, "  Name: %s\n", strings.TrimSpace(w.name.Value()))
+	}
+	if w.step > clusterStepRegion {
+		fmt.Fprintf(&b, "  Region: %s\n", w.regions.Value())
+	}
+	if w.step > clusterStepVersion {
+		fmt.Fprintf(&b, "  Version: %s\n", w.versions.Value())
+	}
+	if w.step > clusterStepSize {
+		fmt.Fprintf(&b, "  Node size: %s\n", w.sizes.Value())
+	}
+	if w.step > clusterStepCount {
+		fmt.Fprintf(&b, "  Nodes: %s\n", strings.TrimSpace(w.count.Value()))
+	}
+	b.WriteString("\n")
+
+	switch {
+	case w.err != nil && w.step != clusterStepName:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the cluster options: "+w.err.Error()))
+		b.WriteString(helpStyle.Render("esc: back"))
+	case w.picker() != nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading the cluster options..."))
+		b.WriteString(helpStyle.Render("esc: back"))
+	case w.step == clusterStepReview:
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("Provisioning takes several minutes. Its nodes are billed like Droplets of their size."))
+		b.WriteString(helpStyle.Render("enter: create • esc: back"))
+	case w.step == clusterStepName:
+		fmt.Fprintf(&b, "%s\n\n", w.name.View())
+		b.WriteString(helpStyle.Render("enter: next • esc: cancel"))
+	default:
+		fmt.Fprintf(&b, "%s\n\n", w.count.View())
+		b.WriteString(helpStyle.Render("enter: next • esc: back"))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// config holds the form defaults read from the config file.
+type config struct {
+	Region string `yaml:"region"`
+	Size   string `yaml:"size"`
+	Image  string `yaml:"image"`
+	// SSHKeys may list keys by name, fingerprint or ID.
+	SSHKeys []string `yaml:"ssh-keys"`
+	Tags    []string `yaml:"tags"`
+	// AutoTag adds defaultAutoTags to every Droplet created, unless AutoTags
+	// gives others, see autoTags.
+	AutoTag  bool     `yaml:"auto-tag"`
+	AutoTags []string `yaml:"auto-tags"`
+	// Domain is a managed domain to create A records for new Droplets in.
+	Domain string `yaml:"domain"`
+	// Firewalls may list cloud firewalls by name or ID.
+	Firewalls []string `yaml:"firewalls"`
+	// NameTemplate is used to generate names, see generateName.
+	NameTemplate string `yaml:"name-template"`
+	// APIURL overrides the API endpoint, though DIGITALOCEAN_API_URL takes
+	// precedence.
+	APIURL string `yaml:"api-url"`
+	// OAuthClientID is the ID of an OAuth app to log in with, though
+	// DIGITALOCEAN_OAUTH_CLIENT_ID takes precedence.
+	OAuthClientID string `yaml:"oauth-client-id"`
+	// SpacesKeyID and SpacesSecret are a Spaces access key for the Spaces
+	// screen, though SPACES_ACCESS_KEY_ID and SPACES_SECRET_ACCESS_KEY take
+	// precedence.
+	SpacesKeyID  string `yaml:"spaces-access-key-id"`
+	SpacesSecret string `yaml:"spaces-secret-access-key"`
+	// SSHUser and SSHIdentity are who to log into new Droplets as, and the
+	// private key to log in with.
+	SSHUser     string `yaml:"ssh-user"`
+	SSHIdentity string `yaml:"ssh-identity"`
+	// WaitForSSH holds off declaring success until SSH accepts connections
+	// on SSHPort, for up to SSHTimeout.
+	WaitForSSH bool          `yaml:"wait-for-ssh"`
+	SSHPort    int           `yaml:"ssh-port"`
+	SSHTimeout time.Duration `yaml:"ssh-timeout"`
+	// Bootstrap is a local shell script to run on new Droplets over SSH
+	// once they're reachable.
+	Bootstrap string `yaml:"bootstrap"`
+	// HostKeyChecking is how new Droplets' host keys are checked when
+	// connecting, see hostKeysPin. SeedKnownHosts adds pinned keys to
+	// ~/.ssh/known_hosts too.
+	HostKeyChecking string `yaml:"host-key-checking"`
+	SeedKnownHosts  bool   `yaml:"seed-known-hosts"`
+	// Uploads are copied to new Droplets over SFTP once they're reachable,
+	// before the bootstrap script runs.
+	Uploads []upload `yaml:"upload"`
+	// Inventory is where to write an Ansible inventory of the new Droplets,
+	// as INI if it ends in .ini and YAML otherwise.
+	Inventory string `yaml:"inventory"`
+	// HealthCheck is a URL to probe on new Droplets once they're set up,
+	// for up to HealthTimeout, see healthURL.
+	HealthCheck   string        `yaml:"health-check"`
+	HealthTimeout time.Duration `yaml:"health-timeout"`
+	// PostCreate is a local command to run for each new Droplet once it's
+	// set up, and healthy if there's a health check, see hookEnv.
+	PostCreate string `yaml:"post-create"`
+}
+
+// loadConfig reads the config file at path, or from the default location if
+// path is empty. Only a file given explicitly has to exist.
+func loadConfig(path string) (config, error) {
+	var cfg config
+
+	explicit := path != ""
+	if !explicit {
+		var err error
+		if path, err = configPath(); err != nil {
+			return cfg, err
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && !explicit {
+		return cfg, nil
+	} else if err != nil {
+		return cfg, err
+	}
+
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("reading %s: %w", path, err)
+	}
+	switch cfg.HostKeyChecking {
+	case "", hostKeysPin, hostKeysAcceptNew, hostKeysOff:
+	default:
+		return cfg, fmt.Errorf("reading %s: host-key-checking must be %s, %s or %s", path, hostKeysPin, hostKeysAcceptNew, hostKeysOff)
+	}
+	return cfg, nil
+}
+
+// setDefaults pre-fills the form from the config.
+func (m *model) setDefaults(cfg config) {
+	m.defaults = cfg
+
+	if cfg.Region != "" {
+		m.fields[regionField].(*selectField).fallback = cfg.Region
+	}
+	if cfg.Size != "" {
+		m.fields[sizeField].(*sizePicker).fallback = cfg.Size
+	}
+	if cfg.Image != "" {
+		m.fields[imageField].(*imagePicker).fallback = cfg.Image
+	}
+	if len(cfg.SSHKeys) > 0 {
+		m.fields[keysField].(*multiSelectField).CheckMatching(cfg.SSHKeys)
+	}
+	if len(cfg.Tags) > 0 {
+		m.fields[tagsField].(*textField).SetValue(strings.Join(cfg.Tags, ","))
+	}
+	if cfg.Domain != "" {
+		m.fields[domainField].(*selectField).Select(cfg.Domain)
+	}
+	if len(cfg.Firewalls) > 0 {
+		m.fields[firewallsField].(*multiSelectField).CheckMatching(cfg.Firewalls)
+	}
+	m.setRegion()
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+// consoleURL is the Recovery Console for a Droplet, by ID. It connects to
+// the Droplet's own console rather than over the network, so it works before
+// SSH is up or when networking is broken.
+const consoleURL = "https://cloud.digitalocean.com/droplets/%d/console"
+
+var consoleKey = key.NewBinding(key.WithKeys("w"), key.WithHelp("w", "open web console"))
+
+// openConsole opens the Droplet's Recovery Console in the default browser.
+func openConsole(droplet *godo.Droplet) tea.Cmd {
+	url := fmt.Sprintf(consoleURL, droplet.ID)
+
+	return func() tea.Msg {
+		return openedMsg{url: url, err: openURL(url)}
+	}
+}
+
+// findDroplet looks up an existing Droplet by ID or name. It's an error if
+// more than one Droplet has the name.
+func findDroplet(ctx context.Context, client *godo.Client, ref string) (*godo.Droplet, error) {
+	if id, err := strconv.Atoi(ref); err == nil {
+		droplet, _, err := client.Droplets.Get(ctx, id)
+		return droplet, err
+	}
+
+	droplets, _, err := client.Droplets.ListByName(ctx, ref, &godo.ListOptions{PerPage: 200})
+	if err != nil {
+		return nil, err
+	}
+	switch len(droplets) {
+	case 0:
+		return nil, fmt.Errorf("no Droplet is named %s", ref)
+	case 1:
+		return &droplets[0], nil
+	default:
+		return nil, fmt.Errorf("%d Droplets are named %s; pass the ID instead", len(droplets), ref)
+	}
+}
+
+// openExistingConsole opens the Recovery Console of an existing Droplet,
+// given by ID or name, printing its URL in case no browser opens.
+func openExistingConsole(client *godo.Client, ref string) error {
+	droplet, err := findDroplet(context.Background(), client, ref)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf(consoleURL, droplet.ID)
+	fmt.Printf("Opening the console of %s: %s\n", droplet.Name, url)
+	if err := openURL(url); err != nil {
+		return fmt.Errorf("couldn't open a browser, visit the URL above instead: %w", err)
+	}
+	return nil
+}
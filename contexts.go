@@ -0,0 +1,96 @@
+package main
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var switchContextKey = key.NewBinding(key.WithKeys("ctrl+x"), key.WithHelp("ctrl+x", "switch context"))
+
+// authContext is a named API token, e.g. one per account.
+type authContext struct {
+	name   string
+	source string
+	token  string
+}
+
+type contextItem struct {
+	authContext
+	team string
+}
+
+func (c contextItem) Title() string {
+	if c.team != "" {
+		return c.team
+	}
+	return c.name
+}
+
+func (c contextItem) Description() string {
+	if c.team != "" && c.source != "" {
+		return c.name + " · " + c.source
+	}
+	if c.team != "" {
+		return c.name
+	}
+	return c.source
+}
+
+func (c contextItem) FilterValue() string { return c.team + " " + c.name }
+func (c contextItem) Value() string       { return c.name }
+
+// findContext returns the context with the given name.
+func findContext(contexts []authContext, name string) (authContext, bool) {
+	for _, c := range contexts {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return authContext{}, false
+}
+
+// newContextSwitcher returns a list of the contexts to switch between, with
+// the current one chosen.
+func newContextSwitcher(contexts []authContext, current string) *selectField {
+	f := newSelectField("Context: ", "Switch team", current)
+	f.hideValue = true
+	setContextOptions(f, contexts, nil)
+
+	return f
+}
+
+// setContextOptions lists the contexts in the switcher, named after their
+// teams where known.
+func setContextOptions(f *selectField, contexts []authContext, teams map[string]string) tea.Cmd {
+	opts := make([]option, len(contexts))
+	for i, c := range contexts {
+		opts[i] = contextItem{c, teams[c.name]}
+	}
+	return f.SetOptions(opts)
+}
+
+// switchContext starts the form over using the named context's token, since
+// the SSH keys, VPCs and projects on offer all belong to the account.
+func (m model) switchContext(name string) (tea.Model, tea.Cmd) {
+	c, _ := findContext(m.contexts, name)
+	client, err := newClient(c.token, m.apiURL)
+	if err != nil {
+		m.formErr = err.Error()
+		return m, nil
+	}
+
+	n := initialModel(client)
+	n.setDefaults(m.defaults)
+	n.setHistory(m.history)
+	n.apiURL = m.apiURL
+	n.dryRun = m.dryRun
+	n.readOnly = m.readOnly
+	n.output = m.output
+	n.contexts = m.contexts
+	n.context = c.name
+	n.switcher = m.switcher
+	n.teams = m.teams
+
+	size := tea.WindowSizeMsg{Width: m.width, Height: m.height}
+	return n, tea.Batch(n.Init(), func() tea.Msg { return size })
+}
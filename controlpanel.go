@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+// controlPanelURL is the page for a Droplet in the control panel, by ID.
+const controlPanelURL = "https://cloud.digitalocean.com/droplets/%d"
+
+var controlPanelKey = key.NewBinding(key.WithKeys("o"), key.WithHelp("o", "open in control panel"))
+
+// openedMsg reports that a URL has been opened in the browser.
+type openedMsg struct {
+	url string
+	err error
+}
+
+// openInControlPanel opens the Droplet's page in the control panel in the
+// default browser.
+func openInControlPanel(droplet *godo.Droplet) tea.Cmd {
+	url := fmt.Sprintf(controlPanelURL, droplet.ID)
+
+	return func() tea.Msg {
+		return openedMsg{url: url, err: openURL(url)}
+	}
+}
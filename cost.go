@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// hoursPerMonth is the number of hours after which DigitalOcean stops
+// billing hourly and charges the monthly price instead.
+const hoursPerMonth = 672
+
+type costLine struct {
+	label   string
+	monthly float64
+	hourly  float64
+}
+
+// estimate prices the Droplets described by the form. It returns nothing
+// until the sizes have loaded.
+func (m model) estimate() []costLine {
+	size, ok := m.fields[sizeField].(*sizePicker).Size()
+	if !ok {
+		return nil
+	}
+
+	count, err := strconv.Atoi(m.fields[countField].Value())
+	if err != nil || count < 1 {
+		count = 1
+	}
+	n := float64(count)
+
+	label := size.Slug
+	if count > 1 {
+		label = fmt.Sprintf("%s × %d", size.Slug, count)
+	}
+	lines := []costLine{{label: label, monthly: size.PriceMonthly * n, hourly: size.PriceHourly * n}}
+
+	if m.fields[backupsField].(*toggleField).Checked() {
+		monthly := backupsPrice(size) * n
+		lines = append(lines, costLine{label: "Backups", monthly: monthly, hourly: monthly / hoursPerMonth})
+	}
+
+	if gb, err := strconv.ParseInt(m.fields[volumeSizeField].Value(), 10, 64); err == nil && gb > 0 {
+		monthly := volumePrice(gb)
+		lines = append(lines, costLine{label: fmt.Sprintf("Volume (%d GB)", gb), monthly: monthly, hourly: monthly / hoursPerMonth})
+	}
+
+	return lines
+}
+
+func (m model) costView() string {
+	lines := m.estimate()
+	if lines == nil {
+		return ""
+	}
+
+	total := costLine{label: "Total"}
+	for _, l := range lines {
+		total.monthly += l.monthly
+		total.hourly += l.hourly
+	}
+	if len(lines) > 1 {
+		lines = append(lines, total)
+	}
+
+	width := 0
+	for _, l := range lines {
+		if n := utf8.RuneCountInString(l.label); n > width {
+			width = n
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(focusedStyle.Render("Estimated cost"))
+	for _, l := range lines {
+		style := placeholderStyle
+		if l.label == total.label {
+			style = focusedStyle
+		}
+		fmt.Fprintf(&b, "\n  %s", style.Render(fmt.Sprintf("%-*s  %9s  %s", width, l.label, fmt.Sprintf("$%.2f/mo", l.monthly), fmt.Sprintf("$%.5f/hr", l.hourly))))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,444 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+	"github.com/digitalocean/godo/util"
+)
+
+// maxCount is the most Droplets a single multi-create request may ask for.
+const maxCount = 10
+
+// dropletPollInterval is how often a new Droplet is checked on when there's
+// no action to wait on, as often as util.WaitForActive checks on actions.
+const dropletPollInterval = 5 * time.Second
+
+// pendingDroplet tracks a Droplet from the create request until it's active.
+type pendingDroplet struct {
+	droplet    *godo.Droplet
+	volume     *godo.Volume
+	record     string
+	reservedIP string
+	// firewalls names the firewalls the Droplet was added to.
+	firewalls    string
+	loadBalancer string
+	ptr          string
+	ptrErr       error
+	// active is set once the Droplet is active, while waiting for SSH.
+	active bool
+	done   bool
+	err    error
+	// bootstrapped is set once the bootstrap script has been started on the
+	// Droplet, and bootstrapErr reports how it exited.
+	bootstrapped bool
+	bootstrapErr error
+	// hostKeys are the Droplet's pinned host keys, unless hostKeyErr says
+	// why they couldn't be.
+	hostKeys   []string
+	hostKeyErr error
+	// uploaded is set once the files have started uploading to the
+	// Droplet, and uploadErr reports how it went.
+	uploaded  bool
+	uploadErr error
+	// health is the URL being probed, healthDone is set once the check is
+	// over and healthErr reports why it failed.
+	health     string
+	healthDone bool
+	healthErr  error
+	// hooked is set once the post-create hook has been started for the
+	// Droplet, hookDone once it's exited and hookErr reports how.
+	hooked   bool
+	hookDone bool
+	hookErr  error
+}
+
+// settingUp reports whether the rest of the setup steps should run on the
+// Droplet, which they don't once it's failed to be created or to have the
+// files uploaded.
+func (p pendingDroplet) settingUp() bool {
+	return p.err == nil && p.uploadErr == nil
+}
+
+// createdMsg reports the Droplets accepted by the API along with the URIs of
+// the actions creating them, in the same order. An action is empty if the
+// API didn't return it, and the Droplet is checked on instead.
+type createdMsg struct {
+	droplets []godo.Droplet
+	actions  []string
+	volume   *godo.Volume
+}
+
+// readyMsg reports that the Droplet at index is active, or failed to become
+// so.
+type readyMsg struct {
+	index   int
+	droplet *godo.Droplet
+	// record is the DNS record pointed at the Droplet, if any.
+	record string
+	// reservedIP is the reserved IP assigned to the Droplet, if any.
+	reservedIP string
+	// ptr is the Droplet's confirmed reverse DNS, if it was named as a
+	// domain, unless ptrErr says otherwise.
+	ptr    string
+	ptrErr error
+	err    error
+}
+
+// dropletNames expands the name into count names. When creating more than one
+// Droplet the name may contain a format verb for the index, e.g. web-%02d;
+// otherwise the index is appended.
+func dropletNames(name, count string) ([]string, error) {
+	n, err := strconv.Atoi(count)
+	if err != nil || n < 1 || n > maxCount {
+		return nil, fmt.Errorf("count must be a number between 1 and %d", maxCount)
+	}
+
+	if n == 1 && !strings.Contains(name, "%") {
+		return []string{name}, nil
+	}
+	if !strings.Contains(name, "%") {
+		name += "-%02d"
+	}
+
+	names := make([]string, n)
+	for i := range names {
+		names[i] = fmt.Sprintf(name, i+1)
+		if strings.Contains(names[i], "%!") {
+			return nil, fmt.Errorf("name %q must contain a single integer verb such as %%02d", name)
+		}
+	}
+
+	return names, nil
+}
+
+// createFailedMsg reports that the create request failed, so there are no
+// Droplets to wait on. cleanupErr is set if the volume created for them was
+// left behind.
+type createFailedMsg struct {
+	err        error
+	cleanupErr error
+}
+
+// dropletCreate creates a Droplet for each name from the request, using a
+// single multi-create call when there's more than one. If volumeReq is set
+// the volume is created first and attached to the Droplet, and deleted again
+// if the Droplets can't be created, rather than left to be billed for.
+func dropletCreate(client *godo.Client, createReq *godo.DropletCreateRequest, names []string, volumeReq *godo.VolumeCreateRequest) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+
+		var volume *godo.Volume
+		if volumeReq != nil {
+			var err error
+			volume, _, err = client.Storage.CreateVolume(ctx, volumeReq)
+			if err != nil {
+				return createFailedMsg{err: err}
+			}
+
+			req := *createReq
+			req.Volumes = []godo.DropletCreateVolume{{ID: volume.ID}}
+			createReq = &req
+		}
+		failed := func(err error) tea.Msg {
+			if volume == nil {
+				return createFailedMsg{err: err}
+			}
+			if _, delErr := client.Storage.DeleteVolume(ctx, volume.ID); delErr != nil {
+				return createFailedMsg{err: err, cleanupErr: fmt.Errorf("the volume %s (%s) created for the Droplets couldn't be deleted, so it's billed until you delete it: %s", volume.Name, volume.ID, delErr)}
+			}
+			return createFailedMsg{err: err}
+		}
+
+		var (
+			droplets []godo.Droplet
+			resp     *godo.Response
+			err      error
+		)
+		if len(names) == 1 {
+			req := *createReq
+			req.Name = names[0]
+
+			var droplet *godo.Droplet
+			droplet, resp, err = client.Droplets.Create(ctx, &req)
+			if droplet != nil {
+				droplets = []godo.Droplet{*droplet}
+			}
+		} else {
+			droplets, resp, err = client.Droplets.CreateMultiple(ctx, multiCreateRequest(createReq, names))
+		}
+		if err != nil {
+			return failed(err)
+		}
+
+		// The Droplets exist by now, volume and all, so they're kept even
+		// without actions to wait on.
+		actions := make([]string, len(droplets))
+		if resp.Links != nil && len(resp.Links.Actions) == len(droplets) {
+			for i, a := range resp.Links.Actions {
+				actions[i] = a.HREF
+			}
+		}
+
+		return createdMsg{droplets: droplets, actions: actions, volume: volume}
+	}
+}
+
+func multiCreateRequest(r *godo.DropletCreateRequest, names []string) *godo.DropletMultiCreateRequest {
+	return &godo.DropletMultiCreateRequest{
+		Names:             names,
+		Region:            r.Region,
+		Size:              r.Size,
+		Image:             r.Image,
+		SSHKeys:           r.SSHKeys,
+		Backups:           r.Backups,
+		IPv6:              r.IPv6,
+		PrivateNetworking: r.PrivateNetworking,
+		Monitoring:        r.Monitoring,
+		UserData:          r.UserData,
+		Tags:              r.Tags,
+		VPCUUID:           r.VPCUUID,
+	}
+}
+
+// dropletSetup is what's done to each Droplet once it's created.
+type dropletSetup struct {
+	projectID  string
+	domain     string
+	reservedIP string
+	firewalls  []string
+	// loadBalancer is added to once the Droplet is active.
+	loadBalancer string
+}
+
+// setup returns what to do to the new Droplets, as chosen in the form.
+func (m model) setup() dropletSetup {
+	return dropletSetup{
+		projectID:    m.projectID(),
+		domain:       m.fields[domainField].Value(),
+		reservedIP:   m.fields[reservedIPField].Value(),
+		firewalls:    m.fields[firewallsField].(*multiSelectField).Values(),
+		loadBalancer: m.fields[loadBalancerField].Value(),
+	}
+}
+
+// waitForDroplet waits for the action creating the Droplet to complete, then
+// finishes setting it up.
+func waitForDroplet(client *godo.Client, index, id int, action string, setup dropletSetup) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+
+		if err := addToFirewalls(ctx, client, setup.firewalls, id); err != nil {
+			return readyMsg{index: index, err: err}
+		}
+		droplet, err := waitForActiveDroplet(ctx, client, id, action)
+		if err != nil {
+			return readyMsg{index: index, err: err}
+		}
+		if err := assignProject(ctx, client, setup.projectID, droplet); err != nil {
+			return readyMsg{index: index, droplet: droplet, err: err}
+		}
+		record, err := createRecord(ctx, client, setup.domain, droplet)
+		if err != nil {
+			return readyMsg{index: index, droplet: droplet, err: err}
+		}
+
+		if err := addToLoadBalancer(ctx, client, setup.loadBalancer, droplet.ID); err != nil {
+			return readyMsg{index: index, droplet: droplet, record: record, err: err}
+		}
+		assigned, err := assignReservedIP(ctx, client, setup.reservedIP, droplet)
+		if err != nil {
+			return readyMsg{index: index, droplet: droplet, record: record, err: err}
+		}
+
+		msg := readyMsg{index: index, droplet: droplet, record: record, reservedIP: assigned}
+		if isFQDN(droplet.Name) {
+			msg.ptr, msg.ptrErr = checkPTR(ctx, droplet)
+		}
+		return msg
+	}
+}
+
+// waitForActiveDroplet waits for the action creating the Droplet to
+// complete or, without one, for the Droplet to become active.
+func waitForActiveDroplet(ctx context.Context, client *godo.Client, id int, action string) (*godo.Droplet, error) {
+	if action != "" {
+		if err := util.WaitForActive(ctx, client, action); err != nil {
+			return nil, err
+		}
+		droplet, _, err := client.Droplets.Get(ctx, id)
+		return droplet, err
+	}
+
+	for {
+		droplet, _, err := client.Droplets.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if isActive(droplet.Status) {
+			return droplet, nil
+		}
+		time.Sleep(dropletPollInterval)
+	}
+}
+
+func (m model) creatingView() string {
+	var b strings.Builder
+
+	title := "Creating Droplet..."
+	if len(m.pending) == 0 && m.fields[volumeNameField].Value() != "" {
+		title = "Creating volume..."
+	}
+	if len(m.pending) == 1 && m.pending[0].active {
+		title = "Waiting for SSH..."
+	}
+	if len(m.pending) > 1 {
+		title = fmt.Sprintf("Creating %d Droplets...", len(m.pending))
+	}
+	fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render(title))
+
+	if len(m.pending) < 2 {
+		return b.String()
+	}
+
+	for _, p := range m.pending {
+		switch {
+		case p.active && !p.done:
+			fmt.Fprintf(&b, "  %s %s %s\n", m.spinner.View(), noStyle.Render(p.droplet.Name), placeholderStyle.Render("waiting for SSH"))
+		case !p.done:
+			fmt.Fprintf(&b, "  %s %s\n", m.spinner.View(), noStyle.Render(p.droplet.Name))
+		case p.err != nil:
+			fmt.Fprintf(&b, "  %s %s %s\n", errorStyle.Render("✗"), noStyle.Render(p.droplet.Name), errorStyle.Render(p.err.Error()))
+		default:
+			ip, _ := p.droplet.PublicIPv4()
+			fmt.Fprintf(&b, "  %s %s %s\n", focusedStyle.Render("✓"), noStyle.Render(p.droplet.Name), placeholderStyle.Render(ip))
+		}
+	}
+	b.WriteRune('\n')
+
+	return b.String()
+}
+
+// createSummary renders the final result of creating the Droplets.
+func createSummary(pending []pendingDroplet) string {
+	var b strings.Builder
+
+	var failed int
+	for _, p := range pending {
+		if p.err != nil {
+			failed++
+		}
+	}
+
+	switch {
+	case failed == len(pending) && len(pending) == 1:
+		return dropletErrorMsg(pending[0].err)
+	case failed > 0:
+		fmt.Fprintf(&b, "😞 %s\n\n", focusedStyle.Render(fmt.Sprintf("%d of %d Droplets failed", failed, len(pending))))
+	default:
+		fmt.Fprintf(&b, "🎉 💧 %s\n\n", focusedStyle.Render("Success!"))
+	}
+
+	for _, p := range pending {
+		if p.err != nil {
+			fmt.Fprintf(&b, "%s %s\n", focusedStyle.Render("Name:"), placeholderStyle.Render(p.droplet.Name))
+			fmt.Fprintf(&b, "%s %s\n\n", focusedStyle.Render("Error:"), errorStyle.Render(p.err.Error()))
+			continue
+		}
+		fmt.Fprint(&b, p.summary())
+		fmt.Fprint(&b, "\n")
+	}
+
+	return b.String()
+}
+
+// summary renders the details of the Droplet and the resources created
+// alongside it.
+func (p pendingDroplet) summary() string {
+	var b strings.Builder
+
+	b.WriteString(dropletSummary(p.droplet))
+	if p.volume != nil {
+		fmt.Fprintf(&b, "%s %s\n", focusedStyle.Render("Volume:"), placeholderStyle.Render(fmt.Sprintf("%s (%d GB, /mnt/%s)", p.volume.Name, p.volume.SizeGigaBytes, mountName(p.volume.Name))))
+	}
+	if p.record != "" {
+		fmt.Fprintf(&b, "%s %s\n", focusedStyle.Render("DNS:"), placeholderStyle.Render(p.record))
+	}
+	if p.firewalls != "" {
+		fmt.Fprintf(&b, "%s %s\n", focusedStyle.Render("Firewalls:"), placeholderStyle.Render(p.firewalls))
+	}
+	if p.loadBalancer != "" {
+		fmt.Fprintf(&b, "%s %s\n", focusedStyle.Render("Load balancer:"), placeholderStyle.Render(p.loadBalancer))
+	}
+	if p.reservedIP != "" {
+		fmt.Fprintf(&b, "%s %s\n", focusedStyle.Render("Reserved IP:"), placeholderStyle.Render(p.reservedIP))
+	}
+	switch {
+	case p.ptrErr != nil:
+		fmt.Fprintf(&b, "%s %s\n", focusedStyle.Render("Reverse DNS:"), errorStyle.Render(p.ptrErr.Error()))
+	case p.ptr != "":
+		fmt.Fprintf(&b, "%s %s\n", focusedStyle.Render("Reverse DNS:"), placeholderStyle.Render(p.ptr+" ✓"))
+	}
+	switch {
+	case p.health != "" && !p.healthDone:
+		fmt.Fprintf(&b, "%s %s\n", focusedStyle.Render("Health:"), placeholderStyle.Render(p.health+" checking..."))
+	case p.health != "" && p.healthErr != nil:
+		fmt.Fprintf(&b, "%s %s\n", focusedStyle.Render("Health:"), errorStyle.Render(p.health+" ✗ "+p.healthErr.Error()))
+	case p.health != "":
+		fmt.Fprintf(&b, "%s %s\n", focusedStyle.Render("Health:"), placeholderStyle.Render(p.health+" ✓"))
+	}
+	switch {
+	case p.hostKeyErr != nil:
+		fmt.Fprintf(&b, "%s %s\n", focusedStyle.Render("Host keys:"), errorStyle.Render(p.hostKeyErr.Error()))
+	case p.hostKeys != nil:
+		fmt.Fprintf(&b, "%s %s\n", focusedStyle.Render("Host keys:"), placeholderStyle.Render("pinned "+keyTypes(p.hostKeys)))
+	}
+	switch {
+	case p.uploadErr != nil:
+		fmt.Fprintf(&b, "%s %s\n", focusedStyle.Render("Upload:"), errorStyle.Render(p.uploadErr.Error()))
+	case p.uploaded:
+		fmt.Fprintf(&b, "%s %s\n", focusedStyle.Render("Upload:"), placeholderStyle.Render("ok"))
+	}
+	switch {
+	case p.hooked && !p.hookDone:
+		fmt.Fprintf(&b, "%s %s\n", focusedStyle.Render("Post-create hook:"), placeholderStyle.Render("running..."))
+	case p.hooked && p.hookErr != nil:
+		fmt.Fprintf(&b, "%s %s\n", focusedStyle.Render("Post-create hook:"), errorStyle.Render(p.hookErr.Error()))
+	case p.hooked:
+		fmt.Fprintf(&b, "%s %s\n", focusedStyle.Render("Post-create hook:"), placeholderStyle.Render("ok"))
+	}
+	switch {
+	case p.bootstrapped && p.bootstrapErr != nil:
+		fmt.Fprintf(&b, "%s %s\n", focusedStyle.Render("Bootstrap:"), errorStyle.Render(p.bootstrapErr.Error()))
+	case p.bootstrapped:
+		fmt.Fprintf(&b, "%s %s\n", focusedStyle.Render("Bootstrap:"), placeholderStyle.Render("ok"))
+	}
+
+	return b.String()
+}
+
+// dropletSummary renders the details of an active Droplet.
+func dropletSummary(droplet *godo.Droplet) string {
+	var b strings.Builder
+
+	pubIP, _ := droplet.PublicIPv4()
+	privIP, _ := droplet.PrivateIPv4()
+	pubIPv6, _ := droplet.PublicIPv6()
+
+	fmt.Fprintf(&b, "%s %s\n", focusedStyle.Render("Name:"), placeholderStyle.Render(droplet.Name))
+	fmt.Fprintf(&b, "%s %s\n", focusedStyle.Render("Price Monthly:"), placeholderStyle.Render(fmt.Sprintf("$%.2f", droplet.Size.PriceMonthly)))
+	fmt.Fprintf(&b, "%s %s\n", focusedStyle.Render("Region:"), placeholderStyle.Render(droplet.Region.Name))
+	fmt.Fprintf(&b, "%s %s\n", focusedStyle.Render("Size:"), placeholderStyle.Render(droplet.Size.Slug))
+	fmt.Fprintf(&b, "%s %s\n", focusedStyle.Render("Public IPv4:"), placeholderStyle.Render(pubIP))
+	fmt.Fprintf(&b, "%s %s\n", focusedStyle.Render("Private IPv4:"), placeholderStyle.Render(privIP))
+	if pubIPv6 != "" {
+		fmt.Fprintf(&b, "%s %s\n", focusedStyle.Render("Public IPv6:"), placeholderStyle.Render(pubIPv6))
+	}
+
+	return b.String()
+}
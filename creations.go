@@ -0,0 +1,199 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+)
+
+var pastCreationsKey = key.NewBinding(key.WithKeys("ctrl+p"), key.WithHelp("ctrl+p", "past creations"))
+
+// maxCreations is how many past creations are kept in the log.
+const maxCreations = 100
+
+// creation is an entry in the log of past creations: what was asked for and
+// how it turned out. Error is set if the request itself failed.
+type creation struct {
+	Time     time.Time       `yaml:"time"`
+	Context  string          `yaml:"context,omitempty"`
+	Spec     spec            `yaml:"spec"`
+	Droplets []loggedDroplet `yaml:"droplets,omitempty"`
+	Error    string          `yaml:"error,omitempty"`
+}
+
+// loggedDroplet is a Droplet that was created, or failed to be.
+type loggedDroplet struct {
+	Name  string `yaml:"name"`
+	ID    int    `yaml:"id,omitempty"`
+	IPv4  string `yaml:"ipv4,omitempty"`
+	IPv6  string `yaml:"ipv6,omitempty"`
+	Error string `yaml:"error,omitempty"`
+}
+
+// creationsMsg carries the log of past creations, newest first.
+type creationsMsg struct {
+	creations []creation
+	err       error
+}
+
+// creationsPath returns where the log of past creations is kept.
+func creationsPath() (string, error) {
+	dir, err := stateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "creations.yaml"), nil
+}
+
+// readCreations reads the log, which is empty until something's been
+// created.
+func readCreations() ([]creation, error) {
+	path, err := creationsPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var creations []creation
+	if err := yaml.Unmarshal(data, &creations); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return creations, nil
+}
+
+func fetchCreations() tea.Msg {
+	creations, err := readCreations()
+	return creationsMsg{creations: creations, err: err}
+}
+
+// logCreation adds how the create request turned out to the log, dropping
+// the oldest entries past maxCreations. Like the history, it's only a
+// convenience, so errors are ignored rather than interrupting.
+func (m model) logCreation(err error) tea.Cmd {
+	c := creation{Time: time.Now(), Context: m.context, Spec: m.spec()}
+	if err != nil {
+		c.Error = err.Error()
+	}
+	for _, p := range m.pending {
+		d := loggedDroplet{Name: p.droplet.Name, ID: p.droplet.ID}
+		d.IPv4, _ = p.droplet.PublicIPv4()
+		d.IPv6, _ = p.droplet.PublicIPv6()
+		if p.err != nil {
+			d.Error = p.err.Error()
+		}
+		c.Droplets = append(c.Droplets, d)
+	}
+
+	return func() tea.Msg {
+		path, err := creationsPath()
+		if err != nil {
+			return nil
+		}
+		creations, err := readCreations()
+		if err != nil {
+			return nil
+		}
+		creations = append([]creation{c}, creations...)
+		if len(creations) > maxCreations {
+			creations = creations[:maxCreations]
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			return nil
+		}
+		data, err := yaml.Marshal(creations)
+		if err != nil {
+			return nil
+		}
+		os.WriteFile(path, data, 0o600)
+
+		return nil
+	}
+}
+
+// creationItem lists a past creation, to load it into the form again.
+type creationItem struct {
+	index int
+	creation
+}
+
+func (c creationItem) Title() string {
+	s := c.Spec
+	title := s.Name
+	if s.Count > 1 {
+		title += fmt.Sprintf(" ×%d", s.Count)
+	}
+	return fmt.Sprintf("%s · %s · %s · %s", title, s.Region, s.Size, s.Image)
+}
+
+func (c creationItem) Description() string {
+	when := c.Time.Local().Format("2006-01-02 15:04")
+	if c.Context != "" {
+		when += " in " + c.Context
+	}
+	if c.Error != "" {
+		return when + " · failed: " + c.Error
+	}
+
+	var created, failed []string
+	for _, d := range c.Droplets {
+		if d.Error != "" {
+			failed = append(failed, d.Name)
+			continue
+		}
+		created = append(created, fmt.Sprintf("%s (%d, %s)", d.Name, d.ID, d.IPv4))
+	}
+	desc := when + " · created " + strings.Join(created, ", ")
+	if len(created) == 0 {
+		desc = when + " · none created"
+	}
+	if len(failed) > 0 {
+		desc += " · failed " + strings.Join(failed, ", ")
+	}
+	return desc
+}
+
+func (c creationItem) FilterValue() string {
+	return c.Spec.Name + " " + c.Spec.Region + " " + c.Spec.Size + " " + c.Spec.Image
+}
+
+func (c creationItem) Value() string { return strconv.Itoa(c.index) }
+
+func newCreationsPicker() *selectField {
+	return newSelectField("", "Past creations", "")
+}
+
+// updateCreations handles the list of past creations, returning false if
+// it isn't showing.
+func (m model) updateCreations(msg tea.KeyMsg) (tea.Model, tea.Cmd, bool) {
+	if !m.creations.Opened() {
+		return m, nil, false
+	}
+	if msg.String() == "ctrl+c" {
+		return m, m.quit(), true
+	}
+
+	_, cmd := m.creations.Update(msg)
+	if c, ok := m.creations.selected.(creationItem); ok && !m.creations.Opened() {
+		m.formErr = ""
+		m.fieldErrs = make(map[int]string)
+		m.notice = "Loaded " + c.Spec.Name + " from " + c.Time.Local().Format("2006-01-02 15:04")
+		m.edited = true
+		return m, tea.Batch(cmd, m.loadSpec(c.Spec)), true
+	}
+	return m, cmd, true
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+var openConnectionKey = key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "connection details"))
+
+// databasesPerPage is how many database clusters the databases screen
+// fetches at a time.
+const databasesPerPage = 20
+
+// databaseListMsg carries a page of the account's database clusters, along
+// with how many there are in all.
+type databaseListMsg struct {
+	page      int
+	databases []godo.Database
+	total     int
+	err       error
+}
+
+// fetchDatabaseList lists a page of the account's database clusters,
+// counting from 1.
+func fetchDatabaseList(client *godo.Client, page int) tea.Cmd {
+	return func() tea.Msg {
+		databases, resp, err := client.Databases.List(context.Background(), &godo.ListOptions{Page: page, PerPage: databasesPerPage})
+		if err != nil {
+			return databaseListMsg{page: page, err: err}
+		}
+
+		total := len(databases)
+		if resp.Meta != nil {
+			total = resp.Meta.Total
+		}
+		return databaseListMsg{page: page, databases: databases, total: total}
+	}
+}
+
+// databaseList is the screen listing the account's database clusters, a
+// page at a time.
+type databaseList struct {
+	table     table.Model
+	databases []godo.Database
+	page      int
+	total     int
+	loading   bool
+	err       error
+	// connection is set while showing a cluster's connection details.
+	connection *dbConnection
+}
+
+func newDatabaseList(width, height int) *databaseList {
+	l := &databaseList{
+		page: 1,
+		table: newListTable([]table.Column{
+			{Title: "Name", Width: 24},
+			{Title: "Engine", Width: 14},
+			{Title: "Region", Width: 8},
+			{Title: "Nodes", Width: 24},
+			{Title: "Status", Width: 12},
+		}),
+	}
+	l.SetSize(width, height)
+	return l
+}
+
+// SetSize fits the table to the screen, under the title and above the help.
+func (l *databaseList) SetSize(width, height int) {
+	setTableSize(&l.table, width, height)
+}
+
+// pages returns how many pages of database clusters there are.
+func (l *databaseList) pages() int {
+	return pageCount(l.total, databasesPerPage)
+}
+
+// setDatabaseList shows a page of database clusters once it's been fetched.
+func (m model) setDatabaseList(msg databaseListMsg) (tea.Model, tea.Cmd) {
+	l := m.databases
+	if l == nil {
+		return m, nil
+	}
+	// The last page was emptied, e.g. by deleting elsewhere, so go back one.
+	if msg.err == nil && len(msg.databases) == 0 && msg.page > 1 {
+		return m, fetchDatabaseList(m.client, msg.page-1)
+	}
+
+	l.loading = false
+	l.err = msg.err
+	if msg.err != nil {
+		return m, nil
+	}
+	l.page, l.databases, l.total = msg.page, msg.databases, msg.total
+	l.setRows()
+	if c := l.connection; c != nil {
+		for _, db := range l.databases {
+			if db.ID == c.db.ID {
+				c.db = db
+			}
+		}
+	}
+	return m, nil
+}
+
+// setRows fills the table in from the database clusters.
+func (l *databaseList) setRows() {
+	rows := make([]table.Row, len(l.databases))
+	for i, db := range l.databases {
+		engine := db.EngineSlug
+		if db.VersionSlug != "" {
+			engine += " " + db.VersionSlug
+		}
+		rows[i] = table.Row{db.Name, engine, db.RegionSlug, fmt.Sprintf("%d × %s", db.NumNodes, db.SizeSlug), db.Status}
+	}
+	l.table.SetRows(rows)
+	if l.table.Cursor() >= len(rows) {
+		l.table.GotoTop()
+	}
+}
+
+// selected returns the database cluster under the cursor, unless there are
+// none.
+func (l *databaseList) selected() (godo.Database, bool) {
+	i := l.table.Cursor()
+	if i < 0 || i >= len(l.databases) {
+		return godo.Database{}, false
+	}
+	return l.databases[i], true
+}
+
+// openDatabases shows the list of database clusters, fetching its first
+// page.
+func (m model) openDatabases() (model, tea.Cmd) {
+	m.databases = newDatabaseList(m.width, m.height-1)
+	m.databases.loading = true
+	m.notice = ""
+	m.formErr = ""
+	return m, tea.Batch(fetchDatabaseList(m.client, 1), m.spinner.Tick)
+}
+
+func (m model) updateDatabases(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.databases
+	if l.connection != nil {
+		return m.updateDBConnection(msg)
+	}
+
+	switch {
+	case msg.String() == "ctrl+c":
+		return m, m.quit()
+	case key.Matches(msg, closeListKeys):
+		return m.closeScreen()
+	case key.Matches(msg, screensKey):
+		return m.openScreens()
+	case l.loading:
+		return m, nil
+	case key.Matches(msg, openConnectionKey):
+		return m.openDBConnection()
+	case key.Matches(msg, refreshListKey):
+		l.loading = true
+		return m, fetchDatabaseList(m.client, l.page)
+	case key.Matches(msg, nextPageKey) && l.page < l.pages():
+		l.loading = true
+		return m, fetchDatabaseList(m.client, l.page+1)
+	case key.Matches(msg, prevPageKey) && l.page > 1:
+		l.loading = true
+		return m, fetchDatabaseList(m.client, l.page-1)
+	}
+
+	var cmd tea.Cmd
+	l.table, cmd = l.table.Update(msg)
+	return m, cmd
+}
+
+func (m model) databasesView() string {
+	l := m.databases
+	if l.connection != nil {
+		return m.dbConnectionView()
+	}
+
+	var b strings.Builder
+
+	title := focusedStyle.Render("Databases")
+	if l.total > 0 {
+		title += placeholderStyle.Render(fmt.Sprintf("  %s · page %d of %d", pluralize(l.total, "database cluster"), l.page, l.pages()))
+	}
+	b.WriteString(title + "\n\n")
+
+	switch {
+	case l.loading && l.databases == nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading database clusters..."))
+	case l.err != nil && l.databases == nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the database clusters: "+l.err.Error()))
+	case len(l.databases) == 0:
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("There are no database clusters in this account yet."))
+	default:
+		fmt.Fprintf(&b, "%s\n\n", l.table.View())
+	}
+
+	switch {
+	case l.loading && l.databases != nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading..."))
+	case l.err != nil && l.databases != nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the database clusters: "+l.err.Error()))
+	}
+	if m.notice != "" {
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render(m.notice))
+	}
+	if m.formErr != "" {
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(m.formErr))
+	}
+
+	help := []string{"↑/↓: move", "enter: connection details"}
+	if l.pages() > 1 {
+		help = append(help, "←/→: page")
+	}
+	help = append(help, "r: refresh", "tab: screens", m.closeScreenHelp())
+	b.WriteString(helpStyle.Render(strings.Join(help, " • ")))
+
+	return b.String()
+}
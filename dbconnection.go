@@ -0,0 +1,200 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+var (
+	copyDetailKey     = key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "copy"))
+	privateNetworkKey = key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "public/private network"))
+)
+
+// maskedPassword is shown in place of a password until it's revealed. It's
+// the same length whatever the password, so as not to give that away.
+const maskedPassword = "********"
+
+// dbConnection shows a database cluster's connection details, one of which
+// is chosen to copy.
+type dbConnection struct {
+	db godo.Database
+	// private shows the details for connecting over the VPC network.
+	private  bool
+	revealed bool
+	cursor   int
+}
+
+// dbDetail is one of a database cluster's connection details.
+type dbDetail struct {
+	label string
+	value string
+	// secret is set for the password and the URI, which includes it.
+	secret bool
+}
+
+// connection returns the connection the details are shown for.
+func (c *dbConnection) connection() *godo.DatabaseConnection {
+	if c.private {
+		return c.db.PrivateConnection
+	}
+	return c.db.Connection
+}
+
+// details returns the connection details for copying, leaving out any the
+// engine doesn't have, such as Redis's database name.
+func (c *dbConnection) details() []dbDetail {
+	conn := c.connection()
+	if conn == nil {
+		return nil
+	}
+
+	var details []dbDetail
+	for _, d := range []dbDetail{
+		{label: "Host", value: conn.Host},
+		{label: "Port", value: strconv.Itoa(conn.Port)},
+		{label: "User", value: conn.User},
+		{label: "Password", value: conn.Password, secret: true},
+		{label: "Database", value: conn.Database},
+		{label: "URI", value: conn.URI, secret: true},
+	} {
+		if d.value != "" && d.value != "0" {
+			details = append(details, d)
+		}
+	}
+	return details
+}
+
+// shown returns the detail as it's displayed, with the password masked
+// unless it's been revealed.
+func (c *dbConnection) shown(d dbDetail) string {
+	if !d.secret || c.revealed {
+		return d.value
+	}
+	password := c.connection().Password
+	if d.value == password {
+		return maskedPassword
+	}
+	if password == "" {
+		return d.value
+	}
+	return strings.Replace(d.value, ":"+password+"@", ":"+maskedPassword+"@", 1)
+}
+
+// openDBConnection shows the connection details of the database cluster
+// under the cursor.
+func (m model) openDBConnection() (model, tea.Cmd) {
+	l := m.databases
+	db, ok := l.selected()
+	if !ok {
+		return m, nil
+	}
+	l.connection = &dbConnection{db: db}
+	m.notice = ""
+	m.formErr = ""
+	return m, nil
+}
+
+func (m model) updateDBConnection(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.databases
+	c := l.connection
+	details := c.details()
+
+	switch {
+	case msg.String() == "ctrl+c":
+		return m, m.quit()
+	case msg.String() == "esc":
+		l.connection = nil
+		m.formErr = ""
+		return m, nil
+	case msg.String() == "up" || msg.String() == "k":
+		if c.cursor > 0 {
+			c.cursor--
+		}
+	case msg.String() == "down" || msg.String() == "j":
+		if c.cursor < len(details)-1 {
+			c.cursor++
+		}
+	case key.Matches(msg, revealKey):
+		c.revealed = !c.revealed
+	case key.Matches(msg, privateNetworkKey):
+		if c.db.PrivateConnection == nil {
+			m.formErr = fmt.Sprintf("%s can't be connected to over a private network", c.db.Name)
+			return m, nil
+		}
+		c.private = !c.private
+		c.cursor = 0
+	case key.Matches(msg, copyDetailKey) && c.cursor < len(details):
+		m.formErr = ""
+		d := details[c.cursor]
+		if d.secret {
+			what := "the URI"
+			if d.label == "Password" {
+				what = "the password"
+			}
+			return m, copySecret(d.value, what)
+		}
+		return m, copyToClipboard(d.value)
+	case key.Matches(msg, refreshListKey):
+		l.loading = true
+		return m, fetchDatabaseList(m.client, l.page)
+	}
+	return m, nil
+}
+
+func (m model) dbConnectionView() string {
+	l := m.databases
+	c := l.connection
+	var b strings.Builder
+
+	network := "public network"
+	if c.private {
+		network = "private network"
+	}
+	fmt.Fprintf(&b, "%s\n\n", focusedStyle.Render(c.db.Name)+placeholderStyle.Render(fmt.Sprintf("  %s %s · %s · %s", c.db.EngineSlug, c.db.VersionSlug, c.db.RegionSlug, network)))
+
+	details := c.details()
+	if len(details) == 0 {
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render(fmt.Sprintf("%s has no connection details yet; it's %s.", c.db.Name, c.db.Status)))
+	}
+	for i, d := range details {
+		line := fmt.Sprintf("  %-9s %s", d.label+":", c.shown(d))
+		if i == c.cursor {
+			line = focusedStyle.Render("> " + line[2:])
+		}
+		fmt.Fprintf(&b, "%s\n", line)
+	}
+	if len(details) > 0 {
+		b.WriteString("\n")
+	}
+	if conn := c.connection(); conn != nil && conn.SSL {
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("Connections must use TLS."))
+	}
+
+	if l.loading {
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading..."))
+	}
+	if m.notice != "" {
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render(m.notice))
+	}
+	if m.formErr != "" {
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(m.formErr))
+	}
+
+	reveal := "ctrl+r: reveal password"
+	if c.revealed {
+		reveal = "ctrl+r: hide password"
+	}
+	help := []string{"↑/↓: move", "y: copy", reveal}
+	if c.db.PrivateConnection != nil {
+		help = append(help, "p: public/private network")
+	}
+	help = append(help, "r: refresh", "esc: back")
+	b.WriteString(helpStyle.Render(strings.Join(help, " • ")))
+
+	return b.String()
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+var deleteKey = key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "delete"))
+
+const (
+	// deletePollInterval is how long to wait between checking whether a
+	// deleted Droplet has gone.
+	deletePollInterval = 2 * time.Second
+	// deleteTimeout is how long to wait for it to go before refreshing the
+	// list anyway.
+	deleteTimeout = 2 * time.Minute
+)
+
+// dropletDeletedMsg reports that a Droplet has been deleted, or why not.
+type dropletDeletedMsg struct {
+	id   int
+	name string
+	err  error
+}
+
+// deletePrompt asks for a Droplet's name before deleting it.
+type deletePrompt struct {
+	droplet godo.Droplet
+	name    *textField
+}
+
+// deleteDroplet deletes the Droplet and waits until it's no longer found,
+// since it goes on being listed for a little while after the request is
+// accepted.
+func deleteDroplet(client *godo.Client, id int, name string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), deleteTimeout)
+		defer cancel()
+
+		if _, err := client.Droplets.Delete(ctx, id); err != nil {
+			return dropletDeletedMsg{id: id, name: name, err: err}
+		}
+		for {
+			_, resp, _ := client.Droplets.Get(ctx, id)
+			if resp != nil && resp.StatusCode == http.StatusNotFound {
+				return dropletDeletedMsg{id: id, name: name}
+			}
+
+			select {
+			case <-ctx.Done():
+				return dropletDeletedMsg{id: id, name: name}
+			case <-time.After(deletePollInterval):
+			}
+		}
+	}
+}
+
+// promptDelete asks to confirm deleting the Droplet under the cursor.
+func (m model) promptDelete() (model, tea.Cmd) {
+	if m.blockChanges("Droplets", "deleted") {
+		return m, nil
+	}
+
+	l := m.list
+	droplet, ok := l.selected()
+	if !ok {
+		return m, nil
+	}
+	name := newOptionalTextField("Type its name to delete it: ", droplet.Name)
+	name.CharLimit = 255
+	l.confirmDelete = &deletePrompt{droplet: droplet, name: name}
+	m.notice = ""
+	m.formErr = ""
+	return m, name.Focus()
+}
+
+func (m model) updateDeletePrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.list
+	p := l.confirmDelete
+	switch msg.String() {
+	case "ctrl+c":
+		return m, m.quit()
+	case "esc":
+		l.confirmDelete = nil
+		m.formErr = ""
+		return m, nil
+	case "enter":
+		if strings.TrimSpace(p.name.Model.Value()) != p.droplet.Name {
+			m.formErr = "the name doesn't match; type it exactly, or press esc to cancel"
+			return m, nil
+		}
+		l.confirmDelete = nil
+		m.formErr = ""
+		l.busy[p.droplet.ID] = "deleting"
+		l.setRows()
+		return m, deleteDroplet(m.client, p.droplet.ID, p.droplet.Name)
+	}
+
+	_, cmd := p.name.Update(msg)
+	return m, cmd
+}
+
+// deleted takes a Droplet off the list once it's been deleted, and refreshes
+// the list in case anything else has changed meanwhile.
+func (m model) deleted(msg dropletDeletedMsg) (tea.Model, tea.Cmd) {
+	l := m.list
+	if l != nil {
+		delete(l.busy, msg.id)
+		if msg.err == nil {
+			for i, d := range l.droplets {
+				if d.ID == msg.id {
+					l.droplets = append(l.droplets[:i:i], l.droplets[i+1:]...)
+					l.total--
+					break
+				}
+			}
+		}
+		l.setRows()
+	}
+	if msg.err != nil {
+		m.formErr = fmt.Sprintf("couldn't delete %s: %s", msg.name, msg.err)
+		return m, nil
+	}
+
+	cmd := m.toast("Deleted " + msg.name)
+	if l == nil {
+		return m, cmd
+	}
+	l.loading = true
+	return m, tea.Batch(cmd, fetchDroplets(m.client, l.page))
+}
+
+func (m model) deletePromptView() string {
+	p := m.list.confirmDelete
+	var b strings.Builder
+
+	ip, _ := p.droplet.PublicIPv4()
+	fmt.Fprintf(&b, "%s\n", focusedStyle.Render(fmt.Sprintf("Delete %s (%d, %s)?", p.droplet.Name, p.droplet.ID, ip)))
+	fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("This destroys the Droplet and everything on its disk, and can't be undone."))
+	fmt.Fprintf(&b, "%s\n\n", p.name.View())
+
+	return b.String()
+}
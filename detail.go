@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+var (
+	openDetailKey   = key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "details"))
+	closeDetailKeys = key.NewBinding(key.WithKeys("esc", "q", "backspace"), key.WithHelp("esc", "back"))
+)
+
+// dropletDetail is the pane showing everything about a Droplet picked from
+// the list, until it's been fetched.
+type dropletDetail struct {
+	id      int
+	droplet *godo.Droplet
+	volumes []godo.Volume
+	err     error
+}
+
+// dropletDetailMsg carries a Droplet fetched for the detail pane, along with
+// its volumes.
+type dropletDetailMsg struct {
+	id      int
+	droplet *godo.Droplet
+	volumes []godo.Volume
+	err     error
+}
+
+// fetchDropletDetail gets the Droplet afresh, since the list may be out of
+// date, and looks up its attached volumes. A volume that can't be looked up
+// is shown by its ID alone.
+func fetchDropletDetail(client *godo.Client, id int) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+
+		droplet, _, err := client.Droplets.Get(ctx, id)
+		if err != nil {
+			return dropletDetailMsg{id: id, err: err}
+		}
+
+		volumes := make([]godo.Volume, len(droplet.VolumeIDs))
+		for i, v := range droplet.VolumeIDs {
+			volumes[i].ID = v
+			if volume, _, err := client.Storage.GetVolume(ctx, v); err == nil {
+				volumes[i] = *volume
+			}
+		}
+		return dropletDetailMsg{id: id, droplet: droplet, volumes: volumes}
+	}
+}
+
+// openDetail shows the detail pane for the Droplet under the cursor.
+func (m model) openDetail() (model, tea.Cmd) {
+	l := m.list
+	i := l.table.Cursor()
+	if i < 0 || i >= len(l.droplets) {
+		return m, nil
+	}
+	l.detail = &dropletDetail{id: l.droplets[i].ID}
+	return m, fetchDropletDetail(m.client, l.detail.id)
+}
+
+func (m model) updateDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	d := m.list.detail
+	switch {
+	case msg.String() == "ctrl+c":
+		return m, m.quit()
+	case key.Matches(msg, closeDetailKeys):
+		m.list.detail = nil
+	case key.Matches(msg, refreshListKey) && (d.droplet != nil || d.err != nil):
+		m.list.detail = &dropletDetail{id: d.id}
+		return m, fetchDropletDetail(m.client, d.id)
+	}
+	return m, nil
+}
+
+func (m model) detailView() string {
+	d := m.list.detail
+	var b strings.Builder
+
+	switch {
+	case d.err != nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(fmt.Sprintf("couldn't get Droplet %d: %s", d.id, d.err)))
+	case d.droplet == nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading the Droplet..."))
+	default:
+		fmt.Fprintf(&b, "%s\n\n%s\n", focusedStyle.Render(d.droplet.Name), dropletDetails(d.droplet, d.volumes))
+	}
+
+	b.WriteString(helpStyle.Render("r: refresh • esc: back"))
+
+	return b.String()
+}
+
+// dropletDetails describes everything about the Droplet that the API does,
+// one row at a time.
+func dropletDetails(droplet *godo.Droplet, volumes []godo.Volume) string {
+	var b strings.Builder
+	row := func(label, value string) {
+		fmt.Fprintf(&b, "%s %s\n", focusedStyle.Render(fmt.Sprintf("%-14s", label+":")), placeholderStyle.Render(value))
+	}
+
+	row("ID", fmt.Sprint(droplet.ID))
+	status := droplet.Status
+	if droplet.Locked {
+		status += " (locked)"
+	}
+	row("Status", status)
+	if droplet.Region != nil {
+		row("Region", fmt.Sprintf("%s (%s)", droplet.Region.Name, droplet.Region.Slug))
+	}
+	size := fmt.Sprintf("%s · %s, %s, %s", droplet.SizeSlug, pluralize(droplet.Vcpus, "vCPU"), formatMemory(droplet.Memory), formatDisk(droplet.Disk))
+	if droplet.Size != nil {
+		size += fmt.Sprintf(" · $%.2f/mo", droplet.Size.PriceMonthly)
+	}
+	row("Size", size)
+	if i := droplet.Image; i != nil {
+		image := strings.TrimSpace(i.Distribution + " " + i.Name)
+		if i.Slug != "" {
+			image += " (" + i.Slug + ")"
+		} else {
+			image += fmt.Sprintf(" (%d)", i.ID)
+		}
+		row("Image", image)
+	}
+	if k := droplet.Kernel; k != nil {
+		row("Kernel", k.Name)
+	} else {
+		row("Kernel", "managed by the Droplet")
+	}
+	if len(droplet.Features) > 0 {
+		row("Features", strings.Join(droplet.Features, ", "))
+	}
+	if len(droplet.Tags) > 0 {
+		row("Tags", strings.Join(droplet.Tags, ", "))
+	}
+	if droplet.VPCUUID != "" {
+		row("VPC", droplet.VPCUUID)
+	}
+
+	if n := droplet.Networks; n != nil {
+		for _, v4 := range n.V4 {
+			ip := v4.IPAddress
+			if v4.Netmask != "" {
+				ip += ", netmask " + v4.Netmask
+			}
+			row("IPv4 "+v4.Type, withGateway(ip, v4.Gateway))
+		}
+		for _, v6 := range n.V6 {
+			ip := v6.IPAddress
+			if v6.Netmask != 0 {
+				ip += fmt.Sprintf("/%d", v6.Netmask)
+			}
+			row("IPv6 "+v6.Type, withGateway(ip, v6.Gateway))
+		}
+	}
+
+	for _, v := range volumes {
+		if v.Name == "" {
+			row("Volume", v.ID)
+			continue
+		}
+		row("Volume", fmt.Sprintf("%s (%d GB)", v.Name, v.SizeGigaBytes))
+	}
+
+	if created, err := time.Parse(time.RFC3339, droplet.Created); err == nil {
+		row("Created", created.Local().Format("2006-01-02 15:04"))
+	}
+
+	return b.String()
+}
+
+func withGateway(ip, gateway string) string {
+	if gateway == "" {
+		return ip
+	}
+	return ip + ", gateway " + gateway
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// doctlConfig is the part of doctl's config file that holds its credentials.
+type doctlConfig struct {
+	AccessToken  string            `yaml:"access-token"`
+	AuthContexts map[string]string `yaml:"auth-contexts"`
+	Context      string            `yaml:"context"`
+}
+
+// doctlConfigPath returns where doctl keeps its config file on this platform.
+func doctlConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "doctl", "config.yaml"), nil
+}
+
+// doctlContexts returns doctl's auth contexts along with the name of its
+// current one. There are none if doctl hasn't been set up.
+func doctlContexts() ([]authContext, string, error) {
+	path, err := doctlConfigPath()
+	if err != nil {
+		return nil, "", err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, "", nil
+	} else if err != nil {
+		return nil, "", err
+	}
+
+	var cfg doctlConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, "", fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	var contexts []authContext
+	if cfg.AccessToken != "" {
+		contexts = append(contexts, authContext{name: "default", source: "doctl", token: cfg.AccessToken})
+	}
+	var names []string
+	for name := range cfg.AuthContexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if token := cfg.AuthContexts[name]; token != "" && name != "default" {
+			contexts = append(contexts, authContext{name: name, source: "doctl", token: token})
+		}
+	}
+
+	current := cfg.Context
+	if current == "" {
+		current = "default"
+	}
+
+	return contexts, current, nil
+}
+
+// shellSafe matches arguments that don't need quoting.
+var shellSafe = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+// shellQuote quotes s for a POSIX shell, if it needs it.
+func shellQuote(s string) string {
+	if shellSafe.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// doctlExport renders the doctl commands that create the same Droplets, with
+// the volume created first if there is one.
+func (m model) doctlExport() string {
+	var b strings.Builder
+
+	req := m.droplet
+	flags := [][]string{
+		{"--region", req.Region},
+		{"--size", req.Size},
+		{"--image", imageRef(req.Image)},
+	}
+	if len(req.SSHKeys) > 0 {
+		keys := make([]string, len(req.SSHKeys))
+		for i, k := range req.SSHKeys {
+			if k.Fingerprint != "" {
+				keys[i] = k.Fingerprint
+			} else {
+				keys[i] = strconv.Itoa(k.ID)
+			}
+		}
+		flags = append(flags, []string{"--ssh-keys", strings.Join(keys, ",")})
+	}
+	if len(req.Tags) > 0 {
+		flags = append(flags, []string{"--tag-names", strings.Join(req.Tags, ",")})
+	}
+	if req.VPCUUID != "" {
+		flags = append(flags, []string{"--vpc-uuid", req.VPCUUID})
+	}
+	if id := m.projectID(); id != "" {
+		flags = append(flags, []string{"--project-id", id})
+	}
+	if req.Backups {
+		flags = append(flags, []string{"--enable-backups"})
+	}
+	if req.Monitoring {
+		flags = append(flags, []string{"--enable-monitoring"})
+	}
+	if req.IPv6 {
+		flags = append(flags, []string{"--enable-ipv6"})
+	}
+	if req.UserData != "" {
+		flags = append(flags, []string{"--user-data", req.UserData})
+	}
+
+	if m.volume != nil {
+		fmt.Fprintf(&b, "VOLUME_ID=$(doctl compute volume create %s --region %s --size %dGiB --format ID --no-header)\n\n",
+			shellQuote(m.volume.Name), shellQuote(m.volume.Region), m.volume.SizeGigaBytes)
+	}
+
+	b.WriteString("doctl compute droplet create")
+	for _, name := range m.names {
+		b.WriteString(" " + shellQuote(name))
+	}
+	// Put each flag on its own line, with its value.
+	for _, f := range flags {
+		b.WriteString(" \\\n  " + f[0])
+		for _, v := range f[1:] {
+			b.WriteString(" " + shellQuote(v))
+		}
+	}
+	if m.volume != nil {
+		b.WriteString(" \\\n  --volumes \"$VOLUME_ID\"")
+	}
+	b.WriteString(" \\\n  --wait\n")
+
+	if domain := m.fields[domainField].Value(); domain != "" {
+		for _, name := range m.names {
+			fmt.Fprintf(&b, "\ndoctl compute domain records create %s \\\n  --record-type A \\\n  --record-name %s \\\n  --record-data \"$(doctl compute droplet get %s --format PublicIPv4 --no-header)\" \\\n  --record-ttl %d\n",
+				shellQuote(domain), shellQuote(recordName(name, domain)), shellQuote(name), dnsTTL)
+		}
+	}
+
+	if firewalls := m.fields[firewallsField].(*multiSelectField).Values(); len(firewalls) > 0 {
+		for _, id := range firewalls {
+			fmt.Fprintf(&b, "\ndoctl compute firewall add-droplets %s \\\n  --droplet-ids \"%s\"\n", shellQuote(id), dropletIDs(m.names))
+		}
+	}
+
+	if lb := m.fields[loadBalancerField].Value(); lb != "" {
+		fmt.Fprintf(&b, "\ndoctl compute load-balancer add-droplets %s \\\n  --droplet-ids \"%s\"\n", shellQuote(lb), dropletIDs(m.names))
+	}
+
+	switch ip := m.fields[reservedIPField].Value(); ip {
+	case "":
+	case newReservedIP:
+		for _, name := range m.names {
+			fmt.Fprintf(&b, "\ndoctl compute reserved-ip create \\\n  --droplet-id \"$(doctl compute droplet get %s --format ID --no-header)\"\n", shellQuote(name))
+		}
+	default:
+		fmt.Fprintf(&b, "\ndoctl compute reserved-ip-action assign %s \"$(doctl compute droplet get %s --format ID --no-header)\"\n", shellQuote(ip), shellQuote(m.names[0]))
+	}
+
+	return b.String()
+}
+
+// dropletIDs returns a comma-separated list of command substitutions that look
+// up the IDs of the named Droplets once they've been created.
+func dropletIDs(names []string) string {
+	ids := make([]string, len(names))
+	for i, name := range names {
+		ids[i] = "$(doctl compute droplet get " + shellQuote(name) + " --format ID --no-header)"
+	}
+	return strings.Join(ids, ",")
+}
@@ -0,0 +1,462 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+var createDomainKey = key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "create"))
+
+// domainsPerPage is how many domains the domains screen fetches at a time.
+const domainsPerPage = 20
+
+// domainListMsg carries a page of the account's domains, along with how
+// many there are in all.
+type domainListMsg struct {
+	page    int
+	domains []godo.Domain
+	total   int
+	err     error
+}
+
+// fetchDomainList lists a page of the account's domains, counting from 1.
+func fetchDomainList(client *godo.Client, page int) tea.Cmd {
+	return func() tea.Msg {
+		domains, resp, err := client.Domains.List(context.Background(), &godo.ListOptions{Page: page, PerPage: domainsPerPage})
+		if err != nil {
+			return domainListMsg{page: page, err: err}
+		}
+
+		total := len(domains)
+		if resp.Meta != nil {
+			total = resp.Meta.Total
+		}
+		return domainListMsg{page: page, domains: domains, total: total}
+	}
+}
+
+// domainDoneMsg reports that a change to a domain has finished. verb and
+// done describe it, e.g. "delete" and "Deleted".
+type domainDoneMsg struct {
+	name string
+	verb string
+	done string
+	err  error
+}
+
+// domainList is the screen listing the domains the account manages the DNS
+// of, a page at a time.
+type domainList struct {
+	table   table.Model
+	domains []godo.Domain
+	page    int
+	total   int
+	loading bool
+	err     error
+	// create is set while adding a domain, and confirmDelete while asking
+	// to confirm deleting one.
+	create        *domainCreate
+	confirmDelete *domainDeletePrompt
+	// records is set while showing the records of one of the domains.
+	records *recordList
+	// busy holds the status to show for the domains that are being added or
+	// deleted, by name, until they're done.
+	busy map[string]string
+}
+
+func newDomainList(width, height int) *domainList {
+	l := &domainList{
+		page: 1,
+		busy: make(map[string]string),
+		table: newListTable([]table.Column{
+			{Title: "Domain", Width: 48},
+			{Title: "TTL", Width: 8},
+			{Title: "Status", Width: 10},
+		}),
+	}
+	l.SetSize(width, height)
+	return l
+}
+
+// SetSize fits the table to the screen, under the title and above the help.
+func (l *domainList) SetSize(width, height int) {
+	setTableSize(&l.table, width, height)
+	if l.records != nil {
+		l.records.SetSize(width, height)
+	}
+}
+
+// pages returns how many pages of domains there are.
+func (l *domainList) pages() int {
+	return pageCount(l.total, domainsPerPage)
+}
+
+// setDomainList shows a page of domains once it's been fetched.
+func (m model) setDomainList(msg domainListMsg) (tea.Model, tea.Cmd) {
+	l := m.domains
+	if l == nil {
+		return m, nil
+	}
+	// The last page was emptied, e.g. by deleting, so go back one.
+	if msg.err == nil && len(msg.domains) == 0 && msg.page > 1 {
+		return m, fetchDomainList(m.client, msg.page-1)
+	}
+
+	l.loading = false
+	l.err = msg.err
+	if msg.err != nil {
+		return m, nil
+	}
+	l.page, l.domains, l.total = msg.page, msg.domains, msg.total
+	l.setRows()
+	return m, nil
+}
+
+// setRows fills the table in from the domains.
+func (l *domainList) setRows() {
+	rows := make([]table.Row, len(l.domains))
+	for i, d := range l.domains {
+		status := ""
+		if s, ok := l.busy[d.Name]; ok {
+			status = s + "…"
+		}
+		rows[i] = table.Row{d.Name, strconv.Itoa(d.TTL), status}
+	}
+	l.table.SetRows(rows)
+	if l.table.Cursor() >= len(rows) {
+		l.table.GotoTop()
+	}
+}
+
+// selected returns the domain under the cursor, unless there are none or
+// it's busy.
+func (l *domainList) selected() (godo.Domain, bool) {
+	i := l.table.Cursor()
+	if i < 0 || i >= len(l.domains) {
+		return godo.Domain{}, false
+	}
+	_, busy := l.busy[l.domains[i].Name]
+	return l.domains[i], !busy
+}
+
+// openDomains shows the list of domains, fetching its first page.
+func (m model) openDomains() (model, tea.Cmd) {
+	m.domains = newDomainList(m.width, m.height-1)
+	m.domains.loading = true
+	m.notice = ""
+	m.formErr = ""
+	return m, tea.Batch(fetchDomainList(m.client, 1), m.spinner.Tick)
+}
+
+func (m model) updateDomains(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.domains
+	if l.records != nil {
+		return m.updateRecords(msg)
+	}
+	if l.create != nil {
+		return m.updateDomainCreate(msg)
+	}
+	if l.confirmDelete != nil {
+		return m.updateDomainDelete(msg)
+	}
+
+	switch {
+	case msg.String() == "ctrl+c":
+		return m, m.quit()
+	case key.Matches(msg, closeListKeys):
+		return m.closeScreen()
+	case key.Matches(msg, screensKey):
+		return m.openScreens()
+	case key.Matches(msg, createDomainKey):
+		return m.openDomainCreate()
+	case l.loading:
+		return m, nil
+	case key.Matches(msg, openRecordsKey):
+		return m.openRecords()
+	case key.Matches(msg, deleteKey):
+		return m.promptDomainDelete()
+	case key.Matches(msg, refreshListKey):
+		l.loading = true
+		return m, fetchDomainList(m.client, l.page)
+	case key.Matches(msg, nextPageKey) && l.page < l.pages():
+		l.loading = true
+		return m, fetchDomainList(m.client, l.page+1)
+	case key.Matches(msg, prevPageKey) && l.page > 1:
+		l.loading = true
+		return m, fetchDomainList(m.client, l.page-1)
+	}
+
+	var cmd tea.Cmd
+	l.table, cmd = l.table.Update(msg)
+	return m, cmd
+}
+
+// domainDone refreshes the list once a change to a domain has finished.
+func (m model) domainDone(msg domainDoneMsg) (tea.Model, tea.Cmd) {
+	l := m.domains
+	if l != nil {
+		delete(l.busy, msg.name)
+		l.setRows()
+	}
+	if msg.err != nil {
+		m.formErr = fmt.Sprintf("couldn't %s %s: %s", msg.verb, msg.name, msg.err)
+		return m, nil
+	}
+
+	cmd := m.toast(fmt.Sprintf("%s %s", msg.done, msg.name))
+	if l == nil {
+		return m, cmd
+	}
+	l.loading = true
+	return m, tea.Batch(cmd, fetchDomainList(m.client, l.page))
+}
+
+func (m model) domainsView() string {
+	l := m.domains
+	if l.records != nil {
+		return m.recordsView()
+	}
+
+	var b strings.Builder
+
+	title := focusedStyle.Render("Domains")
+	if l.total > 0 {
+		title += placeholderStyle.Render(fmt.Sprintf("  %s · page %d of %d", pluralize(l.total, "domain"), l.page, l.pages()))
+	}
+	b.WriteString(title + "\n\n")
+
+	switch {
+	case l.loading && l.domains == nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading domains..."))
+	case l.err != nil && l.domains == nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the domains: "+l.err.Error()))
+	case len(l.domains) == 0:
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("This account doesn't manage any domains yet."))
+	default:
+		fmt.Fprintf(&b, "%s\n\n", l.table.View())
+	}
+
+	switch {
+	case l.loading && l.domains != nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading..."))
+	case l.err != nil && l.domains != nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the domains: "+l.err.Error()))
+	}
+	if m.notice != "" {
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render(m.notice))
+	}
+	if m.formErr != "" {
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(m.formErr))
+	}
+
+	switch {
+	case l.create != nil:
+		b.WriteString(m.domainCreateView())
+		return b.String()
+	case l.confirmDelete != nil:
+		b.WriteString(m.domainDeleteView())
+		b.WriteString(helpStyle.Render("enter: delete • esc: cancel"))
+		return b.String()
+	}
+
+	help := []string{"↑/↓: move", "enter: records", "c: add", "d: delete"}
+	if l.pages() > 1 {
+		help = append(help, "←/→: page")
+	}
+	help = append(help, "r: refresh", "tab: screens", m.closeScreenHelp())
+	b.WriteString(helpStyle.Render(strings.Join(help, " • ")))
+
+	return b.String()
+}
+
+// domainCreate asks for the domain to add and, optionally, the IP address to
+// point it at.
+type domainCreate struct {
+	name *textField
+	// ip is set once the name's been given.
+	ip *textField
+}
+
+// openDomainCreate starts adding a domain.
+func (m model) openDomainCreate() (model, tea.Cmd) {
+	if m.blockChanges("domains", "added") {
+		return m, nil
+	}
+
+	name := newOptionalTextField("Domain: ", "example.com")
+	name.CharLimit = 253
+	m.domains.create = &domainCreate{name: name}
+	m.notice = ""
+	m.formErr = ""
+	return m, name.Focus()
+}
+
+func (m model) updateDomainCreate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.domains
+	c := l.create
+	if msg.String() == "ctrl+c" {
+		return m, m.quit()
+	}
+
+	if c.ip != nil {
+		switch msg.String() {
+		case "esc":
+			// Back to the name.
+			c.ip = nil
+			m.formErr = ""
+			return m, c.name.Focus()
+		case "enter":
+			ip := strings.TrimSpace(c.ip.Value())
+			if ip != "" && net.ParseIP(ip) == nil {
+				m.formErr = fmt.Sprintf("%q isn't an IP address", ip)
+				return m, nil
+			}
+			name := strings.TrimSpace(c.name.Value())
+			l.create = nil
+			m.formErr = ""
+			l.busy[name] = "adding"
+			return m, createDomain(m.client, name, ip)
+		}
+		_, cmd := c.ip.Update(msg)
+		return m, cmd
+	}
+
+	switch msg.String() {
+	case "esc":
+		l.create = nil
+		m.formErr = ""
+		return m, nil
+	case "enter":
+		name := strings.TrimSpace(c.name.Value())
+		if err := checkDomainName(name); err != "" {
+			m.formErr = err
+			return m, nil
+		}
+		m.formErr = ""
+		c.name.Blur()
+		c.ip = newOptionalTextField("Point it at (optional): ", "an IP address for its A record")
+		c.ip.CharLimit = 45
+		return m, c.ip.Focus()
+	}
+	_, cmd := c.name.Update(msg)
+	return m, cmd
+}
+
+// checkDomainName returns an error message if the name isn't a domain that
+// can be added, e.g. example.com.
+func checkDomainName(name string) string {
+	if name == "" {
+		return "type the domain to add, e.g. example.com"
+	}
+	if err := checkHostname(name); err != "" {
+		return err
+	}
+	if !strings.Contains(name, ".") {
+		return fmt.Sprintf("%q isn't a domain; add its top-level domain, e.g. %s.com", name, name)
+	}
+	return ""
+}
+
+// createDomain adds the domain, with an A record for the IP address if
+// there is one.
+func createDomain(client *godo.Client, name, ip string) tea.Cmd {
+	return func() tea.Msg {
+		_, _, err := client.Domains.Create(context.Background(), &godo.DomainCreateRequest{Name: name, IPAddress: ip})
+		return domainDoneMsg{name: name, verb: "add", done: "Added", err: err}
+	}
+}
+
+func (m model) domainCreateView() string {
+	c := m.domains.create
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s\n\n", focusedStyle.Render("Add a domain"))
+	fmt.Fprintf(&b, "%s\n", c.name.View())
+	if c.ip != nil {
+		fmt.Fprintf(&b, "%s\n\n", c.ip.View())
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("Point its nameservers at ns1, ns2 and ns3.digitalocean.com at its registrar for its records to be served."))
+		b.WriteString(helpStyle.Render("enter: add • esc: back"))
+	} else {
+		b.WriteString("\n" + helpStyle.Render("enter: next • esc: cancel"))
+	}
+
+	return b.String()
+}
+
+// domainDeletePrompt asks for a domain's name before deleting it.
+type domainDeletePrompt struct {
+	domain godo.Domain
+	name   *textField
+}
+
+// promptDomainDelete asks to confirm deleting the domain under the cursor.
+func (m model) promptDomainDelete() (model, tea.Cmd) {
+	if m.blockChanges("domains", "deleted") {
+		return m, nil
+	}
+
+	l := m.domains
+	domain, ok := l.selected()
+	if !ok {
+		return m, nil
+	}
+	name := newOptionalTextField("Type it to delete it: ", domain.Name)
+	name.CharLimit = 253
+	l.confirmDelete = &domainDeletePrompt{domain: domain, name: name}
+	m.notice = ""
+	m.formErr = ""
+	return m, name.Focus()
+}
+
+func (m model) updateDomainDelete(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.domains
+	p := l.confirmDelete
+	switch msg.String() {
+	case "ctrl+c":
+		return m, m.quit()
+	case "esc":
+		l.confirmDelete = nil
+		m.formErr = ""
+		return m, nil
+	case "enter":
+		if strings.TrimSpace(p.name.Model.Value()) != p.domain.Name {
+			m.formErr = "the domain doesn't match; type it exactly, or press esc to cancel"
+			return m, nil
+		}
+		l.confirmDelete = nil
+		m.formErr = ""
+		l.busy[p.domain.Name] = "deleting"
+		l.setRows()
+		return m, deleteDomain(m.client, p.domain.Name)
+	}
+
+	_, cmd := p.name.Update(msg)
+	return m, cmd
+}
+
+// deleteDomain deletes the domain along with all of its records.
+func deleteDomain(client *godo.Client, name string) tea.Cmd {
+	return func() tea.Msg {
+		_, err := client.Domains.Delete(context.Background(), name)
+		return domainDoneMsg{name: name, verb: "delete", done: "Deleted", err: err}
+	}
+}
+
+func (m model) domainDeleteView() string {
+	p := m.domains.confirmDelete
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s\n", focusedStyle.Render(fmt.Sprintf("Delete %s?", p.domain.Name)))
+	fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("This deletes all of its DNS records, so nothing under it resolves any more, and can't be undone."))
+	fmt.Fprintf(&b, "%s\n\n", p.name.View())
+
+	return b.String()
+}
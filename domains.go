@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+// dnsTTL is the TTL of the A records created for new Droplets, in seconds.
+const dnsTTL = 1800
+
+type domainItem struct {
+	godo.Domain
+}
+
+func (d domainItem) Title() string { return d.Name }
+func (d domainItem) Description() string {
+	return "create an A record for the Droplet in " + d.Name
+}
+func (d domainItem) FilterValue() string { return d.Name }
+func (d domainItem) Value() string       { return d.Name }
+
+// domainsMsg carries the domain picker's options. If the domains couldn't
+// be listed, the only option is not to create a record.
+type domainsMsg struct {
+	options []option
+	err     error
+}
+
+// fetchDomains lists the domains managed on the account.
+func fetchDomains(client *godo.Client) tea.Cmd {
+	return func() tea.Msg {
+		opts := []option{noneOption("don't create a DNS record")}
+		domains, _, err := client.Domains.List(context.Background(), &godo.ListOptions{PerPage: 200})
+		if err != nil {
+			return domainsMsg{options: opts, err: err}
+		}
+
+		for _, d := range domains {
+			opts = append(opts, domainItem{d})
+		}
+
+		return domainsMsg{options: opts}
+	}
+}
+
+// recordName returns the name of the Droplet's record within the domain,
+// which is the Droplet's name less the domain if it's already a subdomain.
+func recordName(name, domain string) string {
+	if n := strings.TrimSuffix(name, "."+domain); n != name {
+		return n
+	}
+	if name == domain {
+		return "@"
+	}
+	return name
+}
+
+// createRecord points an A record in the domain at the Droplet, returning
+// the record's full name.
+func createRecord(ctx context.Context, client *godo.Client, domain string, droplet *godo.Droplet) (string, error) {
+	if domain == "" {
+		return "", nil
+	}
+	ip, err := droplet.PublicIPv4()
+	if err != nil {
+		return "", err
+	}
+
+	name := recordName(droplet.Name, domain)
+	_, _, err = client.Domains.CreateRecord(ctx, domain, &godo.DomainRecordEditRequest{
+		Type: "A",
+		Name: name,
+		Data: ip,
+		TTL:  dnsTTL,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return recordFQDN(name, domain), nil
+}
+
+// recordFQDN returns the full name of the record within the domain.
+func recordFQDN(name, domain string) string {
+	if name == "@" {
+		return domain
+	}
+	return name + "." + domain
+}
+
+// dnsLabel describes the records that will be created, for review.
+func (m model) dnsLabel() string {
+	domain := m.fields[domainField].Value()
+	if domain == "" {
+		return "none"
+	}
+
+	records := make([]string, len(m.names))
+	for i, name := range m.names {
+		records[i] = recordFQDN(recordName(name, domain), domain)
+	}
+	return "A " + strings.Join(records, ", ")
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+)
+
+// draftPath returns where a half-filled form is saved on quitting.
+func draftPath() (string, error) {
+	dir, err := stateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "draft.yaml"), nil
+}
+
+// loadDraft reads the form saved when last quitting, if there is one.
+func loadDraft() *spec {
+	path, err := draftPath()
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var s spec
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return nil
+	}
+	return &s
+}
+
+// saveDraft writes the form so that it can be restored next time.
+func saveDraft(s spec) tea.Cmd {
+	return func() tea.Msg {
+		path, err := draftPath()
+		if err != nil {
+			return nil
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			return nil
+		}
+		data, err := yaml.Marshal(s)
+		if err != nil {
+			return nil
+		}
+		os.WriteFile(path, data, 0o600)
+
+		return nil
+	}
+}
+
+// clearDraft removes the saved form once it's been restored or dismissed.
+func clearDraft() tea.Msg {
+	if path, err := draftPath(); err == nil {
+		os.Remove(path)
+	}
+	return nil
+}
+
+// quit exits, first saving the form if anything has been entered.
+func (m model) quit() tea.Cmd {
+	if !m.edited {
+		return tea.Quit
+	}
+	return tea.Sequence(saveDraft(m.spec()), tea.Quit)
+}
+
+// updateRestore handles the offer to restore the form from last time.
+func (m model) updateRestore(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "y", "Y":
+		cmd := m.loadSpec(*m.restore)
+		m.restore = nil
+		m.edited = true
+		return m, tea.Batch(cmd, clearDraft)
+	case "n", "N", "esc", "enter":
+		m.restore = nil
+		return m, clearDraft
+	}
+	return m, nil
+}
+
+func (m model) restoreView() string {
+	var b strings.Builder
+
+	name := m.restore.Name
+	if name == "" {
+		name = "an unnamed Droplet"
+	}
+	fmt.Fprintf(&b, "%s\n\n", focusedStyle.Render("You quit last time in the middle of filling out the form for "+name+"."))
+	fmt.Fprintf(&b, "%s\n\n", focusedStyle.Render("Restore it? (y/N)"))
+
+	return b.String()
+}
@@ -0,0 +1,402 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+var (
+	listKey        = key.NewBinding(key.WithKeys("ctrl+o"), key.WithHelp("ctrl+o", "droplets"))
+	nextPageKey    = key.NewBinding(key.WithKeys("right"), key.WithHelp("→", "next page"))
+	prevPageKey    = key.NewBinding(key.WithKeys("left"), key.WithHelp("←", "previous page"))
+	refreshListKey = key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "refresh"))
+	closeListKeys  = key.NewBinding(key.WithKeys("esc", "q"), key.WithHelp("esc", "back"))
+)
+
+// dropletsPerPage is how many Droplets the list fetches at a time.
+const dropletsPerPage = 20
+
+// dropletsMsg carries a page of the account's Droplets, along with how many
+// there are in all.
+type dropletsMsg struct {
+	page     int
+	droplets []godo.Droplet
+	total    int
+	err      error
+}
+
+// fetchDroplets lists a page of the account's Droplets, counting from 1.
+func fetchDroplets(client *godo.Client, page int) tea.Cmd {
+	return func() tea.Msg {
+		droplets, resp, err := client.Droplets.List(context.Background(), &godo.ListOptions{Page: page, PerPage: dropletsPerPage})
+		if err != nil {
+			return dropletsMsg{page: page, err: err}
+		}
+
+		total := len(droplets)
+		if resp.Meta != nil {
+			total = resp.Meta.Total
+		}
+		return dropletsMsg{page: page, droplets: droplets, total: total}
+	}
+}
+
+// dropletList is the screen listing the account's Droplets, a page at a
+// time.
+type dropletList struct {
+	table    table.Model
+	droplets []godo.Droplet
+	page     int
+	total    int
+	loading  bool
+	err      error
+	// detail is set while showing a Droplet's details.
+	detail *dropletDetail
+	// confirmDelete is set while asking to confirm deleting a Droplet,
+	// rename while editing one's name, snapshot while naming a snapshot of
+	// one and passwordReset while confirming resetting its root password.
+	confirmDelete *deletePrompt
+	rename        *renamePrompt
+	snapshot      *snapshotPrompt
+	passwordReset *passwordPrompt
+	// actions is the menu of actions for target, the Droplet it was
+	// opened on.
+	actions *selectField
+	target  godo.Droplet
+	// resize and rebuild are set while resizing or rebuilding a Droplet,
+	// backups while browsing one's backups, kernels while choosing its
+	// kernel and volumes while attaching or detaching its volumes.
+	resize  *resizeFlow
+	rebuild *rebuildFlow
+	backups *backupsFlow
+	kernels *kernelsFlow
+	volumes *dropletVolumesFlow
+	// busy holds the status to show for the Droplets that are being
+	// deleted or acted on, by ID, until they're done.
+	busy map[int]string
+}
+
+// newListTable returns a table for one of the screens listing the account's
+// resources.
+func newListTable(columns []table.Column) table.Model {
+	// Leave the letters to the screen's own actions.
+	keys := table.DefaultKeyMap()
+	keys.PageUp = key.NewBinding(key.WithKeys("pgup"))
+	keys.PageDown = key.NewBinding(key.WithKeys("pgdown"))
+	keys.HalfPageUp = key.NewBinding(key.WithDisabled())
+	keys.HalfPageDown = key.NewBinding(key.WithDisabled())
+	keys.GotoTop = key.NewBinding(key.WithKeys("home"))
+	keys.GotoBottom = key.NewBinding(key.WithKeys("end"))
+
+	styles := table.DefaultStyles()
+	styles.Header = styles.Header.Foreground(blurredStyle.GetForeground())
+	styles.Selected = focusedStyle.Copy().Bold(true)
+
+	return table.New(
+		table.WithColumns(columns),
+		table.WithFocused(true),
+		table.WithKeyMap(keys),
+		table.WithStyles(styles),
+	)
+}
+
+// setTableSize fits a screen's table to it, under the title and above the
+// help.
+func setTableSize(t *table.Model, width, height int) {
+	h := height - 6
+	if h < 5 {
+		h = 5
+	}
+	t.SetHeight(h)
+	t.SetWidth(width)
+}
+
+// pageCount returns how many pages of perPage it takes to list total
+// resources.
+func pageCount(total, perPage int) int {
+	if total == 0 {
+		return 1
+	}
+	return (total + perPage - 1) / perPage
+}
+
+func newDropletList(width, height int) *dropletList {
+	l := &dropletList{
+		page: 1,
+		busy: make(map[int]string),
+		table: newListTable([]table.Column{
+			{Title: "Name", Width: 32},
+			{Title: "Region", Width: 8},
+			{Title: "Size", Width: 20},
+			{Title: "Status", Width: 10},
+			{Title: "Public IPv4", Width: 16},
+		}),
+	}
+	l.SetSize(width, height)
+	return l
+}
+
+// SetSize fits the table to the screen, under the title and above the help.
+func (l *dropletList) SetSize(width, height int) {
+	setTableSize(&l.table, width, height)
+	if l.actions != nil {
+		l.actions.SetSize(width, height)
+	}
+	if l.resize != nil {
+		l.resize.sizes.SetSize(width, height)
+		if l.resize.disk != nil {
+			l.resize.disk.SetSize(width, height)
+		}
+	}
+	if l.rebuild != nil {
+		l.rebuild.images.SetSize(width, height)
+	}
+	if l.backups != nil {
+		l.backups.backups.SetSize(width, height)
+	}
+	if l.kernels != nil {
+		l.kernels.kernels.SetSize(width, height)
+	}
+	if l.volumes != nil {
+		l.volumes.volumes.SetSize(width, height)
+	}
+}
+
+// pages returns how many pages of Droplets there are.
+func (l *dropletList) pages() int {
+	return pageCount(l.total, dropletsPerPage)
+}
+
+// setDroplets shows a page of Droplets once it's been fetched.
+func (l *dropletList) setDroplets(msg dropletsMsg) {
+	l.loading = false
+	l.err = msg.err
+	if msg.err != nil {
+		return
+	}
+
+	l.page, l.droplets, l.total = msg.page, msg.droplets, msg.total
+	l.setRows()
+}
+
+// setRows fills the table in from the Droplets.
+func (l *dropletList) setRows() {
+	rows := make([]table.Row, len(l.droplets))
+	for i, d := range l.droplets {
+		ip, _ := d.PublicIPv4()
+		region := ""
+		if d.Region != nil {
+			region = d.Region.Slug
+		}
+		status := d.Status
+		if s, ok := l.busy[d.ID]; ok {
+			status = s + "…"
+		}
+		rows[i] = table.Row{d.Name, region, d.SizeSlug, status, ip}
+	}
+	l.table.SetRows(rows)
+	if l.table.Cursor() >= len(rows) {
+		l.table.GotoTop()
+	}
+}
+
+// openList shows the list of Droplets, fetching its first page.
+func (m model) openList() (model, tea.Cmd) {
+	m.list = newDropletList(m.width, m.height-1)
+	m.list.loading = true
+	m.notice = ""
+	m.formErr = ""
+	return m, tea.Batch(fetchDroplets(m.client, 1), m.spinner.Tick)
+}
+
+func (m model) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.list
+	if l.detail != nil {
+		return m.updateDetail(msg)
+	}
+	if l.confirmDelete != nil {
+		return m.updateDeletePrompt(msg)
+	}
+	if l.rename != nil {
+		return m.updateRename(msg)
+	}
+	if l.snapshot != nil {
+		return m.updateSnapshot(msg)
+	}
+	if l.passwordReset != nil {
+		return m.updatePasswordReset(msg)
+	}
+	if l.actions != nil {
+		return m.updateActions(msg)
+	}
+	if l.resize != nil {
+		return m.updateResize(msg)
+	}
+	if l.rebuild != nil {
+		return m.updateRebuild(msg)
+	}
+	if l.backups != nil {
+		return m.updateBackups(msg)
+	}
+	if l.kernels != nil {
+		return m.updateKernels(msg)
+	}
+	if l.volumes != nil {
+		return m.updateDropletVolumes(msg)
+	}
+
+	switch {
+	case msg.String() == "ctrl+c":
+		return m, m.quit()
+	case key.Matches(msg, closeListKeys):
+		return m.closeScreen()
+	case key.Matches(msg, screensKey):
+		return m.openScreens()
+	case l.loading:
+		return m, nil
+	case key.Matches(msg, openDetailKey):
+		return m.openDetail()
+	case key.Matches(msg, deleteKey):
+		return m.promptDelete()
+	case key.Matches(msg, actionsKey):
+		return m.openActions()
+	case key.Matches(msg, renameKey):
+		droplet, ok := l.selected()
+		if !ok || m.blockChanges("Droplets", "renamed") {
+			return m, nil
+		}
+		return m.openRename(droplet)
+	case key.Matches(msg, refreshListKey):
+		l.loading = true
+		return m, fetchDroplets(m.client, l.page)
+	case key.Matches(msg, nextPageKey) && l.page < l.pages():
+		l.loading = true
+		return m, fetchDroplets(m.client, l.page+1)
+	case key.Matches(msg, prevPageKey) && l.page > 1:
+		l.loading = true
+		return m, fetchDroplets(m.client, l.page-1)
+	}
+
+	var cmd tea.Cmd
+	l.table, cmd = l.table.Update(msg)
+	return m, cmd
+}
+
+func (m model) listView() string {
+	l := m.list
+	if l.detail != nil {
+		return m.detailView()
+	}
+	if l.actions != nil {
+		return l.actions.View()
+	}
+	if l.resize != nil {
+		return m.resizeView()
+	}
+	if l.rebuild != nil {
+		return m.rebuildView()
+	}
+	if l.backups != nil {
+		return m.backupsView()
+	}
+	if l.kernels != nil {
+		return m.kernelsView()
+	}
+	if l.volumes != nil {
+		return m.dropletVolumesView()
+	}
+
+	var b strings.Builder
+
+	title := focusedStyle.Render("Droplets")
+	if l.total > 0 {
+		title += placeholderStyle.Render(fmt.Sprintf("  %s · page %d of %d", pluralize(l.total, "Droplet"), l.page, l.pages()))
+	}
+	b.WriteString(title + "\n\n")
+
+	switch {
+	case l.loading && l.droplets == nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading Droplets..."))
+	case l.err != nil && l.droplets == nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the Droplets: "+l.err.Error()))
+	case len(l.droplets) == 0:
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("There are no Droplets in this account yet."))
+	default:
+		fmt.Fprintf(&b, "%s\n\n", l.table.View())
+	}
+
+	switch {
+	case l.loading && l.droplets != nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading..."))
+	case l.err != nil && l.droplets != nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the Droplets: "+l.err.Error()))
+	}
+	b.WriteString(m.busyView())
+	if m.notice != "" {
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render(m.notice))
+	}
+	if m.formErr != "" {
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(m.formErr))
+	}
+
+	if l.confirmDelete != nil {
+		b.WriteString(m.deletePromptView())
+		b.WriteString(helpStyle.Render("enter: delete • esc: cancel"))
+		return b.String()
+	}
+	if l.rename != nil {
+		b.WriteString(m.renameView())
+		b.WriteString(helpStyle.Render("enter: rename • esc: cancel"))
+		return b.String()
+	}
+	if l.snapshot != nil {
+		b.WriteString(m.snapshotView())
+		b.WriteString(helpStyle.Render("enter: take snapshot • esc: cancel"))
+		return b.String()
+	}
+	if l.passwordReset != nil {
+		b.WriteString(m.passwordResetView())
+		b.WriteString(helpStyle.Render("y: reset password • n: cancel"))
+		return b.String()
+	}
+
+	help := []string{"↑/↓: move", "enter: details", "a: actions", "n: rename", "d: delete"}
+	if l.pages() > 1 {
+		help = append(help, "←/→: page")
+	}
+	help = append(help, "r: refresh", "tab: screens", m.closeScreenHelp())
+	b.WriteString(helpStyle.Render(strings.Join(help, " • ")))
+
+	return b.String()
+}
+
+// selected returns the Droplet under the cursor, unless there are none or
+// it's busy.
+func (l *dropletList) selected() (godo.Droplet, bool) {
+	i := l.table.Cursor()
+	if i < 0 || i >= len(l.droplets) {
+		return godo.Droplet{}, false
+	}
+	_, busy := l.busy[l.droplets[i].ID]
+	return l.droplets[i], !busy
+}
+
+// blockChanges explains why the account's resources, e.g. its Droplets,
+// can't be changed, e.g. deleted, returning false if they can.
+func (m *model) blockChanges(what, done string) bool {
+	if r := m.readOnlyReason(); r != "" {
+		m.formErr = fmt.Sprintf("%s, so %s can't be %s", r, what, done)
+		return true
+	}
+	if m.dryRun {
+		m.formErr = fmt.Sprintf("--dry-run is on, so %s can't be %s", what, done)
+		return true
+	}
+	return false
+}
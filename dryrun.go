@@ -0,0 +1,34 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// dryRunOutput renders the requests that would be sent to create the
+// Droplets as indented JSON, one document per request.
+func (m model) dryRunOutput() string {
+	var docs []interface{}
+	if m.volume != nil {
+		docs = append(docs, m.volume)
+	}
+	if len(m.names) > 1 {
+		docs = append(docs, multiCreateRequest(m.droplet, m.names))
+	} else {
+		req := *m.droplet
+		req.Name = m.names[0]
+		docs = append(docs, &req)
+	}
+
+	var b strings.Builder
+	for _, d := range docs {
+		out, err := json.MarshalIndent(d, "", "  ")
+		if err != nil {
+			return dropletErrorMsg(err)
+		}
+		b.Write(out)
+		b.WriteRune('\n')
+	}
+
+	return b.String()
+}
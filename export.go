@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var (
+	exportKey       = key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "export"))
+	nextExportKey   = key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "next format"))
+	copyExportKey   = key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "copy"))
+	closeExportKeys = key.NewBinding(key.WithKeys("esc", "q"), key.WithHelp("esc", "back"))
+)
+
+// exportFormat renders the create request in a form that can be pasted
+// elsewhere to create the same Droplets, or, if created is set, describes the
+// Droplets once they've been created.
+type exportFormat struct {
+	name    string
+	render  func(model) string
+	created bool
+}
+
+var exportFormats = []exportFormat{
+	{name: "Terraform", render: model.terraformExport},
+	{name: "doctl", render: model.doctlExport},
+	{name: "Go", render: model.goExport},
+	{name: "Ansible inventory", render: model.ansibleExport, created: true},
+}
+
+// exportScreen shows the create request in one of the export formats.
+type exportScreen struct {
+	formats []exportFormat
+	format  int
+	output  viewport.Model
+}
+
+// openExport shows the export screen over the review or success screen.
+func (m model) openExport() model {
+	m.export = &exportScreen{output: viewport.New(m.width, m.exportHeight())}
+	for _, f := range exportFormats {
+		if !f.created || m.succeeded {
+			m.export.formats = append(m.export.formats, f)
+		}
+	}
+	m.notice = ""
+	m.formErr = ""
+	m.renderExport()
+	return m
+}
+
+func (m model) renderExport() {
+	m.export.output.SetContent(m.export.formats[m.export.format].render(m))
+	m.export.output.GotoTop()
+}
+
+// exportHeight is how many lines of the export fit on the screen under the
+// title and above the help.
+func (m model) exportHeight() int {
+	if h := m.height - 6; h > 0 {
+		return h
+	}
+	return 10
+}
+
+func (m model) updateExport(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.String() == "ctrl+c":
+		if m.succeeded {
+			return m.quitWith(createSummary(m.pending))
+		}
+		return m, m.quit()
+	case key.Matches(msg, closeExportKeys):
+		m.export = nil
+		m.notice = ""
+		return m, nil
+	case key.Matches(msg, nextExportKey):
+		m.export.format = (m.export.format + 1) % len(m.export.formats)
+		m.renderExport()
+		return m, nil
+	case key.Matches(msg, copyExportKey):
+		return m, copyToClipboard(m.export.formats[m.export.format].render(m))
+	}
+
+	var cmd tea.Cmd
+	m.export.output, cmd = m.export.output.Update(msg)
+	return m, cmd
+}
+
+func (m model) exportView() string {
+	var b strings.Builder
+
+	names := make([]string, len(m.export.formats))
+	for i, f := range m.export.formats {
+		if i == m.export.format {
+			names[i] = focusedStyle.Render(f.name)
+		} else {
+			names[i] = blurredStyle.Render(f.name)
+		}
+	}
+	fmt.Fprintf(&b, "%s  %s\n\n", focusedStyle.Render("Export"), strings.Join(names, " · "))
+	fmt.Fprintf(&b, "%s\n\n", m.export.output.View())
+
+	switch {
+	case m.formErr != "":
+		fmt.Fprintf(&b, "%s  ", errorStyle.Render(m.formErr))
+	case m.notice != "":
+		fmt.Fprintf(&b, "%s  ", placeholderStyle.Render(m.notice))
+	}
+	help := "↑/↓: scroll • y: copy • esc: back"
+	if len(m.export.formats) > 1 {
+		help = "tab: next format • " + help
+	}
+	b.WriteString(helpStyle.Render(help))
+
+	return b.String()
+}
@@ -0,0 +1,587 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// field is a single row of the create form.
+type field interface {
+	Focus() tea.Cmd
+	Blur()
+	Update(tea.Msg) (field, tea.Cmd)
+	View() string
+	Value() string
+}
+
+// textField is a free-text form row. Unless the field is optional, its
+// placeholder doubles as the default value.
+type textField struct {
+	textinput.Model
+	optional bool
+	// history lists recent values, newest first. recent is the position in
+	// it while cycling through, or -1 while editing draft.
+	history []string
+	recent  int
+	draft   string
+}
+
+func newTextField(prompt, placeholder string) *textField {
+	t := textinput.New()
+	t.Cursor.Style = cursorStyle
+	t.CharLimit = 32
+	t.Prompt = prompt
+	t.Placeholder = placeholder
+	t.PlaceholderStyle = placeholderStyle
+
+	return &textField{Model: t, recent: -1}
+}
+
+// newOptionalTextField returns a textField whose placeholder is only a hint.
+func newOptionalTextField(prompt, placeholder string) *textField {
+	f := newTextField(prompt, placeholder)
+	f.optional = true
+
+	return f
+}
+
+func (f *textField) Focus() tea.Cmd {
+	f.PromptStyle = focusedStyle
+	f.TextStyle = focusedStyle
+	return f.Model.Focus()
+}
+
+func (f *textField) Blur() {
+	f.Model.Blur()
+	f.recent = -1
+	f.PromptStyle = noStyle
+	f.TextStyle = noStyle
+}
+
+func (f *textField) Update(msg tea.Msg) (field, tea.Cmd) {
+	var cmd tea.Cmd
+	f.Model, cmd = f.Model.Update(msg)
+	return f, cmd
+}
+
+// cycleHistory replaces the value with the previous or next recent one, like
+// a shell. It reports false when there's nowhere to go, i.e. there's no
+// history or the draft is showing and next was asked for.
+func (f *textField) cycleHistory(previous bool) bool {
+	if len(f.history) == 0 || (!previous && f.recent == -1) {
+		return false
+	}
+
+	if f.recent == -1 {
+		f.draft = f.Model.Value()
+	}
+	if previous && f.recent < len(f.history)-1 {
+		f.recent++
+	} else if !previous {
+		f.recent--
+	}
+
+	if f.recent == -1 {
+		f.SetValue(f.draft)
+	} else {
+		f.SetValue(f.history[f.recent])
+	}
+	f.CursorEnd()
+
+	return true
+}
+
+// Value returns the entered text, falling back to the placeholder for
+// required fields.
+func (f *textField) Value() string {
+	if v := f.Model.Value(); v != "" || f.optional {
+		return v
+	}
+	return f.Placeholder
+}
+
+var revealKey = key.NewBinding(key.WithKeys("ctrl+r"), key.WithHelp("ctrl+r", "reveal"))
+
+// secretField is an optional textField for tokens, passwords and the like,
+// echoed as asterisks unless revealed with ctrl+r. It's hidden again
+// whenever it loses focus.
+type secretField struct {
+	*textField
+}
+
+func newSecretField(prompt, placeholder string) *secretField {
+	f := newOptionalTextField(prompt, placeholder)
+	f.CharLimit = 0
+	f.EchoMode = textinput.EchoPassword
+	f.EchoCharacter = '*'
+
+	return &secretField{f}
+}
+
+// Revealed reports whether the value is shown in the clear.
+func (f *secretField) Revealed() bool {
+	return f.EchoMode == textinput.EchoNormal
+}
+
+func (f *secretField) Blur() {
+	f.textField.Blur()
+	f.EchoMode = textinput.EchoPassword
+}
+
+func (f *secretField) Update(msg tea.Msg) (field, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, revealKey) {
+		if f.Revealed() {
+			f.EchoMode = textinput.EchoPassword
+		} else {
+			f.EchoMode = textinput.EchoNormal
+		}
+		return f, nil
+	}
+
+	_, cmd := f.textField.Update(msg)
+	return f, cmd
+}
+
+var toggleKey = key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "toggle"))
+
+// picker is a field that takes over the screen while its value is chosen.
+type picker interface {
+	field
+	Open()
+	Opened() bool
+	SetSize(width, height int)
+}
+
+// option is a list item that can be chosen in a selectField.
+type option interface {
+	list.DefaultItem
+	Value() string
+}
+
+// selectField is a form row whose value is chosen from a list. Pressing
+// enter on the row opens the list; enter again picks the highlighted item.
+type selectField struct {
+	prompt    string
+	fallback  string
+	hideValue bool
+	list      list.Model
+	focused   bool
+	open      bool
+	loading   bool
+	selected  option
+	// want is the value to choose once the options have loaded.
+	want string
+}
+
+func newSelectField(prompt, title, fallback string) *selectField {
+	// The list only enables its navigation keys when created with a non-nil
+	// slice of items.
+	l := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
+	l.Title = title
+	l.Styles.Title = l.Styles.Title.Background(focusedStyle.GetForeground())
+	l.DisableQuitKeybindings()
+
+	return &selectField{
+		prompt:   prompt,
+		fallback: fallback,
+		list:     l,
+		loading:  true,
+	}
+}
+
+// SetOptions replaces the available options, moving the cursor to the one
+// matching the current value if present.
+func (f *selectField) SetOptions(opts []option) tea.Cmd {
+	items := make([]list.Item, len(opts))
+	for i, o := range opts {
+		items[i] = o
+	}
+	cmd := f.list.SetItems(items)
+	f.loading = false
+
+	if f.want != "" {
+		f.Select(f.want)
+		f.want = ""
+		return cmd
+	}
+	for i, o := range opts {
+		if o.Value() == f.Value() {
+			f.list.Select(i)
+			if _, ok := f.selected.(valueOption); f.selected == nil || ok {
+				f.selected = o
+			}
+			break
+		}
+	}
+
+	return cmd
+}
+
+// Select chooses the option with the given value, waiting for the options to
+// load if need be. A value that isn't one of the options is kept as is, and
+// an empty one goes back to the default.
+func (f *selectField) Select(value string) {
+	if f.loading {
+		f.want = value
+		return
+	}
+
+	f.selected = nil
+	if value == "" {
+		value = f.fallback
+	}
+	if value == "" {
+		return
+	}
+	for i, o := range f.options() {
+		if o.Value() == value {
+			f.list.Select(i)
+			f.selected = o
+			return
+		}
+	}
+	f.selected = valueOption(value)
+}
+
+// noneOption leaves an optional selectField unset. It's described by its
+// string.
+type noneOption string
+
+func (o noneOption) Title() string       { return "none" }
+func (o noneOption) Description() string { return string(o) }
+func (o noneOption) FilterValue() string { return "none" }
+func (o noneOption) Value() string       { return "" }
+
+// valueOption is a value chosen for a selectField that isn't one of its
+// options, e.g. from a template. It's replaced by the matching option if one
+// turns up.
+type valueOption string
+
+func (o valueOption) Title() string       { return string(o) }
+func (o valueOption) Description() string { return "" }
+func (o valueOption) FilterValue() string { return string(o) }
+func (o valueOption) Value() string       { return string(o) }
+
+// options returns every option the field can choose from.
+func (f *selectField) options() []option {
+	var opts []option
+	for _, i := range f.list.Items() {
+		if o, ok := i.(option); ok {
+			opts = append(opts, o)
+		}
+	}
+	return opts
+}
+
+func (f *selectField) SetSize(width, height int) {
+	f.list.SetSize(width, height)
+}
+
+func (f *selectField) Focus() tea.Cmd {
+	f.focused = true
+	return nil
+}
+
+func (f *selectField) Blur() {
+	f.focused = false
+}
+
+// Open shows the option list. It's a no-op while options are still loading.
+func (f *selectField) Open() {
+	if !f.loading {
+		f.open = true
+	}
+}
+
+func (f *selectField) Opened() bool {
+	return f.open
+}
+
+// Loaded reports whether the options have loaded.
+func (f *selectField) Loaded() bool {
+	return !f.loading
+}
+
+func (f *selectField) Update(msg tea.Msg) (field, tea.Cmd) {
+	if !f.open {
+		return f, nil
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok && f.list.FilterState() != list.Filtering {
+		switch msg.String() {
+		case "enter":
+			if o, ok := f.list.SelectedItem().(option); ok {
+				f.selected = o
+			}
+			f.open = false
+			return f, nil
+		case "esc":
+			if f.list.FilterState() == list.FilterApplied {
+				break
+			}
+			f.open = false
+			return f, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	f.list, cmd = f.list.Update(msg)
+	return f, cmd
+}
+
+func (f *selectField) View() string {
+	if f.open {
+		return f.list.View()
+	}
+
+	prompt := noStyle
+	value := placeholderStyle
+	if f.focused {
+		prompt = focusedStyle
+		if f.selected != nil {
+			value = focusedStyle
+		}
+	}
+
+	return prompt.Render(f.prompt) + value.Render(f.Label())
+}
+
+// Label describes the current value for display.
+func (f *selectField) Label() string {
+	switch {
+	case f.selected != nil && (f.hideValue || f.selected.Value() == "" || f.selected.Title() == f.selected.Value()):
+		return f.selected.Title()
+	case f.selected != nil:
+		return fmt.Sprintf("%s (%s)", f.selected.Title(), f.selected.Value())
+	case f.loading && f.fallback == "":
+		return "loading..."
+	case f.loading:
+		return fmt.Sprintf("%s (loading...)", f.fallback)
+	case f.fallback == "":
+		return "none"
+	default:
+		return f.fallback
+	}
+}
+
+// Value returns the slug of the chosen option, falling back to the default.
+func (f *selectField) Value() string {
+	if f.selected != nil {
+		return f.selected.Value()
+	}
+	return f.fallback
+}
+
+// multiSelectField is a form row whose values are chosen from a list. Space
+// toggles the highlighted item and enter returns to the form.
+type multiSelectField struct {
+	*selectField
+	options []option
+	checked map[string]bool
+	// match is checked once the options have loaded.
+	match []string
+}
+
+func newMultiSelectField(prompt, title string) *multiSelectField {
+	f := &multiSelectField{
+		selectField: newSelectField(prompt, title, ""),
+		checked:     make(map[string]bool),
+	}
+	f.list.SetDelegate(checkDelegate{DefaultDelegate: list.NewDefaultDelegate(), checked: f.checked})
+	f.list.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{toggleKey}
+	}
+
+	return f
+}
+
+func (f *multiSelectField) SetOptions(opts []option) tea.Cmd {
+	f.options = opts
+	cmd := f.selectField.SetOptions(opts)
+	if f.match != nil {
+		f.CheckMatching(f.match)
+	}
+	return cmd
+}
+
+// Check marks the options with the given values as chosen.
+func (f *multiSelectField) Check(values ...string) {
+	for _, v := range values {
+		f.checked[v] = true
+	}
+}
+
+// CheckMatching chooses exactly the options whose value, title or
+// description is one of match, waiting for the options to load if need be.
+func (f *multiSelectField) CheckMatching(match []string) {
+	if f.loading {
+		f.match = match
+		return
+	}
+	f.match = nil
+
+	for v := range f.checked {
+		delete(f.checked, v)
+	}
+	for _, o := range f.options {
+		if contains(match, o.Value()) || contains(match, o.Title()) || contains(match, o.Description()) {
+			f.checked[o.Value()] = true
+		}
+	}
+}
+
+func (f *multiSelectField) Update(msg tea.Msg) (field, tea.Cmd) {
+	if !f.open {
+		return f, nil
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok && f.list.FilterState() != list.Filtering {
+		switch {
+		case key.Matches(msg, toggleKey):
+			if o, ok := f.list.SelectedItem().(option); ok {
+				f.checked[o.Value()] = !f.checked[o.Value()]
+			}
+			return f, nil
+		case msg.String() == "enter":
+			f.open = false
+			return f, nil
+		}
+	}
+
+	_, cmd := f.selectField.Update(msg)
+	return f, cmd
+}
+
+func (f *multiSelectField) View() string {
+	if f.open {
+		return f.list.View()
+	}
+
+	prompt := noStyle
+	value := placeholderStyle
+	if f.focused {
+		prompt = focusedStyle
+		if len(f.Values()) > 0 {
+			value = focusedStyle
+		}
+	}
+
+	return prompt.Render(f.prompt) + value.Render(f.Label())
+}
+
+// Label describes the chosen options for display.
+func (f *multiSelectField) Label() string {
+	var titles []string
+	for _, o := range f.options {
+		if f.checked[o.Value()] {
+			titles = append(titles, o.Title())
+		}
+	}
+
+	switch {
+	case f.loading:
+		return "loading..."
+	case len(titles) == 0:
+		return "none"
+	default:
+		return strings.Join(titles, ", ")
+	}
+}
+
+// Values returns the values of the chosen options.
+func (f *multiSelectField) Values() []string {
+	var values []string
+	for _, o := range f.options {
+		if f.checked[o.Value()] {
+			values = append(values, o.Value())
+		}
+	}
+	return values
+}
+
+func (f *multiSelectField) Value() string {
+	return strings.Join(f.Values(), ",")
+}
+
+// checkDelegate renders list items with a marker next to the checked ones.
+type checkDelegate struct {
+	list.DefaultDelegate
+	checked map[string]bool
+}
+
+func (d checkDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	if o, ok := item.(option); ok && d.checked[o.Value()] {
+		item = checkedOption{o}
+	}
+	d.DefaultDelegate.Render(w, m, index, item)
+}
+
+type checkedOption struct {
+	option
+}
+
+func (o checkedOption) Title() string { return o.option.Title() + " ✓" }
+
+// toggleField is an on/off form row, toggled with space. The detail func, if
+// set, renders extra information next to the row.
+type toggleField struct {
+	prompt  string
+	checked bool
+	focused bool
+	detail  func() string
+}
+
+func newToggleField(prompt string, detail func() string) *toggleField {
+	return &toggleField{prompt: prompt, detail: detail}
+}
+
+func (f *toggleField) Focus() tea.Cmd {
+	f.focused = true
+	return nil
+}
+
+func (f *toggleField) Blur() {
+	f.focused = false
+}
+
+func (f *toggleField) Update(msg tea.Msg) (field, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && f.focused && key.Matches(msg, toggleKey) {
+		f.checked = !f.checked
+	}
+	return f, nil
+}
+
+func (f *toggleField) View() string {
+	style := noStyle
+	if f.focused {
+		style = focusedStyle
+	}
+
+	box := "[ ]"
+	if f.checked {
+		box = "[x]"
+	}
+
+	v := style.Render(box + " " + f.prompt)
+	if f.detail != nil {
+		if d := f.detail(); d != "" {
+			v += " " + placeholderStyle.Render(d)
+		}
+	}
+	return v
+}
+
+func (f *toggleField) Checked() bool {
+	return f.checked
+}
+
+func (f *toggleField) Value() string {
+	return strconv.FormatBool(f.checked)
+}
@@ -0,0 +1,757 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+var (
+	createFirewallKey   = key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "create"))
+	openRulesKey        = key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "rules"))
+	firewallDropletsKey = key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "Droplets"))
+	firewallTagsKey     = key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "tags"))
+)
+
+// firewallsPerPage is how many firewalls the firewalls screen fetches at a
+// time.
+const firewallsPerPage = 20
+
+// validFirewallName matches the names the API accepts for firewalls.
+var validFirewallName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
+// firewallListMsg carries a page of the account's firewalls, along with how
+// many there are in all.
+type firewallListMsg struct {
+	page      int
+	firewalls []godo.Firewall
+	total     int
+	err       error
+}
+
+// fetchFirewallList lists a page of the account's firewalls, counting from 1.
+func fetchFirewallList(client *godo.Client, page int) tea.Cmd {
+	return func() tea.Msg {
+		firewalls, resp, err := client.Firewalls.List(context.Background(), &godo.ListOptions{Page: page, PerPage: firewallsPerPage})
+		if err != nil {
+			return firewallListMsg{page: page, err: err}
+		}
+
+		total := len(firewalls)
+		if resp.Meta != nil {
+			total = resp.Meta.Total
+		}
+		return firewallListMsg{page: page, firewalls: firewalls, total: total}
+	}
+}
+
+// firewallDoneMsg reports that a change to a firewall has finished. id is
+// blank for one that's been created.
+type firewallDoneMsg struct {
+	id   string
+	name string
+	verb string
+	done string
+	err  error
+}
+
+// firewallList is the screen listing the account's cloud firewalls, a page
+// at a time.
+type firewallList struct {
+	table     table.Model
+	firewalls []godo.Firewall
+	page      int
+	total     int
+	loading   bool
+	err       error
+	// create is set while naming a new firewall, confirmDelete while asking
+	// to confirm deleting one, droplets while choosing the Droplets one
+	// applies to and tags while typing its tags.
+	create        *textField
+	confirmDelete *firewallDeletePrompt
+	droplets      *firewallDropletsFlow
+	tags          *firewallTagsPrompt
+	// rules is set while showing the rules of one of the firewalls.
+	rules *firewallRules
+	// busy holds the status to show for the firewalls that are being
+	// changed, by ID, and creating the names of those being created, until
+	// they're done.
+	busy     map[string]string
+	creating []string
+}
+
+func newFirewallList(width, height int) *firewallList {
+	l := &firewallList{
+		page: 1,
+		busy: make(map[string]string),
+		table: newListTable([]table.Column{
+			{Title: "Name", Width: 24},
+			{Title: "Inbound", Width: 8},
+			{Title: "Outbound", Width: 8},
+			{Title: "Droplets", Width: 8},
+			{Title: "Tags", Width: 20},
+			{Title: "Status", Width: 12},
+		}),
+	}
+	l.SetSize(width, height)
+	return l
+}
+
+// SetSize fits the table to the screen, under the title and above the help.
+func (l *firewallList) SetSize(width, height int) {
+	setTableSize(&l.table, width, height)
+	if l.rules != nil {
+		l.rules.SetSize(width, height)
+	}
+}
+
+// pages returns how many pages of firewalls there are.
+func (l *firewallList) pages() int {
+	return pageCount(l.total, firewallsPerPage)
+}
+
+// setFirewallList shows a page of firewalls once it's been fetched, and
+// refreshes the rules being shown.
+func (m model) setFirewallList(msg firewallListMsg) (tea.Model, tea.Cmd) {
+	l := m.firewalls
+	if l == nil {
+		return m, nil
+	}
+	// The last page was emptied, e.g. by deleting, so go back one.
+	if msg.err == nil && len(msg.firewalls) == 0 && msg.page > 1 {
+		return m, fetchFirewallList(m.client, msg.page-1)
+	}
+
+	l.loading = false
+	l.err = msg.err
+	if msg.err != nil {
+		return m, nil
+	}
+	l.page, l.firewalls, l.total = msg.page, msg.firewalls, msg.total
+	l.setRows()
+	if l.rules != nil {
+		for _, f := range l.firewalls {
+			if f.ID == l.rules.firewall.ID {
+				l.rules.setFirewall(f)
+			}
+		}
+	}
+	return m, nil
+}
+
+// setRows fills the table in from the firewalls.
+func (l *firewallList) setRows() {
+	rows := make([]table.Row, len(l.firewalls))
+	for i, f := range l.firewalls {
+		status := f.Status
+		if s, ok := l.busy[f.ID]; ok {
+			status = s + "…"
+		}
+		rows[i] = table.Row{
+			f.Name,
+			strconv.Itoa(len(f.InboundRules)),
+			strconv.Itoa(len(f.OutboundRules)),
+			strconv.Itoa(len(f.DropletIDs)),
+			strings.Join(f.Tags, ", "),
+			status,
+		}
+	}
+	l.table.SetRows(rows)
+	if l.table.Cursor() >= len(rows) {
+		l.table.GotoTop()
+	}
+}
+
+// selected returns the firewall under the cursor, unless there are none or
+// it's busy.
+func (l *firewallList) selected() (godo.Firewall, bool) {
+	i := l.table.Cursor()
+	if i < 0 || i >= len(l.firewalls) {
+		return godo.Firewall{}, false
+	}
+	_, busy := l.busy[l.firewalls[i].ID]
+	return l.firewalls[i], !busy
+}
+
+// openFirewalls shows the list of firewalls, fetching its first page.
+func (m model) openFirewalls() (model, tea.Cmd) {
+	m.firewalls = newFirewallList(m.width, m.height-1)
+	m.firewalls.loading = true
+	m.notice = ""
+	m.formErr = ""
+	return m, tea.Batch(fetchFirewallList(m.client, 1), m.spinner.Tick)
+}
+
+func (m model) updateFirewalls(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.firewalls
+	switch {
+	case l.rules != nil:
+		return m.updateFirewallRules(msg)
+	case l.create != nil:
+		return m.updateFirewallCreate(msg)
+	case l.confirmDelete != nil:
+		return m.updateFirewallDelete(msg)
+	case l.droplets != nil:
+		return m.updateFirewallDroplets(msg)
+	case l.tags != nil:
+		return m.updateFirewallTags(msg)
+	}
+
+	switch {
+	case msg.String() == "ctrl+c":
+		return m, m.quit()
+	case key.Matches(msg, closeListKeys):
+		return m.closeScreen()
+	case key.Matches(msg, screensKey):
+		return m.openScreens()
+	case key.Matches(msg, createFirewallKey):
+		return m.openFirewallCreate()
+	case l.loading:
+		return m, nil
+	case key.Matches(msg, openRulesKey):
+		return m.openFirewallRules()
+	case key.Matches(msg, firewallDropletsKey):
+		return m.openFirewallDroplets()
+	case key.Matches(msg, firewallTagsKey):
+		return m.openFirewallTags()
+	case key.Matches(msg, deleteKey):
+		return m.promptFirewallDelete()
+	case key.Matches(msg, refreshListKey):
+		l.loading = true
+		return m, fetchFirewallList(m.client, l.page)
+	case key.Matches(msg, nextPageKey) && l.page < l.pages():
+		l.loading = true
+		return m, fetchFirewallList(m.client, l.page+1)
+	case key.Matches(msg, prevPageKey) && l.page > 1:
+		l.loading = true
+		return m, fetchFirewallList(m.client, l.page-1)
+	}
+
+	var cmd tea.Cmd
+	l.table, cmd = l.table.Update(msg)
+	return m, cmd
+}
+
+// firewallDone refreshes the list once a change to a firewall has finished.
+func (m model) firewallDone(msg firewallDoneMsg) (tea.Model, tea.Cmd) {
+	l := m.firewalls
+	if l != nil {
+		delete(l.busy, msg.id)
+		if msg.verb == "create" {
+			for i, name := range l.creating {
+				if name == msg.name {
+					l.creating = append(l.creating[:i:i], l.creating[i+1:]...)
+					break
+				}
+			}
+		}
+		if l.rules != nil && l.rules.firewall.ID == msg.id {
+			l.rules.saving = false
+		}
+		l.setRows()
+	}
+	if msg.err != nil {
+		m.formErr = fmt.Sprintf("couldn't %s %s: %s", msg.verb, msg.name, msg.err)
+		return m, nil
+	}
+
+	cmd := m.toast(fmt.Sprintf("%s %s", msg.done, msg.name))
+	if l == nil {
+		return m, cmd
+	}
+	l.loading = true
+	return m, tea.Batch(cmd, fetchFirewallList(m.client, l.page))
+}
+
+func (m model) firewallsView() string {
+	l := m.firewalls
+	if l.rules != nil {
+		return m.firewallRulesView()
+	}
+	if l.droplets != nil && l.droplets.droplets.Opened() {
+		return l.droplets.droplets.View()
+	}
+
+	var b strings.Builder
+
+	title := focusedStyle.Render("Firewalls")
+	if l.total > 0 {
+		title += placeholderStyle.Render(fmt.Sprintf("  %s · page %d of %d", pluralize(l.total, "firewall"), l.page, l.pages()))
+	}
+	b.WriteString(title + "\n\n")
+
+	switch {
+	case l.loading && l.firewalls == nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading firewalls..."))
+	case l.err != nil && l.firewalls == nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the firewalls: "+l.err.Error()))
+	case len(l.firewalls) == 0:
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("There are no firewalls in this account yet."))
+	default:
+		fmt.Fprintf(&b, "%s\n\n", l.table.View())
+	}
+
+	switch {
+	case l.loading && l.firewalls != nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading..."))
+	case l.err != nil && l.firewalls != nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the firewalls: "+l.err.Error()))
+	}
+	if len(l.creating) > 0 {
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Creating "+strings.Join(l.creating, ", ")+"..."))
+	}
+	if m.notice != "" {
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render(m.notice))
+	}
+	if m.formErr != "" {
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(m.formErr))
+	}
+
+	switch {
+	case l.create != nil:
+		fmt.Fprintf(&b, "%s\n\n", focusedStyle.Render("Create a firewall"))
+		fmt.Fprintf(&b, "%s\n", l.create.View())
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("It starts out letting SSH in from anywhere and everything out; press enter on it afterwards to change its rules."))
+		b.WriteString(helpStyle.Render("enter: create • esc: cancel"))
+		return b.String()
+	case l.confirmDelete != nil:
+		b.WriteString(m.firewallDeleteView())
+		b.WriteString(helpStyle.Render("enter: delete • esc: cancel"))
+		return b.String()
+	case l.droplets != nil:
+		b.WriteString(m.firewallDropletsView())
+		return b.String()
+	case l.tags != nil:
+		b.WriteString(m.firewallTagsView())
+		b.WriteString(helpStyle.Render("enter: save • esc: cancel"))
+		return b.String()
+	}
+
+	help := []string{"↑/↓: move", "enter: rules", "c: create", "a: Droplets", "t: tags", "d: delete"}
+	if l.pages() > 1 {
+		help = append(help, "←/→: page")
+	}
+	help = append(help, "r: refresh", "tab: screens", m.closeScreenHelp())
+	b.WriteString(helpStyle.Render(strings.Join(help, " • ")))
+
+	return b.String()
+}
+
+// defaultFirewallRules are the rules new firewalls start out with: SSH in
+// from anywhere, and anything out to anywhere.
+func defaultFirewallRules() *godo.FirewallRequest {
+	anywhere := []string{"0.0.0.0/0", "::/0"}
+	return &godo.FirewallRequest{
+		InboundRules: []godo.InboundRule{
+			{Protocol: "tcp", PortRange: "22", Sources: &godo.Sources{Addresses: anywhere}},
+		},
+		OutboundRules: []godo.OutboundRule{
+			{Protocol: "tcp", PortRange: "all", Destinations: &godo.Destinations{Addresses: anywhere}},
+			{Protocol: "udp", PortRange: "all", Destinations: &godo.Destinations{Addresses: anywhere}},
+			{Protocol: "icmp", Destinations: &godo.Destinations{Addresses: anywhere}},
+		},
+	}
+}
+
+// openFirewallCreate asks for the name of a new firewall.
+func (m model) openFirewallCreate() (model, tea.Cmd) {
+	if m.blockChanges("firewalls", "created") {
+		return m, nil
+	}
+
+	name := newTextField("Name: ", "")
+	name.CharLimit = 255
+	m.firewalls.create = name
+	m.notice = ""
+	m.formErr = ""
+	return m, name.Focus()
+}
+
+func (m model) updateFirewallCreate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.firewalls
+	switch msg.String() {
+	case "ctrl+c":
+		return m, m.quit()
+	case "esc":
+		l.create = nil
+		m.formErr = ""
+		return m, nil
+	case "enter":
+		name := strings.TrimSpace(l.create.Value())
+		if !validFirewallName.MatchString(name) {
+			m.formErr = "firewall names are letters, numbers, dots, dashes and underscores, starting with a letter or number"
+			return m, nil
+		}
+		l.create = nil
+		m.formErr = ""
+		l.creating = append(l.creating, name)
+		return m, createFirewall(m.client, name)
+	}
+
+	_, cmd := l.create.Update(msg)
+	return m, cmd
+}
+
+// createFirewall creates the firewall with the default rules, applying to no
+// Droplets yet.
+func createFirewall(client *godo.Client, name string) tea.Cmd {
+	return func() tea.Msg {
+		req := defaultFirewallRules()
+		req.Name = name
+		_, _, err := client.Firewalls.Create(context.Background(), req)
+		return firewallDoneMsg{name: name, verb: "create", done: "Created", err: err}
+	}
+}
+
+// firewallDeletePrompt asks for a firewall's name before deleting it.
+type firewallDeletePrompt struct {
+	firewall godo.Firewall
+	name     *textField
+}
+
+// promptFirewallDelete asks to confirm deleting the firewall under the
+// cursor.
+func (m model) promptFirewallDelete() (model, tea.Cmd) {
+	if m.blockChanges("firewalls", "deleted") {
+		return m, nil
+	}
+
+	l := m.firewalls
+	firewall, ok := l.selected()
+	if !ok {
+		return m, nil
+	}
+	name := newOptionalTextField("Type its name to delete it: ", firewall.Name)
+	name.CharLimit = 255
+	l.confirmDelete = &firewallDeletePrompt{firewall: firewall, name: name}
+	m.notice = ""
+	m.formErr = ""
+	return m, name.Focus()
+}
+
+func (m model) updateFirewallDelete(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.firewalls
+	p := l.confirmDelete
+	switch msg.String() {
+	case "ctrl+c":
+		return m, m.quit()
+	case "esc":
+		l.confirmDelete = nil
+		m.formErr = ""
+		return m, nil
+	case "enter":
+		if strings.TrimSpace(p.name.Value()) != p.firewall.Name {
+			m.formErr = "the name doesn't match; type it exactly, or press esc to cancel"
+			return m, nil
+		}
+		l.confirmDelete = nil
+		m.formErr = ""
+		l.busy[p.firewall.ID] = "deleting"
+		l.setRows()
+		return m, deleteFirewall(m.client, p.firewall)
+	}
+
+	_, cmd := p.name.Update(msg)
+	return m, cmd
+}
+
+// deleteFirewall deletes the firewall, which opens its Droplets up to
+// everything it was blocking.
+func deleteFirewall(client *godo.Client, firewall godo.Firewall) tea.Cmd {
+	return func() tea.Msg {
+		_, err := client.Firewalls.Delete(context.Background(), firewall.ID)
+		return firewallDoneMsg{id: firewall.ID, name: firewall.Name, verb: "delete", done: "Deleted", err: err}
+	}
+}
+
+func (m model) firewallDeleteView() string {
+	p := m.firewalls.confirmDelete
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s\n", focusedStyle.Render(fmt.Sprintf("Delete %s?", p.firewall.Name)))
+	applies := pluralize(len(p.firewall.DropletIDs), "Droplet")
+	if len(p.firewall.Tags) > 0 {
+		applies += " and any tagged " + strings.Join(p.firewall.Tags, ", ")
+	}
+	fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(fmt.Sprintf("Everything it blocks reaches its %s again, and it can't be undone.", applies)))
+	fmt.Fprintf(&b, "%s\n\n", p.name.View())
+
+	return b.String()
+}
+
+// firewallDropletsFlow chooses which Droplets a firewall applies to, by
+// name, besides those it applies to by tag.
+type firewallDropletsFlow struct {
+	firewall godo.Firewall
+	droplets *multiSelectField
+	err      error
+}
+
+// firewallDropletsMsg carries the account's Droplets for a firewall to
+// apply to.
+type firewallDropletsMsg struct {
+	firewallID string
+	droplets   []godo.Droplet
+	err        error
+}
+
+// fetchFirewallDroplets lists the account's Droplets.
+func fetchFirewallDroplets(client *godo.Client, firewallID string) tea.Cmd {
+	return func() tea.Msg {
+		droplets, _, err := client.Droplets.List(context.Background(), &godo.ListOptions{PerPage: 200})
+		return firewallDropletsMsg{firewallID: firewallID, droplets: droplets, err: err}
+	}
+}
+
+// openFirewallDroplets chooses the Droplets the firewall under the cursor
+// applies to, starting with the ones it does.
+func (m model) openFirewallDroplets() (model, tea.Cmd) {
+	if m.blockChanges("firewalls", "changed") {
+		return m, nil
+	}
+
+	l := m.firewalls
+	firewall, ok := l.selected()
+	if !ok {
+		return m, nil
+	}
+	droplets := newMultiSelectField("", "Choose the Droplets "+firewall.Name+" applies to")
+	droplets.SetSize(m.width, m.height-1)
+	l.droplets = &firewallDropletsFlow{firewall: firewall, droplets: droplets}
+	m.notice = ""
+	m.formErr = ""
+	return m, fetchFirewallDroplets(m.client, firewall.ID)
+}
+
+// setFirewallDroplets offers the Droplets once they've been fetched.
+func (m model) setFirewallDroplets(msg firewallDropletsMsg) (tea.Model, tea.Cmd) {
+	if m.firewalls == nil || m.firewalls.droplets == nil || m.firewalls.droplets.firewall.ID != msg.firewallID {
+		return m, nil
+	}
+	f := m.firewalls.droplets
+	if msg.err == nil && len(msg.droplets) == 0 {
+		msg.err = errors.New("there are no Droplets in this account")
+	}
+	if msg.err != nil {
+		f.err = msg.err
+		return m, nil
+	}
+
+	opts := make([]option, len(msg.droplets))
+	for i, d := range msg.droplets {
+		opts[i] = dropletOption{d}
+	}
+	cmd := f.droplets.SetOptions(opts)
+	for _, id := range f.firewall.DropletIDs {
+		f.droplets.Check(strconv.Itoa(id))
+	}
+	f.droplets.Open()
+	return m, cmd
+}
+
+func (m model) updateFirewallDroplets(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.firewalls
+	f := l.droplets
+	if msg.String() == "ctrl+c" {
+		return m, m.quit()
+	}
+	if f.err != nil || !f.droplets.Loaded() {
+		if msg.String() == "esc" {
+			l.droplets = nil
+		}
+		return m, nil
+	}
+	// Unlike enter, esc leaves the Droplets as they were.
+	cancelled := msg.String() == "esc" && f.droplets.list.FilterState() == list.Unfiltered
+
+	_, cmd := f.droplets.Update(msg)
+	if f.droplets.Opened() {
+		return m, cmd
+	}
+	l.droplets = nil
+	if cancelled {
+		return m, cmd
+	}
+
+	var chosen []int
+	for _, v := range f.droplets.Values() {
+		id, _ := strconv.Atoi(v)
+		chosen = append(chosen, id)
+	}
+	add, remove := diffIDs(f.firewall.DropletIDs, chosen)
+	if len(add) == 0 && len(remove) == 0 {
+		return m, cmd
+	}
+	l.busy[f.firewall.ID] = "applying"
+	l.setRows()
+	return m, tea.Batch(cmd, setFirewallDroplets(m.client, f.firewall, add, remove))
+}
+
+// diffIDs returns the IDs in want that aren't in have, and those in have
+// that aren't in want.
+func diffIDs(have, want []int) (add, remove []int) {
+	in := func(ids []int, id int) bool {
+		for _, i := range ids {
+			if i == id {
+				return true
+			}
+		}
+		return false
+	}
+	for _, id := range want {
+		if !in(have, id) {
+			add = append(add, id)
+		}
+	}
+	for _, id := range have {
+		if !in(want, id) {
+			remove = append(remove, id)
+		}
+	}
+	return add, remove
+}
+
+// setFirewallDroplets applies the firewall to the Droplets in add, and stops
+// applying it to those in remove.
+func setFirewallDroplets(client *godo.Client, firewall godo.Firewall, add, remove []int) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+
+		var err error
+		if len(add) > 0 {
+			_, err = client.Firewalls.AddDroplets(ctx, firewall.ID, add...)
+		}
+		if err == nil && len(remove) > 0 {
+			_, err = client.Firewalls.RemoveDroplets(ctx, firewall.ID, remove...)
+		}
+		return firewallDoneMsg{id: firewall.ID, name: "the Droplets of " + firewall.Name, verb: "change", done: "Changed", err: err}
+	}
+}
+
+func (m model) firewallDropletsView() string {
+	f := m.firewalls.droplets
+	var b strings.Builder
+
+	switch {
+	case f.err != nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(fmt.Sprintf("can't choose the Droplets of %s: %s", f.firewall.Name, f.err)))
+		b.WriteString(helpStyle.Render("esc: back"))
+	case !f.droplets.Loaded():
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading Droplets..."))
+		b.WriteString(helpStyle.Render("esc: cancel"))
+	}
+
+	return b.String()
+}
+
+// firewallTagsPrompt asks for the tags of the Droplets a firewall applies
+// to, comma-separated.
+type firewallTagsPrompt struct {
+	firewall godo.Firewall
+	tags     *textField
+}
+
+// openFirewallTags asks for the tags the firewall under the cursor applies
+// to, starting with the ones it does.
+func (m model) openFirewallTags() (model, tea.Cmd) {
+	if m.blockChanges("firewalls", "changed") {
+		return m, nil
+	}
+
+	l := m.firewalls
+	firewall, ok := l.selected()
+	if !ok {
+		return m, nil
+	}
+	tags := newOptionalTextField("Tags: ", "comma-separated, e.g. web,prod")
+	tags.CharLimit = 512
+	tags.SetValue(strings.Join(firewall.Tags, ","))
+	l.tags = &firewallTagsPrompt{firewall: firewall, tags: tags}
+	m.notice = ""
+	m.formErr = ""
+	return m, tags.Focus()
+}
+
+func (m model) updateFirewallTags(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.firewalls
+	p := l.tags
+	switch msg.String() {
+	case "ctrl+c":
+		return m, m.quit()
+	case "esc":
+		l.tags = nil
+		m.formErr = ""
+		return m, nil
+	case "enter":
+		tags := parseTags(p.tags.Value())
+		for _, t := range tags {
+			if invalidTagChars.MatchString(t) {
+				m.formErr = fmt.Sprintf("tag %q can only have letters, numbers, colons, dashes and underscores", t)
+				return m, nil
+			}
+		}
+		l.tags = nil
+		m.formErr = ""
+		add, remove := diffStrings(p.firewall.Tags, tags)
+		if len(add) == 0 && len(remove) == 0 {
+			return m, nil
+		}
+		l.busy[p.firewall.ID] = "applying"
+		l.setRows()
+		return m, setFirewallTags(m.client, p.firewall, add, remove)
+	}
+
+	_, cmd := p.tags.Update(msg)
+	return m, cmd
+}
+
+// diffStrings returns the strings in want that aren't in have, and those in
+// have that aren't in want.
+func diffStrings(have, want []string) (add, remove []string) {
+	for _, s := range want {
+		if !contains(have, s) {
+			add = append(add, s)
+		}
+	}
+	for _, s := range have {
+		if !contains(want, s) {
+			remove = append(remove, s)
+		}
+	}
+	return add, remove
+}
+
+// setFirewallTags applies the firewall to the Droplets tagged with the tags
+// in add, and stops applying it by the tags in remove.
+func setFirewallTags(client *godo.Client, firewall godo.Firewall, add, remove []string) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+
+		var err error
+		if len(add) > 0 {
+			_, err = client.Firewalls.AddTags(ctx, firewall.ID, add...)
+		}
+		if err == nil && len(remove) > 0 {
+			_, err = client.Firewalls.RemoveTags(ctx, firewall.ID, remove...)
+		}
+		return firewallDoneMsg{id: firewall.ID, name: "the tags of " + firewall.Name, verb: "change", done: "Changed", err: err}
+	}
+}
+
+func (m model) firewallTagsView() string {
+	p := m.firewalls.tags
+
+	return fmt.Sprintf("%s\n%s\n%s\n\n",
+		focusedStyle.Render(fmt.Sprintf("Tags %s applies to", p.firewall.Name)),
+		placeholderStyle.Render("It applies to every Droplet with any of them, including ones created later."),
+		p.tags.View())
+}
@@ -0,0 +1,625 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+var (
+	createRuleKey = key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "add"))
+	editRuleKey   = key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "edit"))
+)
+
+// firewallRule is an inbound or outbound firewall rule, whose targets are
+// where traffic's let in from or out to.
+type firewallRule struct {
+	inbound  bool
+	protocol string
+	ports    string
+	targets  godo.Sources
+}
+
+// rulesOf returns the firewall's rules, inbound first.
+func rulesOf(f godo.Firewall) []firewallRule {
+	var rules []firewallRule
+	for _, r := range f.InboundRules {
+		rule := firewallRule{inbound: true, protocol: r.Protocol, ports: r.PortRange}
+		if r.Sources != nil {
+			rule.targets = *r.Sources
+		}
+		rules = append(rules, rule)
+	}
+	for _, r := range f.OutboundRules {
+		rule := firewallRule{protocol: r.Protocol, ports: r.PortRange}
+		if r.Destinations != nil {
+			rule.targets = godo.Sources(*r.Destinations)
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// request returns the rule as a request to add or remove it.
+func (r firewallRule) request() *godo.FirewallRulesRequest {
+	ports := r.ports
+	if r.protocol == "icmp" {
+		ports = ""
+	}
+	targets := r.targets
+	if r.inbound {
+		return &godo.FirewallRulesRequest{InboundRules: []godo.InboundRule{{Protocol: r.protocol, PortRange: ports, Sources: &targets}}}
+	}
+	destinations := godo.Destinations(targets)
+	return &godo.FirewallRulesRequest{OutboundRules: []godo.OutboundRule{{Protocol: r.protocol, PortRange: ports, Destinations: &destinations}}}
+}
+
+// direction returns "in" or "out".
+func (r firewallRule) direction() string {
+	if r.inbound {
+		return "in"
+	}
+	return "out"
+}
+
+// portsLabel describes the rule's ports, which ICMP doesn't have.
+func (r firewallRule) portsLabel() string {
+	switch {
+	case r.protocol == "icmp":
+		return ""
+	case r.ports == "" || r.ports == "0" || r.ports == "all":
+		return "all"
+	}
+	return r.ports
+}
+
+// label describes the rule in a sentence, e.g. "TCP on port 22 in from all
+// IPv4".
+func (r firewallRule) label() string {
+	dir := "in from"
+	if !r.inbound {
+		dir = "out to"
+	}
+	ports := ""
+	switch p := r.portsLabel(); {
+	case r.protocol == "icmp":
+	case p == "all":
+		ports = " on all ports"
+	case strings.Contains(p, "-"):
+		ports = " on ports " + p
+	default:
+		ports = " on port " + p
+	}
+	return fmt.Sprintf("%s%s %s %s", strings.ToUpper(r.protocol), ports, dir, r.targetsLabel())
+}
+
+// targetsLabel describes where the rule lets traffic in from or out to.
+func (r firewallRule) targetsLabel() string {
+	t := r.targets
+	var parts []string
+	for _, a := range t.Addresses {
+		switch a {
+		case "0.0.0.0/0":
+			a = "all IPv4"
+		case "::/0":
+			a = "all IPv6"
+		}
+		parts = append(parts, a)
+	}
+	for _, tag := range t.Tags {
+		parts = append(parts, "tag:"+tag)
+	}
+	if n := len(t.DropletIDs); n > 0 {
+		parts = append(parts, pluralize(n, "Droplet"))
+	}
+	if n := len(t.LoadBalancerUIDs); n > 0 {
+		parts = append(parts, pluralize(n, "load balancer"))
+	}
+	if n := len(t.KubernetesIDs); n > 0 {
+		parts = append(parts, pluralize(n, "cluster"))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// firewallRules is the table of a firewall's rules, shown on the firewalls
+// screen once a firewall's been chosen.
+type firewallRules struct {
+	firewall godo.Firewall
+	table    table.Model
+	rules    []firewallRule
+	// edit is set while adding or editing a rule, and confirmDelete while
+	// asking to confirm deleting one. saving is set while a change is being
+	// made, since each is made against the rules as they were.
+	edit          *ruleEditor
+	confirmDelete *firewallRule
+	saving        bool
+}
+
+func newFirewallRules(firewall godo.Firewall, width, height int) *firewallRules {
+	r := &firewallRules{
+		table: newListTable([]table.Column{
+			{Title: "Dir", Width: 4},
+			{Title: "Protocol", Width: 8},
+			{Title: "Ports", Width: 12},
+			{Title: "From/to", Width: 56},
+		}),
+	}
+	r.SetSize(width, height)
+	r.setFirewall(firewall)
+	return r
+}
+
+// SetSize fits the table to the screen, under the title and above the help.
+func (r *firewallRules) SetSize(width, height int) {
+	setTableSize(&r.table, width, height)
+}
+
+// setFirewall shows the firewall's rules.
+func (r *firewallRules) setFirewall(f godo.Firewall) {
+	r.firewall = f
+	r.rules = rulesOf(f)
+	rows := make([]table.Row, len(r.rules))
+	for i, rule := range r.rules {
+		rows[i] = table.Row{rule.direction(), rule.protocol, rule.portsLabel(), rule.targetsLabel()}
+	}
+	r.table.SetRows(rows)
+	if r.table.Cursor() >= len(rows) {
+		r.table.GotoTop()
+	}
+}
+
+// selected returns the rule under the cursor, if there is one.
+func (r *firewallRules) selected() (firewallRule, bool) {
+	i := r.table.Cursor()
+	if i < 0 || i >= len(r.rules) {
+		return firewallRule{}, false
+	}
+	return r.rules[i], true
+}
+
+// openFirewallRules shows the rules of the firewall under the cursor.
+func (m model) openFirewallRules() (model, tea.Cmd) {
+	firewall, ok := m.firewalls.selected()
+	if !ok {
+		return m, nil
+	}
+	m.firewalls.rules = newFirewallRules(firewall, m.width, m.height-1)
+	m.notice = ""
+	m.formErr = ""
+	return m, nil
+}
+
+func (m model) updateFirewallRules(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	r := m.firewalls.rules
+	if r.edit != nil {
+		return m.updateRuleEditor(msg)
+	}
+	if r.confirmDelete != nil {
+		return m.updateRuleDelete(msg)
+	}
+
+	switch {
+	case msg.String() == "ctrl+c":
+		return m, m.quit()
+	case msg.String() == "esc":
+		// Back to the firewalls.
+		m.firewalls.rules = nil
+		m.notice = ""
+		m.formErr = ""
+		return m, nil
+	case key.Matches(msg, screensKey):
+		return m.openScreens()
+	case r.saving || m.firewalls.loading:
+		return m, nil
+	case key.Matches(msg, createRuleKey):
+		return m.openRuleEditor(nil)
+	case key.Matches(msg, editRuleKey):
+		rule, ok := r.selected()
+		if !ok {
+			return m, nil
+		}
+		return m.openRuleEditor(&rule)
+	case key.Matches(msg, deleteKey):
+		rule, ok := r.selected()
+		if !ok || m.blockChanges("firewalls", "changed") {
+			return m, nil
+		}
+		r.confirmDelete = &rule
+		m.notice = ""
+		m.formErr = ""
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	r.table, cmd = r.table.Update(msg)
+	return m, cmd
+}
+
+// changeRules removes the rule in remove from the firewall, if it's set,
+// and adds the one in add, if it's set.
+func changeRules(client *godo.Client, firewall godo.Firewall, remove, add *firewallRule, verb, done string) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+
+		var err error
+		if remove != nil {
+			_, err = client.Firewalls.RemoveRules(ctx, firewall.ID, remove.request())
+		}
+		if err == nil && add != nil {
+			_, err = client.Firewalls.AddRules(ctx, firewall.ID, add.request())
+		}
+		return firewallDoneMsg{id: firewall.ID, name: "a rule of " + firewall.Name, verb: verb, done: done, err: err}
+	}
+}
+
+// replaceRule puts rule in place of the firewall's rule at i. The firewall
+// is updated with all of its rules at once, so the old rule is only dropped
+// if the API accepts the new one.
+func replaceRule(client *godo.Client, firewall godo.Firewall, i int, rule firewallRule) tea.Cmd {
+	return func() tea.Msg {
+		msg := firewallDoneMsg{id: firewall.ID, name: "a rule of " + firewall.Name, verb: "change", done: "Changed"}
+		rules := rulesOf(firewall)
+		if i < 0 || i >= len(rules) {
+			msg.err = errors.New("the rule is no longer on the firewall")
+			return msg
+		}
+		rules[i] = rule
+
+		req := &godo.FirewallRequest{Name: firewall.Name, DropletIDs: firewall.DropletIDs, Tags: firewall.Tags}
+		for _, r := range rules {
+			one := r.request()
+			req.InboundRules = append(req.InboundRules, one.InboundRules...)
+			req.OutboundRules = append(req.OutboundRules, one.OutboundRules...)
+		}
+		_, _, msg.err = client.Firewalls.Update(context.Background(), firewall.ID, req)
+		return msg
+	}
+}
+
+func (m model) firewallRulesView() string {
+	l := m.firewalls
+	r := l.rules
+	if r.edit != nil && r.edit.kinds != nil && r.edit.kinds.Opened() {
+		return r.edit.kinds.View()
+	}
+
+	var b strings.Builder
+
+	title := focusedStyle.Render(r.firewall.Name)
+	title += placeholderStyle.Render(fmt.Sprintf("  %d inbound · %d outbound", len(r.firewall.InboundRules), len(r.firewall.OutboundRules)))
+	b.WriteString(title + "\n\n")
+
+	if len(r.rules) == 0 {
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("It has no rules, so it blocks everything in and out."))
+	} else {
+		fmt.Fprintf(&b, "%s\n\n", r.table.View())
+	}
+	if r.saving || l.loading {
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Saving..."))
+	}
+	if m.notice != "" {
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render(m.notice))
+	}
+	if m.formErr != "" {
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(m.formErr))
+	}
+
+	switch {
+	case r.edit != nil:
+		b.WriteString(m.ruleEditorView())
+		return b.String()
+	case r.confirmDelete != nil:
+		rule := r.confirmDelete
+		fmt.Fprintf(&b, "%s\n", focusedStyle.Render(fmt.Sprintf("Delete the rule letting %s?", rule.label())))
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("Traffic it was letting through is blocked unless another rule lets it."))
+		b.WriteString(helpStyle.Render("y: delete • n: cancel"))
+		return b.String()
+	}
+
+	b.WriteString(helpStyle.Render(strings.Join([]string{"↑/↓: move", "c: add", "enter: edit", "d: delete", "tab: screens", "esc: firewalls"}, " • ")))
+
+	return b.String()
+}
+
+func (m model) updateRuleDelete(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.firewalls
+	r := l.rules
+	switch msg.String() {
+	case "ctrl+c":
+		return m, m.quit()
+	case "y", "Y":
+		rule := r.confirmDelete
+		r.confirmDelete = nil
+		r.saving = true
+		l.busy[r.firewall.ID] = "saving"
+		return m, changeRules(m.client, r.firewall, rule, nil, "delete", "Deleted")
+	case "n", "N", "esc":
+		r.confirmDelete = nil
+	}
+	return m, nil
+}
+
+// ruleKind is the direction and protocol of a rule being added.
+type ruleKind struct {
+	inbound  bool
+	protocol string
+}
+
+func (k ruleKind) Title() string {
+	if k.inbound {
+		return "Inbound " + strings.ToUpper(k.protocol)
+	}
+	return "Outbound " + strings.ToUpper(k.protocol)
+}
+
+func (k ruleKind) Description() string {
+	dir := "in from"
+	if !k.inbound {
+		dir = "out to"
+	}
+	if k.protocol == "icmp" {
+		return "lets pings and other ICMP " + dir + " the sources"
+	}
+	return fmt.Sprintf("lets %s traffic on some ports %s the sources", strings.ToUpper(k.protocol), dir)
+}
+
+func (k ruleKind) FilterValue() string { return k.Title() }
+func (k ruleKind) Value() string       { return k.Title() }
+
+// ruleEditor adds a rule to a firewall or edits one of its rules.
+type ruleEditor struct {
+	// rule is the rule being edited, or nil when adding one, and index is
+	// where it is in the firewall's rules. kinds is set while choosing the
+	// direction and protocol of the rule to add.
+	rule  *firewallRule
+	index int
+	kinds *selectField
+	kind  ruleKind
+
+	// ports is nil for ICMP rules, which have none.
+	ports     *textField
+	addresses *textField
+	tags      *textField
+	focus     int
+}
+
+// openRuleEditor starts adding a rule, choosing its direction and protocol
+// first, or editing the rule.
+func (m model) openRuleEditor(rule *firewallRule) (model, tea.Cmd) {
+	if m.blockChanges("firewalls", "changed") {
+		return m, nil
+	}
+
+	r := m.firewalls.rules
+	m.notice = ""
+	m.formErr = ""
+	if rule != nil {
+		r.edit = &ruleEditor{rule: rule, index: r.table.Cursor()}
+		return m, r.edit.setKind(ruleKind{inbound: rule.inbound, protocol: rule.protocol}, *rule)
+	}
+
+	var opts []option
+	for _, inbound := range []bool{true, false} {
+		for _, protocol := range []string{"tcp", "udp", "icmp"} {
+			opts = append(opts, ruleKind{inbound: inbound, protocol: protocol})
+		}
+	}
+	kinds := newSelectField("", "Choose the kind of rule to add to "+r.firewall.Name, "")
+	kinds.SetSize(m.width, m.height-1)
+	cmd := kinds.SetOptions(opts)
+	kinds.Open()
+	r.edit = &ruleEditor{kinds: kinds}
+	return m, cmd
+}
+
+// setKind lays the editor's fields out for the kind of rule, filled in from
+// the rule.
+func (e *ruleEditor) setKind(kind ruleKind, rule firewallRule) tea.Cmd {
+	e.kind = kind
+	e.focus = 0
+
+	e.ports = nil
+	if kind.protocol != "icmp" {
+		e.ports = newOptionalTextField("Ports: ", "e.g. 22, 8000-9000, or all")
+		e.ports.CharLimit = 11
+		if e.rule != nil {
+			e.ports.SetValue(rule.portsLabel())
+		}
+	}
+
+	addresses := rule.targets.Addresses
+	if e.rule == nil {
+		addresses = []string{"0.0.0.0/0", "::/0"}
+	}
+	prompt := "From addresses: "
+	if !kind.inbound {
+		prompt = "To addresses: "
+	}
+	e.addresses = newOptionalTextField(prompt, "IPs or CIDRs, comma-separated")
+	e.addresses.CharLimit = 1024
+	e.addresses.SetValue(strings.Join(addresses, ", "))
+
+	prompt = "From tags: "
+	if !kind.inbound {
+		prompt = "To tags: "
+	}
+	e.tags = newOptionalTextField(prompt, "Droplet tags, comma-separated")
+	e.tags.CharLimit = 512
+	e.tags.SetValue(strings.Join(rule.targets.Tags, ","))
+
+	return e.inputs()[0].Focus()
+}
+
+// inputs returns the editor's fields in the order they're shown.
+func (e *ruleEditor) inputs() []*textField {
+	if e.ports == nil {
+		return []*textField{e.addresses, e.tags}
+	}
+	return []*textField{e.ports, e.addresses, e.tags}
+}
+
+// move focuses the next field, or the previous one, wrapping around.
+func (e *ruleEditor) move(back bool) tea.Cmd {
+	fields := e.inputs()
+	fields[e.focus].Blur()
+	if back {
+		e.focus = (e.focus + len(fields) - 1) % len(fields)
+	} else {
+		e.focus = (e.focus + 1) % len(fields)
+	}
+	return fields[e.focus].Focus()
+}
+
+// result returns the rule the editor's fields describe, or an error
+// explaining what's wrong with them. The targets it doesn't edit, like
+// Droplets and load balancers, are kept from the rule being edited.
+func (e *ruleEditor) result() (firewallRule, error) {
+	rule := firewallRule{inbound: e.kind.inbound, protocol: e.kind.protocol}
+	if e.rule != nil {
+		rule.targets = e.rule.targets
+	}
+
+	if e.ports != nil {
+		ports, err := checkPorts(strings.TrimSpace(e.ports.Value()))
+		if err != nil {
+			return firewallRule{}, err
+		}
+		rule.ports = ports
+	}
+
+	rule.targets.Addresses = splitList(e.addresses.Value())
+	for _, a := range rule.targets.Addresses {
+		if net.ParseIP(a) == nil {
+			if _, _, err := net.ParseCIDR(a); err != nil {
+				return firewallRule{}, fmt.Errorf("%q isn't an IP address or CIDR, like 203.0.113.0/24", a)
+			}
+		}
+	}
+	rule.targets.Tags = parseTags(e.tags.Value())
+	for _, t := range rule.targets.Tags {
+		if invalidTagChars.MatchString(t) {
+			return firewallRule{}, fmt.Errorf("tag %q can only have letters, numbers, colons, dashes and underscores", t)
+		}
+	}
+
+	t := rule.targets
+	if len(t.Addresses)+len(t.Tags)+len(t.DropletIDs)+len(t.LoadBalancerUIDs)+len(t.KubernetesIDs) == 0 {
+		if rule.inbound {
+			return firewallRule{}, errors.New("give the addresses or tags to let traffic in from")
+		}
+		return firewallRule{}, errors.New("give the addresses or tags to let traffic out to")
+	}
+	return rule, nil
+}
+
+// checkPorts returns the ports as the API takes them: a port, a range like
+// 8000-9000, or all.
+func checkPorts(ports string) (string, error) {
+	switch ports {
+	case "":
+		return "", errors.New("give the ports: a port, a range like 8000-9000, or all")
+	case "all", "0":
+		return "all", nil
+	}
+
+	invalid := fmt.Errorf("%q isn't a port, a range like 8000-9000, or all", ports)
+	from, to, isRange := strings.Cut(ports, "-")
+	first, err := strconv.Atoi(from)
+	if err != nil || first < 1 || first > 65535 {
+		return "", invalid
+	}
+	if isRange {
+		last, err := strconv.Atoi(to)
+		if err != nil || last < first || last > 65535 {
+			return "", invalid
+		}
+	}
+	return ports, nil
+}
+
+func (m model) updateRuleEditor(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.firewalls
+	r := l.rules
+	e := r.edit
+	if msg.String() == "ctrl+c" {
+		return m, m.quit()
+	}
+
+	if e.kinds != nil {
+		_, cmd := e.kinds.Update(msg)
+		if e.kinds.Opened() {
+			return m, cmd
+		}
+		kind, ok := e.kinds.selected.(ruleKind)
+		if !ok {
+			r.edit = nil
+			return m, cmd
+		}
+		e.kinds = nil
+		return m, tea.Batch(cmd, e.setKind(kind, firewallRule{}))
+	}
+
+	switch msg.String() {
+	case "esc":
+		r.edit = nil
+		m.formErr = ""
+		return m, nil
+	case "tab", "down":
+		return m, e.move(false)
+	case "shift+tab", "up":
+		return m, e.move(true)
+	case "enter":
+		rule, err := e.result()
+		if err != nil {
+			m.formErr = err.Error()
+			return m, nil
+		}
+		r.edit = nil
+		m.formErr = ""
+		r.saving = true
+		l.busy[r.firewall.ID] = "saving"
+		if e.rule == nil {
+			return m, changeRules(m.client, r.firewall, nil, &rule, "add", "Added")
+		}
+		return m, replaceRule(m.client, r.firewall, e.index, rule)
+	}
+
+	_, cmd := e.inputs()[e.focus].Update(msg)
+	return m, cmd
+}
+
+func (m model) ruleEditorView() string {
+	e := m.firewalls.rules.edit
+	if e.kinds != nil {
+		return ""
+	}
+	var b strings.Builder
+
+	title := "Add an " + strings.ToLower(e.kind.Title()) + " rule"
+	if e.rule != nil {
+		title = "Edit the " + strings.ToLower(e.kind.Title()) + " rule"
+	}
+	fmt.Fprintf(&b, "%s\n\n", focusedStyle.Render(title))
+	for _, f := range e.inputs() {
+		fmt.Fprintf(&b, "%s\n", f.View())
+	}
+	b.WriteString("\n")
+	if e.rule != nil {
+		t := e.rule.targets
+		if len(t.DropletIDs)+len(t.LoadBalancerUIDs)+len(t.KubernetesIDs) > 0 {
+			fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("Its Droplets, load balancers and clusters are kept."))
+		}
+	}
+	fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("0.0.0.0/0 and ::/0 are every IPv4 and IPv6 address."))
+	b.WriteString(helpStyle.Render("tab: next field • enter: save • esc: cancel"))
+
+	return b.String()
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+type firewallItem struct {
+	godo.Firewall
+}
+
+func (f firewallItem) Title() string { return f.Name }
+func (f firewallItem) Description() string {
+	if len(f.DropletIDs) == 1 {
+		return "1 Droplet"
+	}
+	return fmt.Sprintf("%d Droplets", len(f.DropletIDs))
+}
+func (f firewallItem) FilterValue() string { return f.Name }
+func (f firewallItem) Value() string       { return f.ID }
+
+// firewallsMsg carries the firewall picker's options. If the firewalls
+// couldn't be listed, there are none, so the Droplets are added to none.
+type firewallsMsg struct {
+	options []option
+	err     error
+}
+
+// fetchFirewalls lists the cloud firewalls on the account.
+func fetchFirewalls(client *godo.Client) tea.Cmd {
+	return func() tea.Msg {
+		firewalls, _, err := client.Firewalls.List(context.Background(), &godo.ListOptions{PerPage: 200})
+		if err != nil {
+			return firewallsMsg{err: err}
+		}
+
+		opts := make([]option, len(firewalls))
+		for i, f := range firewalls {
+			opts[i] = firewallItem{f}
+		}
+
+		return firewallsMsg{options: opts}
+	}
+}
+
+// addToFirewalls adds the Droplet to each of the firewalls. It's done as
+// soon as the Droplet is created, so that it's never reachable on ports the
+// firewalls would close.
+func addToFirewalls(ctx context.Context, client *godo.Client, firewalls []string, dropletID int) error {
+	for _, id := range firewalls {
+		if _, err := client.Firewalls.AddDroplets(ctx, id, dropletID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+var (
+	openTriggersKey = key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "triggers"))
+	copyAPIHostKey  = key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "copy API host"))
+)
+
+// namespaceListMsg carries the account's Functions namespaces, which the
+// API lists all at once.
+type namespaceListMsg struct {
+	namespaces []godo.FunctionsNamespace
+	err        error
+}
+
+func fetchNamespaceList(client *godo.Client) tea.Cmd {
+	return func() tea.Msg {
+		namespaces, _, err := client.Functions.ListNamespaces(context.Background())
+		if err != nil {
+			return namespaceListMsg{err: err}
+		}
+
+		sort.Slice(namespaces, func(i, j int) bool {
+			a, b := namespaces[i], namespaces[j]
+			if a.Region != b.Region {
+				return a.Region < b.Region
+			}
+			return a.Label < b.Label
+		})
+		return namespaceListMsg{namespaces: namespaces}
+	}
+}
+
+// namespaceList is the screen listing the account's Functions namespaces,
+// region by region.
+type namespaceList struct {
+	table      table.Model
+	namespaces []godo.FunctionsNamespace
+	loaded     bool
+	loading    bool
+	err        error
+	// triggers is set while showing a namespace's triggers.
+	triggers *namespaceTriggers
+}
+
+func newNamespaceList(width, height int) *namespaceList {
+	l := &namespaceList{
+		table: newListTable([]table.Column{
+			{Title: "Label", Width: 24},
+			{Title: "Region", Width: 8},
+			{Title: "Namespace", Width: 36},
+			{Title: "API host", Width: 40},
+			{Title: "Created", Width: 12},
+		}),
+	}
+	l.SetSize(width, height)
+	return l
+}
+
+// SetSize fits the table to the screen, under the title and above the help.
+func (l *namespaceList) SetSize(width, height int) {
+	setTableSize(&l.table, width, height)
+	if l.triggers != nil {
+		setTableSize(&l.triggers.table, width, height)
+	}
+}
+
+// setNamespaceList shows the namespaces once they've been listed.
+func (m model) setNamespaceList(msg namespaceListMsg) (tea.Model, tea.Cmd) {
+	l := m.functions
+	if l == nil {
+		return m, nil
+	}
+
+	l.loading = false
+	l.err = msg.err
+	if msg.err != nil {
+		return m, nil
+	}
+	l.namespaces, l.loaded = msg.namespaces, true
+	rows := make([]table.Row, len(l.namespaces))
+	for i, ns := range l.namespaces {
+		created := ""
+		if !ns.CreatedAt.IsZero() {
+			created = ns.CreatedAt.Format("2006-01-02")
+		}
+		rows[i] = table.Row{ns.Label, ns.Region, ns.Namespace, strings.TrimPrefix(ns.ApiHost, "https://"), created}
+	}
+	l.table.SetRows(rows)
+	if l.table.Cursor() >= len(rows) {
+		l.table.GotoTop()
+	}
+	return m, nil
+}
+
+// selected returns the namespace under the cursor, unless there are none.
+func (l *namespaceList) selected() (godo.FunctionsNamespace, bool) {
+	i := l.table.Cursor()
+	if i < 0 || i >= len(l.namespaces) {
+		return godo.FunctionsNamespace{}, false
+	}
+	return l.namespaces[i], true
+}
+
+// openFunctions shows the list of Functions namespaces.
+func (m model) openFunctions() (model, tea.Cmd) {
+	m.functions = newNamespaceList(m.width, m.height-1)
+	m.functions.loading = true
+	m.notice = ""
+	m.formErr = ""
+	return m, tea.Batch(fetchNamespaceList(m.client), m.spinner.Tick)
+}
+
+func (m model) updateFunctions(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.functions
+	if l.triggers != nil {
+		return m.updateNamespaceTriggers(msg)
+	}
+
+	switch {
+	case msg.String() == "ctrl+c":
+		return m, m.quit()
+	case key.Matches(msg, closeListKeys):
+		return m.closeScreen()
+	case key.Matches(msg, screensKey):
+		return m.openScreens()
+	case l.loading:
+		return m, nil
+	case key.Matches(msg, openTriggersKey):
+		return m.openNamespaceTriggers()
+	case key.Matches(msg, copyAPIHostKey):
+		if ns, ok := l.selected(); ok {
+			return m, copyToClipboard(ns.ApiHost)
+		}
+		return m, nil
+	case key.Matches(msg, refreshListKey):
+		l.loading = true
+		return m, fetchNamespaceList(m.client)
+	}
+
+	var cmd tea.Cmd
+	l.table, cmd = l.table.Update(msg)
+	return m, cmd
+}
+
+func (m model) functionsView() string {
+	l := m.functions
+	if l.triggers != nil {
+		return m.namespaceTriggersView()
+	}
+
+	var b strings.Builder
+
+	title := focusedStyle.Render("Functions")
+	if l.loaded {
+		title += placeholderStyle.Render("  " + pluralize(len(l.namespaces), "namespace"))
+	}
+	b.WriteString(title + "\n\n")
+
+	switch {
+	case l.loading && !l.loaded:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading namespaces..."))
+	case l.err != nil && !l.loaded:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the namespaces: "+l.err.Error()))
+	case len(l.namespaces) == 0:
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("There are no Functions namespaces in this account yet."))
+	default:
+		fmt.Fprintf(&b, "%s\n\n", l.table.View())
+	}
+
+	switch {
+	case l.loading && l.loaded:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading..."))
+	case l.err != nil && l.loaded:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the namespaces: "+l.err.Error()))
+	}
+	if m.notice != "" {
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render(m.notice))
+	}
+	if m.formErr != "" {
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(m.formErr))
+	}
+
+	help := []string{"↑/↓: move", "enter: triggers", "y: copy API host", "r: refresh", "tab: screens", m.closeScreenHelp()}
+	b.WriteString(helpStyle.Render(strings.Join(help, " • ")))
+
+	return b.String()
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+// namespaceTriggersMsg carries the triggers in a Functions namespace, which
+// the API lists all at once.
+type namespaceTriggersMsg struct {
+	namespace string
+	triggers  []godo.FunctionsTrigger
+	err       error
+}
+
+func fetchNamespaceTriggers(client *godo.Client, namespace string) tea.Cmd {
+	return func() tea.Msg {
+		triggers, _, err := client.Functions.ListTriggers(context.Background(), namespace)
+		return namespaceTriggersMsg{namespace: namespace, triggers: triggers, err: err}
+	}
+}
+
+// namespaceTriggers lists the triggers that run a namespace's functions.
+type namespaceTriggers struct {
+	namespace godo.FunctionsNamespace
+	table     table.Model
+	triggers  []godo.FunctionsTrigger
+	loaded    bool
+	loading   bool
+	err       error
+}
+
+// openNamespaceTriggers shows the triggers in the namespace under the
+// cursor.
+func (m model) openNamespaceTriggers() (model, tea.Cmd) {
+	l := m.functions
+	ns, ok := l.selected()
+	if !ok {
+		return m, nil
+	}
+
+	t := &namespaceTriggers{
+		namespace: ns,
+		loading:   true,
+		table: newListTable([]table.Column{
+			{Title: "Name", Width: 24},
+			{Title: "Function", Width: 28},
+			{Title: "Schedule", Width: 16},
+			{Title: "Last run", Width: 17},
+			{Title: "Next run", Width: 17},
+			{Title: "Enabled", Width: 8},
+		}),
+	}
+	setTableSize(&t.table, m.width, m.height-1)
+	l.triggers = t
+	m.notice = ""
+	m.formErr = ""
+	return m, fetchNamespaceTriggers(m.client, ns.Namespace)
+}
+
+// setNamespaceTriggers shows the triggers once they've been listed.
+func (m model) setNamespaceTriggers(msg namespaceTriggersMsg) (tea.Model, tea.Cmd) {
+	if m.functions == nil || m.functions.triggers == nil || m.functions.triggers.namespace.Namespace != msg.namespace {
+		return m, nil
+	}
+	t := m.functions.triggers
+
+	t.loading = false
+	t.err = msg.err
+	if msg.err != nil {
+		return m, nil
+	}
+	t.triggers, t.loaded = msg.triggers, true
+	rows := make([]table.Row, len(t.triggers))
+	for i, trigger := range t.triggers {
+		schedule, last, next := trigger.Type, "", ""
+		if d := trigger.ScheduledDetails; d != nil && d.Cron != "" {
+			schedule = d.Cron
+		}
+		if r := trigger.ScheduledRuns; r != nil {
+			if !r.LastRunAt.IsZero() {
+				last = r.LastRunAt.Local().Format("2006-01-02 15:04")
+			}
+			if !r.NextRunAt.IsZero() && trigger.IsEnabled {
+				next = r.NextRunAt.Local().Format("2006-01-02 15:04")
+			}
+		}
+		rows[i] = table.Row{trigger.Name, trigger.Function, schedule, last, next, yesNoLabel(trigger.IsEnabled)}
+	}
+	t.table.SetRows(rows)
+	if t.table.Cursor() >= len(rows) {
+		t.table.GotoTop()
+	}
+	return m, nil
+}
+
+func (m model) updateNamespaceTriggers(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.functions
+	t := l.triggers
+
+	switch {
+	case msg.String() == "ctrl+c":
+		return m, m.quit()
+	case msg.String() == "esc":
+		l.triggers = nil
+		return m, nil
+	case t.loading:
+		return m, nil
+	case key.Matches(msg, refreshListKey):
+		t.loading = true
+		return m, fetchNamespaceTriggers(m.client, t.namespace.Namespace)
+	}
+
+	var cmd tea.Cmd
+	t.table, cmd = t.table.Update(msg)
+	return m, cmd
+}
+
+func (m model) namespaceTriggersView() string {
+	t := m.functions.triggers
+	var b strings.Builder
+
+	title := focusedStyle.Render(t.namespace.Label)
+	title += placeholderStyle.Render("  " + t.namespace.Region)
+	if t.loaded {
+		title += placeholderStyle.Render(" · " + pluralize(len(t.triggers), "trigger"))
+	}
+	b.WriteString(title + "\n\n")
+
+	switch {
+	case t.loading && !t.loaded:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading triggers..."))
+	case t.err != nil && !t.loaded:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the namespace's triggers: "+t.err.Error()))
+	case len(t.triggers) == 0:
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("Nothing runs this namespace's functions on a schedule; they're only run when called."))
+	default:
+		fmt.Fprintf(&b, "%s\n\n", t.table.View())
+	}
+
+	switch {
+	case t.loading && t.loaded:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading..."))
+	case t.err != nil && t.loaded:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the namespace's triggers: "+t.err.Error()))
+	}
+
+	b.WriteString(helpStyle.Render(strings.Join([]string{"↑/↓: move", "r: refresh", "esc: back"}, " • ")))
+
+	return b.String()
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+
+	"github.com/digitalocean/godo"
+)
+
+// goString returns s as a Go string literal, using a raw string for user
+// data and the like where it can.
+func goString(s string) string {
+	if strings.Contains(s, "\n") && !strings.Contains(s, "`") && !strings.Contains(s, "\r") {
+		return "`" + s + "`"
+	}
+	return strconv.Quote(s)
+}
+
+// goStrings returns values as a Go []string literal.
+func goStrings(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = strconv.Quote(v)
+	}
+	return "[]string{" + strings.Join(quoted, ", ") + "}"
+}
+
+// goExport renders a Go program that creates the same Droplets with godo.
+func (m model) goExport() string {
+	var b strings.Builder
+
+	req := m.droplet
+	multi := len(m.names) > 1
+
+	b.WriteString("package main\n\n")
+	b.WriteString("import (\n\t\"context\"\n\t\"fmt\"\n\t\"os\"\n\n\t\"github.com/digitalocean/godo\"\n)\n\n")
+	b.WriteString("func main() {\n")
+	b.WriteString("\tctx := context.Background()\n")
+	b.WriteString("\tclient := godo.NewFromToken(os.Getenv(\"DIGITALOCEAN_TOKEN\"))\n\n")
+
+	if m.volume != nil {
+		b.WriteString("\tvolume, _, err := client.Storage.CreateVolume(ctx, &godo.VolumeCreateRequest{\n")
+		fmt.Fprintf(&b, "\t\tName: %s,\n", strconv.Quote(m.volume.Name))
+		fmt.Fprintf(&b, "\t\tRegion: %s,\n", strconv.Quote(m.volume.Region))
+		fmt.Fprintf(&b, "\t\tSizeGigaBytes: %d,\n", m.volume.SizeGigaBytes)
+		b.WriteString("\t})\n")
+		b.WriteString("\tif err != nil {\n\t\tfmt.Fprintln(os.Stderr, err)\n\t\tos.Exit(1)\n\t}\n\n")
+	}
+
+	if multi {
+		b.WriteString("\treq := &godo.DropletMultiCreateRequest{\n")
+		fmt.Fprintf(&b, "\t\tNames: %s,\n", goStrings(m.names))
+	} else {
+		b.WriteString("\treq := &godo.DropletCreateRequest{\n")
+		fmt.Fprintf(&b, "\t\tName: %s,\n", strconv.Quote(m.names[0]))
+	}
+	fmt.Fprintf(&b, "\t\tRegion: %s,\n", strconv.Quote(req.Region))
+	fmt.Fprintf(&b, "\t\tSize: %s,\n", strconv.Quote(req.Size))
+	fmt.Fprintf(&b, "\t\tImage: %s,\n", goImage(req.Image))
+	if len(req.SSHKeys) > 0 {
+		keys := make([]string, len(req.SSHKeys))
+		for i, k := range req.SSHKeys {
+			if k.Fingerprint != "" {
+				keys[i] = fmt.Sprintf("{Fingerprint: %s}", strconv.Quote(k.Fingerprint))
+			} else {
+				keys[i] = fmt.Sprintf("{ID: %d}", k.ID)
+			}
+		}
+		fmt.Fprintf(&b, "\t\tSSHKeys: []godo.DropletCreateSSHKey{%s},\n", strings.Join(keys, ", "))
+	}
+	if len(req.Tags) > 0 {
+		fmt.Fprintf(&b, "\t\tTags: %s,\n", goStrings(req.Tags))
+	}
+	if req.VPCUUID != "" {
+		fmt.Fprintf(&b, "\t\tVPCUUID: %s,\n", strconv.Quote(req.VPCUUID))
+	}
+	if req.Backups {
+		b.WriteString("\t\tBackups: true,\n")
+	}
+	if req.Monitoring {
+		b.WriteString("\t\tMonitoring: true,\n")
+	}
+	if req.IPv6 {
+		b.WriteString("\t\tIPv6: true,\n")
+	}
+	if req.UserData != "" {
+		fmt.Fprintf(&b, "\t\tUserData: %s,\n", goString(req.UserData))
+	}
+	b.WriteString("\t}\n")
+	// Volumes are only ever attached to a single Droplet.
+	if m.volume != nil {
+		b.WriteString("\treq.Volumes = []godo.DropletCreateVolume{{ID: volume.ID}}\n")
+	}
+	b.WriteString("\n")
+
+	if multi {
+		b.WriteString("\tdroplets, _, err := client.Droplets.CreateMultiple(ctx, req)\n")
+	} else {
+		b.WriteString("\tdroplet, _, err := client.Droplets.Create(ctx, req)\n")
+	}
+	b.WriteString("\tif err != nil {\n\t\tfmt.Fprintln(os.Stderr, err)\n\t\tos.Exit(1)\n\t}\n")
+	if multi {
+		b.WriteString("\tfor _, d := range droplets {\n\t\tfmt.Println(d.ID, d.Name)\n\t}\n")
+	} else {
+		b.WriteString("\tfmt.Println(droplet.ID, droplet.Name)\n")
+	}
+	b.WriteString("}\n")
+
+	src, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return b.String()
+	}
+	return string(src)
+}
+
+// goImage returns the image as a godo.DropletCreateImage literal.
+func goImage(image godo.DropletCreateImage) string {
+	if image.Slug != "" {
+		return fmt.Sprintf("godo.DropletCreateImage{Slug: %s}", strconv.Quote(image.Slug))
+	}
+	return fmt.Sprintf("godo.DropletCreateImage{ID: %d}", image.ID)
+}
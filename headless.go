@@ -0,0 +1,92 @@
+package main
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// driveHeadless stands in for the keyboard with --no-tui, taking each
+// screen's default action: the form is submitted as soon as it's loaded
+// (see autoSubmit), the review is confirmed, and the program quits once the
+// Droplets are set up. Anything that would leave it waiting on a person
+// quits with the reason instead.
+func (m model) driveHeadless() (tea.Model, tea.Cmd) {
+	switch {
+	case m.finalMsg != "" || m.autoSubmit || m.checking || m.creating:
+		return m, nil
+
+	case !m.submitted && !m.reviewing && (m.formErr != "" || len(m.fieldErrs) > 0):
+		m.headlessErr = true
+		return m.quitWith(m.formErrors())
+
+	case m.reviewing:
+		if r := m.readOnlyReason(); r != "" && !m.dryRun {
+			m.headlessErr = true
+			return m.quitWith(r + ", so Droplets can't be created\n")
+		}
+		if m.quota.exceeded(len(m.names)) && !m.dryRun {
+			m.headlessErr = true
+			return m.quitWith(m.quota.warning(len(m.names)) + "\n")
+		}
+		return m.updateReview(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+
+	case m.upload != nil && m.upload.running == 0:
+		// Carry on with the Droplets the files made it to.
+		m.upload = nil
+		return m.runSetup(bootstrapStep)
+
+	case m.bootstrap != nil && m.bootstrap.running == 0:
+		m.bootstrap = nil
+		return m, nil
+
+	case m.succeeded && m.upload == nil && m.bootstrap == nil && m.setupDone():
+		return m.quitWith(createSummary(m.pending))
+	}
+	return m, nil
+}
+
+// formErrors lists what's wrong with the form, for --no-tui.
+func (m model) formErrors() string {
+	var errs []string
+	for i := range m.fields {
+		if err, ok := m.fieldErrs[i]; ok {
+			errs = append(errs, err)
+		}
+	}
+	if m.formErr != "" {
+		errs = append(errs, m.formErr)
+	}
+	return strings.Join(errs, "\n") + "\n"
+}
+
+// setupDone reports whether every setup step has finished on the Droplets
+// created.
+func (m model) setupDone() bool {
+	for _, p := range m.pending {
+		if (p.health != "" && !p.healthDone) || (p.hooked && !p.hookDone) {
+			return false
+		}
+	}
+	return true
+}
+
+// headlessFailed reports whether --no-tui should exit with an error: if
+// nothing was created, or any Droplet failed to be or to be set up.
+func (m model) headlessFailed() bool {
+	if m.headlessErr || !m.submitted {
+		return true
+	}
+	if m.dryRun {
+		return false
+	}
+	if !m.succeeded {
+		return true
+	}
+	for _, p := range m.pending {
+		if p.err != nil || p.uploadErr != nil || p.bootstrapErr != nil || p.healthErr != nil || p.hookErr != nil {
+			return true
+		}
+	}
+	return false
+}
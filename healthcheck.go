@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+const (
+	// defaultHealthTimeout is how long to keep probing the health check URL
+	// unless the config sets health-timeout. It allows for cloud-init
+	// installing and starting the app.
+	defaultHealthTimeout = 10 * time.Minute
+	// healthBackoff and maxHealthBackoff bound the wait between probes,
+	// which doubles after each failure.
+	healthBackoff    = 2 * time.Second
+	maxHealthBackoff = 30 * time.Second
+	// healthRequestTimeout is how long a single probe may take.
+	healthRequestTimeout = 10 * time.Second
+)
+
+// healthMsg reports how the health check of the Droplet at index went.
+type healthMsg struct {
+	index int
+	err   error
+}
+
+// healthURL fills in the health check URL's placeholders for the Droplet:
+// {ip} for its public IPv4 address and {name} for its name.
+func healthURL(template string, droplet *godo.Droplet) string {
+	ip, _ := droplet.PublicIPv4()
+	return strings.NewReplacer("{ip}", ip, "{name}", droplet.Name).Replace(template)
+}
+
+// startHealthChecks probes the health check URL of every Droplet that was
+// created, once it's finished setting up.
+func (m model) startHealthChecks() (model, tea.Cmd) {
+	var cmds []tea.Cmd
+	for i, p := range m.pending {
+		if !p.settingUp() {
+			continue
+		}
+		m.pending[i].health = healthURL(m.defaults.HealthCheck, p.droplet)
+		cmds = append(cmds, m.checkHealth(i))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// checkHealth waits for cloud-init to finish on the Droplet at index, if it
+// can be reached over SSH, then requests the health check URL until it
+// responds with a 2xx status, backing off between attempts.
+func (m model) checkHealth(index int) tea.Cmd {
+	droplet := m.pending[index].droplet
+	url := m.pending[index].health
+	timeout := m.defaults.HealthTimeout
+	if timeout == 0 {
+		timeout = defaultHealthTimeout
+	}
+	// Without waiting for SSH it may not be up yet, so just keep probing.
+	var cloudInit func(context.Context) error
+	if m.waitsForSSH() {
+		cloudInit = func(ctx context.Context) error {
+			cmd, err := m.sshCommand(droplet, bootstrapSSHOptions, "cloud-init", "status", "--wait")
+			if err != nil {
+				return err
+			}
+			if err := cmd.Start(); err != nil {
+				return err
+			}
+			done := make(chan error, 1)
+			go func() { done <- cmd.Wait() }()
+			select {
+			case err := <-done:
+				return err
+			case <-ctx.Done():
+				cmd.Process.Kill()
+				return ctx.Err()
+			}
+		}
+	}
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		// Images without cloud-init fail this, which is no reason not to
+		// probe.
+		if cloudInit != nil {
+			_ = cloudInit(ctx)
+		}
+
+		client := &http.Client{Timeout: healthRequestTimeout}
+		wait := healthBackoff
+		var last error
+		for {
+			err := probe(ctx, client, url)
+			if err == nil {
+				return healthMsg{index: index}
+			}
+			// Report why the last complete attempt failed, rather than
+			// that the time ran out during this one.
+			if ctx.Err() == nil || last == nil {
+				last = err
+			}
+
+			select {
+			case <-ctx.Done():
+				return healthMsg{index: index, err: fmt.Errorf("still failing after %s: %w", timeout, last)}
+			case <-time.After(wait):
+			}
+			if wait *= 2; wait > maxHealthBackoff {
+				wait = maxHealthBackoff
+			}
+		}
+	}
+}
+
+// probe requests the URL once. It's an error unless the response has a 2xx
+// status.
+func probe(ctx context.Context, client *http.Client, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("got %s", resp.Status)
+	}
+	return nil
+}
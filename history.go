@@ -0,0 +1,103 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+)
+
+// maxHistory is how many recent values are kept for each field.
+const maxHistory = 10
+
+// historyKeys names the text fields whose recent values are remembered.
+var historyKeys = map[int]string{
+	nameField:       "name",
+	countField:      "count",
+	tagsField:       "tags",
+	volumeNameField: "volume-name",
+	volumeSizeField: "volume-size",
+}
+
+// history holds the recent values entered in each field, newest first.
+type history map[string][]string
+
+// historyPath returns where the history is kept.
+func historyPath() (string, error) {
+	dir, err := stateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "history.yaml"), nil
+}
+
+// loadHistory reads the history, which is empty on first run.
+func loadHistory() history {
+	h := make(history)
+
+	path, err := historyPath()
+	if err != nil {
+		return h
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return h
+	}
+	yaml.Unmarshal(data, &h)
+
+	return h
+}
+
+// saveHistory writes the history. It's only a convenience, so errors are
+// ignored rather than interrupting.
+func saveHistory(h history) tea.Cmd {
+	return func() tea.Msg {
+		path, err := historyPath()
+		if err != nil {
+			return nil
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			return nil
+		}
+		data, err := yaml.Marshal(h)
+		if err != nil {
+			return nil
+		}
+		os.WriteFile(path, data, 0o600)
+
+		return nil
+	}
+}
+
+// add records value as the most recent for the key.
+func (h history) add(key, value string) {
+	if value == "" {
+		return
+	}
+
+	values := []string{value}
+	for _, v := range h[key] {
+		if v != value && len(values) < maxHistory {
+			values = append(values, v)
+		}
+	}
+	h[key] = values
+}
+
+// setHistory gives each text field its recent values.
+func (m *model) setHistory(h history) {
+	m.history = h
+	for i, k := range historyKeys {
+		m.fields[i].(*textField).history = h[k]
+	}
+}
+
+// recordHistory adds the values entered in the form to the history and
+// saves it.
+func (m model) recordHistory() tea.Cmd {
+	for i, k := range historyKeys {
+		m.history.add(k, m.fields[i].(*textField).Model.Value())
+	}
+	return saveHistory(m.history)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+// hookMsg reports how the post-create hook for the Droplet at index exited.
+type hookMsg struct {
+	index int
+	err   error
+}
+
+// hookEnv describes the Droplet to the post-create hook:
+//
+//	DROPLET_ID          its ID
+//	DROPLET_NAME        its name
+//	DROPLET_IP          its public IPv4 address
+//	DROPLET_PRIVATE_IP  its private IPv4 address, in the VPC
+//	DROPLET_IPV6        its public IPv6 address, if enabled
+//	DROPLET_REGION      the region slug
+func hookEnv(droplet *godo.Droplet) []string {
+	ip, _ := droplet.PublicIPv4()
+	privateIP, _ := droplet.PrivateIPv4()
+	ipv6, _ := droplet.PublicIPv6()
+	region := ""
+	if droplet.Region != nil {
+		region = droplet.Region.Slug
+	}
+
+	return append(os.Environ(),
+		"DROPLET_ID="+strconv.Itoa(droplet.ID),
+		"DROPLET_NAME="+droplet.Name,
+		"DROPLET_IP="+ip,
+		"DROPLET_PRIVATE_IP="+privateIP,
+		"DROPLET_IPV6="+ipv6,
+		"DROPLET_REGION="+region,
+	)
+}
+
+// shellCommand runs the command line with the local shell.
+func shellCommand(line string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", line)
+	}
+	return exec.Command("sh", "-c", line)
+}
+
+// startHooks runs the post-create hook for every Droplet that was created.
+func (m model) startHooks() (model, tea.Cmd) {
+	var cmds []tea.Cmd
+	for i, p := range m.pending {
+		if !p.settingUp() {
+			continue
+		}
+		m.pending[i].hooked = true
+		cmds = append(cmds, m.runHook(i))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// runHook runs the post-create hook for the Droplet at index. Its output
+// isn't shown, except for the last line when it fails.
+func (m model) runHook(index int) tea.Cmd {
+	cmd := shellCommand(m.defaults.PostCreate)
+	cmd.Env = hookEnv(m.pending[index].droplet)
+
+	return func() tea.Msg {
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+			if last := lines[len(lines)-1]; last != "" {
+				err = fmt.Errorf("%w: %s", err, last)
+			}
+		}
+		return hookMsg{index: index, err: err}
+	}
+}
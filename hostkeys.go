@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+// The host-key-checking modes:
+//
+//	pin         scan each new Droplet's host keys as soon as SSH is up and
+//	            only accept those from then on (the default)
+//	accept-new  trust whatever key is offered on the first connection
+//	off         don't check host keys at all, for throwaway Droplets
+const (
+	hostKeysPin       = "pin"
+	hostKeysAcceptNew = "accept-new"
+	hostKeysOff       = "off"
+)
+
+// keyscanTimeout is how long ssh-keyscan waits for the Droplet, in seconds.
+const keyscanTimeout = 5
+
+// pinnedMsg reports that the host keys of the Droplet at index have been
+// pinned. If ssh is set, an SSH session was waiting on them.
+type pinnedMsg struct {
+	index int
+	keys  []string
+	ssh   bool
+	err   error
+}
+
+// hostKeyMode returns the configured host-key-checking mode.
+func (m model) hostKeyMode() string {
+	if m.defaults.HostKeyChecking == "" {
+		return hostKeysPin
+	}
+	return m.defaults.HostKeyChecking
+}
+
+// knownHostsPath returns the file the pinned host keys are kept in.
+func knownHostsPath() (string, error) {
+	dir, err := stateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "known_hosts"), nil
+}
+
+// hostKeyOptions returns the SSH options that check the host key the
+// configured way.
+func (m model) hostKeyOptions() []string {
+	switch m.hostKeyMode() {
+	case hostKeysOff:
+		return []string{"-o", "StrictHostKeyChecking=no", "-o", "UserKnownHostsFile=" + os.DevNull}
+	case hostKeysAcceptNew:
+		return []string{"-o", "StrictHostKeyChecking=accept-new"}
+	}
+
+	path, err := knownHostsPath()
+	if err != nil {
+		return []string{"-o", "StrictHostKeyChecking=yes"}
+	}
+	return []string{"-o", "StrictHostKeyChecking=yes", "-o", "UserKnownHostsFile=" + path}
+}
+
+// knownHostsName returns how the Droplet is named in known_hosts files.
+func (m model) knownHostsName(ip string) string {
+	if port := m.sshPort(); port != defaultSSHPort {
+		return "[" + ip + "]:" + strconv.Itoa(port)
+	}
+	return ip
+}
+
+// scanHostKeys asks the Droplet for its host keys, returning them as
+// known_hosts lines.
+func (m model) scanHostKeys(droplet *godo.Droplet) ([]string, error) {
+	ip, _ := droplet.PublicIPv4()
+	if ip == "" {
+		return nil, errors.New("the Droplet has no public IPv4 address")
+	}
+
+	cmd := exec.Command("ssh-keyscan", "-T", strconv.Itoa(keyscanTimeout), "-p", strconv.Itoa(m.sshPort()), ip)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if msg := strings.TrimSpace(stderr.String()); err != nil && msg != "" {
+		return nil, fmt.Errorf("ssh-keyscan: %s", msg)
+	} else if err != nil {
+		return nil, fmt.Errorf("ssh-keyscan: %w", err)
+	}
+
+	var keys []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			keys = append(keys, line)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%s offered no host keys", net.JoinHostPort(ip, strconv.Itoa(m.sshPort())))
+	}
+	return keys, nil
+}
+
+// replaceKnownHosts returns the known_hosts file with the lines for the host
+// replaced by keys. Reserved and recycled IPs mean an old Droplet's keys may
+// still be there.
+func replaceKnownHosts(data, host string, keys []string) string {
+	var kept []string
+	for _, line := range strings.Split(data, "\n") {
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) > 0 && contains(strings.Split(fields[0], ","), host) {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.Join(append(kept, keys...), "\n") + "\n"
+}
+
+// knownHostsMu serializes changes to known_hosts files, since the Droplets
+// of a batch are pinned at once and each change rewrites the whole file.
+var knownHostsMu sync.Mutex
+
+// writeKnownHosts puts the host's keys in the known_hosts file at path.
+func writeKnownHosts(path, host string, keys []string) error {
+	knownHostsMu.Lock()
+	defer knownHostsMu.Unlock()
+
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(replaceKnownHosts(string(data), host, keys)), 0o600)
+}
+
+// pinHostKeys scans the Droplet's host keys and pins them, adding them to
+// ~/.ssh/known_hosts too if the config asks for it.
+func (m model) pinHostKeys(droplet *godo.Droplet) ([]string, error) {
+	keys, err := m.scanHostKeys(droplet)
+	if err != nil {
+		return nil, err
+	}
+	ip, _ := droplet.PublicIPv4()
+	host := m.knownHostsName(ip)
+
+	path, err := knownHostsPath()
+	if err != nil {
+		return nil, err
+	}
+	if err := writeKnownHosts(path, host, keys); err != nil {
+		return nil, err
+	}
+
+	if m.defaults.SeedKnownHosts {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		if err := writeKnownHosts(filepath.Join(home, ".ssh", "known_hosts"), host, keys); err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
+
+// pinForSSH pins the host keys of the Droplet at index before logging into
+// it, when they weren't pinned once SSH came up.
+func (m model) pinForSSH(index int) tea.Cmd {
+	droplet := m.pending[index].droplet
+
+	return func() tea.Msg {
+		keys, err := m.pinHostKeys(droplet)
+		return pinnedMsg{index: index, keys: keys, ssh: true, err: err}
+	}
+}
+
+// keyTypes lists the types of the host keys, e.g. ssh-ed25519, for display.
+func keyTypes(keys []string) string {
+	var types []string
+	for _, k := range keys {
+		if fields := strings.Fields(k); len(fields) > 1 && !contains(types, fields[1]) {
+			types = append(types, fields[1])
+		}
+	}
+	return strings.Join(types, ", ")
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+// userImagesGroup is the group that the account's own images are listed
+// under, regardless of their distribution, and snapshotsGroup the group for
+// its Droplet snapshots.
+const (
+	userImagesGroup = "My images"
+	snapshotsGroup  = "My snapshots"
+)
+
+var groupKey = key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "distribution"))
+
+type imageItem struct {
+	godo.Image
+	group string
+}
+
+// private reports whether the image is one of the account's own, which are
+// only in the regions they've been copied to.
+func (i imageItem) private() bool {
+	return i.group == userImagesGroup || i.group == snapshotsGroup
+}
+
+func (i imageItem) Title() string {
+	if i.private() {
+		return i.Name
+	}
+	return i.Distribution + " " + i.Name
+}
+
+func (i imageItem) Description() string {
+	switch i.group {
+	case userImagesGroup:
+		return fmt.Sprintf("%s · %s · %d", i.group, i.Distribution, i.ID)
+	case snapshotsGroup:
+		return fmt.Sprintf("%s · %.1f GB · needs a %s disk · %d", i.group, i.SizeGigaBytes, formatDisk(i.MinDiskSize), i.ID)
+	}
+	return fmt.Sprintf("%s · %s", i.group, i.Slug)
+}
+
+func (i imageItem) FilterValue() string { return i.group + " " + i.Title() + " " + i.Value() }
+
+func (i imageItem) Value() string {
+	if i.Slug != "" {
+		return i.Slug
+	}
+	return strconv.Itoa(i.ID)
+}
+
+type imagesMsg []imageItem
+
+// fetchImages lists the images for the form.
+func fetchImages(client *godo.Client) tea.Cmd {
+	return func() tea.Msg {
+		images, err := listImages(context.Background(), client)
+		if err != nil {
+			return dropletMsg(dropletErrorMsg(err))
+		}
+		return imagesMsg(images)
+	}
+}
+
+// listImages lists the public distribution images along with the account's
+// own images and Droplet snapshots, sorted by group with the newest versions
+// first.
+func listImages(ctx context.Context, client *godo.Client) ([]imageItem, error) {
+	opt := &godo.ListOptions{PerPage: 200}
+
+	dists, _, err := client.Images.ListDistribution(ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+	user, _, err := client.Images.ListUser(ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	var images []imageItem
+	for _, i := range dists {
+		images = append(images, imageItem{Image: i, group: i.Distribution})
+	}
+	sort.SliceStable(images, func(a, b int) bool {
+		if images[a].group != images[b].group {
+			return images[a].group < images[b].group
+		}
+		return images[a].Name > images[b].Name
+	})
+	snapshots, _, err := client.Snapshots.ListDroplet(ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	// The account's images include its snapshots, which are listed in their
+	// own group instead.
+	isSnapshot := make(map[int]bool)
+	for _, s := range snapshots {
+		id, err := strconv.Atoi(s.ID)
+		if err != nil {
+			continue
+		}
+		isSnapshot[id] = true
+	}
+	for _, i := range user {
+		if !isSnapshot[i.ID] {
+			images = append(images, imageItem{Image: i, group: userImagesGroup})
+		}
+	}
+	for _, s := range snapshots {
+		id, err := strconv.Atoi(s.ID)
+		if err != nil {
+			continue
+		}
+		images = append(images, imageItem{
+			Image: godo.Image{
+				ID:            id,
+				Name:          s.Name,
+				Type:          "snapshot",
+				Regions:       s.Regions,
+				MinDiskSize:   s.MinDiskSize,
+				SizeGigaBytes: s.SizeGigaBytes,
+				Created:       s.Created,
+			},
+			group: snapshotsGroup,
+		})
+	}
+
+	return images, nil
+}
+
+// imagePicker is a selectField for images that can additionally be narrowed
+// down to a single distribution.
+type imagePicker struct {
+	*selectField
+	images []imageItem
+	groups []string
+	group  int
+}
+
+func newImagePicker(fallback string) *imagePicker {
+	p := &imagePicker{
+		selectField: newSelectField("Image: ", "Choose an image", fallback),
+	}
+	p.list.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{groupKey}
+	}
+
+	return p
+}
+
+// SetImages replaces the full set of images the picker chooses from.
+func (p *imagePicker) SetImages(images []imageItem) tea.Cmd {
+	p.images = images
+
+	seen := make(map[string]bool)
+	p.groups = []string{""}
+	for _, i := range images {
+		if !seen[i.group] {
+			seen[i.group] = true
+			p.groups = append(p.groups, i.group)
+		}
+	}
+	p.group = 0
+
+	return p.refresh()
+}
+
+func (p *imagePicker) refresh() tea.Cmd {
+	group := p.groups[p.group]
+
+	var opts []option
+	for _, i := range p.images {
+		if group == "" || i.group == group {
+			opts = append(opts, i)
+		}
+	}
+
+	p.list.Title = "Choose an image"
+	if group != "" {
+		p.list.Title += " · " + group
+	}
+
+	return p.SetOptions(opts)
+}
+
+func (p *imagePicker) Update(msg tea.Msg) (field, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && p.open && !p.list.SettingFilter() {
+		if key.Matches(msg, groupKey) {
+			p.group = (p.group + 1) % len(p.groups)
+			return p, p.refresh()
+		}
+	}
+
+	_, cmd := p.selectField.Update(msg)
+	return p, cmd
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+// kernelsFlow chooses the external kernel a legacy Droplet boots, from the
+// list.
+type kernelsFlow struct {
+	droplet godo.Droplet
+	kernels *selectField
+	err     error
+}
+
+// kernelItem lists a kernel in the kernels menu, marking the one the
+// Droplet boots now.
+type kernelItem struct {
+	godo.Kernel
+	current bool
+}
+
+func (k kernelItem) Title() string { return k.Name }
+
+func (k kernelItem) Description() string {
+	if k.current {
+		return k.Version + " · current"
+	}
+	return k.Version
+}
+
+func (k kernelItem) FilterValue() string { return k.Name }
+func (k kernelItem) Value() string       { return strconv.Itoa(k.ID) }
+
+// kernelsMsg carries the kernels a Droplet can boot.
+type kernelsMsg struct {
+	id      int
+	kernels []godo.Kernel
+	err     error
+}
+
+// fetchKernels lists the kernels the Droplet can boot, following the pages
+// to the last since older Droplets can boot hundreds.
+func fetchKernels(client *godo.Client, id int) tea.Cmd {
+	return func() tea.Msg {
+		var all []godo.Kernel
+		opt := &godo.ListOptions{PerPage: 200}
+		for {
+			kernels, resp, err := client.Droplets.Kernels(context.Background(), id, opt)
+			if err != nil {
+				return kernelsMsg{id: id, err: err}
+			}
+			all = append(all, kernels...)
+			if resp.Links == nil || resp.Links.IsLastPage() {
+				return kernelsMsg{id: id, kernels: all}
+			}
+
+			page, err := resp.Links.CurrentPage()
+			if err != nil {
+				return kernelsMsg{id: id, err: err}
+			}
+			opt.Page = page + 1
+		}
+	}
+}
+
+// openKernels shows the kernels the Droplet can boot, fetching them. Only
+// older Droplets boot an external kernel; newer ones manage their own.
+func (m model) openKernels(droplet godo.Droplet) (model, tea.Cmd) {
+	kernels := newSelectField("", "Kernels for "+droplet.Name+" · booted on its next power on", "")
+	kernels.SetSize(m.width, m.height-1)
+	m.list.kernels = &kernelsFlow{droplet: droplet, kernels: kernels}
+	if droplet.Kernel == nil {
+		m.list.kernels.err = errors.New("it manages its own kernel, so change it from inside the Droplet instead")
+		return m, nil
+	}
+	return m, fetchKernels(m.client, droplet.ID)
+}
+
+// setKernels opens the kernels menu once they've been fetched.
+func (m model) setKernels(msg kernelsMsg) (tea.Model, tea.Cmd) {
+	if m.list == nil || m.list.kernels == nil || m.list.kernels.droplet.ID != msg.id {
+		return m, nil
+	}
+	k := m.list.kernels
+	if msg.err != nil {
+		k.err = msg.err
+		return m, nil
+	}
+
+	opts := make([]option, len(msg.kernels))
+	for i, kernel := range msg.kernels {
+		opts[i] = kernelItem{Kernel: kernel, current: kernel.ID == k.droplet.Kernel.ID}
+	}
+	cmd := k.kernels.SetOptions(opts)
+	k.kernels.Select(strconv.Itoa(k.droplet.Kernel.ID))
+	k.kernels.Open()
+	return m, cmd
+}
+
+func (m model) updateKernels(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.list
+	k := l.kernels
+	if msg.String() == "ctrl+c" {
+		return m, m.quit()
+	}
+
+	if k.err != nil || !k.kernels.Loaded() {
+		if msg.String() == "esc" {
+			l.kernels = nil
+		}
+		return m, nil
+	}
+
+	_, cmd := k.kernels.Update(msg)
+	if k.kernels.Opened() {
+		return m, cmd
+	}
+	l.kernels = nil
+	kernel, ok := k.kernels.selected.(kernelItem)
+	if !ok || kernel.current {
+		return m, cmd
+	}
+	l.busy[k.droplet.ID] = "changing"
+	l.setRows()
+	return m, tea.Batch(cmd, changeKernel(m.client, k.droplet, kernel.Kernel))
+}
+
+// changeKernel switches the Droplet to the kernel and waits for the action
+// to complete. It boots the new kernel the next time it's powered on.
+func changeKernel(client *godo.Client, droplet godo.Droplet, kernel godo.Kernel) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+
+		action, _, err := client.DropletActions.ChangeKernel(ctx, droplet.ID, kernel.ID)
+		if err == nil {
+			err = waitForAction(ctx, client, action)
+		}
+		return dropletActionMsg{id: droplet.ID, name: fmt.Sprintf("%s to %s", droplet.Name, kernel.Name), verb: "change the kernel of", done: "Changed the kernel of", err: err}
+	}
+}
+
+func (m model) kernelsView() string {
+	k := m.list.kernels
+	var b strings.Builder
+
+	switch {
+	case k.err != nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(fmt.Sprintf("can't change the kernel of %s: %s", k.droplet.Name, k.err)))
+		b.WriteString(helpStyle.Render("esc: back"))
+	case !k.kernels.Loaded():
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading kernels..."))
+		b.WriteString(helpStyle.Render("esc: cancel"))
+	default:
+		return k.kernels.View()
+	}
+
+	return b.String()
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+var newKeyKey = key.NewBinding(key.WithKeys("ctrl+n"), key.WithHelp("ctrl+n", "new key"))
+
+// newKeyFile is what a generated key is called in ~/.ssh, before a number is
+// added to keep it from replacing another.
+const newKeyFile = "id_ed25519_digitalocean"
+
+// keyGeneratedMsg reports that ssh-keygen has exited, leaving the new key at
+// path unless err says otherwise.
+type keyGeneratedMsg struct {
+	path string
+	err  error
+}
+
+// keyCreatedMsg reports that the public key at path has been added to the
+// account.
+type keyCreatedMsg struct {
+	key  *godo.Key
+	path string
+	err  error
+}
+
+// newKeyPath returns where in ~/.ssh to save a generated key without
+// replacing one that's already there.
+func newKeyPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	for i := 1; ; i++ {
+		name := newKeyFile
+		if i > 1 {
+			name += "_" + strconv.Itoa(i)
+		}
+		path := filepath.Join(home, ".ssh", name)
+		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+			return path, nil
+		} else if err != nil {
+			return "", err
+		}
+	}
+}
+
+// newKeyName is what the generated key is called on the account, so it's
+// clear where it came from.
+func newKeyName() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		return localUsername()
+	}
+	return localUsername() + "@" + host
+}
+
+// generateKey hands the terminal over to ssh-keygen to make a new ed25519
+// keypair, so that it can ask for a passphrase.
+func generateKey() tea.Cmd {
+	path, err := newKeyPath()
+	if err != nil {
+		return func() tea.Msg { return keyGeneratedMsg{err: err} }
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return func() tea.Msg { return keyGeneratedMsg{err: err} }
+	}
+
+	cmd := exec.Command("ssh-keygen", "-t", "ed25519", "-f", path, "-C", newKeyName())
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return keyGeneratedMsg{path: path, err: err}
+	})
+}
+
+// createKey adds the public half of the key at path to the account.
+func createKey(client *godo.Client, path string) tea.Cmd {
+	return func() tea.Msg {
+		pub, err := os.ReadFile(path + ".pub")
+		if err != nil {
+			return keyCreatedMsg{path: path, err: err}
+		}
+
+		k, _, err := client.Keys.Create(context.Background(), &godo.KeyCreateRequest{
+			Name:      newKeyName(),
+			PublicKey: strings.TrimSpace(string(pub)),
+		})
+		return keyCreatedMsg{key: k, path: path, err: err}
+	}
+}
+
+// addKey chooses the key that was just created for the Droplet, and logs in
+// with it unless another identity is configured.
+func (m model) addKey(msg keyCreatedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.formErr = fmt.Sprintf("couldn't add %s.pub to the account: %s", msg.path, msg.err)
+		return m, nil
+	}
+
+	keys := m.fields[keysField].(*multiSelectField)
+	cmd := keys.SetOptions(append(keys.options, keyItem{*msg.key}))
+	keys.Check(strconv.Itoa(msg.key.ID))
+	if m.defaults.SSHIdentity == "" {
+		m.defaults.SSHIdentity = msg.path
+	}
+	m.edited = true
+	m.formErr = ""
+
+	return m, tea.Batch(cmd, m.toast(fmt.Sprintf("Saved the new key to %s and added it to the account", msg.path)))
+}
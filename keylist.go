@@ -0,0 +1,513 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+var uploadKeyKey = key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "upload"))
+
+// keysPerPage is how many SSH keys the keys screen fetches at a time.
+const keysPerPage = 20
+
+// keyListMsg carries a page of the account's SSH keys, along with how many
+// there are in all.
+type keyListMsg struct {
+	page  int
+	keys  []godo.Key
+	total int
+	err   error
+}
+
+// fetchKeyList lists a page of the account's SSH keys, counting from 1.
+func fetchKeyList(client *godo.Client, page int) tea.Cmd {
+	return func() tea.Msg {
+		keys, resp, err := client.Keys.List(context.Background(), &godo.ListOptions{Page: page, PerPage: keysPerPage})
+		if err != nil {
+			return keyListMsg{page: page, err: err}
+		}
+
+		total := len(keys)
+		if resp.Meta != nil {
+			total = resp.Meta.Total
+		}
+		return keyListMsg{page: page, keys: keys, total: total}
+	}
+}
+
+// keyList is the screen listing the SSH keys on the account, a page at a
+// time.
+type keyList struct {
+	table   table.Model
+	keys    []godo.Key
+	page    int
+	total   int
+	loading bool
+	err     error
+	// upload is set while uploading a key, and confirmDelete while asking to
+	// confirm deleting one.
+	upload        *keyUpload
+	confirmDelete *keyDeletePrompt
+	// uploading holds the names of the keys being uploaded, and deleting
+	// the IDs of the keys being deleted.
+	uploading []string
+	deleting  map[int]bool
+}
+
+func newKeyList(width, height int) *keyList {
+	l := &keyList{
+		page:     1,
+		deleting: make(map[int]bool),
+		table: newListTable([]table.Column{
+			{Title: "Name", Width: 28},
+			{Title: "Fingerprint", Width: 50},
+			{Title: "ID", Width: 10},
+			{Title: "Status", Width: 10},
+		}),
+	}
+	l.SetSize(width, height)
+	return l
+}
+
+// SetSize fits the table to the screen, under the title and above the help.
+func (l *keyList) SetSize(width, height int) {
+	setTableSize(&l.table, width, height)
+}
+
+// pages returns how many pages of keys there are.
+func (l *keyList) pages() int {
+	return pageCount(l.total, keysPerPage)
+}
+
+// setKeyList shows a page of keys once it's been fetched.
+func (m model) setKeyList(msg keyListMsg) (tea.Model, tea.Cmd) {
+	l := m.sshKeys
+	if l == nil {
+		return m, nil
+	}
+	// The last page was emptied, e.g. by deleting, so go back one.
+	if msg.err == nil && len(msg.keys) == 0 && msg.page > 1 {
+		return m, fetchKeyList(m.client, msg.page-1)
+	}
+
+	l.loading = false
+	l.err = msg.err
+	if msg.err != nil {
+		return m, nil
+	}
+	l.page, l.keys, l.total = msg.page, msg.keys, msg.total
+	l.setRows()
+	return m, nil
+}
+
+// setRows fills the table in from the keys.
+func (l *keyList) setRows() {
+	rows := make([]table.Row, len(l.keys))
+	for i, k := range l.keys {
+		status := ""
+		if l.deleting[k.ID] {
+			status = "deleting…"
+		}
+		rows[i] = table.Row{k.Name, k.Fingerprint, strconv.Itoa(k.ID), status}
+	}
+	l.table.SetRows(rows)
+	if l.table.Cursor() >= len(rows) {
+		l.table.GotoTop()
+	}
+}
+
+// selected returns the key under the cursor, unless there are none or it's
+// being deleted.
+func (l *keyList) selected() (godo.Key, bool) {
+	i := l.table.Cursor()
+	if i < 0 || i >= len(l.keys) {
+		return godo.Key{}, false
+	}
+	return l.keys[i], !l.deleting[l.keys[i].ID]
+}
+
+// openKeys shows the list of SSH keys, fetching its first page.
+func (m model) openKeys() (model, tea.Cmd) {
+	m.sshKeys = newKeyList(m.width, m.height-1)
+	m.sshKeys.loading = true
+	m.notice = ""
+	m.formErr = ""
+	return m, tea.Batch(fetchKeyList(m.client, 1), m.spinner.Tick)
+}
+
+func (m model) updateKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.sshKeys
+	if l.upload != nil {
+		return m.updateKeyUpload(msg)
+	}
+	if l.confirmDelete != nil {
+		return m.updateKeyDelete(msg)
+	}
+
+	switch {
+	case msg.String() == "ctrl+c":
+		return m, m.quit()
+	case key.Matches(msg, closeListKeys):
+		return m.closeScreen()
+	case key.Matches(msg, screensKey):
+		return m.openScreens()
+	case key.Matches(msg, uploadKeyKey):
+		return m.openKeyUpload()
+	case l.loading:
+		return m, nil
+	case key.Matches(msg, deleteKey):
+		return m.promptKeyDelete()
+	case key.Matches(msg, refreshListKey):
+		l.loading = true
+		return m, fetchKeyList(m.client, l.page)
+	case key.Matches(msg, nextPageKey) && l.page < l.pages():
+		l.loading = true
+		return m, fetchKeyList(m.client, l.page+1)
+	case key.Matches(msg, prevPageKey) && l.page > 1:
+		l.loading = true
+		return m, fetchKeyList(m.client, l.page-1)
+	}
+
+	var cmd tea.Cmd
+	l.table, cmd = l.table.Update(msg)
+	return m, cmd
+}
+
+func (m model) keysView() string {
+	l := m.sshKeys
+	var b strings.Builder
+
+	title := focusedStyle.Render("SSH keys")
+	if l.total > 0 {
+		title += placeholderStyle.Render(fmt.Sprintf("  %s · page %d of %d", pluralize(l.total, "key"), l.page, l.pages()))
+	}
+	b.WriteString(title + "\n\n")
+
+	switch {
+	case l.loading && l.keys == nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading SSH keys..."))
+	case l.err != nil && l.keys == nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the SSH keys: "+l.err.Error()))
+	case len(l.keys) == 0:
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("There are no SSH keys on this account yet."))
+	default:
+		fmt.Fprintf(&b, "%s\n\n", l.table.View())
+	}
+
+	switch {
+	case l.loading && l.keys != nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading..."))
+	case l.err != nil && l.keys != nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the SSH keys: "+l.err.Error()))
+	}
+	if len(l.uploading) > 0 {
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Uploading "+strings.Join(l.uploading, ", ")+"..."))
+	}
+	if m.notice != "" {
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render(m.notice))
+	}
+	if m.formErr != "" {
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(m.formErr))
+	}
+
+	switch {
+	case l.upload != nil:
+		b.WriteString(m.keyUploadView())
+		return b.String()
+	case l.confirmDelete != nil:
+		b.WriteString(m.keyDeleteView())
+		b.WriteString(helpStyle.Render("enter: delete • esc: cancel"))
+		return b.String()
+	}
+
+	help := []string{"↑/↓: move", "c: upload", "d: delete"}
+	if l.pages() > 1 {
+		help = append(help, "←/→: page")
+	}
+	help = append(help, "r: refresh", "tab: screens", m.closeScreenHelp())
+	b.WriteString(helpStyle.Render(strings.Join(help, " • ")))
+
+	return b.String()
+}
+
+// keyUpload asks for the public key file to upload and then what to call
+// the key on the account.
+type keyUpload struct {
+	path *textField
+	// name and publicKey are set once the file's been read.
+	name      *textField
+	publicKey string
+}
+
+// defaultPublicKeyPath is offered for uploading, as the key ssh-keygen
+// makes by default.
+const defaultPublicKeyPath = "~/.ssh/id_ed25519.pub"
+
+// openKeyUpload starts uploading a public key.
+func (m model) openKeyUpload() (model, tea.Cmd) {
+	if m.blockChanges("SSH keys", "uploaded") {
+		return m, nil
+	}
+
+	path := newOptionalTextField("Public key file: ", defaultPublicKeyPath)
+	path.CharLimit = 4096
+	path.SetValue(defaultPublicKeyPath)
+	m.sshKeys.upload = &keyUpload{path: path}
+	m.notice = ""
+	m.formErr = ""
+	return m, path.Focus()
+}
+
+func (m model) updateKeyUpload(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.sshKeys
+	u := l.upload
+	if msg.String() == "ctrl+c" {
+		return m, m.quit()
+	}
+
+	if u.name != nil {
+		switch msg.String() {
+		case "esc":
+			// Back to the file.
+			u.name = nil
+			m.formErr = ""
+			return m, u.path.Focus()
+		case "enter":
+			name := strings.TrimSpace(u.name.Value())
+			if name == "" {
+				m.formErr = "the key needs a name"
+				return m, nil
+			}
+			l.upload = nil
+			m.formErr = ""
+			l.uploading = append(l.uploading, name)
+			return m, uploadKey(m.client, name, u.publicKey)
+		}
+		_, cmd := u.name.Update(msg)
+		return m, cmd
+	}
+
+	switch msg.String() {
+	case "esc":
+		l.upload = nil
+		m.formErr = ""
+		return m, nil
+	case "enter":
+		path, err := expandHome(strings.TrimSpace(u.path.Value()))
+		if err != nil {
+			m.formErr = err.Error()
+			return m, nil
+		}
+		pub, comment, err := readPublicKey(path)
+		if err != nil {
+			m.formErr = err.Error()
+			return m, nil
+		}
+		name := comment
+		if name == "" {
+			name = strings.TrimSuffix(filepath.Base(path), ".pub")
+		}
+
+		m.formErr = ""
+		u.path.Blur()
+		u.publicKey = pub
+		u.name = newOptionalTextField("Name: ", "what to call it on the account")
+		u.name.CharLimit = 255
+		u.name.SetValue(name)
+		return m, u.name.Focus()
+	}
+	_, cmd := u.path.Update(msg)
+	return m, cmd
+}
+
+// readPublicKey reads the OpenSSH public key at path, returning it along
+// with its comment, which is usually who it belongs to.
+func readPublicKey(path string) (string, string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", err
+	}
+
+	pub := strings.TrimSpace(string(b))
+	fields := strings.Fields(pub)
+	if strings.Contains(pub, "PRIVATE KEY") {
+		return "", "", fmt.Errorf("%s is a private key; choose its .pub file instead", path)
+	}
+	if len(fields) < 2 || strings.Contains(pub, "\n") {
+		return "", "", fmt.Errorf("%s isn't an OpenSSH public key, e.g. one made by ssh-keygen", path)
+	}
+	return pub, strings.Join(fields[2:], " "), nil
+}
+
+// keyUploadedMsg reports that a key has been uploaded, or couldn't be.
+type keyUploadedMsg struct {
+	name string
+	key  *godo.Key
+	err  error
+}
+
+func uploadKey(client *godo.Client, name, publicKey string) tea.Cmd {
+	return func() tea.Msg {
+		k, _, err := client.Keys.Create(context.Background(), &godo.KeyCreateRequest{Name: name, PublicKey: publicKey})
+		return keyUploadedMsg{name: name, key: k, err: err}
+	}
+}
+
+// keyUploaded refreshes the list once a key's been uploaded, offering it in
+// the form's SSH keys straight away.
+func (m model) keyUploaded(msg keyUploadedMsg) (tea.Model, tea.Cmd) {
+	l := m.sshKeys
+	if l != nil {
+		for i, name := range l.uploading {
+			if name == msg.name {
+				l.uploading = append(l.uploading[:i:i], l.uploading[i+1:]...)
+				break
+			}
+		}
+	}
+	if msg.err != nil {
+		m.formErr = fmt.Sprintf("couldn't upload %s: %s", msg.name, msg.err)
+		return m, nil
+	}
+
+	keys := m.fields[keysField].(*multiSelectField)
+	cmds := []tea.Cmd{
+		keys.SetOptions(append(keys.options, keyItem{*msg.key})),
+		m.toast(fmt.Sprintf("Uploaded %s; it can be chosen for new Droplets now", msg.name)),
+	}
+	if l != nil {
+		l.loading = true
+		cmds = append(cmds, fetchKeyList(m.client, l.page))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+func (m model) keyUploadView() string {
+	u := m.sshKeys.upload
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s\n\n", focusedStyle.Render("Upload a public key"))
+	fmt.Fprintf(&b, "%s\n", u.path.View())
+	if u.name != nil {
+		fmt.Fprintf(&b, "%s\n\n", u.name.View())
+		b.WriteString(helpStyle.Render("enter: upload • esc: back"))
+	} else {
+		b.WriteString("\n" + helpStyle.Render("enter: next • esc: cancel"))
+	}
+
+	return b.String()
+}
+
+// keyDeletePrompt asks for a key's name before deleting it.
+type keyDeletePrompt struct {
+	key  godo.Key
+	name *textField
+}
+
+// promptKeyDelete asks to confirm deleting the key under the cursor.
+func (m model) promptKeyDelete() (model, tea.Cmd) {
+	if m.blockChanges("SSH keys", "deleted") {
+		return m, nil
+	}
+
+	l := m.sshKeys
+	k, ok := l.selected()
+	if !ok {
+		return m, nil
+	}
+	name := newOptionalTextField("Type its name to delete it: ", k.Name)
+	name.CharLimit = 255
+	l.confirmDelete = &keyDeletePrompt{key: k, name: name}
+	m.notice = ""
+	m.formErr = ""
+	return m, name.Focus()
+}
+
+func (m model) updateKeyDelete(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.sshKeys
+	p := l.confirmDelete
+	switch msg.String() {
+	case "ctrl+c":
+		return m, m.quit()
+	case "esc":
+		l.confirmDelete = nil
+		m.formErr = ""
+		return m, nil
+	case "enter":
+		if strings.TrimSpace(p.name.Model.Value()) != p.key.Name {
+			m.formErr = "the name doesn't match; type it exactly, or press esc to cancel"
+			return m, nil
+		}
+		l.confirmDelete = nil
+		m.formErr = ""
+		l.deleting[p.key.ID] = true
+		l.setRows()
+		return m, deleteSSHKey(m.client, p.key)
+	}
+
+	_, cmd := p.name.Update(msg)
+	return m, cmd
+}
+
+// keyDeletedMsg reports that a key has been deleted, or couldn't be.
+type keyDeletedMsg struct {
+	key godo.Key
+	err error
+}
+
+func deleteSSHKey(client *godo.Client, k godo.Key) tea.Cmd {
+	return func() tea.Msg {
+		_, err := client.Keys.DeleteByID(context.Background(), k.ID)
+		return keyDeletedMsg{key: k, err: err}
+	}
+}
+
+// keyDeleted refreshes the list once a key's been deleted, and takes it out
+// of the form's SSH keys so it can't be chosen for a Droplet.
+func (m model) keyDeleted(msg keyDeletedMsg) (tea.Model, tea.Cmd) {
+	l := m.sshKeys
+	if l != nil {
+		delete(l.deleting, msg.key.ID)
+		l.setRows()
+	}
+	if msg.err != nil {
+		m.formErr = fmt.Sprintf("couldn't delete %s: %s", msg.key.Name, msg.err)
+		return m, nil
+	}
+
+	id := strconv.Itoa(msg.key.ID)
+	keys := m.fields[keysField].(*multiSelectField)
+	var opts []option
+	for _, o := range keys.options {
+		if o.Value() != id {
+			opts = append(opts, o)
+		}
+	}
+	delete(keys.checked, id)
+	cmds := []tea.Cmd{keys.SetOptions(opts), m.toast("Deleted " + msg.key.Name)}
+	if l != nil {
+		l.loading = true
+		cmds = append(cmds, fetchKeyList(m.client, l.page))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+func (m model) keyDeleteView() string {
+	p := m.sshKeys.confirmDelete
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s\n", focusedStyle.Render(fmt.Sprintf("Delete %s?", p.key.Name)))
+	fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("Droplets that already have it keep it, but it can't be added to new ones."))
+	fmt.Fprintf(&b, "%s\n\n", p.name.View())
+
+	return b.String()
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/zalando/go-keyring"
+)
+
+// The keyring entry the API token is saved under.
+const (
+	keyringService = "bubbletea-droplet"
+	keyringUser    = "api-token"
+)
+
+// savedMsg reports the result of saving the token to the keyring.
+type savedMsg struct {
+	err error
+}
+
+// keyringToken returns the token saved in the system keyring, or an empty
+// string if there isn't one or no keyring is available.
+func keyringToken() string {
+	token, err := keyring.Get(keyringService, keyringUser)
+	if err != nil {
+		return ""
+	}
+	return token
+}
+
+// saveToken stores the token in the system keyring for next time.
+func saveToken(token string) tea.Cmd {
+	return func() tea.Msg {
+		return savedMsg{err: keyring.Set(keyringService, keyringUser, token)}
+	}
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+	"gopkg.in/yaml.v3"
+)
+
+var kubeconfigKey = key.NewBinding(key.WithKeys("k"), key.WithHelp("k", "save kubeconfig"))
+
+// kubeconfig is the part of a kubeconfig file that's merged. Everything
+// else in it is kept as it is.
+type kubeconfig struct {
+	APIVersion     string                   `yaml:"apiVersion,omitempty"`
+	Kind           string                   `yaml:"kind,omitempty"`
+	Clusters       []map[string]interface{} `yaml:"clusters"`
+	Contexts       []map[string]interface{} `yaml:"contexts"`
+	Users          []map[string]interface{} `yaml:"users"`
+	CurrentContext string                   `yaml:"current-context"`
+	Rest           map[string]interface{}   `yaml:",inline"`
+}
+
+// kubeconfigPath returns where kubectl reads its config from: the first
+// file in $KUBECONFIG, or else ~/.kube/config.
+func kubeconfigPath() (string, error) {
+	if paths := filepath.SplitList(os.Getenv("KUBECONFIG")); len(paths) > 0 && paths[0] != "" {
+		return paths[0], nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".kube", "config"), nil
+}
+
+// expandHome replaces a leading ~ in a typed path with the home directory.
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, path[1:]), nil
+}
+
+// mergeKubeconfig adds the clusters, users and contexts of add to config,
+// replacing any of the same name, and makes add's context the current one.
+// It returns the merged config and the name of that context.
+func mergeKubeconfig(config, add []byte) ([]byte, string, error) {
+	var merged, added kubeconfig
+	if err := yaml.Unmarshal(config, &merged); err != nil {
+		return nil, "", fmt.Errorf("couldn't read the existing kubeconfig: %w", err)
+	}
+	if err := yaml.Unmarshal(add, &added); err != nil {
+		return nil, "", fmt.Errorf("couldn't read the cluster's kubeconfig: %w", err)
+	}
+
+	if merged.APIVersion == "" {
+		merged.APIVersion, merged.Kind = added.APIVersion, added.Kind
+	}
+	merged.Clusters = mergeNamed(merged.Clusters, added.Clusters)
+	merged.Users = mergeNamed(merged.Users, added.Users)
+	merged.Contexts = mergeNamed(merged.Contexts, added.Contexts)
+	if added.CurrentContext != "" {
+		merged.CurrentContext = added.CurrentContext
+	}
+
+	// Indented the way kubectl writes it.
+	var b bytes.Buffer
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	if err := enc.Encode(merged); err != nil {
+		return nil, "", err
+	}
+	return b.Bytes(), added.CurrentContext, nil
+}
+
+// mergeNamed returns the entries with those of add replacing the ones of
+// the same name, or appended after them.
+func mergeNamed(entries, add []map[string]interface{}) []map[string]interface{} {
+	for _, a := range add {
+		replaced := false
+		for i, e := range entries {
+			if e["name"] == a["name"] {
+				entries[i], replaced = a, true
+				break
+			}
+		}
+		if !replaced {
+			entries = append(entries, a)
+		}
+	}
+	return entries
+}
+
+// kubeconfigPrompt asks where to save a cluster's kubeconfig before
+// merging it in.
+type kubeconfigPrompt struct {
+	cluster *godo.KubernetesCluster
+	path    *textField
+}
+
+// kubeconfigMsg reports that a cluster's kubeconfig has been saved.
+type kubeconfigMsg struct {
+	cluster     string
+	path        string
+	contextName string
+	err         error
+}
+
+// promptKubeconfig asks where to save the kubeconfig of the cluster under
+// the cursor, starting on where kubectl reads it from.
+func (m model) promptKubeconfig() (model, tea.Cmd) {
+	l := m.clusters
+	c, ok := l.selected()
+	if !ok {
+		return m, nil
+	}
+	if c.Status == nil || c.Status.State != godo.KubernetesClusterStatusRunning {
+		m.formErr = fmt.Sprintf("%s isn't running yet, so it has no kubeconfig", c.Name)
+		return m, nil
+	}
+	def, err := kubeconfigPath()
+	if err != nil {
+		m.formErr = "couldn't find the kubeconfig: " + err.Error()
+		return m, nil
+	}
+
+	path := newTextField("Save to: ", def)
+	path.CharLimit = 4096
+	l.kubeconfig = &kubeconfigPrompt{cluster: c, path: path}
+	m.notice = ""
+	m.formErr = ""
+	return m, path.Focus()
+}
+
+func (m model) updateKubeconfig(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.clusters
+	p := l.kubeconfig
+	switch msg.String() {
+	case "ctrl+c":
+		return m, m.quit()
+	case "esc":
+		l.kubeconfig = nil
+		m.formErr = ""
+		return m, nil
+	case "enter":
+		path, err := expandHome(strings.TrimSpace(p.path.Value()))
+		if err != nil {
+			m.formErr = err.Error()
+			return m, nil
+		}
+		l.kubeconfig = nil
+		m.formErr = ""
+		return m, saveKubeconfig(m.client, p.cluster, path)
+	}
+
+	_, cmd := p.path.Update(msg)
+	return m, cmd
+}
+
+// saveKubeconfig downloads the cluster's kubeconfig and merges it into the
+// file at path, creating it if need be.
+func saveKubeconfig(client *godo.Client, c *godo.KubernetesCluster, path string) tea.Cmd {
+	return func() tea.Msg {
+		config, _, err := client.Kubernetes.GetKubeConfig(context.Background(), c.ID)
+		if err != nil {
+			return kubeconfigMsg{cluster: c.Name, path: path, err: err}
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return kubeconfigMsg{cluster: c.Name, path: path, err: err}
+		}
+		merged, contextName, err := mergeKubeconfig(data, config.KubeconfigYAML)
+		if err != nil {
+			return kubeconfigMsg{cluster: c.Name, path: path, err: err}
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			return kubeconfigMsg{cluster: c.Name, path: path, err: err}
+		}
+		if err := os.WriteFile(path, merged, 0o600); err != nil {
+			return kubeconfigMsg{cluster: c.Name, path: path, err: err}
+		}
+
+		return kubeconfigMsg{cluster: c.Name, path: path, contextName: contextName}
+	}
+}
+
+// kubeconfigSaved reports where a cluster's kubeconfig was saved.
+func (m model) kubeconfigSaved(msg kubeconfigMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.formErr = fmt.Sprintf("couldn't save the kubeconfig of %s to %s: %s", msg.cluster, msg.path, msg.err)
+		return m, nil
+	}
+	// Left up, rather than toasted, so the context can be copied.
+	m.notice = fmt.Sprintf("Saved %s to %s as the current context, %s", msg.cluster, msg.path, msg.contextName)
+	return m, nil
+}
+
+func (m model) kubeconfigView() string {
+	p := m.clusters.kubeconfig
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s\n", focusedStyle.Render(fmt.Sprintf("Save the kubeconfig of %s?", p.cluster.Name)))
+	fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("Its cluster, user and context are merged into the file, replacing any of the same name, and the context is made current. The file is created if it doesn't exist. Its token expires after 7 days, when it can be saved again."))
+	fmt.Fprintf(&b, "%s\n\n", p.path.View())
+
+	return b.String()
+}
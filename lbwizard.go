@@ -0,0 +1,780 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+// lbPollInterval is how often a new load balancer is checked on while it
+// starts up, as often as util.WaitForActive checks on actions.
+const lbPollInterval = 5 * time.Second
+
+// lbStep is a step of the load balancer wizard, in the order they're taken.
+type lbStep int
+
+const (
+	lbStepName lbStep = iota
+	lbStepRegion
+	lbStepRules
+	lbStepCertificate
+	lbStepHealth
+	lbStepTargets
+	lbStepDroplets
+	lbStepTag
+	lbStepReview
+)
+
+// lbTargetKind is how a new load balancer picks the Droplets it sends
+// traffic to.
+type lbTargetKind struct {
+	tag bool
+}
+
+func (k lbTargetKind) Title() string {
+	if k.tag {
+		return "A tag"
+	}
+	return "Droplets"
+}
+
+func (k lbTargetKind) Description() string {
+	if k.tag {
+		return "sends traffic to every Droplet with the tag, including ones tagged later"
+	}
+	return "sends traffic to the Droplets chosen now"
+}
+
+func (k lbTargetKind) FilterValue() string { return k.Title() }
+func (k lbTargetKind) Value() string       { return k.Title() }
+
+// lbWizard walks through creating a load balancer a step at a time. Esc goes
+// back a step.
+type lbWizard struct {
+	step lbStep
+
+	name         *textField
+	regions      *selectField
+	rules        *textField
+	certificates *selectField
+	health       *textField
+	targets      *selectField
+	droplets     *multiSelectField
+	tag          *textField
+	// dropletsRegion is the region droplets lists the Droplets of.
+	dropletsRegion string
+
+	// The answers so far, parsed.
+	region     string
+	forwarding []godo.ForwardingRule
+	check      *godo.HealthCheck
+	// dropletNames is the names of the chosen Droplets, for the review.
+	dropletNames []string
+	// err is set if the regions, certificates or Droplets couldn't be
+	// fetched.
+	err error
+}
+
+// lbRegionsMsg carries the regions a load balancer can be created in.
+type lbRegionsMsg struct {
+	regions []option
+	err     error
+}
+
+// fetchLBRegions lists the regions that currently accept new resources.
+func fetchLBRegions(client *godo.Client) tea.Cmd {
+	return func() tea.Msg {
+		regions, _, err := client.Regions.List(context.Background(), &godo.ListOptions{PerPage: 200})
+		if err != nil {
+			return lbRegionsMsg{err: err}
+		}
+
+		var opts []option
+		for _, r := range regions {
+			if r.Available {
+				opts = append(opts, regionItem{r})
+			}
+		}
+		return lbRegionsMsg{regions: opts}
+	}
+}
+
+// lbDropletsMsg carries the Droplets in the region a load balancer is
+// being created in.
+type lbDropletsMsg struct {
+	region   string
+	droplets []godo.Droplet
+	err      error
+}
+
+func fetchLBDroplets(client *godo.Client, region string) tea.Cmd {
+	return func() tea.Msg {
+		droplets, err := dropletsInRegion(context.Background(), client, region)
+		return lbDropletsMsg{region: region, droplets: droplets, err: err}
+	}
+}
+
+// openLBWizard starts creating a load balancer, asking for its name first.
+func (m model) openLBWizard() (model, tea.Cmd) {
+	if m.blockChanges("load balancers", "created") {
+		return m, nil
+	}
+
+	w := &lbWizard{name: newOptionalTextField("Name: ", "e.g. web-lb")}
+	w.name.CharLimit = 255
+	m.loadBalancers.create = w
+	m.notice = ""
+	m.formErr = ""
+	return m, w.name.Focus()
+}
+
+// setLBRegions offers the regions once they've been fetched, starting on
+// the form's region.
+func (m model) setLBRegions(msg lbRegionsMsg) (tea.Model, tea.Cmd) {
+	if m.loadBalancers == nil || m.loadBalancers.create == nil || m.loadBalancers.create.regions == nil {
+		return m, nil
+	}
+	w := m.loadBalancers.create
+	if msg.err != nil {
+		w.err = msg.err
+		return m, nil
+	}
+
+	cmd := w.regions.SetOptions(msg.regions)
+	w.regions.Select(m.fields[regionField].Value())
+	w.regions.selected = nil
+	if w.step == lbStepRegion {
+		w.regions.Open()
+	}
+	return m, cmd
+}
+
+// setLBCertificates offers the certificates once they've been fetched, for
+// the rules that end TLS at the load balancer.
+func (m model) setLBCertificates(msg certificatesMsg) (tea.Model, tea.Cmd) {
+	w := m.loadBalancers.create
+	if msg.err == nil && len(msg.certificates) == 0 {
+		msg.err = errors.New("there are no certificates in this account; add one on the Certificates screen, or forward https to https to pass TLS through instead")
+	}
+	if msg.err != nil {
+		// Fetched again if the step's come back to.
+		w.certificates = nil
+		w.err = msg.err
+		return m, nil
+	}
+
+	opts := make([]option, len(msg.certificates))
+	for i, c := range msg.certificates {
+		opts[i] = certificateItem{c}
+	}
+	cmd := w.certificates.SetOptions(opts)
+	if w.step == lbStepCertificate {
+		w.certificates.Open()
+	}
+	return m, cmd
+}
+
+// setLBDroplets offers the Droplets in the chosen region once they've been
+// fetched.
+func (m model) setLBDroplets(msg lbDropletsMsg) (tea.Model, tea.Cmd) {
+	if m.loadBalancers == nil || m.loadBalancers.create == nil {
+		return m, nil
+	}
+	w := m.loadBalancers.create
+	if w.droplets == nil || w.dropletsRegion != msg.region {
+		return m, nil
+	}
+	if msg.err == nil && len(msg.droplets) == 0 {
+		msg.err = fmt.Errorf("there are no Droplets in %s; press esc to choose a tag instead", msg.region)
+	}
+	if msg.err != nil {
+		w.err = msg.err
+		return m, nil
+	}
+
+	opts := make([]option, len(msg.droplets))
+	for i, d := range msg.droplets {
+		opts[i] = dropletOption{d}
+	}
+	cmd := w.droplets.SetOptions(opts)
+	w.droplets.Open()
+	return m, cmd
+}
+
+func (m model) updateLBWizard(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.loadBalancers
+	w := l.create
+	if msg.String() == "ctrl+c" {
+		return m, m.quit()
+	}
+
+	switch w.step {
+	case lbStepRegion:
+		if !w.regions.Opened() {
+			// Waiting for the regions.
+			if msg.String() == "esc" {
+				return m.lbWizardBack()
+			}
+			return m, nil
+		}
+		_, cmd := w.regions.Update(msg)
+		if w.regions.Opened() {
+			return m, cmd
+		}
+		if w.regions.selected == nil {
+			return m.lbWizardBack()
+		}
+		w.region = w.regions.Value()
+		return m.lbWizardStep(lbStepRules)
+
+	case lbStepCertificate:
+		if !w.certificates.Opened() {
+			// Waiting for the certificates.
+			if msg.String() == "esc" {
+				return m.lbWizardBack()
+			}
+			return m, nil
+		}
+		_, cmd := w.certificates.Update(msg)
+		if w.certificates.Opened() {
+			return m, cmd
+		}
+		if msg.String() == "esc" || w.certificates.selected == nil {
+			return m.lbWizardBack()
+		}
+		for i, rule := range w.forwarding {
+			if ruleNeedsCertificate(rule) {
+				w.forwarding[i].CertificateID = w.certificates.Value()
+			}
+		}
+		return m.lbWizardStep(lbStepHealth)
+
+	case lbStepTargets:
+		_, cmd := w.targets.Update(msg)
+		if w.targets.Opened() {
+			return m, cmd
+		}
+		kind, ok := w.targets.selected.(lbTargetKind)
+		if !ok {
+			return m.lbWizardBack()
+		}
+		if kind.tag {
+			return m.lbWizardStep(lbStepTag)
+		}
+		return m.lbWizardStep(lbStepDroplets)
+
+	case lbStepDroplets:
+		if msg.String() == "esc" && w.droplets.list.FilterState() == list.Unfiltered {
+			return m.lbWizardBack()
+		}
+		if !w.droplets.Opened() {
+			// Waiting for the Droplets.
+			return m, nil
+		}
+		_, cmd := w.droplets.Update(msg)
+		if w.droplets.Opened() {
+			return m, cmd
+		}
+		var names []string
+		for _, o := range w.droplets.options {
+			if w.droplets.checked[o.Value()] {
+				names = append(names, o.Title())
+			}
+		}
+		if len(names) == 0 {
+			m.formErr = "choose at least one Droplet with space, or press esc to choose a tag instead"
+			w.droplets.Open()
+			return m, cmd
+		}
+		m.formErr = ""
+		w.dropletNames = names
+		return m.lbWizardStep(lbStepReview)
+
+	case lbStepReview:
+		switch msg.String() {
+		case "esc":
+			return m.lbWizardBack()
+		case "enter":
+			req := w.request()
+			l.create = nil
+			m.formErr = ""
+			l.creating = append(l.creating, req.Name)
+			return m, createLoadBalancer(m.client, req)
+		}
+		return m, nil
+	}
+
+	// The rest of the steps are typed.
+	f := w.field()
+	switch msg.String() {
+	case "esc":
+		return m.lbWizardBack()
+	case "enter":
+		if err := w.answer(); err != "" {
+			m.formErr = err
+			return m, nil
+		}
+		m.formErr = ""
+		return m.lbWizardStep(w.next())
+	}
+
+	_, cmd := f.Update(msg)
+	return m, cmd
+}
+
+// field returns the text field of the current step.
+func (w *lbWizard) field() *textField {
+	switch w.step {
+	case lbStepName:
+		return w.name
+	case lbStepRules:
+		return w.rules
+	case lbStepHealth:
+		return w.health
+	case lbStepTag:
+		return w.tag
+	}
+	return nil
+}
+
+// answer checks and keeps the answer typed at the current step, returning
+// an error message if it's no good.
+func (w *lbWizard) answer() string {
+	switch w.step {
+	case lbStepName:
+		name := strings.TrimSpace(w.name.Value())
+		if name == "" {
+			return "the load balancer needs a name"
+		}
+		return checkHostname(name)
+	case lbStepRules:
+		rules, err := parseForwardingRules(w.rules.Value())
+		if err != nil {
+			return err.Error()
+		}
+		w.forwarding = rules
+	case lbStepHealth:
+		check, err := parseHealthCheck(w.health.Value())
+		if err != nil {
+			return err.Error()
+		}
+		w.check = check
+	case lbStepTag:
+		tag := strings.TrimSpace(w.tag.Value())
+		if tag == "" {
+			return "type the tag whose Droplets it sends traffic to"
+		}
+		if invalidTagChars.MatchString(tag) {
+			return fmt.Sprintf("tag %q can only have letters, numbers, colons, dashes and underscores", tag)
+		}
+	}
+	return ""
+}
+
+// next returns the step after the current typed one.
+func (w *lbWizard) next() lbStep {
+	switch w.step {
+	case lbStepName:
+		return lbStepRegion
+	case lbStepRules:
+		if w.needsCertificate() {
+			return lbStepCertificate
+		}
+		return lbStepHealth
+	case lbStepHealth:
+		return lbStepTargets
+	}
+	return lbStepReview
+}
+
+// needsCertificate reports whether any of the forwarding rules end TLS at
+// the load balancer, and so need a certificate.
+func (w *lbWizard) needsCertificate() bool {
+	for _, rule := range w.forwarding {
+		if ruleNeedsCertificate(rule) {
+			return true
+		}
+	}
+	return false
+}
+
+// lbWizardStep moves the wizard on to the step, setting up its field the
+// first time it's reached.
+func (m model) lbWizardStep(step lbStep) (tea.Model, tea.Cmd) {
+	w := m.loadBalancers.create
+	w.step = step
+	w.err = nil
+
+	switch step {
+	case lbStepName:
+		return m, w.name.Focus()
+
+	case lbStepRegion:
+		if w.regions == nil {
+			w.regions = newSelectField("", "Choose a region for "+strings.TrimSpace(w.name.Value()), "")
+			w.regions.SetSize(m.width, m.height-1)
+			return m, fetchLBRegions(m.client)
+		}
+		w.regions.Open()
+		return m, nil
+
+	case lbStepRules:
+		if w.rules == nil {
+			w.rules = newTextField("Forwarding rules: ", "http:80")
+			w.rules.CharLimit = 255
+		}
+		return m, w.rules.Focus()
+
+	case lbStepCertificate:
+		if w.certificates == nil {
+			w.certificates = newSelectField("", "Choose a certificate for "+strings.TrimSpace(w.name.Value()), "")
+			w.certificates.SetSize(m.width, m.height-1)
+			return m, fetchCertificates(m.client)
+		}
+		w.certificates.Open()
+		return m, nil
+
+	case lbStepHealth:
+		// Check the first rule's target unless another check was typed.
+		def := defaultHealthCheck(w.forwarding[0])
+		if w.health == nil || w.health.Value() == w.health.Placeholder {
+			w.health = newTextField("Health check: ", def)
+			w.health.CharLimit = 255
+		}
+		return m, w.health.Focus()
+
+	case lbStepTargets:
+		if w.targets == nil {
+			w.targets = newSelectField("", "Send traffic to", "")
+			w.targets.SetSize(m.width, m.height-1)
+			cmd := w.targets.SetOptions([]option{lbTargetKind{}, lbTargetKind{tag: true}})
+			w.targets.Open()
+			return m, cmd
+		}
+		w.targets.Open()
+		return m, nil
+
+	case lbStepDroplets:
+		if w.droplets == nil || w.dropletsRegion != w.region {
+			w.droplets = newMultiSelectField("", "Choose the Droplets in "+w.region+" to send traffic to")
+			w.dropletsRegion = w.region
+			w.droplets.SetSize(m.width, m.height-1)
+			return m, fetchLBDroplets(m.client, w.region)
+		}
+		w.droplets.Open()
+		return m, nil
+
+	case lbStepTag:
+		if w.tag == nil {
+			w.tag = newOptionalTextField("Tag: ", "e.g. web")
+			w.tag.CharLimit = 255
+		}
+		return m, w.tag.Focus()
+	}
+	return m, nil
+}
+
+// lbWizardBack goes back a step, or stops creating the load balancer from
+// the first.
+func (m model) lbWizardBack() (tea.Model, tea.Cmd) {
+	w := m.loadBalancers.create
+	m.formErr = ""
+
+	switch w.step {
+	case lbStepName:
+		m.loadBalancers.create = nil
+		return m, nil
+	case lbStepReview:
+		if w.tag != nil && w.targets.selected == (lbTargetKind{tag: true}) {
+			return m.lbWizardStep(lbStepTag)
+		}
+		return m.lbWizardStep(lbStepDroplets)
+	case lbStepHealth:
+		if !w.needsCertificate() {
+			return m.lbWizardStep(lbStepRules)
+		}
+	case lbStepDroplets, lbStepTag:
+		return m.lbWizardStep(lbStepTargets)
+	}
+	return m.lbWizardStep(w.step - 1)
+}
+
+// request returns the load balancer described by the wizard's answers.
+func (w *lbWizard) request() *godo.LoadBalancerRequest {
+	req := &godo.LoadBalancerRequest{
+		Name:            strings.TrimSpace(w.name.Value()),
+		Region:          w.region,
+		SizeUnit:        1,
+		ForwardingRules: w.forwarding,
+		HealthCheck:     w.check,
+	}
+	if w.targets.selected == (lbTargetKind{tag: true}) {
+		req.Tag = strings.TrimSpace(w.tag.Value())
+		return req
+	}
+
+	for _, o := range w.droplets.options {
+		if w.droplets.checked[o.Value()] {
+			id, _ := strconv.Atoi(o.Value())
+			req.DropletIDs = append(req.DropletIDs, id)
+		}
+	}
+	return req
+}
+
+// lbProtocols are the protocols load balancers forward.
+var lbProtocols = []string{"http", "https", "http2", "tcp"}
+
+// parseForwardingRules parses comma-separated forwarding rules, each an
+// entry protocol and port, optionally followed by a target protocol and
+// port, e.g. "http:80" or "https:443:http:80". HTTPS forwarded as HTTPS
+// passes TLS through to the Droplets; otherwise HTTPS and HTTP/2 end TLS at
+// the load balancer, with a certificate chosen afterwards.
+func parseForwardingRules(s string) ([]godo.ForwardingRule, error) {
+	items := splitList(s)
+	if len(items) == 0 {
+		return nil, errors.New("the load balancer needs at least one forwarding rule, e.g. http:80")
+	}
+
+	var rules []godo.ForwardingRule
+	for _, item := range items {
+		parts := strings.Split(strings.ToLower(item), ":")
+		if len(parts) != 2 && len(parts) != 4 {
+			return nil, fmt.Errorf("forwarding rule %q isn't protocol:port or protocol:port:protocol:port", item)
+		}
+		if len(parts) == 2 {
+			parts = append(parts, parts...)
+		}
+
+		var ports [2]int
+		for i := range ports {
+			if !contains(lbProtocols, parts[i*2]) {
+				return nil, fmt.Errorf("forwarding rule %q has protocol %q; it can be %s", item, parts[i*2], strings.Join(lbProtocols, ", "))
+			}
+			port, err := strconv.Atoi(parts[i*2+1])
+			if err != nil || port < 1 || port > 65535 {
+				return nil, fmt.Errorf("forwarding rule %q has port %q, which isn't between 1 and 65535", item, parts[i*2+1])
+			}
+			ports[i] = port
+		}
+
+		rule := godo.ForwardingRule{EntryProtocol: parts[0], EntryPort: ports[0], TargetProtocol: parts[2], TargetPort: ports[1]}
+		switch {
+		case rule.EntryProtocol == "https" && rule.TargetProtocol == "https":
+			rule.TlsPassthrough = true
+		case (rule.EntryProtocol == "tcp") != (rule.TargetProtocol == "tcp"):
+			return nil, fmt.Errorf("forwarding rule %q mixes tcp with another protocol; tcp only forwards to tcp", item)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// ruleNeedsCertificate reports whether the rule ends TLS at the load
+// balancer rather than passing it through.
+func ruleNeedsCertificate(rule godo.ForwardingRule) bool {
+	return (rule.EntryProtocol == "https" || rule.EntryProtocol == "http2") && !rule.TlsPassthrough
+}
+
+// defaultHealthCheck checks the rule's target, over HTTP if it speaks it
+// unencrypted and over TCP otherwise.
+func defaultHealthCheck(rule godo.ForwardingRule) string {
+	if rule.TargetProtocol == "http" {
+		return fmt.Sprintf("http:%d/", rule.TargetPort)
+	}
+	return fmt.Sprintf("tcp:%d", rule.TargetPort)
+}
+
+// parseHealthCheck parses a health check, e.g. "http:80/healthz" or
+// "tcp:22".
+func parseHealthCheck(s string) (*godo.HealthCheck, error) {
+	s = strings.TrimSpace(s)
+	protocol, rest, ok := strings.Cut(strings.ToLower(s), ":")
+	if !ok {
+		return nil, fmt.Errorf("health check %q isn't protocol:port, e.g. tcp:22, or protocol:port/path, e.g. http:80/healthz", s)
+	}
+	port, path := rest, ""
+	if i := strings.Index(rest, "/"); i >= 0 {
+		// Keep the path's case; only the protocol is lowered.
+		port, path = rest[:i], s[len(protocol)+1+i:]
+	}
+
+	check := &godo.HealthCheck{Protocol: protocol, Path: path}
+	switch protocol {
+	case "http", "https":
+		if check.Path == "" {
+			check.Path = "/"
+		}
+	case "tcp":
+		if path != "" {
+			return nil, fmt.Errorf("health check %q has a path, but tcp checks only connect", s)
+		}
+	default:
+		return nil, fmt.Errorf("health check %q has protocol %q; it can be http, https or tcp", s, protocol)
+	}
+	n, err := strconv.Atoi(port)
+	if err != nil || n < 1 || n > 65535 {
+		return nil, fmt.Errorf("health check %q has port %q, which isn't between 1 and 65535", s, port)
+	}
+	check.Port = n
+	return check, nil
+}
+
+// loadBalancerCreatedMsg reports that a load balancer has been created,
+// though it's still starting up.
+type loadBalancerCreatedMsg struct {
+	name string
+	lb   *godo.LoadBalancer
+	err  error
+}
+
+func createLoadBalancer(client *godo.Client, req *godo.LoadBalancerRequest) tea.Cmd {
+	return func() tea.Msg {
+		lb, _, err := client.LoadBalancers.Create(context.Background(), req)
+		return loadBalancerCreatedMsg{name: req.Name, lb: lb, err: err}
+	}
+}
+
+// loadBalancerCreated lists a new load balancer while waiting for it to
+// start up.
+func (m model) loadBalancerCreated(msg loadBalancerCreatedMsg) (tea.Model, tea.Cmd) {
+	l := m.loadBalancers
+	if l != nil {
+		for i, name := range l.creating {
+			if name == msg.name {
+				l.creating = append(l.creating[:i:i], l.creating[i+1:]...)
+				break
+			}
+		}
+	}
+	if msg.err != nil {
+		m.formErr = fmt.Sprintf("couldn't create %s: %s", msg.name, msg.err)
+		return m, nil
+	}
+
+	cmd := waitForLoadBalancer(m.client, msg.lb.ID, msg.name)
+	if l == nil {
+		return m, cmd
+	}
+	l.busy[msg.lb.ID] = "starting"
+	l.loading = true
+	return m, tea.Batch(cmd, fetchLoadBalancerList(m.client, l.page))
+}
+
+// waitForLoadBalancer checks on a new load balancer until it's active.
+func waitForLoadBalancer(client *godo.Client, id, name string) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		for {
+			lb, _, err := client.LoadBalancers.Get(ctx, id)
+			if err != nil {
+				return loadBalancerDoneMsg{id: id, name: name, verb: "create", err: err}
+			}
+			switch lb.Status {
+			case "active":
+				return loadBalancerDoneMsg{id: id, name: name, verb: "create", done: "Created", lb: lb}
+			case "errored":
+				return loadBalancerDoneMsg{id: id, name: name, verb: "create", err: errors.New("it errored while starting up")}
+			}
+			time.Sleep(lbPollInterval)
+		}
+	}
+}
+
+// lbWizardPickerView returns the list being chosen from, if the wizard's at
+// a step that's chosen from one.
+func (m model) lbWizardPickerView() (string, bool) {
+	w := m.loadBalancers.create
+	switch {
+	case w == nil:
+		return "", false
+	case w.step == lbStepRegion && w.regions.Opened():
+		return w.regions.View(), true
+	case w.step == lbStepCertificate && w.certificates.Opened():
+		return w.certificates.View(), true
+	case w.step == lbStepTargets && w.targets.Opened():
+		return w.targets.View(), true
+	case w.step == lbStepDroplets && w.droplets.Opened():
+		return w.droplets.View(), true
+	}
+	return "", false
+}
+
+func (m model) lbWizardView() string {
+	w := m.loadBalancers.create
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s\n", focusedStyle.Render("New load balancer"))
+	if w.step > lbStepName {
+		fmt.Fprintf(&b, "  Name: %s\n", strings.TrimSpace(w.name.Value()))
+	}
+	if w.step > lbStepRegion {
+		fmt.Fprintf(&b, "  Region: %s\n", w.region)
+	}
+	if w.step > lbStepRules {
+		fmt.Fprintf(&b, "  Forwarding: %s\n", forwardingLabel(w.forwarding))
+	}
+	if w.step > lbStepCertificate && w.needsCertificate() {
+		fmt.Fprintf(&b, "  Certificate: %s\n", w.certificates.selected.Title())
+	}
+	if w.step > lbStepHealth {
+		fmt.Fprintf(&b, "  Health check: %s\n", healthCheckLabel(w.check))
+	}
+	if w.step == lbStepReview {
+		if w.targets.selected == (lbTargetKind{tag: true}) {
+			fmt.Fprintf(&b, "  Targets: Droplets tagged %s\n", strings.TrimSpace(w.tag.Value()))
+		} else {
+			fmt.Fprintf(&b, "  Targets: %s\n", strings.Join(w.dropletNames, ", "))
+		}
+	}
+	b.WriteString("\n")
+
+	switch {
+	case w.err != nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(w.err.Error()))
+		b.WriteString(helpStyle.Render("esc: back"))
+	case w.step == lbStepRegion:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading regions..."))
+		b.WriteString(helpStyle.Render("esc: back"))
+	case w.step == lbStepCertificate:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading certificates..."))
+		b.WriteString(helpStyle.Render("esc: back"))
+	case w.step == lbStepDroplets:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading Droplets..."))
+		b.WriteString(helpStyle.Render("esc: back"))
+	case w.step == lbStepReview:
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("It starts with a single node, billed hourly until it's deleted."))
+		b.WriteString(helpStyle.Render("enter: create • esc: back"))
+	default:
+		fmt.Fprintf(&b, "%s\n", w.field().View())
+		switch w.step {
+		case lbStepRules:
+			fmt.Fprintf(&b, "%s\n", placeholderStyle.Render("  e.g. http:80, https:443:http:80, https:443:https:443 or tcp:5432"))
+		case lbStepHealth:
+			fmt.Fprintf(&b, "%s\n", placeholderStyle.Render("  e.g. http:80/healthz or tcp:22"))
+		}
+		b.WriteString("\n")
+		back := "esc: back"
+		if w.step == lbStepName {
+			back = "esc: cancel"
+		}
+		b.WriteString(helpStyle.Render("enter: next • " + back))
+	}
+
+	return b.String()
+}
+
+// healthCheckLabel describes a health check the way it's typed.
+func healthCheckLabel(h *godo.HealthCheck) string {
+	if h == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d%s", h.Protocol, h.Port, h.Path)
+}
@@ -0,0 +1,381 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+var createLoadBalancerKey = key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "create"))
+
+// loadBalancersPerPage is how many load balancers the load balancers screen
+// fetches at a time.
+const loadBalancersPerPage = 20
+
+// loadBalancerListMsg carries a page of the account's load balancers, along
+// with how many there are in all.
+type loadBalancerListMsg struct {
+	page  int
+	lbs   []godo.LoadBalancer
+	total int
+	err   error
+}
+
+// fetchLoadBalancerList lists a page of the account's load balancers,
+// counting from 1.
+func fetchLoadBalancerList(client *godo.Client, page int) tea.Cmd {
+	return func() tea.Msg {
+		lbs, resp, err := client.LoadBalancers.List(context.Background(), &godo.ListOptions{Page: page, PerPage: loadBalancersPerPage})
+		if err != nil {
+			return loadBalancerListMsg{page: page, err: err}
+		}
+
+		total := len(lbs)
+		if resp.Meta != nil {
+			total = resp.Meta.Total
+		}
+		return loadBalancerListMsg{page: page, lbs: lbs, total: total}
+	}
+}
+
+// loadBalancerDoneMsg reports that a change to a load balancer has
+// finished. For one that's been created, lb is it once it's active.
+type loadBalancerDoneMsg struct {
+	id   string
+	name string
+	verb string
+	done string
+	lb   *godo.LoadBalancer
+	err  error
+}
+
+// loadBalancerList is the screen listing the account's load balancers, a
+// page at a time.
+type loadBalancerList struct {
+	table   table.Model
+	lbs     []godo.LoadBalancer
+	page    int
+	total   int
+	loading bool
+	err     error
+	// create is set while walking through creating a load balancer, and
+	// confirmDelete while asking to confirm deleting one.
+	create        *lbWizard
+	confirmDelete *loadBalancerDeletePrompt
+	// busy holds the status to show for the load balancers that are
+	// starting up or being deleted, by ID, and creating the names of those
+	// being created, until they're done.
+	busy     map[string]string
+	creating []string
+}
+
+func newLoadBalancerList(width, height int) *loadBalancerList {
+	l := &loadBalancerList{
+		page: 1,
+		busy: make(map[string]string),
+		table: newListTable([]table.Column{
+			{Title: "Name", Width: 24},
+			{Title: "Region", Width: 8},
+			{Title: "IP", Width: 16},
+			{Title: "Rules", Width: 24},
+			{Title: "Targets", Width: 16},
+			{Title: "Status", Width: 12},
+		}),
+	}
+	l.SetSize(width, height)
+	return l
+}
+
+// SetSize fits the table to the screen, under the title and above the help.
+func (l *loadBalancerList) SetSize(width, height int) {
+	setTableSize(&l.table, width, height)
+}
+
+// pages returns how many pages of load balancers there are.
+func (l *loadBalancerList) pages() int {
+	return pageCount(l.total, loadBalancersPerPage)
+}
+
+// setLoadBalancerList shows a page of load balancers once it's been fetched.
+func (m model) setLoadBalancerList(msg loadBalancerListMsg) (tea.Model, tea.Cmd) {
+	l := m.loadBalancers
+	if l == nil {
+		return m, nil
+	}
+	// The last page was emptied, e.g. by deleting, so go back one.
+	if msg.err == nil && len(msg.lbs) == 0 && msg.page > 1 {
+		return m, fetchLoadBalancerList(m.client, msg.page-1)
+	}
+
+	l.loading = false
+	l.err = msg.err
+	if msg.err != nil {
+		return m, nil
+	}
+	l.page, l.lbs, l.total = msg.page, msg.lbs, msg.total
+	l.setRows()
+	return m, nil
+}
+
+// setRows fills the table in from the load balancers.
+func (l *loadBalancerList) setRows() {
+	rows := make([]table.Row, len(l.lbs))
+	for i, lb := range l.lbs {
+		region := ""
+		if lb.Region != nil {
+			region = lb.Region.Slug
+		}
+		status := lb.Status
+		if s, ok := l.busy[lb.ID]; ok {
+			status = s + "…"
+		}
+		rows[i] = table.Row{lb.Name, region, lb.IP, forwardingLabel(lb.ForwardingRules), targetsLabel(lb.Tag, lb.DropletIDs), status}
+	}
+	l.table.SetRows(rows)
+	if l.table.Cursor() >= len(rows) {
+		l.table.GotoTop()
+	}
+}
+
+// forwardingLabel describes forwarding rules briefly, e.g. "http:80→80".
+func forwardingLabel(rules []godo.ForwardingRule) string {
+	labels := make([]string, len(rules))
+	for i, r := range rules {
+		labels[i] = fmt.Sprintf("%s:%d→%d", r.EntryProtocol, r.EntryPort, r.TargetPort)
+	}
+	return strings.Join(labels, ", ")
+}
+
+// targetsLabel describes the Droplets a load balancer sends traffic to.
+func targetsLabel(tag string, dropletIDs []int) string {
+	if tag != "" {
+		return "tag:" + tag
+	}
+	return pluralize(len(dropletIDs), "Droplet")
+}
+
+// selected returns the load balancer under the cursor, unless there are
+// none or it's busy.
+func (l *loadBalancerList) selected() (godo.LoadBalancer, bool) {
+	i := l.table.Cursor()
+	if i < 0 || i >= len(l.lbs) {
+		return godo.LoadBalancer{}, false
+	}
+	_, busy := l.busy[l.lbs[i].ID]
+	return l.lbs[i], !busy
+}
+
+// openLoadBalancers shows the list of load balancers, fetching its first
+// page.
+func (m model) openLoadBalancers() (model, tea.Cmd) {
+	m.loadBalancers = newLoadBalancerList(m.width, m.height-1)
+	m.loadBalancers.loading = true
+	m.notice = ""
+	m.formErr = ""
+	return m, tea.Batch(fetchLoadBalancerList(m.client, 1), m.spinner.Tick)
+}
+
+func (m model) updateLoadBalancers(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.loadBalancers
+	if l.create != nil {
+		return m.updateLBWizard(msg)
+	}
+	if l.confirmDelete != nil {
+		return m.updateLoadBalancerDelete(msg)
+	}
+
+	switch {
+	case msg.String() == "ctrl+c":
+		return m, m.quit()
+	case key.Matches(msg, closeListKeys):
+		return m.closeScreen()
+	case key.Matches(msg, screensKey):
+		return m.openScreens()
+	case key.Matches(msg, createLoadBalancerKey):
+		return m.openLBWizard()
+	case l.loading:
+		return m, nil
+	case key.Matches(msg, deleteKey):
+		return m.promptLoadBalancerDelete()
+	case key.Matches(msg, refreshListKey):
+		l.loading = true
+		return m, fetchLoadBalancerList(m.client, l.page)
+	case key.Matches(msg, nextPageKey) && l.page < l.pages():
+		l.loading = true
+		return m, fetchLoadBalancerList(m.client, l.page+1)
+	case key.Matches(msg, prevPageKey) && l.page > 1:
+		l.loading = true
+		return m, fetchLoadBalancerList(m.client, l.page-1)
+	}
+
+	var cmd tea.Cmd
+	l.table, cmd = l.table.Update(msg)
+	return m, cmd
+}
+
+// loadBalancerDone refreshes the list once a change to a load balancer has
+// finished.
+func (m model) loadBalancerDone(msg loadBalancerDoneMsg) (tea.Model, tea.Cmd) {
+	l := m.loadBalancers
+	if l != nil {
+		delete(l.busy, msg.id)
+		l.setRows()
+	}
+
+	if msg.err != nil {
+		m.formErr = fmt.Sprintf("couldn't %s %s: %s", msg.verb, msg.name, msg.err)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	if msg.lb != nil {
+		// Left up, rather than toasted, to be copied.
+		m.notice = fmt.Sprintf("%s is active at %s", msg.name, msg.lb.IP)
+	} else {
+		cmd = m.toast(fmt.Sprintf("%s %s", msg.done, msg.name))
+	}
+	if l == nil {
+		return m, cmd
+	}
+	l.loading = true
+	return m, tea.Batch(cmd, fetchLoadBalancerList(m.client, l.page))
+}
+
+func (m model) loadBalancersView() string {
+	l := m.loadBalancers
+	if v, ok := m.lbWizardPickerView(); ok {
+		return v
+	}
+
+	var b strings.Builder
+
+	title := focusedStyle.Render("Load balancers")
+	if l.total > 0 {
+		title += placeholderStyle.Render(fmt.Sprintf("  %s · page %d of %d", pluralize(l.total, "load balancer"), l.page, l.pages()))
+	}
+	b.WriteString(title + "\n\n")
+
+	switch {
+	case l.loading && l.lbs == nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading load balancers..."))
+	case l.err != nil && l.lbs == nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the load balancers: "+l.err.Error()))
+	case len(l.lbs) == 0:
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("There are no load balancers in this account yet."))
+	default:
+		fmt.Fprintf(&b, "%s\n\n", l.table.View())
+	}
+
+	switch {
+	case l.loading && l.lbs != nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading..."))
+	case l.err != nil && l.lbs != nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the load balancers: "+l.err.Error()))
+	}
+	if len(l.creating) > 0 {
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Creating "+strings.Join(l.creating, ", ")+"..."))
+	}
+	if m.notice != "" {
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render(m.notice))
+	}
+	if m.formErr != "" {
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(m.formErr))
+	}
+
+	switch {
+	case l.create != nil:
+		b.WriteString(m.lbWizardView())
+		return b.String()
+	case l.confirmDelete != nil:
+		b.WriteString(m.loadBalancerDeleteView())
+		b.WriteString(helpStyle.Render("enter: delete • esc: cancel"))
+		return b.String()
+	}
+
+	help := []string{"↑/↓: move", "c: create", "d: delete"}
+	if l.pages() > 1 {
+		help = append(help, "←/→: page")
+	}
+	help = append(help, "r: refresh", "tab: screens", m.closeScreenHelp())
+	b.WriteString(helpStyle.Render(strings.Join(help, " • ")))
+
+	return b.String()
+}
+
+// loadBalancerDeletePrompt asks for a load balancer's name before deleting
+// it.
+type loadBalancerDeletePrompt struct {
+	lb   godo.LoadBalancer
+	name *textField
+}
+
+// promptLoadBalancerDelete asks to confirm deleting the load balancer under
+// the cursor.
+func (m model) promptLoadBalancerDelete() (model, tea.Cmd) {
+	if m.blockChanges("load balancers", "deleted") {
+		return m, nil
+	}
+
+	l := m.loadBalancers
+	lb, ok := l.selected()
+	if !ok {
+		return m, nil
+	}
+	name := newOptionalTextField("Type its name to delete it: ", lb.Name)
+	name.CharLimit = 255
+	l.confirmDelete = &loadBalancerDeletePrompt{lb: lb, name: name}
+	m.notice = ""
+	m.formErr = ""
+	return m, name.Focus()
+}
+
+func (m model) updateLoadBalancerDelete(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.loadBalancers
+	p := l.confirmDelete
+	switch msg.String() {
+	case "ctrl+c":
+		return m, m.quit()
+	case "esc":
+		l.confirmDelete = nil
+		m.formErr = ""
+		return m, nil
+	case "enter":
+		if strings.TrimSpace(p.name.Value()) != p.lb.Name {
+			m.formErr = "the name doesn't match; type it exactly, or press esc to cancel"
+			return m, nil
+		}
+		l.confirmDelete = nil
+		m.formErr = ""
+		l.busy[p.lb.ID] = "deleting"
+		l.setRows()
+		return m, deleteLoadBalancer(m.client, p.lb)
+	}
+
+	_, cmd := p.name.Update(msg)
+	return m, cmd
+}
+
+// deleteLoadBalancer deletes the load balancer. Its Droplets are left as
+// they are.
+func deleteLoadBalancer(client *godo.Client, lb godo.LoadBalancer) tea.Cmd {
+	return func() tea.Msg {
+		_, err := client.LoadBalancers.Delete(context.Background(), lb.ID)
+		return loadBalancerDoneMsg{id: lb.ID, name: lb.Name, verb: "delete", done: "Deleted", err: err}
+	}
+}
+
+func (m model) loadBalancerDeleteView() string {
+	p := m.loadBalancers.confirmDelete
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s\n", focusedStyle.Render(fmt.Sprintf("Delete %s?", p.lb.Name)))
+	fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(fmt.Sprintf("Traffic to %s stops reaching its %s, and its IP is given up. Its Droplets are kept.", p.lb.IP, targetsLabel(p.lb.Tag, p.lb.DropletIDs))))
+	fmt.Fprintf(&b, "%s\n\n", p.name.View())
+
+	return b.String()
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+type loadBalancerItem struct {
+	godo.LoadBalancer
+}
+
+func (l loadBalancerItem) Title() string { return l.Name }
+func (l loadBalancerItem) Description() string {
+	if l.Tag != "" {
+		return fmt.Sprintf("%s · Droplets tagged %s", l.IP, l.Tag)
+	}
+	return fmt.Sprintf("%s · %d Droplets", l.IP, len(l.DropletIDs))
+}
+func (l loadBalancerItem) FilterValue() string { return l.Name }
+func (l loadBalancerItem) Value() string       { return l.ID }
+
+// loadBalancersMsg carries the account's load balancers. If they couldn't
+// be listed, lbs is empty so that none can still be chosen.
+type loadBalancersMsg struct {
+	lbs []godo.LoadBalancer
+	err error
+}
+
+// fetchLoadBalancers lists the load balancers on the account.
+func fetchLoadBalancers(client *godo.Client) tea.Cmd {
+	return func() tea.Msg {
+		lbs, _, err := client.LoadBalancers.List(context.Background(), &godo.ListOptions{PerPage: 200})
+		if err != nil {
+			return loadBalancersMsg{lbs: []godo.LoadBalancer{}, err: err}
+		}
+
+		return loadBalancersMsg{lbs: lbs}
+	}
+}
+
+// loadBalancerPicker is a selectField for the load balancers in the chosen
+// region. Load balancers that pick their Droplets by tag are left out, since
+// Droplets can't be added to them directly.
+type loadBalancerPicker struct {
+	*selectField
+	lbs    []godo.LoadBalancer
+	region string
+}
+
+func newLoadBalancerPicker() *loadBalancerPicker {
+	p := &loadBalancerPicker{
+		selectField: newSelectField("Load balancer: ", "Choose a load balancer", ""),
+	}
+	p.hideValue = true
+
+	return p
+}
+
+// SetLoadBalancers replaces the full set of load balancers the picker
+// chooses from.
+func (p *loadBalancerPicker) SetLoadBalancers(lbs []godo.LoadBalancer) tea.Cmd {
+	p.lbs = lbs
+	return p.refresh()
+}
+
+// SetRegion restricts the picker to load balancers in the given region,
+// dropping the current choice if it belongs to another region.
+func (p *loadBalancerPicker) SetRegion(region string) tea.Cmd {
+	if p.region == region {
+		return nil
+	}
+	p.region = region
+	if l, ok := p.selected.(loadBalancerItem); ok && l.Region != nil && l.Region.Slug != region {
+		p.selected = nil
+	}
+
+	return p.refresh()
+}
+
+func (p *loadBalancerPicker) refresh() tea.Cmd {
+	if p.lbs == nil {
+		return nil
+	}
+
+	opts := []option{noneOption("don't add to a load balancer")}
+	for _, l := range p.lbs {
+		if l.Region != nil && l.Region.Slug == p.region && l.Tag == "" {
+			opts = append(opts, loadBalancerItem{l})
+		}
+	}
+
+	p.list.Title = "Choose a load balancer in " + p.region
+
+	return p.SetOptions(opts)
+}
+
+func (p *loadBalancerPicker) Update(msg tea.Msg) (field, tea.Cmd) {
+	_, cmd := p.selectField.Update(msg)
+	return p, cmd
+}
+
+// addToLoadBalancer adds the Droplet to the load balancer's pool.
+func addToLoadBalancer(ctx context.Context, client *godo.Client, lbID string, dropletID int) error {
+	if lbID == "" {
+		return nil
+	}
+	_, err := client.LoadBalancers.AddDroplets(ctx, lbID, dropletID)
+	return err
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+// tokenPrompt asks for an API token when none is configured, checking it
+// against the API before the form is shown.
+type tokenPrompt struct {
+	input    *secretField
+	checking bool
+	err      error
+	// client is set once the token has been accepted, while asking whether
+	// to save it.
+	client *godo.Client
+	// oauthClientID enables logging in with the browser.
+	oauthClientID string
+	oauthURL      string
+	cancelOAuth   context.CancelFunc
+}
+
+// tokenMsg reports whether a token entered at the prompt is usable.
+type tokenMsg struct {
+	client *godo.Client
+	err    error
+}
+
+func newTokenPrompt() *tokenPrompt {
+	f := newSecretField("Token: ", "dop_v1_...")
+	f.Focus()
+
+	return &tokenPrompt{input: f}
+}
+
+// checkToken makes a lightweight request with the token to confirm that it
+// authenticates.
+func checkToken(token, apiURL string) tea.Cmd {
+	return func() tea.Msg {
+		client, err := newClient(token, apiURL)
+		if err != nil {
+			return tokenMsg{err: err}
+		}
+		if _, _, err := client.Account.Get(context.Background()); err != nil {
+			var errResp *godo.ErrorResponse
+			if errors.As(err, &errResp) && errResp.Response.StatusCode == http.StatusUnauthorized {
+				err = errors.New("the API didn't accept that token")
+			}
+			return tokenMsg{err: err}
+		}
+
+		return tokenMsg{client: client}
+	}
+}
+
+func (m model) updateLogin(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			if m.login.cancelOAuth == nil {
+				return m, tea.Quit
+			}
+			m.login.cancelOAuth()
+			m.login.cancelOAuth = nil
+			return m, nil
+		}
+		if m.login.checking || m.login.cancelOAuth != nil {
+			return m, nil
+		}
+		if m.login.client != nil {
+			switch msg.String() {
+			case "y", "Y":
+				return m, saveToken(strings.TrimSpace(m.login.input.Value()))
+			case "n", "N", "enter":
+				return m.loggedIn(m.login.client)
+			}
+			return m, nil
+		}
+		if key.Matches(msg, browserLoginKey) && m.login.oauthClientID != "" {
+			state := oauthState()
+			ctx, cancel := context.WithCancel(context.Background())
+			m.login.oauthURL = oauthURL(m.login.oauthClientID, state)
+			m.login.cancelOAuth = cancel
+			m.login.err = nil
+			return m, tea.Batch(oauthLogin(ctx, m.login.oauthURL, state), m.spinner.Tick)
+		}
+		if msg.String() == "enter" {
+			token := strings.TrimSpace(m.login.input.Value())
+			if token == "" {
+				return m, nil
+			}
+			m.login.checking = true
+			m.login.err = nil
+			return m, tea.Batch(checkToken(token, m.apiURL), m.spinner.Tick)
+		}
+
+	case oauthMsg:
+		// Ignore a login that's since been cancelled.
+		if m.login.cancelOAuth == nil {
+			return m, nil
+		}
+		m.login.cancelOAuth()
+		m.login.cancelOAuth = nil
+		if msg.err != nil {
+			m.login.err = msg.err
+			return m, nil
+		}
+		m.login.input.SetValue(msg.token)
+		m.login.checking = true
+		return m, checkToken(msg.token, m.apiURL)
+
+	case tokenMsg:
+		m.login.checking = false
+		if msg.err != nil {
+			m.login.err = msg.err
+			return m, nil
+		}
+		m.login.client = msg.client
+		m.login.input.Blur()
+		return m, nil
+
+	case savedMsg:
+		if msg.err != nil {
+			m.formErr = fmt.Sprintf("couldn't save the token to the keyring: %s", msg.err)
+		}
+		return m.loggedIn(m.login.client)
+	}
+
+	var cmd, spinnerCmd tea.Cmd
+	_, cmd = m.login.input.Update(msg)
+	m.spinner, spinnerCmd = m.spinner.Update(msg)
+
+	return m, tea.Batch(cmd, spinnerCmd)
+}
+
+// loggedIn switches from the token prompt to the form.
+func (m model) loggedIn(client *godo.Client) (tea.Model, tea.Cmd) {
+	m.client = client
+	m.login = nil
+	return m, m.Init()
+}
+
+func (m model) loginView() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s\n", focusedStyle.Render("No API token found"))
+	fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("Paste a DigitalOcean API token to continue."))
+	fmt.Fprintf(&b, "%s\n\n", m.login.input.View())
+
+	if m.login.client != nil {
+		fmt.Fprintf(&b, "%s\n\n", focusedStyle.Render("Save the token in the system keyring so you aren't asked next time? (y/N)"))
+		b.WriteString(helpStyle.Render("y: save • n: don't save • esc: quit"))
+
+		return b.String()
+	}
+
+	if m.login.cancelOAuth != nil {
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Waiting for you to log in with the browser..."))
+		fmt.Fprintf(&b, "%s\n%s\n\n", placeholderStyle.Render("If it didn't open, visit:"), m.login.oauthURL)
+		b.WriteString(helpStyle.Render("esc: cancel"))
+
+		return b.String()
+	}
+
+	switch {
+	case m.login.checking:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Checking token..."))
+	case m.login.err != nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(m.login.err.Error()))
+	}
+
+	help := []string{"enter: continue", "ctrl+r: reveal"}
+	if m.login.input.Revealed() {
+		help[1] = "ctrl+r: hide"
+	}
+	if m.login.oauthClientID != "" {
+		help = append(help, "ctrl+l: log in with browser")
+	}
+	help = append(help, "esc: quit")
+	b.WriteString(helpStyle.Render(strings.Join(help, " • ")))
+
+	return b.String()
+}
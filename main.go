@@ -1,19 +1,21 @@
 package main
 
 import (
-	"context"
 	"errors"
+	"flag"
 	"fmt"
+	"net/http"
 	"os"
 	"strconv"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/digitalocean/godo"
-	"github.com/digitalocean/godo/util"
 )
 
 var (
@@ -23,90 +25,481 @@ var (
 	cursorStyle      = focusedStyle.Copy()
 	noStyle          = lipgloss.NewStyle()
 	helpStyle        = blurredStyle.Copy()
+	errorStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("#F25D5D"))
 
 	focusedButton = focusedStyle.Copy().Render("[ Create ]")
 	blurredButton = fmt.Sprintf("[ %s ]", blurredStyle.Render("Create"))
+	// disabledButton is shown when nothing may be created.
+	disabledButton = fmt.Sprintf("[ %s ]", blurredStyle.Copy().Strikethrough(true).Render("Create"))
 )
 
 type model struct {
-	focusIndex int
-	inputs     []textinput.Model
-	cursorMode textinput.CursorMode
-	spinner    spinner.Model
-	creating   bool
-	finalMsg   string
-	droplet    *godo.DropletCreateRequest
+	client    *godo.Client
+	login     *tokenPrompt
+	contexts  []authContext
+	context   string
+	switcher  *selectField
+	teams     map[string]string
+	templates *selectField
+	creations *selectField
+	// templateName is set while asking what to save the form as.
+	templateName *textField
+	defaults     config
+	history      history
+	// restore is the form saved when last quitting, while offering to
+	// restore it.
+	restore *spec
+	// autoSubmit skips the form, submitting it as soon as it's loaded.
+	autoSubmit bool
+	// headless runs without an interface, see driveHeadless, and
+	// headlessErr is set if it gave up.
+	headless    bool
+	headlessErr bool
+	edited      bool
+	apiURL      string
+	width       int
+	height      int
+	focusIndex  int
+	fields      []field
+	cursorMode  cursor.Mode
+	spinner     spinner.Model
+	dryRun      bool
+	readOnly    bool
+	checking    bool
+	account     accountMsg
+	quota       quotaMsg
+	reviewing   bool
+	creating    bool
+	formErr     string
+	notice      string
+	fieldErrs   map[int]string
+	finalMsg    string
+	droplet     *godo.DropletCreateRequest
+	names       []string
+	volume      *godo.VolumeCreateRequest
+	pending     []pendingDroplet
+	// succeeded is set once the Droplets are done and at least one was
+	// created, while showing the success screen. chosen is the Droplet its
+	// actions apply to.
+	succeeded bool
+	chosen    int
+	bootstrap *bootstrapRun
+	upload    *uploadRun
+	// confirmSSHConfig is set while asking whether to add the chosen
+	// Droplet to the SSH config.
+	confirmSSHConfig bool
+	// output is the format to print the created Droplets in on exit.
+	// submitted is set once the create request has been confirmed.
+	output    string
+	submitted bool
+	// export is set while showing the create request in an export format.
+	export *exportScreen
+	// list is set while showing the account's Droplets, volumes while
+	// showing its volumes, and so on for each of the screens, and screens
+	// while choosing between them.
+	list          *dropletList
+	volumes       *volumeList
+	domains       *domainList
+	reservedIPs   *reservedIPList
+	firewalls     *firewallList
+	loadBalancers *loadBalancerList
+	clusters      *clusterList
+	databases     *databaseList
+	apps          *appList
+	spaces        *spaceList
+	registry      *registryList
+	projects      *projectList
+	sshKeys       *keyList
+	vpcs          *vpcList
+	cdns          *cdnList
+	certificates  *certificateList
+	alerts        *alertList
+	functions     *namespaceList
+	screens       *selectField
+	// standalone is set when started on a screen with its command, so that
+	// leaving the screen quits rather than going back to the form.
+	// screenCmd starts the screen, e.g. fetching its first page.
+	standalone bool
+	screenCmd  tea.Cmd
 }
 
 type dropletMsg string
 
-func initialModel() model {
+// Indexes of the rows in the create form.
+const (
+	nameField = iota
+	countField
+	regionField
+	sizeField
+	imageField
+	keysField
+	tagsField
+	userDataField
+	backupsField
+	monitoringField
+	ipv6Field
+	vpcField
+	projectField
+	domainField
+	reservedIPField
+	firewallsField
+	loadBalancerField
+	volumeNameField
+	volumeSizeField
+)
+
+func initialModel(client *godo.Client) model {
 	m := model{
-		inputs:  make([]textinput.Model, 4),
-		spinner: spinner.NewModel(),
+		client:    client,
+		fields:    make([]field, 19),
+		history:   make(history),
+		templates: newTemplatePicker(),
+		creations: newCreationsPicker(),
+		spinner:   spinner.New(),
+		fieldErrs: make(map[int]string),
 	}
 
 	m.spinner.Style = focusedStyle
 	m.spinner.Spinner = spinner.Points
 
-	var t textinput.Model
-	for i := range m.inputs {
-		t = textinput.NewModel()
-		t.CursorStyle = cursorStyle
-		t.CharLimit = 32
-
-		switch i {
-		case 0:
-			t.Focus()
-			t.Prompt = "Name: "
-			t.Placeholder = "web-001"
-			t.PlaceholderStyle = placeholderStyle
-			t.PromptStyle = focusedStyle
-			t.TextStyle = focusedStyle
-			t.CharLimit = 64
-		case 1:
-			t.Prompt = "Region: "
-			t.Placeholder = "nyc3"
-			t.PlaceholderStyle = placeholderStyle
-		case 2:
-			t.Prompt = "Size: "
-			t.Placeholder = "s-1vcpu-1gb"
-			t.PlaceholderStyle = placeholderStyle
-		case 3:
-			t.Prompt = "Image: "
-			t.Placeholder = "ubuntu-20-04-x64"
-			t.PlaceholderStyle = placeholderStyle
-		}
-
-		m.inputs[i] = t
-	}
+	name := newTextField("Name: ", "web-001")
+	name.CharLimit = 64
+	name.Focus()
+
+	m.fields[nameField] = name
+	m.fields[countField] = newTextField("Count: ", "1")
+	m.fields[regionField] = newSelectField("Region: ", "Choose a region", "nyc3")
+	sizes := newSizePicker("s-1vcpu-1gb")
+	m.fields[sizeField] = sizes
+	m.fields[imageField] = newImagePicker("ubuntu-20-04-x64")
+	m.fields[keysField] = newMultiSelectField("SSH keys: ", "Choose SSH keys")
+
+	tags := newOptionalTextField("Tags: ", "comma-separated, e.g. web,production")
+	tags.CharLimit = 0
+	m.fields[tagsField] = tags
+	m.fields[userDataField] = newUserDataEditor()
+	m.fields[backupsField] = newToggleField("Backups", func() string {
+		if s, ok := sizes.Size(); ok {
+			return fmt.Sprintf("+$%.2f/mo", backupsPrice(s))
+		}
+		return ""
+	})
+	m.fields[monitoringField] = newToggleField("Monitoring", func() string {
+		return "install the metrics agent"
+	})
+	m.fields[ipv6Field] = newToggleField("IPv6", nil)
+	m.fields[vpcField] = newVPCPicker()
+
+	project := newSelectField("Project: ", "Choose a project", "")
+	project.hideValue = true
+	m.fields[projectField] = project
+	m.fields[domainField] = newSelectField("DNS record in: ", "Choose a domain", "")
+	m.fields[reservedIPField] = newReservedIPPicker()
+	m.fields[firewallsField] = newMultiSelectField("Firewalls: ", "Choose firewalls")
+	m.fields[loadBalancerField] = newLoadBalancerPicker()
+	m.fields[volumeNameField] = newOptionalTextField("Volume name: ", "none")
+	m.fields[volumeSizeField] = newOptionalTextField("Volume size (GB): ", "100")
+	m.setRegion()
 
 	return m
 }
+
 func (m model) Init() tea.Cmd {
-	return textinput.Blink
+	if m.login != nil {
+		return textinput.Blink
+	}
+	if m.standalone {
+		return tea.Batch(checkAccount(m.client, !m.readOnly), m.screenCmd)
+	}
+	cmds := []tea.Cmd{textinput.Blink, checkAccount(m.client, !m.readOnly), fetchRegions(m.client), fetchSizes(m.client), fetchImages(m.client), fetchKeys(m.client), fetchVPCs(m.client), fetchProjects(m.client), fetchDomains(m.client), fetchReservedIPs(m.client), fetchFirewalls(m.client), fetchLoadBalancers(m.client)}
+	if m.switcher != nil && m.teams == nil {
+		cmds = append(cmds, fetchTeams(m.contexts, m.apiURL))
+	}
+	return tea.Batch(cmds...)
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := m.update(msg)
+	n, ok := next.(model)
+	if !ok {
+		return next, cmd
+	}
+	// With --yes, go straight to the review once the form can be checked.
+	if n.autoSubmit && n.loaded() {
+		n.autoSubmit = false
+		var submitCmd tea.Cmd
+		next, submitCmd = n.submit()
+		cmd = tea.Batch(cmd, submitCmd)
+		n = next.(model)
+	}
+	if n.headless {
+		next, headlessCmd := n.driveHeadless()
+		return next, tea.Batch(cmd, headlessCmd)
+	}
+	return next, cmd
+}
+
+func (m model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, ok := msg.(tea.WindowSizeMsg); !ok && m.login != nil {
+		return m.updateLogin(msg)
+	}
+
+	if m.upload != nil {
+		switch msg.(type) {
+		case uploadProgressMsg, uploadDoneMsg, spinner.TickMsg, tea.KeyMsg, tea.WindowSizeMsg:
+			return m.updateUpload(msg)
+		}
+	}
+
+	if m.bootstrap != nil {
+		switch msg.(type) {
+		case bootstrapLineMsg, bootstrapDoneMsg, spinner.TickMsg, tea.KeyMsg, tea.WindowSizeMsg:
+			return m.updateBootstrap(msg)
+		}
+	}
+
+	if m.switcher != nil && m.switcher.Opened() {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			if msg.String() == "ctrl+c" {
+				return m, m.quit()
+			}
+			_, cmd := m.switcher.Update(msg)
+			if !m.switcher.Opened() && m.switcher.Value() != m.context {
+				return m.switchContext(m.switcher.Value())
+			}
+			return m, cmd
+		}
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		if m, cmd, ok := m.updateTemplates(msg); ok {
+			return m, cmd
+		}
+		if m, cmd, ok := m.updateCreations(msg); ok {
+			return m, cmd
+		}
+	}
+
+	if p := m.openPicker(); p != nil {
+		if msg, ok := msg.(tea.KeyMsg); ok && msg.String() == "ctrl+c" {
+			return m, m.quit()
+		}
+		if _, ok := msg.(tea.KeyMsg); ok {
+			m.edited = true
+			_, cmd := p.Update(msg)
+			if !p.Opened() {
+				delete(m.fieldErrs, m.focusIndex)
+			}
+			return m, tea.Batch(cmd, m.setRegion())
+		}
+	}
+
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		// Leave a line for the status bar.
+		msg.Height--
+		for _, f := range m.fields {
+			if p, ok := f.(picker); ok {
+				p.SetSize(msg.Width, msg.Height)
+			}
+		}
+		if m.switcher != nil {
+			m.switcher.SetSize(msg.Width, msg.Height)
+		}
+		m.templates.SetSize(msg.Width, msg.Height)
+		m.creations.SetSize(msg.Width, msg.Height)
+		if m.export != nil {
+			m.export.output.Width = msg.Width
+			m.export.output.Height = m.exportHeight()
+		}
+		if m.list != nil {
+			m.list.SetSize(msg.Width, msg.Height)
+		}
+		if m.volumes != nil {
+			m.volumes.SetSize(msg.Width, msg.Height)
+		}
+		if m.domains != nil {
+			m.domains.SetSize(msg.Width, msg.Height)
+		}
+		if m.reservedIPs != nil {
+			m.reservedIPs.SetSize(msg.Width, msg.Height)
+		}
+		if m.firewalls != nil {
+			m.firewalls.SetSize(msg.Width, msg.Height)
+		}
+		if m.loadBalancers != nil {
+			m.loadBalancers.SetSize(msg.Width, msg.Height)
+		}
+		if m.clusters != nil {
+			m.clusters.SetSize(msg.Width, msg.Height)
+		}
+		if m.databases != nil {
+			m.databases.SetSize(msg.Width, msg.Height)
+		}
+		if m.apps != nil {
+			m.apps.SetSize(msg.Width, msg.Height)
+		}
+		if m.spaces != nil {
+			m.spaces.SetSize(msg.Width, msg.Height)
+		}
+		if m.registry != nil {
+			m.registry.SetSize(msg.Width, msg.Height)
+		}
+		if m.projects != nil {
+			m.projects.SetSize(msg.Width, msg.Height)
+		}
+		if m.sshKeys != nil {
+			m.sshKeys.SetSize(msg.Width, msg.Height)
+		}
+		if m.vpcs != nil {
+			m.vpcs.SetSize(msg.Width, msg.Height)
+		}
+		if m.cdns != nil {
+			m.cdns.SetSize(msg.Width, msg.Height)
+		}
+		if m.certificates != nil {
+			m.certificates.SetSize(msg.Width, msg.Height)
+		}
+		if m.alerts != nil {
+			m.alerts.SetSize(msg.Width, msg.Height)
+		}
+		if m.functions != nil {
+			m.functions.SetSize(msg.Width, msg.Height)
+		}
+		if m.screens != nil {
+			m.screens.SetSize(msg.Width, msg.Height)
+		}
+
 	case tea.KeyMsg:
+		if m.screens != nil {
+			return m.updateScreens(msg)
+		}
+		if m.list != nil {
+			return m.updateList(msg)
+		}
+		if m.volumes != nil {
+			return m.updateVolumes(msg)
+		}
+		if m.domains != nil {
+			return m.updateDomains(msg)
+		}
+		if m.reservedIPs != nil {
+			return m.updateReservedIPs(msg)
+		}
+		if m.firewalls != nil {
+			return m.updateFirewalls(msg)
+		}
+		if m.loadBalancers != nil {
+			return m.updateLoadBalancers(msg)
+		}
+		if m.clusters != nil {
+			return m.updateClusters(msg)
+		}
+		if m.databases != nil {
+			return m.updateDatabases(msg)
+		}
+		if m.apps != nil {
+			return m.updateApps(msg)
+		}
+		if m.spaces != nil {
+			return m.updateSpaces(msg)
+		}
+		if m.registry != nil {
+			return m.updateRegistry(msg)
+		}
+		if m.projects != nil {
+			return m.updateProjects(msg)
+		}
+		if m.sshKeys != nil {
+			return m.updateKeys(msg)
+		}
+		if m.vpcs != nil {
+			return m.updateVPCs(msg)
+		}
+		if m.cdns != nil {
+			return m.updateCDNs(msg)
+		}
+		if m.certificates != nil {
+			return m.updateCertificates(msg)
+		}
+		if m.alerts != nil {
+			return m.updateAlerts(msg)
+		}
+		if m.functions != nil {
+			return m.updateFunctions(msg)
+		}
+		if m.checking && msg.String() != "ctrl+c" {
+			return m, nil
+		}
+		if m.export != nil {
+			return m.updateExport(msg)
+		}
+		if m.reviewing {
+			return m.updateReview(msg)
+		}
+		if m.succeeded {
+			return m.updateCreated(msg)
+		}
+		if m.restore != nil {
+			return m.updateRestore(msg)
+		}
+
+		switch {
+		case key.Matches(msg, switchContextKey) && m.switcher != nil:
+			m.switcher.Open()
+			return m, nil
+		case key.Matches(msg, saveTemplateKey):
+			m.templateName = newTextField("Name: ", "")
+			m.templateName.CharLimit = 64
+			m.formErr = ""
+			return m, m.templateName.Focus()
+		case key.Matches(msg, loadTemplateKey):
+			m.templates.selected = nil
+			return m, fetchTemplates()
+		case key.Matches(msg, pastCreationsKey):
+			m.creations.selected = nil
+			return m, fetchCreations
+		case key.Matches(msg, listKey):
+			return m.openList()
+		case key.Matches(msg, generateNameKey) && m.focusIndex == nameField:
+			name := m.fields[nameField].(*textField)
+			name.SetValue(m.generateName(m.defaults.NameTemplate))
+			name.CursorEnd()
+			m.edited = true
+			m.checkName()
+			return m, nil
+		case key.Matches(msg, newKeyKey) && m.focusIndex == keysField:
+			if r := m.readOnlyReason(); r != "" {
+				m.formErr = r + ", so keys can't be added"
+				return m, nil
+			}
+			if m.dryRun {
+				m.formErr = "--dry-run is on, so keys can't be added"
+				return m, nil
+			}
+			return m, generateKey()
+		}
+
 		switch msg.String() {
 		case "ctrl+c", "esc":
-			return m, tea.Quit
+			return m, m.quit()
 
 		// Set focus to next input
 		case "tab", "shift+tab", "enter", "up", "down":
 			s := msg.String()
 
-			if s == "enter" && m.focusIndex == len(m.inputs) {
-				m.droplet = setDropletCreate(m.inputs)
+			if f, ok := m.focused().(*textField); ok && (s == "up" || s == "down") && f.cycleHistory(s == "up") {
+				return m, nil
+			}
 
-				m.creating = true
-				cmds := make([]tea.Cmd, 2)
-				cmds[0] = dropletCreate(m.droplet)
-				cmds[1] = spinner.Tick
+			if s == "enter" && m.focusIndex == len(m.fields) {
+				return m.submit()
+			}
 
-				return m, tea.Batch(cmds...)
+			if p, ok := m.focused().(picker); ok && s == "enter" {
+				p.Open()
+				return m, nil
 			}
 
 			// Cycle indexes
@@ -116,38 +509,529 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.focusIndex++
 			}
 
-			if m.focusIndex > len(m.inputs) {
+			if m.focusIndex > len(m.fields) {
 				m.focusIndex = 0
 			} else if m.focusIndex < 0 {
-				m.focusIndex = len(m.inputs)
+				m.focusIndex = len(m.fields)
 			}
 
-			cmds := make([]tea.Cmd, len(m.inputs))
-			for i := 0; i <= len(m.inputs)-1; i++ {
+			cmds := make([]tea.Cmd, len(m.fields))
+			for i := 0; i <= len(m.fields)-1; i++ {
 				if i == m.focusIndex {
 					// Set focused state
-					cmds[i] = m.inputs[i].Focus()
-					m.inputs[i].PromptStyle = focusedStyle
-					m.inputs[i].TextStyle = focusedStyle
+					cmds[i] = m.fields[i].Focus()
 					continue
 				}
 				// Remove focused state
-				m.inputs[i].Blur()
-				m.inputs[i].PromptStyle = noStyle
-				m.inputs[i].TextStyle = noStyle
+				m.fields[i].Blur()
 			}
 
 			return m, tea.Batch(cmds...)
 		}
 
+	case regionsMsg:
+		cmd := m.fields[regionField].(*selectField).SetOptions(msg)
+		return m, tea.Batch(cmd, m.setRegion())
+
+	case sizesMsg:
+		return m, m.fields[sizeField].(*sizePicker).SetSizes(msg)
+
+	case imagesMsg:
+		return m, m.fields[imageField].(*imagePicker).SetImages(msg)
+
+	case keysMsg:
+		return m, m.fields[keysField].(*multiSelectField).SetOptions(msg)
+
+	case vpcsMsg:
+		if msg.err != nil {
+			m.fieldErrs[vpcField] = "couldn't list the VPCs: " + msg.err.Error()
+		}
+		return m, m.fields[vpcField].(*vpcPicker).SetVPCs(msg.vpcs)
+
+	case projectsMsg:
+		if msg.err != nil {
+			m.fieldErrs[projectField] = "couldn't list the projects: " + msg.err.Error()
+		}
+		p := m.fields[projectField].(*selectField)
+		p.fallback = msg.defaultID
+		return m, p.SetOptions(msg.options)
+
+	case domainsMsg:
+		if msg.err != nil {
+			m.fieldErrs[domainField] = "couldn't list the domains: " + msg.err.Error()
+		}
+		return m, m.fields[domainField].(*selectField).SetOptions(msg.options)
+
+	case reservedIPsMsg:
+		if msg.err != nil {
+			m.fieldErrs[reservedIPField] = "couldn't list the reserved IPs: " + msg.err.Error()
+		}
+		return m, m.fields[reservedIPField].(*reservedIPPicker).SetReservedIPs(msg.ips)
+
+	case cloudInitMsg:
+		m.fields[userDataField].(*userDataEditor).SetValue(m.fillCloudInit(string(msg)))
+		m.edited = true
+		return m, nil
+
+	case keyGeneratedMsg:
+		if msg.err != nil {
+			m.formErr = fmt.Sprintf("couldn't generate a key: %s", msg.err)
+			return m, nil
+		}
+		return m, createKey(m.client, msg.path)
+
+	case keyCreatedMsg:
+		return m.addKey(msg)
+
+	case firewallsMsg:
+		if msg.err != nil {
+			m.fieldErrs[firewallsField] = "couldn't list the firewalls: " + msg.err.Error()
+		}
+		return m, m.fields[firewallsField].(*multiSelectField).SetOptions(msg.options)
+
+	case loadBalancersMsg:
+		if msg.err != nil {
+			m.fieldErrs[loadBalancerField] = "couldn't list the load balancers: " + msg.err.Error()
+		}
+		return m, m.fields[loadBalancerField].(*loadBalancerPicker).SetLoadBalancers(msg.lbs)
+
+	case templatesMsg:
+		switch {
+		case msg.err != nil:
+			m.formErr = msg.err.Error()
+		case len(msg.options) == 0:
+			m.formErr = "no templates have been saved yet"
+		default:
+			cmd := m.templates.SetOptions(msg.options)
+			m.templates.Open()
+			return m, cmd
+		}
+		return m, nil
+
+	case templateMsg:
+		if msg.err != nil {
+			m.formErr = msg.err.Error()
+			return m, nil
+		}
+		m.formErr = ""
+		m.fieldErrs = make(map[int]string)
+		m.notice = fmt.Sprintf("Loaded template %s", msg.name)
+		m.edited = true
+		return m, m.loadSpec(msg.spec)
+
+	case creationsMsg:
+		switch {
+		case msg.err != nil:
+			m.formErr = msg.err.Error()
+		case len(msg.creations) == 0:
+			m.formErr = "nothing has been created yet"
+		default:
+			opts := make([]option, len(msg.creations))
+			for i, c := range msg.creations {
+				opts[i] = creationItem{index: i, creation: c}
+			}
+			cmd := m.creations.SetOptions(opts)
+			m.creations.Open()
+			return m, cmd
+		}
+		return m, nil
+
+	case templateSavedMsg:
+		if msg.err != nil {
+			m.formErr = msg.err.Error()
+			return m, nil
+		}
+		m.notice = fmt.Sprintf("Saved template %s", msg.name)
+		return m, nil
+
+	case accountMsg:
+		m.account = msg
+		return m, nil
+
+	case dropletsMsg:
+		if m.list == nil {
+			return m, nil
+		}
+		// The last page was emptied, e.g. by deleting, so go back one.
+		if msg.err == nil && len(msg.droplets) == 0 && msg.page > 1 {
+			return m, fetchDroplets(m.client, msg.page-1)
+		}
+		m.list.setDroplets(msg)
+		return m, nil
+
+	case dropletDeletedMsg:
+		return m.deleted(msg)
+
+	case volumesMsg:
+		return m.setVolumes(msg)
+
+	case volumeRegionsMsg:
+		return m.setVolumeRegions(msg)
+
+	case volumeDoneMsg:
+		return m.volumeDone(msg)
+
+	case volumeAttachMsg:
+		return m.volumeAttached(msg)
+
+	case domainListMsg:
+		return m.setDomainList(msg)
+
+	case domainDoneMsg:
+		return m.domainDone(msg)
+
+	case recordsMsg:
+		return m.setRecords(msg)
+
+	case recordDoneMsg:
+		return m.recordDone(msg)
+
+	case reservedIPListMsg:
+		return m.setReservedIPList(msg)
+
+	case reservedIPRegionsMsg:
+		return m.setReservedIPRegions(msg)
+
+	case assignDropletsMsg:
+		return m.setAssignDroplets(msg)
+
+	case reservedIPDoneMsg:
+		return m.reservedIPDone(msg)
+
+	case firewallListMsg:
+		return m.setFirewallList(msg)
+
+	case firewallDropletsMsg:
+		return m.setFirewallDroplets(msg)
+
+	case firewallDoneMsg:
+		return m.firewallDone(msg)
+
+	case loadBalancerListMsg:
+		return m.setLoadBalancerList(msg)
+
+	case lbRegionsMsg:
+		return m.setLBRegions(msg)
+
+	case lbDropletsMsg:
+		return m.setLBDroplets(msg)
+
+	case loadBalancerCreatedMsg:
+		return m.loadBalancerCreated(msg)
+
+	case loadBalancerDoneMsg:
+		return m.loadBalancerDone(msg)
+
+	case clusterListMsg:
+		return m.setClusterList(msg)
+
+	case clusterOptionsMsg:
+		return m.setClusterOptions(msg)
+
+	case clusterCreatedMsg:
+		return m.clusterCreated(msg)
+
+	case clusterDoneMsg:
+		return m.clusterDone(msg)
+
+	case kubeconfigMsg:
+		return m.kubeconfigSaved(msg)
+
+	case databaseListMsg:
+		return m.setDatabaseList(msg)
+
+	case appListMsg:
+		return m.setAppList(msg)
+
+	case appProposedMsg:
+		return m.setAppProposal(msg)
+
+	case appDeployStartedMsg:
+		return m.appDeployStarted(msg)
+
+	case appDeploymentMsg:
+		return m.appDeploymentUpdated(msg)
+
+	case spacesListMsg:
+		return m.setSpacesList(msg)
+
+	case spacesRegionsMsg:
+		return m.setSpacesRegions(msg)
+
+	case bucketCreatedMsg:
+		return m.bucketCreated(msg)
+
+	case registryListMsg:
+		return m.setRegistryList(msg)
+
+	case registryTagsMsg:
+		return m.setRegistryTags(msg)
+
+	case registryGCMsg:
+		return m.garbageCollectionUpdated(msg)
+
+	case projectListMsg:
+		return m.setProjectList(msg)
+
+	case projectCreatedMsg:
+		return m.projectCreated(msg)
+
+	case projectResourcesMsg:
+		return m.setProjectResources(msg)
+
+	case projectTargetsMsg:
+		return m.setProjectTargets(msg)
+
+	case projectDropletsMsg:
+		return m.setProjectDroplets(msg)
+
+	case projectAssignedMsg:
+		return m.projectAssigned(msg)
+
+	case keyListMsg:
+		return m.setKeyList(msg)
+
+	case keyUploadedMsg:
+		return m.keyUploaded(msg)
+
+	case keyDeletedMsg:
+		return m.keyDeleted(msg)
+
+	case vpcListMsg:
+		return m.setVPCList(msg)
+
+	case vpcRegionsMsg:
+		return m.setVPCRegions(msg)
+
+	case vpcCreatedMsg:
+		return m.vpcCreated(msg)
+
+	case vpcMembersMsg:
+		return m.setVPCMembers(msg)
+
+	case cdnListMsg:
+		return m.setCDNList(msg)
+
+	case cdnOriginsMsg:
+		return m.setCDNOrigins(msg)
+
+	case cdnCreatedMsg:
+		return m.cdnCreated(msg)
+
+	case cdnDoneMsg:
+		return m.cdnDone(msg)
+
+	case certificatesMsg:
+		return m.setCertificates(msg)
+
+	case certificateListMsg:
+		return m.setCertificateList(msg)
+
+	case certificateCreatedMsg:
+		return m.certificateCreated(msg)
+
+	case certificateIssuedMsg:
+		return m.certificateIssued(msg)
+
+	case alertListMsg:
+		return m.setAlertList(msg)
+
+	case alertDropletsMsg:
+		return m.setAlertDroplets(msg)
+
+	case alertPolicyCreatedMsg:
+		return m.alertPolicyCreated(msg)
+
+	case namespaceListMsg:
+		return m.setNamespaceList(msg)
+
+	case namespaceTriggersMsg:
+		return m.setNamespaceTriggers(msg)
+
+	case attachDropletsMsg:
+		return m.setAttachDroplets(msg)
+
+	case dropletVolumesMsg:
+		return m.setDropletVolumes(msg)
+
+	case dropletActionMsg:
+		return m.actionDone(msg)
+
+	case resizeSizesMsg:
+		return m.setResizeSizes(msg)
+
+	case rebuildImagesMsg:
+		return m.setRebuildImages(msg)
+
+	case dropletBackupsMsg:
+		return m.setBackups(msg)
+
+	case kernelsMsg:
+		return m.setKernels(msg)
+
+	case dropletDetailMsg:
+		if m.list != nil && m.list.detail != nil && m.list.detail.id == msg.id {
+			m.list.detail.droplet, m.list.detail.volumes, m.list.detail.err = msg.droplet, msg.volumes, msg.err
+		}
+		return m, nil
+
+	case teamsMsg:
+		m.teams = msg
+		return m, setContextOptions(m.switcher, m.contexts, m.teams)
+
+	case quotaMsg:
+		m.quota = msg
+		m.checking = false
+		m.reviewing = true
+		return m, nil
+
+	case createdMsg:
+		m.pending = make([]pendingDroplet, len(msg.droplets))
+		cmds := make([]tea.Cmd, len(msg.droplets))
+		for i := range msg.droplets {
+			m.pending[i].droplet = &msg.droplets[i]
+			m.pending[i].volume = msg.volume
+			cmds[i] = waitForDroplet(m.client, i, msg.droplets[i].ID, msg.actions[i], m.setup())
+		}
+		return m, tea.Batch(cmds...)
+
+	case readyMsg:
+		p := &m.pending[msg.index]
+		p.err = msg.err
+		if msg.droplet != nil {
+			p.droplet = msg.droplet
+		}
+		p.record = msg.record
+		p.reservedIP = msg.reservedIP
+		if f := m.fields[firewallsField].(*multiSelectField); msg.err == nil && len(f.Values()) > 0 {
+			p.firewalls = f.Label()
+		}
+		if l := m.fields[loadBalancerField].(*loadBalancerPicker); msg.err == nil && l.Value() != "" {
+			p.loadBalancer = l.Label()
+		}
+		p.ptr, p.ptrErr = msg.ptr, msg.ptrErr
+		if msg.err == nil && m.waitsForSSH() {
+			p.active = true
+			return m, m.waitForSSH(msg.index, p.droplet)
+		}
+		p.done = true
+		return m.checkCreated()
+
+	case reachableMsg:
+		p := &m.pending[msg.index]
+		p.done = true
+		p.err = msg.err
+		p.hostKeys, p.hostKeyErr = msg.hostKeys, msg.pinErr
+		return m.checkCreated()
+
+	case sshConfigMsg:
+		if msg.err != nil {
+			m.formErr = fmt.Sprintf("couldn't update the SSH config: %s", msg.err)
+			return m, nil
+		}
+		m.notice = fmt.Sprintf("Added Host %s to the SSH config; run ssh %s to log in", msg.host, msg.host)
+		return m, nil
+
+	case inventoryMsg:
+		if msg.err != nil {
+			m.formErr = fmt.Sprintf("couldn't write the Ansible inventory: %s", msg.err)
+			return m, nil
+		}
+		return m, m.toast(fmt.Sprintf("Wrote the Ansible inventory to %s", msg.path))
+
+	case pinnedMsg:
+		p := &m.pending[msg.index]
+		p.hostKeys, p.hostKeyErr = msg.keys, msg.err
+		if msg.err != nil {
+			m.formErr = fmt.Sprintf("couldn't pin the host keys of %s: %s", p.droplet.Name, msg.err)
+			return m, nil
+		}
+		if msg.ssh {
+			return m, m.ssh()
+		}
+		return m, nil
+
+	case healthMsg:
+		m.pending[msg.index].healthDone = true
+		m.pending[msg.index].healthErr = msg.err
+		// Only hand healthy Droplets on to the post-create hook.
+		if msg.err == nil && m.defaults.PostCreate != "" {
+			m.pending[msg.index].hooked = true
+			return m, m.runHook(msg.index)
+		}
+		return m, nil
+
+	case hookMsg:
+		m.pending[msg.index].hookDone = true
+		m.pending[msg.index].hookErr = msg.err
+		return m, nil
+
+	case copiedMsg:
+		if msg.err != nil {
+			m.formErr = fmt.Sprintf("couldn't copy to the clipboard: %s", msg.err)
+			return m, nil
+		}
+		return m, m.toast(fmt.Sprintf("Copied %s to the clipboard", msg.text))
+
+	case openedMsg:
+		if msg.err != nil {
+			m.formErr = fmt.Sprintf("couldn't open a browser, visit %s instead: %s", msg.url, msg.err)
+			return m, nil
+		}
+		return m, m.toast("Opened " + msg.url)
+
+	case toastExpiredMsg:
+		if m.notice == string(msg) {
+			m.notice = ""
+		}
+		return m, nil
+
+	case sshDoneMsg:
+		if msg.err != nil {
+			m.formErr = fmt.Sprintf("ssh: %s", msg.err)
+		}
+		return m, nil
+
+	case createFailedMsg:
+		logged := msg.err
+		if msg.cleanupErr != nil {
+			logged = fmt.Errorf("%s; %s", msg.err, msg.cleanupErr)
+		}
+		if isForbidden(msg.err) {
+			// The probe at startup couldn't tell, but the token can't
+			// create Droplets, so back to the form, which now shows it.
+			m.account.scope = writeRefused
+			m.creating = false
+			m.submitted = false
+			m.formErr = m.readOnlyReason() + ", so Droplets can't be created"
+			if msg.cleanupErr != nil {
+				m.formErr += "; " + msg.cleanupErr.Error()
+			}
+			return m, m.logCreation(logged)
+		}
+		out := dropletErrorMsg(msg.err)
+		if msg.cleanupErr != nil {
+			out += errorStyle.Render(msg.cleanupErr.Error()) + "\n\n"
+		}
+		return m.quitWith(out, m.logCreation(logged))
+
 	case dropletMsg:
-		m.finalMsg = string(msg)
-		return m, tea.Quit
+		return m.quitWith(string(msg))
+	}
+
+	if _, ok := msg.(tea.KeyMsg); ok {
+		m.edited = true
 	}
 
 	cmds := make([]tea.Cmd, 2)
 	cmds[0] = m.updateInputs(msg)
 
+	// The names depend on both the name and count, so check them as either
+	// is edited.
+	if _, ok := msg.(tea.KeyMsg); ok && (m.focusIndex == nameField || m.focusIndex == countField) {
+		m.checkName()
+	}
+
 	var spinnerCmd tea.Cmd
 	m.spinner, spinnerCmd = m.spinner.Update(msg)
 	cmds[1] = spinnerCmd
@@ -155,118 +1039,346 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// submit validates the form and, once the account limits have been checked,
+// moves on to the review.
+func (m model) submit() (tea.Model, tea.Cmd) {
+	if r := m.readOnlyReason(); r != "" && !m.dryRun {
+		m.formErr = r + ", so Droplets can't be created"
+		return m, nil
+	}
+
+	m.fieldErrs = m.validateSlugs()
+	if err := m.validateName(); err != "" {
+		m.fieldErrs[nameField] = err
+	}
+	if err := m.fields[userDataField].(*userDataEditor).validate(); err != "" {
+		m.fieldErrs[userDataField] = err
+	}
+	if len(m.fieldErrs) > 0 {
+		m.formErr = "fix the errors above before creating"
+		return m, nil
+	}
+
+	m.droplet = setDropletCreate(m.fields)
+	m.droplet.Tags = parseTags(strings.Join(append(m.droplet.Tags, m.autoTags()...), ","))
+
+	names, err := dropletNames(m.droplet.Name, m.fields[countField].Value())
+	if err != nil {
+		m.formErr = err.Error()
+		return m, nil
+	}
+	volume, err := volumeRequest(m.fields[volumeNameField].Value(), m.fields[volumeSizeField].Value(), m.droplet.Region)
+	if err == nil && volume != nil && len(names) > 1 {
+		err = errors.New("a volume can only be attached when creating a single Droplet")
+	}
+	if err == nil {
+		err = checkReservedIP(m.fields[reservedIPField].Value(), names)
+	}
+	if err != nil {
+		m.formErr = err.Error()
+		return m, nil
+	}
+	m.formErr = ""
+
+	m.names = names
+	m.volume = volume
+	m.checking = true
+
+	return m, tea.Batch(checkQuota(m.client), m.spinner.Tick)
+}
+
+// checkName shows an error under the name field if any of the names it
+// expands to isn't a valid hostname.
+func (m model) checkName() {
+	if err := m.validateName(); err != "" {
+		m.fieldErrs[nameField] = err
+	} else {
+		delete(m.fieldErrs, nameField)
+	}
+}
+
+// setRegion narrows the fields that depend on the region down to the one
+// currently chosen.
+func (m model) setRegion() tea.Cmd {
+	region := m.fields[regionField].Value()
+
+	return tea.Batch(
+		m.fields[sizeField].(*sizePicker).SetRegion(region),
+		m.fields[vpcField].(*vpcPicker).SetRegion(region),
+		m.fields[reservedIPField].(*reservedIPPicker).SetRegion(region),
+		m.fields[loadBalancerField].(*loadBalancerPicker).SetRegion(region),
+	)
+}
+
+// projectID returns the project to assign new Droplets to, or an empty string
+// when they should stay in the default project.
+func (m model) projectID() string {
+	p := m.fields[projectField].(*selectField)
+	if p.Value() == p.fallback {
+		return ""
+	}
+	return p.Value()
+}
+
+// focused returns the form row that currently has focus, or nil when the
+// Create button is focused.
+func (m model) focused() field {
+	if m.focusIndex < len(m.fields) {
+		return m.fields[m.focusIndex]
+	}
+	return nil
+}
+
+// openPicker returns the field whose option list is being shown.
+func (m model) openPicker() picker {
+	if p, ok := m.focused().(picker); ok && p.Opened() {
+		return p
+	}
+	return nil
+}
+
 func (m *model) updateInputs(msg tea.Msg) tea.Cmd {
-	var cmds = make([]tea.Cmd, len(m.inputs))
+	var cmds = make([]tea.Cmd, len(m.fields))
 
 	// Only text inputs with Focus() set will respond, so it's safe to simply
 	// update all of them here without any further logic.
-	for i := range m.inputs {
-		m.inputs[i], cmds[i] = m.inputs[i].Update(msg)
+	for i := range m.fields {
+		m.fields[i], cmds[i] = m.fields[i].Update(msg)
 	}
 
 	return tea.Batch(cmds...)
 }
 
+// quitWith runs cmds, then prints msg above the interface and exits. Printed
+// that way, msg stays in the terminal's scrollback after the program exits,
+// however long it is, rather than being cut down to the last frame.
+func (m model) quitWith(msg string, cmds ...tea.Cmd) (tea.Model, tea.Cmd) {
+	m.finalMsg = msg
+	cmds = append(cmds, tea.Println(strings.TrimSuffix(msg, "\n")), tea.Quit)
+	return m, tea.Sequence(cmds...)
+}
+
 func (m model) View() string {
+	// The final message has been printed above the interface.
+	if m.finalMsg != "" {
+		return ""
+	}
+
+	if m.login != nil {
+		return m.loginView()
+	}
+
+	return m.withStatusBar(m.view())
+}
+
+// view renders the screen for the current state, without the status bar.
+func (m model) view() string {
 	var b strings.Builder
 
-	if m.finalMsg != "" {
-		fmt.Fprint(&b, m.finalMsg)
+	if m.export != nil {
+		return m.exportView()
+	}
 
-		return b.String()
+	if m.screens != nil {
+		return m.screens.View()
+	}
+	if m.list != nil {
+		return m.listView()
+	}
+	if m.volumes != nil {
+		return m.volumesView()
+	}
+	if m.domains != nil {
+		return m.domainsView()
+	}
+	if m.reservedIPs != nil {
+		return m.reservedIPsView()
+	}
+	if m.firewalls != nil {
+		return m.firewallsView()
+	}
+	if m.loadBalancers != nil {
+		return m.loadBalancersView()
+	}
+	if m.clusters != nil {
+		return m.clustersView()
+	}
+	if m.databases != nil {
+		return m.databasesView()
+	}
+	if m.apps != nil {
+		return m.appsView()
+	}
+	if m.spaces != nil {
+		return m.spacesView()
+	}
+	if m.registry != nil {
+		return m.registryView()
+	}
+	if m.projects != nil {
+		return m.projectsView()
+	}
+	if m.sshKeys != nil {
+		return m.keysView()
+	}
+	if m.vpcs != nil {
+		return m.vpcsView()
+	}
+	if m.cdns != nil {
+		return m.cdnsView()
+	}
+	if m.certificates != nil {
+		return m.certificatesView()
+	}
+	if m.alerts != nil {
+		return m.alertsView()
+	}
+	if m.functions != nil {
+		return m.functionsView()
 	}
 
 	if m.creating {
-		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Creating Droplet..."))
+		return m.creatingView()
+	}
+
+	if m.upload != nil {
+		return m.uploadView()
+	}
+
+	if m.bootstrap != nil {
+		return m.bootstrapView()
+	}
+
+	if m.succeeded {
+		return m.createdView()
+	}
+
+	if m.checking {
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Checking account limits..."))
 
 		return b.String()
 	}
 
-	for i := range m.inputs {
-		b.WriteString(m.inputs[i].View())
-		if i < len(m.inputs)-1 {
-			b.WriteRune('\n')
-		}
+	if m.reviewing {
+		return m.reviewView()
 	}
 
-	button := &blurredButton
-	if m.focusIndex == len(m.inputs) {
-		button = &focusedButton
+	if m.restore != nil {
+		return m.restoreView()
 	}
-	fmt.Fprintf(&b, "\n\n%s\n\n", *button)
 
-	return b.String()
-}
+	if m.switcher != nil && m.switcher.Opened() {
+		return m.switcher.View()
+	}
 
-func dropletCreate(createReq *godo.DropletCreateRequest) tea.Cmd {
-	return func() tea.Msg {
-		token := os.Getenv("DO_TOKEN")
-		if token == "" {
-			return dropletMsg(dropletErrorMsg(errors.New("set the 'DO_TOKEN' environment variable to a DigitalOcean API token")))
-		}
+	if m.templateName != nil {
+		return m.templateNameView()
+	}
 
-		client := godo.NewFromToken(token)
-		ctx := context.Background()
+	if m.templates.Opened() {
+		return m.templates.View()
+	}
 
-		droplet, resp, err := client.Droplets.Create(ctx, createReq)
-		if err != nil {
-			return dropletMsg(dropletErrorMsg(err))
-		}
-		err = util.WaitForActive(ctx, client, resp.Links.Actions[0].HREF)
-		if err != nil {
-			return dropletMsg(dropletErrorMsg(err))
-		}
-		droplet, _, err = client.Droplets.Get(ctx, droplet.ID)
-		if err != nil {
-			return dropletMsg(dropletErrorMsg(err))
+	if m.creations.Opened() {
+		return m.creations.View()
+	}
+
+	if p := m.openPicker(); p != nil {
+		return p.View()
+	}
+
+	if team := m.team(); team != "" {
+		fmt.Fprintf(&b, "%s %s  ", noStyle.Render("Team:"), teamStyle.Render(team))
+	}
+	fmt.Fprintf(&b, "%s %s", noStyle.Render("Account:"), m.accountView())
+	if m.context != "" {
+		fmt.Fprintf(&b, "  %s %s", noStyle.Render("Context:"), focusedStyle.Render(m.context))
+		if m.switcher != nil {
+			fmt.Fprintf(&b, "  %s", helpStyle.Render("ctrl+x: switch"))
 		}
+	}
+	b.WriteString("\n\n")
 
-		pubIP, err := droplet.PublicIPv4()
-		if err != nil {
-			return dropletMsg(dropletErrorMsg(err))
+	for i := range m.fields {
+		b.WriteString(m.fields[i].View())
+		if err, ok := m.fieldErrs[i]; ok {
+			fmt.Fprintf(&b, "\n  %s", errorStyle.Render("✗ "+err))
+		} else if hint := m.nameHint(); i == nameField && hint != "" {
+			fmt.Fprintf(&b, "\n  %s", placeholderStyle.Render("ℹ "+hint))
 		}
-		privIP, err := droplet.PrivateIPv4()
-		if err != nil {
-			return dropletMsg(dropletErrorMsg(err))
+		if i < len(m.fields)-1 {
+			b.WriteRune('\n')
 		}
+	}
 
-		var b strings.Builder
-		fmt.Fprintf(&b, "🎉 💧 %s\n\n", focusedStyle.Render("Success!"))
-		fmt.Fprintf(&b, "%s %s\n", focusedStyle.Render("Name:"), placeholderStyle.Render(droplet.Name))
-		fmt.Fprintf(&b, "%s %s\n", focusedStyle.Render("Price Monthly:"), placeholderStyle.Render(fmt.Sprintf("$%.2f", droplet.Size.PriceMonthly)))
-		fmt.Fprintf(&b, "%s %s\n", focusedStyle.Render("Region:"), placeholderStyle.Render(droplet.Region.Name))
-		fmt.Fprintf(&b, "%s %s\n", focusedStyle.Render("Size:"), placeholderStyle.Render(droplet.Size.Slug))
-		fmt.Fprintf(&b, "%s %s\n", focusedStyle.Render("Public IPv4:"), placeholderStyle.Render(pubIP))
-		fmt.Fprintf(&b, "%s %s\n", focusedStyle.Render("Private IPv4:"), placeholderStyle.Render(privIP))
-		fmt.Fprint(&b, "\n")
+	if cost := m.costView(); cost != "" {
+		fmt.Fprintf(&b, "\n\n%s", cost)
+	}
 
-		return dropletMsg(b.String())
+	if m.notice != "" {
+		fmt.Fprintf(&b, "\n\n%s", placeholderStyle.Render(m.notice))
 	}
-}
 
-func dropletErrorMsg(err error) string {
-	return fmt.Sprintf("%s\n\n%s\n\n", focusedStyle.Render("😞 Something went wrong:"), placeholderStyle.Render(err.Error()))
+	if m.formErr != "" {
+		fmt.Fprintf(&b, "\n\n%s", errorStyle.Render(m.formErr))
+	}
+
+	button := &blurredButton
+	if m.focusIndex == len(m.fields) {
+		button = &focusedButton
+	}
+	if r := m.readOnlyReason(); r != "" && !m.dryRun {
+		button = &disabledButton
+	}
+	fmt.Fprintf(&b, "\n\n%s\n\n", *button)
+	help := "↑/↓ in a text field: recent values • ctrl+s: save template • ctrl+t: load template • ctrl+p: past creations • ctrl+o: droplets"
+	switch m.focusIndex {
+	case nameField:
+		help = "ctrl+g: generate name • " + help
+	case keysField:
+		help = "ctrl+n: new key • " + help
+	}
+	b.WriteString(helpStyle.Render(help))
+
+	return b.String()
 }
 
-func setDropletCreate(inputs []textinput.Model) *godo.DropletCreateRequest {
-	droplet := &godo.DropletCreateRequest{}
+func dropletErrorMsg(err error) string {
+	var errResp *godo.ErrorResponse
+	if !errors.As(err, &errResp) || errResp.Response == nil {
+		return fmt.Sprintf("%s\n\n%s\n\n", focusedStyle.Render("😞 Something went wrong:"), placeholderStyle.Render(err.Error()))
+	}
 
-	droplet.Name = inputs[0].Value()
-	if droplet.Name == "" {
-		droplet.Name = inputs[0].Placeholder
+	// Spell out the details that DigitalOcean support will ask for.
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n\n%s\n\n", focusedStyle.Render("😞 Something went wrong:"), placeholderStyle.Render(errResp.Message))
+	rows := [][2]string{
+		{"Status", fmt.Sprintf("%d %s", errResp.Response.StatusCode, http.StatusText(errResp.Response.StatusCode))},
+	}
+	if req := errResp.Response.Request; req != nil {
+		rows = append(rows, [2]string{"Request", req.Method + " " + req.URL.String()})
 	}
-	droplet.Region = inputs[1].Value()
-	if droplet.Region == "" {
-		droplet.Region = inputs[1].Placeholder
+	if errResp.RequestID != "" {
+		rows = append(rows, [2]string{"Request ID", errResp.RequestID})
 	}
-	droplet.Size = inputs[2].Value()
-	if droplet.Size == "" {
-		droplet.Size = inputs[2].Placeholder
+	for _, r := range rows {
+		fmt.Fprintf(&b, "%s %s\n", focusedStyle.Render(fmt.Sprintf("%-11s", r[0]+":")), placeholderStyle.Render(r[1]))
 	}
-	imageStr := inputs[3].Value()
-	if imageStr == "" {
-		imageStr = inputs[3].Placeholder
+	if errResp.RequestID != "" {
+		fmt.Fprintf(&b, "\n%s\n", helpStyle.Render("Include the request ID if you contact DigitalOcean support."))
 	}
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+func setDropletCreate(fields []field) *godo.DropletCreateRequest {
+	droplet := &godo.DropletCreateRequest{}
+
+	droplet.Name = fields[nameField].Value()
+	droplet.Region = fields[regionField].Value()
+	droplet.Size = fields[sizeField].Value()
+	imageStr := fields[imageField].Value()
 	createImage := godo.DropletCreateImage{Slug: imageStr}
 	i, err := strconv.Atoi(imageStr)
 	if err == nil {
@@ -274,12 +1386,209 @@ func setDropletCreate(inputs []textinput.Model) *godo.DropletCreateRequest {
 	}
 	droplet.Image = createImage
 
+	for _, v := range fields[keysField].(*multiSelectField).Values() {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			continue
+		}
+		droplet.SSHKeys = append(droplet.SSHKeys, godo.DropletCreateSSHKey{ID: id})
+	}
+	droplet.Tags = parseTags(fields[tagsField].Value())
+	if userData := fields[userDataField].Value(); strings.TrimSpace(userData) != "" {
+		droplet.UserData = userData
+	}
+	droplet.Backups = fields[backupsField].(*toggleField).Checked()
+	droplet.Monitoring = fields[monitoringField].(*toggleField).Checked()
+	droplet.IPv6 = fields[ipv6Field].(*toggleField).Checked()
+	droplet.VPCUUID = fields[vpcField].Value()
+
 	return droplet
 }
 
 func main() {
-	if err := tea.NewProgram(initialModel()).Start(); err != nil {
+	dryRun := flag.Bool("dry-run", false, "print the create request as JSON instead of sending it")
+	contextName := flag.String("context", "", "use the named doctl auth context")
+	readOnly := flag.Bool("read-only", false, "browse without making any changes to the account")
+	configFile := flag.String("config", "", "read the config from this file instead of the default location")
+	waitSSH := flag.Bool("wait-ssh", false, "wait for SSH to accept connections before reporting success")
+	bootstrap := flag.String("bootstrap", "", "run this local shell script on new Droplets over SSH")
+	var uploads []upload
+	flag.Func("upload", "copy a local file or directory to new Droplets over SFTP, as local:remote (may be repeated)", func(s string) error {
+		u, err := parseUpload(s)
+		uploads = append(uploads, u)
+		return err
+	})
+	inventory := flag.String("inventory", "", "write an Ansible inventory of the new Droplets to this file (.ini or .yml)")
+	healthCheck := flag.String("health-check", "", "probe this URL on new Droplets once they're set up, e.g. http://{ip}/healthz")
+	postCreate := flag.String("post-create", "", "run this local command for each new Droplet once it's set up, with DROPLET_IP, DROPLET_ID and DROPLET_NAME set")
+	console := flag.String("console", "", "open the web console of an existing Droplet, by name or ID, and exit")
+	output := flag.String("o", outputText, "print the created Droplets to stdout on exit as text or json, or the request as a yaml spec")
+	specFile := flag.String("f", "", "fill the form in from this YAML or JSON spec, or - for stdin (the default when it's piped)")
+	yes := flag.Bool("yes", false, "skip the form and go straight to the review, e.g. with -f")
+	noTUI := flag.Bool("no-tui", false, "create the Droplets without an interface, from the flags, -f and the config, printing them as JSON")
+	fields := newFieldFlags()
+
+	// create is the default command, and may be left out.
+	command := "create"
+	args := os.Args[1:]
+	if len(args) > 0 {
+		if _, ok := findScreen(args[0]); ok || args[0] == "create" {
+			command, args = args[0], args[1:]
+		}
+	}
+	flag.CommandLine.Parse(args)
+
+	// Without an interface, print something a script can read unless
+	// asked otherwise.
+	outputSet := false
+	flag.Visit(func(f *flag.Flag) { outputSet = outputSet || f.Name == "o" })
+	if *noTUI && !outputSet {
+		*output = outputJSON
+	}
+
+	if *output != outputText && *output != outputJSON && *output != outputYAML {
+		fmt.Fprintf(os.Stderr, "unknown output format %q; use text, json or yaml\n", *output)
+		os.Exit(2)
+	}
+
+	cfg, err := loadConfig(*configFile)
+	if err != nil {
+		fmt.Print(dropletErrorMsg(err))
+		os.Exit(1)
+	}
+
+	if *specFile == "" && command == "create" && stdinPiped() {
+		*specFile = "-"
+	}
+	var fromFile *spec
+	if *specFile != "" {
+		s, err := readSpecFile(*specFile)
+		if err != nil {
+			fmt.Print(dropletErrorMsg(err))
+			os.Exit(1)
+		}
+		fromFile = &s
+	}
+
+	apiURL := os.Getenv("DIGITALOCEAN_API_URL")
+	if apiURL == "" {
+		apiURL = cfg.APIURL
+	}
+
+	contexts, active, err := resolveContext(*contextName)
+
+	var m model
+	if active.token != "" {
+		client, err := newClient(active.token, apiURL)
+		if err != nil {
+			fmt.Print(dropletErrorMsg(err))
+			os.Exit(1)
+		}
+		if *console != "" {
+			if err := openExistingConsole(client, *console); err != nil {
+				fmt.Print(dropletErrorMsg(err))
+				os.Exit(1)
+			}
+			return
+		}
+		m = initialModel(client)
+	} else if *console != "" || *noTUI {
+		if err == nil {
+			err = errors.New("no API token found; set DIGITALOCEAN_TOKEN or log in with doctl first")
+		}
+		fmt.Print(dropletErrorMsg(err))
+		os.Exit(1)
+	} else {
+		m = initialModel(nil)
+		m.login = newTokenPrompt()
+		m.login.err = err
+		m.login.oauthClientID = os.Getenv("DIGITALOCEAN_OAUTH_CLIENT_ID")
+		if m.login.oauthClientID == "" {
+			m.login.oauthClientID = cfg.OAuthClientID
+		}
+	}
+	if *waitSSH {
+		cfg.WaitForSSH = true
+	}
+	if *bootstrap != "" {
+		cfg.Bootstrap = *bootstrap
+	}
+	if len(uploads) > 0 {
+		cfg.Uploads = uploads
+	}
+	if *inventory != "" {
+		cfg.Inventory = *inventory
+	}
+	if *healthCheck != "" {
+		cfg.HealthCheck = *healthCheck
+	}
+	if *postCreate != "" {
+		cfg.PostCreate = *postCreate
+	}
+	m.setDefaults(cfg)
+	m.setHistory(loadHistory())
+	s, onScreen := findScreen(command)
+	switch {
+	case onScreen:
+		m, m.screenCmd = s.open(m)
+		m.standalone = true
+	case fromFile != nil:
+		m.loadSpec(*fromFile)
+	case !*noTUI:
+		m.restore = loadDraft()
+	}
+	if command == "create" {
+		if err := fields.apply(&m); err != nil {
+			fmt.Print(dropletErrorMsg(err))
+			os.Exit(1)
+		}
+		m.autoSubmit = *yes || *noTUI
+		m.headless = *noTUI
+	}
+	m.apiURL = apiURL
+	m.dryRun = *dryRun
+	m.readOnly = *readOnly
+	m.output = *output
+	m.contexts = contexts
+	m.context = active.name
+	if len(contexts) > 1 {
+		m.switcher = newContextSwitcher(contexts, active.name)
+	}
+
+	// Leave stdout to the output so that it can be piped.
+	var opts []tea.ProgramOption
+	if m.output != outputText {
+		opts = append(opts, tea.WithOutput(os.Stderr))
+	}
+	switch {
+	case m.headless:
+		opts = append(opts, tea.WithInput(nil), tea.WithoutRenderer())
+	case *specFile == "-":
+		// The spec was read from stdin, so take the keyboard from the
+		// terminal.
+		opts = append(opts, tea.WithInputTTY())
+	}
+
+	final, err := tea.NewProgram(m, opts...).Run()
+	if err != nil {
 		fmt.Printf("could not start program: %s\n", err)
 		os.Exit(1)
 	}
+	if err := final.(model).printOutput(os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "could not print the Droplets: %s\n", err)
+		os.Exit(1)
+	}
+	if f := final.(model); f.headless {
+		// Nothing was drawn, so print how it went: to stdout with the
+		// text output or a dry run, otherwise to stderr, leaving stdout
+		// to the output.
+		if f.output == outputText || f.dryRun {
+			fmt.Print(f.finalMsg)
+		} else {
+			fmt.Fprint(os.Stderr, f.finalMsg)
+		}
+		if f.headlessFailed() {
+			os.Exit(1)
+		}
+	}
 }
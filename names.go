@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// defaultNameTemplate is used to generate names unless the config sets
+// name-template.
+const defaultNameTemplate = "{pet}"
+
+var generateNameKey = key.NewBinding(key.WithKeys("ctrl+g"), key.WithHelp("ctrl+g", "generate name"))
+
+var (
+	nameRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	adjectives = []string{
+		"amber", "bold", "brave", "bright", "calm", "clever", "cosmic", "crisp",
+		"daring", "eager", "fancy", "gentle", "happy", "jolly", "keen", "lively",
+		"lucky", "mellow", "misty", "nimble", "plucky", "proud", "quiet", "rapid",
+		"rustic", "shiny", "snappy", "sunny", "swift", "tidy", "witty", "zesty",
+	}
+	animals = []string{
+		"badger", "beaver", "bison", "crane", "dingo", "falcon", "ferret", "gecko",
+		"heron", "ibex", "jackal", "koala", "lemur", "lynx", "marmot", "newt",
+		"ocelot", "otter", "panda", "puffin", "quokka", "raven", "salmon", "shark",
+		"sloth", "tapir", "toucan", "urchin", "walrus", "wombat", "yak", "zebra",
+	}
+)
+
+// generateName fills in the template's placeholders:
+//
+//	{pet}     a random adjective and animal, e.g. plucky-otter
+//	{region}  the chosen region
+//	{size}    the chosen size
+//	{image}   the chosen image
+//	{random}  four random hex digits
+//	{date}    today's date as YYYYMMDD
+func (m model) generateName(template string) string {
+	if template == "" {
+		template = defaultNameTemplate
+	}
+
+	pet := adjectives[nameRand.Intn(len(adjectives))] + "-" + animals[nameRand.Intn(len(animals))]
+	r := strings.NewReplacer(
+		"{pet}", pet,
+		"{region}", m.fields[regionField].Value(),
+		"{size}", m.fields[sizeField].Value(),
+		"{image}", m.fields[imageField].Value(),
+		"{random}", fmt.Sprintf("%04x", nameRand.Intn(0x10000)),
+		"{date}", time.Now().Format("20060102"),
+	)
+
+	return r.Replace(template)
+}
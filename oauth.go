@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	oauthAuthorizeURL = "https://cloud.digitalocean.com/v1/oauth/authorize"
+	// oauthRedirectAddr is where the callback server listens. The OAuth app
+	// must be registered with http://localhost:8976/callback as its callback
+	// URL.
+	oauthRedirectAddr = "localhost:8976"
+	oauthTimeout      = 5 * time.Minute
+)
+
+// callbackPage hands the token, which the browser receives in the URL
+// fragment, back to the callback server.
+const callbackPage = `<!doctype html>
+<title>bubbletea-droplet</title>
+<script>location.replace("/token?" + (location.hash.slice(1) || location.search.slice(1)))</script>
+`
+
+var browserLoginKey = key.NewBinding(key.WithKeys("ctrl+l"), key.WithHelp("ctrl+l", "log in with browser"))
+
+// oauthMsg reports the token granted by logging in with the browser.
+type oauthMsg struct {
+	token string
+	err   error
+}
+
+// oauthURL returns the URL to authorize the app for read and write access.
+func oauthURL(clientID, state string) string {
+	v := url.Values{}
+	v.Set("client_id", clientID)
+	v.Set("redirect_uri", "http://"+oauthRedirectAddr+"/callback")
+	v.Set("response_type", "token")
+	v.Set("scope", "read write")
+	v.Set("state", state)
+
+	return oauthAuthorizeURL + "?" + v.Encode()
+}
+
+// oauthState returns a random value to tie the callback to the request.
+func oauthState() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// oauthLogin opens the authorization URL in the browser, then waits for it
+// to redirect back to a local callback server with the token.
+func oauthLogin(ctx context.Context, authURL, state string) tea.Cmd {
+	return func() tea.Msg {
+		ln, err := net.Listen("tcp", oauthRedirectAddr)
+		if err != nil {
+			return oauthMsg{err: err}
+		}
+
+		tokens := make(chan oauthMsg, 1)
+		mux := http.NewServeMux()
+		mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, callbackPage)
+		})
+		mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+			q := r.URL.Query()
+
+			var msg oauthMsg
+			switch {
+			case q.Get("state") != state:
+				msg.err = errors.New("the login response didn't match the request")
+			case q.Get("error") != "":
+				msg.err = fmt.Errorf("logging in failed: %s", q.Get("error"))
+			default:
+				msg.token = q.Get("access_token")
+			}
+
+			if msg.err != nil {
+				fmt.Fprintln(w, msg.err)
+			} else {
+				fmt.Fprintln(w, "Logged in. You can close this tab and return to the terminal.")
+			}
+			select {
+			case tokens <- msg:
+			default:
+			}
+		})
+
+		srv := &http.Server{Handler: mux}
+		go srv.Serve(ln)
+		defer func() {
+			// Let the browser finish loading the response first.
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			srv.Shutdown(ctx)
+		}()
+
+		// The URL is shown in the terminal too in case no browser opens.
+		openURL(authURL)
+
+		ctx, cancel := context.WithTimeout(ctx, oauthTimeout)
+		defer cancel()
+
+		select {
+		case msg := <-tokens:
+			return msg
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return oauthMsg{err: errors.New("timed out waiting to log in")}
+			}
+			return oauthMsg{err: ctx.Err()}
+		}
+	}
+}
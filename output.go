@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/digitalocean/godo"
+	"gopkg.in/yaml.v3"
+)
+
+// Formats the created Droplets can be printed in once the program exits,
+// chosen with -o.
+const (
+	outputText = "text"
+	outputJSON = "json"
+	// outputYAML prints the create request as a spec, in the same format as
+	// templates, so that it can be replayed later.
+	outputYAML = "yaml"
+)
+
+var printJSONKey = key.NewBinding(key.WithKeys("j"), key.WithHelp("j", "quit and print JSON"))
+
+// createdDroplets returns the Droplets that were created successfully.
+func (m model) createdDroplets() []*godo.Droplet {
+	var droplets []*godo.Droplet
+	for _, p := range m.pending {
+		if p.err == nil && p.done {
+			droplets = append(droplets, p.droplet)
+		}
+	}
+	return droplets
+}
+
+// printOutput writes the created Droplets to w in the chosen format, for
+// scripts to read once the program exits. A single Droplet is written as an
+// object, several as an array.
+func (m model) printOutput(w io.Writer) error {
+	switch {
+	case m.output == outputYAML && m.submitted:
+		data, err := yaml.Marshal(m.spec())
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	case m.output != outputJSON:
+		return nil
+	}
+
+	droplets := m.createdDroplets()
+	var v interface{} = droplets
+	switch len(droplets) {
+	case 0:
+		return nil
+	case 1:
+		v = droplets[0]
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
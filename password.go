@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+// passwordPrompt asks to confirm resetting a Droplet's root password.
+type passwordPrompt struct {
+	droplet godo.Droplet
+}
+
+// openPasswordReset asks to confirm resetting the Droplet's root password,
+// explaining where the new one is sent.
+func (m model) openPasswordReset(droplet godo.Droplet) (model, tea.Cmd) {
+	m.list.passwordReset = &passwordPrompt{droplet: droplet}
+	m.notice = ""
+	m.formErr = ""
+	return m, nil
+}
+
+func (m model) updatePasswordReset(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.list
+	p := l.passwordReset
+	switch msg.String() {
+	case "ctrl+c":
+		return m, m.quit()
+	case "y", "Y":
+		l.passwordReset = nil
+		l.busy[p.droplet.ID] = "resetting"
+		l.setRows()
+		return m, resetPassword(m.client, p.droplet)
+	case "n", "N", "esc":
+		l.passwordReset = nil
+	}
+	return m, nil
+}
+
+// resetPassword resets the Droplet's root password and waits for the action
+// to complete, by which time the new password has been emailed.
+func resetPassword(client *godo.Client, droplet godo.Droplet) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+
+		action, _, err := client.DropletActions.PasswordReset(ctx, droplet.ID)
+		if err == nil {
+			err = waitForAction(ctx, client, action)
+		}
+		return dropletActionMsg{id: droplet.ID, name: droplet.Name, verb: "reset the root password of", done: "Emailed a new root password for", err: err}
+	}
+}
+
+func (m model) passwordResetView() string {
+	p := m.list.passwordReset
+	var b strings.Builder
+
+	to := "the account's email address"
+	if m.account.account != nil {
+		to = m.account.account.Email
+	}
+	fmt.Fprintf(&b, "%s\n", focusedStyle.Render(fmt.Sprintf("Reset the root password of %s?", p.droplet.Name)))
+	fmt.Fprintf(&b, "%s\n", placeholderStyle.Render(fmt.Sprintf("The new password is emailed to %s, not shown here, and must be changed at the first login.", to)))
+	fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("It's power cycled to set the password, which interrupts anything running on it."))
+
+	return b.String()
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// appName is the directory created under each of the base directories.
+const appName = "bubbletea-droplet"
+
+// configDir returns the directory for files the user edits: the config file
+// and templates. XDG_CONFIG_HOME is honored on every platform, otherwise it's
+// ~/.config on Linux, ~/Library/Application Support on macOS and %AppData%
+// on Windows.
+func configDir() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, appName), nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, appName), nil
+}
+
+// configPath returns the default location of the config file.
+func configPath() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.yaml"), nil
+}
+
+// stateDir returns the directory for state kept between runs, such as the
+// history and drafts. XDG_STATE_HOME is honored on every platform, otherwise
+// it's ~/.local/state on Linux, ~/Library/Application Support on macOS and
+// %LocalAppData% on Windows.
+func stateDir() (string, error) {
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, appName), nil
+	}
+
+	var base string
+	var err error
+	switch runtime.GOOS {
+	case "darwin":
+		// There's no separate place for state, so it's kept with the config.
+		base, err = os.UserConfigDir()
+	case "windows":
+		// %LocalAppData%, which unlike %AppData% doesn't roam between
+		// machines.
+		base, err = os.UserCacheDir()
+	default:
+		var home string
+		home, err = os.UserHomeDir()
+		base = filepath.Join(home, ".local", "state")
+	}
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, appName), nil
+}
+
+// cacheDir returns the directory for files that can be recreated at will.
+// XDG_CACHE_HOME is honored on every platform, otherwise it's ~/.cache on
+// Linux, ~/Library/Caches on macOS and %LocalAppData% on Windows.
+func cacheDir() (string, error) {
+	if dir := os.Getenv("XDG_CACHE_HOME"); dir != "" {
+		return filepath.Join(dir, appName), nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, appName), nil
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+var (
+	createProjectKey = key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "create"))
+	openResourcesKey = key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "resources"))
+)
+
+// projectsPerPage is how many projects the projects screen fetches at a
+// time.
+const projectsPerPage = 20
+
+// projectListMsg carries a page of the account's projects, along with how
+// many there are in all.
+type projectListMsg struct {
+	page     int
+	projects []godo.Project
+	total    int
+	err      error
+}
+
+// fetchProjectList lists a page of the account's projects, counting from 1.
+func fetchProjectList(client *godo.Client, page int) tea.Cmd {
+	return func() tea.Msg {
+		projects, resp, err := client.Projects.List(context.Background(), &godo.ListOptions{Page: page, PerPage: projectsPerPage})
+		if err != nil {
+			return projectListMsg{page: page, err: err}
+		}
+
+		total := len(projects)
+		if resp.Meta != nil {
+			total = resp.Meta.Total
+		}
+		return projectListMsg{page: page, projects: projects, total: total}
+	}
+}
+
+// projectList is the screen listing the account's projects, a page at a
+// time.
+type projectList struct {
+	table    table.Model
+	projects []godo.Project
+	page     int
+	total    int
+	loading  bool
+	err      error
+	// create is set while creating a project, and resources while showing
+	// a project's resources.
+	create    *projectWizard
+	resources *projectResources
+	// creating holds the names of the projects being created.
+	creating []string
+}
+
+func newProjectList(width, height int) *projectList {
+	l := &projectList{
+		page: 1,
+		table: newListTable([]table.Column{
+			{Title: "Name", Width: 24},
+			{Title: "Purpose", Width: 28},
+			{Title: "Environment", Width: 12},
+			{Title: "Description", Width: 32},
+		}),
+	}
+	l.SetSize(width, height)
+	return l
+}
+
+// SetSize fits the table to the screen, under the title and above the help.
+func (l *projectList) SetSize(width, height int) {
+	setTableSize(&l.table, width, height)
+	if l.resources != nil {
+		setTableSize(&l.resources.table, width, height)
+	}
+}
+
+// pages returns how many pages of projects there are.
+func (l *projectList) pages() int {
+	return pageCount(l.total, projectsPerPage)
+}
+
+// setProjectList shows a page of projects once it's been fetched.
+func (m model) setProjectList(msg projectListMsg) (tea.Model, tea.Cmd) {
+	l := m.projects
+	if l == nil {
+		return m, nil
+	}
+	// The last page was emptied, e.g. by deleting elsewhere, so go back one.
+	if msg.err == nil && len(msg.projects) == 0 && msg.page > 1 {
+		return m, fetchProjectList(m.client, msg.page-1)
+	}
+
+	l.loading = false
+	l.err = msg.err
+	if msg.err != nil {
+		return m, nil
+	}
+	l.page, l.projects, l.total = msg.page, msg.projects, msg.total
+	l.setRows()
+	return m, nil
+}
+
+// setRows fills the table in from the projects.
+func (l *projectList) setRows() {
+	rows := make([]table.Row, len(l.projects))
+	for i, p := range l.projects {
+		name := p.Name
+		if p.IsDefault {
+			name += " (default)"
+		}
+		rows[i] = table.Row{name, p.Purpose, p.Environment, p.Description}
+	}
+	l.table.SetRows(rows)
+	if l.table.Cursor() >= len(rows) {
+		l.table.GotoTop()
+	}
+}
+
+// selected returns the project under the cursor, unless there are none.
+func (l *projectList) selected() (godo.Project, bool) {
+	i := l.table.Cursor()
+	if i < 0 || i >= len(l.projects) {
+		return godo.Project{}, false
+	}
+	return l.projects[i], true
+}
+
+// openProjects shows the list of projects, fetching its first page.
+func (m model) openProjects() (model, tea.Cmd) {
+	m.projects = newProjectList(m.width, m.height-1)
+	m.projects.loading = true
+	m.notice = ""
+	m.formErr = ""
+	return m, tea.Batch(fetchProjectList(m.client, 1), m.spinner.Tick)
+}
+
+func (m model) updateProjects(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.projects
+	if l.create != nil {
+		return m.updateProjectWizard(msg)
+	}
+	if l.resources != nil {
+		return m.updateProjectResources(msg)
+	}
+
+	switch {
+	case msg.String() == "ctrl+c":
+		return m, m.quit()
+	case key.Matches(msg, closeListKeys):
+		return m.closeScreen()
+	case key.Matches(msg, screensKey):
+		return m.openScreens()
+	case key.Matches(msg, createProjectKey):
+		return m.openProjectWizard()
+	case l.loading:
+		return m, nil
+	case key.Matches(msg, openResourcesKey):
+		return m.openProjectResources()
+	case key.Matches(msg, refreshListKey):
+		l.loading = true
+		return m, fetchProjectList(m.client, l.page)
+	case key.Matches(msg, nextPageKey) && l.page < l.pages():
+		l.loading = true
+		return m, fetchProjectList(m.client, l.page+1)
+	case key.Matches(msg, prevPageKey) && l.page > 1:
+		l.loading = true
+		return m, fetchProjectList(m.client, l.page-1)
+	}
+
+	var cmd tea.Cmd
+	l.table, cmd = l.table.Update(msg)
+	return m, cmd
+}
+
+func (m model) projectsView() string {
+	l := m.projects
+	if v, ok := m.projectPickerView(); ok {
+		return v
+	}
+	if l.resources != nil {
+		return m.projectResourcesView()
+	}
+
+	var b strings.Builder
+
+	title := focusedStyle.Render("Projects")
+	if l.total > 0 {
+		title += placeholderStyle.Render(fmt.Sprintf("  %s · page %d of %d", pluralize(l.total, "project"), l.page, l.pages()))
+	}
+	b.WriteString(title + "\n\n")
+
+	switch {
+	case l.loading && l.projects == nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading projects..."))
+	case l.err != nil && l.projects == nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the projects: "+l.err.Error()))
+	case len(l.projects) == 0:
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("There are no projects in this account yet."))
+	default:
+		fmt.Fprintf(&b, "%s\n\n", l.table.View())
+	}
+
+	switch {
+	case l.loading && l.projects != nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading..."))
+	case l.err != nil && l.projects != nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the projects: "+l.err.Error()))
+	}
+	if len(l.creating) > 0 {
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Creating "+strings.Join(l.creating, ", ")+"..."))
+	}
+	if m.notice != "" {
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render(m.notice))
+	}
+	if m.formErr != "" {
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(m.formErr))
+	}
+
+	if l.create != nil {
+		b.WriteString(m.projectWizardView())
+		return b.String()
+	}
+
+	help := []string{"↑/↓: move", "enter: resources", "c: create"}
+	if l.pages() > 1 {
+		help = append(help, "←/→: page")
+	}
+	help = append(help, "r: refresh", "tab: screens", m.closeScreenHelp())
+	b.WriteString(helpStyle.Render(strings.Join(help, " • ")))
+
+	return b.String()
+}
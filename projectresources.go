@@ -0,0 +1,521 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+var (
+	moveResourceKey = key.NewBinding(key.WithKeys("m"), key.WithHelp("m", "move to another project"))
+	addDropletsKey  = key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "move Droplets here"))
+)
+
+// projectResourcesPerPage is how many of a project's resources are fetched
+// at a time.
+const projectResourcesPerPage = 20
+
+// resourceKinds names the kinds of resource that appear in URNs, e.g.
+// do:droplet:123.
+var resourceKinds = map[string]string{
+	"droplet":      "Droplet",
+	"volume":       "Volume",
+	"domain":       "Domain",
+	"floatingip":   "Reserved IP",
+	"reservedip":   "Reserved IP",
+	"loadbalancer": "Load balancer",
+	"kubernetes":   "Kubernetes cluster",
+	"dbaas":        "Database",
+	"space":        "Space",
+	"app":          "App",
+}
+
+// projectResourcesMsg carries a page of a project's resources, along with
+// how many there are in all and the names of the Droplets among them.
+type projectResourcesMsg struct {
+	projectID string
+	page      int
+	resources []godo.ProjectResource
+	total     int
+	names     map[string]string
+	err       error
+}
+
+// fetchProjectResources lists a page of the project's resources, counting
+// from 1. Resources are only listed by URN, so the account's Droplets are
+// listed too for their names.
+func fetchProjectResources(client *godo.Client, projectID string, page int) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		resources, resp, err := client.Projects.ListResources(ctx, projectID, &godo.ListOptions{Page: page, PerPage: projectResourcesPerPage})
+		if err != nil {
+			return projectResourcesMsg{projectID: projectID, page: page, err: err}
+		}
+		total := len(resources)
+		if resp.Meta != nil {
+			total = resp.Meta.Total
+		}
+
+		droplets, _, err := client.Droplets.List(ctx, &godo.ListOptions{PerPage: 200})
+		if err != nil {
+			return projectResourcesMsg{projectID: projectID, page: page, err: err}
+		}
+		names := make(map[string]string, len(droplets))
+		for _, d := range droplets {
+			names[d.URN()] = d.Name
+		}
+		return projectResourcesMsg{projectID: projectID, page: page, resources: resources, total: total, names: names}
+	}
+}
+
+// projectResources lists a project's resources, a page at a time, and moves
+// them between projects.
+type projectResources struct {
+	project   godo.Project
+	table     table.Model
+	resources []godo.ProjectResource
+	names     map[string]string
+	page      int
+	total     int
+	loading   bool
+	err       error
+	// move is set while choosing the project to move the resource in moving
+	// to, and add while choosing Droplets to move into this project.
+	move   *selectField
+	moving godo.ProjectResource
+	add    *multiSelectField
+	// pickErr is set if the projects or Droplets to choose from couldn't
+	// be fetched.
+	pickErr error
+	// assigning holds what's being moved, until it's done.
+	assigning []string
+}
+
+// pages returns how many pages of resources there are.
+func (r *projectResources) pages() int {
+	return pageCount(r.total, projectResourcesPerPage)
+}
+
+// resourceName returns the resource's kind, and its name if it's a Droplet
+// or else the ID in its URN.
+func (r *projectResources) resourceName(res godo.ProjectResource) (kind, name string) {
+	parts := strings.SplitN(res.URN, ":", 3)
+	if len(parts) != 3 {
+		return "", res.URN
+	}
+	kind, ok := resourceKinds[parts[1]]
+	if !ok {
+		kind = parts[1]
+	}
+	if n, ok := r.names[res.URN]; ok {
+		return kind, n
+	}
+	return kind, parts[2]
+}
+
+// selected returns the resource under the cursor, unless there are none.
+func (r *projectResources) selected() (godo.ProjectResource, bool) {
+	i := r.table.Cursor()
+	if i < 0 || i >= len(r.resources) {
+		return godo.ProjectResource{}, false
+	}
+	return r.resources[i], true
+}
+
+// openProjectResources shows the resources of the project under the cursor.
+func (m model) openProjectResources() (model, tea.Cmd) {
+	l := m.projects
+	project, ok := l.selected()
+	if !ok {
+		return m, nil
+	}
+
+	r := &projectResources{
+		project: project,
+		page:    1,
+		loading: true,
+		table: newListTable([]table.Column{
+			{Title: "Kind", Width: 20},
+			{Title: "Name", Width: 40},
+			{Title: "Status", Width: 12},
+			{Title: "Assigned", Width: 12},
+		}),
+	}
+	setTableSize(&r.table, m.width, m.height-1)
+	l.resources = r
+	m.notice = ""
+	m.formErr = ""
+	return m, fetchProjectResources(m.client, project.ID, 1)
+}
+
+// setProjectResources shows a page of resources once it's been fetched.
+func (m model) setProjectResources(msg projectResourcesMsg) (tea.Model, tea.Cmd) {
+	if m.projects == nil || m.projects.resources == nil || m.projects.resources.project.ID != msg.projectID {
+		return m, nil
+	}
+	r := m.projects.resources
+	// The last page was emptied by moving its resources away, so go back one.
+	if msg.err == nil && len(msg.resources) == 0 && msg.page > 1 {
+		return m, fetchProjectResources(m.client, msg.projectID, msg.page-1)
+	}
+
+	r.loading = false
+	r.err = msg.err
+	if msg.err != nil {
+		return m, nil
+	}
+	r.page, r.resources, r.total, r.names = msg.page, msg.resources, msg.total, msg.names
+	rows := make([]table.Row, len(r.resources))
+	for i, res := range r.resources {
+		kind, name := r.resourceName(res)
+		assigned := res.AssignedAt
+		if len(assigned) > 10 {
+			assigned = assigned[:10]
+		}
+		rows[i] = table.Row{kind, name, res.Status, assigned}
+	}
+	r.table.SetRows(rows)
+	if r.table.Cursor() >= len(rows) {
+		r.table.GotoTop()
+	}
+	return m, nil
+}
+
+func (m model) updateProjectResources(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.projects
+	r := l.resources
+	if r.move != nil {
+		return m.updateResourceMove(msg)
+	}
+	if r.add != nil {
+		return m.updateDropletsAdd(msg)
+	}
+
+	switch {
+	case msg.String() == "ctrl+c":
+		return m, m.quit()
+	case msg.String() == "esc":
+		l.resources = nil
+		m.formErr = ""
+		return m, nil
+	case r.loading:
+		return m, nil
+	case key.Matches(msg, moveResourceKey):
+		return m.openResourceMove()
+	case key.Matches(msg, addDropletsKey):
+		return m.openDropletsAdd()
+	case key.Matches(msg, refreshListKey):
+		r.loading = true
+		return m, fetchProjectResources(m.client, r.project.ID, r.page)
+	case key.Matches(msg, nextPageKey) && r.page < r.pages():
+		r.loading = true
+		return m, fetchProjectResources(m.client, r.project.ID, r.page+1)
+	case key.Matches(msg, prevPageKey) && r.page > 1:
+		r.loading = true
+		return m, fetchProjectResources(m.client, r.project.ID, r.page-1)
+	}
+
+	var cmd tea.Cmd
+	r.table, cmd = r.table.Update(msg)
+	return m, cmd
+}
+
+// projectTargetsMsg carries the projects a resource can be moved to.
+type projectTargetsMsg struct {
+	projects []godo.Project
+	err      error
+}
+
+func fetchProjectTargets(client *godo.Client) tea.Cmd {
+	return func() tea.Msg {
+		projects, _, err := client.Projects.List(context.Background(), &godo.ListOptions{PerPage: 200})
+		return projectTargetsMsg{projects: projects, err: err}
+	}
+}
+
+// openResourceMove moves the resource under the cursor to another project,
+// choosing which once the projects have been fetched.
+func (m model) openResourceMove() (model, tea.Cmd) {
+	if m.blockChanges("resources", "moved") {
+		return m, nil
+	}
+
+	r := m.projects.resources
+	res, ok := r.selected()
+	if !ok {
+		return m, nil
+	}
+	_, name := r.resourceName(res)
+	r.move = newSelectField("", "Move "+name+" to", "")
+	r.move.SetSize(m.width, m.height-1)
+	r.moving = res
+	r.pickErr = nil
+	m.notice = ""
+	m.formErr = ""
+	return m, fetchProjectTargets(m.client)
+}
+
+// setProjectTargets offers the other projects once they've been fetched.
+func (m model) setProjectTargets(msg projectTargetsMsg) (tea.Model, tea.Cmd) {
+	if m.projects == nil || m.projects.resources == nil || m.projects.resources.move == nil {
+		return m, nil
+	}
+	r := m.projects.resources
+	var opts []option
+	for _, p := range msg.projects {
+		if p.ID != r.project.ID {
+			opts = append(opts, projectItem{p})
+		}
+	}
+	if msg.err == nil && len(opts) == 0 {
+		msg.err = errors.New("there are no other projects; create one first")
+	}
+	if msg.err != nil {
+		r.pickErr = msg.err
+		return m, nil
+	}
+
+	cmd := r.move.SetOptions(opts)
+	r.move.Open()
+	return m, cmd
+}
+
+func (m model) updateResourceMove(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	r := m.projects.resources
+	if msg.String() == "ctrl+c" {
+		return m, m.quit()
+	}
+	if r.pickErr != nil || !r.move.Loaded() {
+		if msg.String() == "esc" {
+			r.move = nil
+		}
+		return m, nil
+	}
+
+	_, cmd := r.move.Update(msg)
+	if r.move.Opened() {
+		return m, cmd
+	}
+	target, ok := r.move.selected.(projectItem)
+	r.move = nil
+	if !ok || msg.String() == "esc" {
+		return m, cmd
+	}
+	_, name := r.resourceName(r.moving)
+	r.assigning = append(r.assigning, name)
+	return m, tea.Batch(cmd, assignResources(m.client, target.Project, name, r.moving.URN))
+}
+
+// projectDropletsMsg carries the account's Droplets to move into a project.
+type projectDropletsMsg struct {
+	projectID string
+	droplets  []godo.Droplet
+	err       error
+}
+
+func fetchProjectDroplets(client *godo.Client, projectID string) tea.Cmd {
+	return func() tea.Msg {
+		droplets, _, err := client.Droplets.List(context.Background(), &godo.ListOptions{PerPage: 200})
+		return projectDropletsMsg{projectID: projectID, droplets: droplets, err: err}
+	}
+}
+
+// openDropletsAdd moves Droplets into the project from whichever projects
+// they're in, choosing them once they've been fetched.
+func (m model) openDropletsAdd() (model, tea.Cmd) {
+	if m.blockChanges("Droplets", "moved") {
+		return m, nil
+	}
+
+	r := m.projects.resources
+	r.add = newMultiSelectField("", "Choose the Droplets to move into "+r.project.Name)
+	r.add.SetSize(m.width, m.height-1)
+	r.pickErr = nil
+	m.notice = ""
+	m.formErr = ""
+	return m, fetchProjectDroplets(m.client, r.project.ID)
+}
+
+// setProjectDroplets offers the Droplets that aren't in the project once
+// they've been fetched.
+func (m model) setProjectDroplets(msg projectDropletsMsg) (tea.Model, tea.Cmd) {
+	if m.projects == nil || m.projects.resources == nil || m.projects.resources.add == nil || m.projects.resources.project.ID != msg.projectID {
+		return m, nil
+	}
+	r := m.projects.resources
+	in := make(map[string]bool)
+	for _, res := range r.resources {
+		in[res.URN] = true
+	}
+	var opts []option
+	for _, d := range msg.droplets {
+		if !in[d.URN()] {
+			opts = append(opts, dropletOption{d})
+		}
+	}
+	if msg.err == nil && len(opts) == 0 {
+		msg.err = errors.New("there are no other Droplets to move here")
+	}
+	if msg.err != nil {
+		r.pickErr = msg.err
+		return m, nil
+	}
+
+	cmd := r.add.SetOptions(opts)
+	r.add.Open()
+	return m, cmd
+}
+
+func (m model) updateDropletsAdd(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	r := m.projects.resources
+	if msg.String() == "ctrl+c" {
+		return m, m.quit()
+	}
+	if r.pickErr != nil || !r.add.Loaded() {
+		if msg.String() == "esc" {
+			r.add = nil
+		}
+		return m, nil
+	}
+	// Unlike enter, esc moves nothing.
+	cancelled := msg.String() == "esc" && r.add.list.FilterState() == list.Unfiltered
+
+	_, cmd := r.add.Update(msg)
+	if r.add.Opened() {
+		return m, cmd
+	}
+	add := r.add
+	r.add = nil
+	if cancelled || len(add.Values()) == 0 {
+		return m, cmd
+	}
+
+	var urns, names []string
+	for _, o := range add.options {
+		if add.checked[o.Value()] {
+			id, _ := strconv.Atoi(o.Value())
+			urns = append(urns, godo.ToURN("Droplet", id))
+			names = append(names, o.Title())
+		}
+	}
+	name := strings.Join(names, ", ")
+	r.assigning = append(r.assigning, name)
+	return m, tea.Batch(cmd, assignResources(m.client, r.project, name, urns...))
+}
+
+// projectAssignedMsg reports that resources have been moved into a project,
+// or couldn't be.
+type projectAssignedMsg struct {
+	project string
+	name    string
+	err     error
+}
+
+// assignResources moves the resources into the project, out of whichever
+// they were in, since each resource is in exactly one.
+func assignResources(client *godo.Client, project godo.Project, name string, urns ...string) tea.Cmd {
+	return func() tea.Msg {
+		resources := make([]interface{}, len(urns))
+		for i, urn := range urns {
+			resources[i] = urn
+		}
+		_, _, err := client.Projects.AssignResources(context.Background(), project.ID, resources...)
+		return projectAssignedMsg{project: project.Name, name: name, err: err}
+	}
+}
+
+// projectAssigned refreshes the resources once they've been moved.
+func (m model) projectAssigned(msg projectAssignedMsg) (tea.Model, tea.Cmd) {
+	var r *projectResources
+	if m.projects != nil {
+		r = m.projects.resources
+	}
+	if r != nil {
+		for i, name := range r.assigning {
+			if name == msg.name {
+				r.assigning = append(r.assigning[:i:i], r.assigning[i+1:]...)
+				break
+			}
+		}
+	}
+	if msg.err != nil {
+		m.formErr = fmt.Sprintf("couldn't move %s to %s: %s", msg.name, msg.project, msg.err)
+		return m, nil
+	}
+
+	cmd := m.toast(fmt.Sprintf("Moved %s to %s", msg.name, msg.project))
+	if r == nil {
+		return m, cmd
+	}
+	r.loading = true
+	return m, tea.Batch(cmd, fetchProjectResources(m.client, r.project.ID, r.page))
+}
+
+func (m model) projectResourcesView() string {
+	r := m.projects.resources
+	var b strings.Builder
+
+	title := focusedStyle.Render(r.project.Name)
+	if r.total > 0 {
+		title += placeholderStyle.Render(fmt.Sprintf("  %s · page %d of %d", pluralize(r.total, "resource"), r.page, r.pages()))
+	}
+	b.WriteString(title + "\n\n")
+
+	switch {
+	case r.loading && r.resources == nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading resources..."))
+	case r.err != nil && r.resources == nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the resources: "+r.err.Error()))
+	case len(r.resources) == 0:
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render(fmt.Sprintf("There's nothing in %s yet.", r.project.Name)))
+	default:
+		fmt.Fprintf(&b, "%s\n\n", r.table.View())
+	}
+
+	switch {
+	case r.loading && r.resources != nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading..."))
+	case r.err != nil && r.resources != nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the resources: "+r.err.Error()))
+	}
+	if len(r.assigning) > 0 {
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Moving "+strings.Join(r.assigning, ", ")+"..."))
+	}
+	if m.notice != "" {
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render(m.notice))
+	}
+	if m.formErr != "" {
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(m.formErr))
+	}
+
+	if r.move != nil || r.add != nil {
+		what := "projects"
+		if r.add != nil {
+			what = "Droplets"
+		}
+		if r.pickErr != nil {
+			fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(fmt.Sprintf("can't move anything: %s", r.pickErr)))
+		} else {
+			fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading "+what+"..."))
+		}
+		b.WriteString(helpStyle.Render("esc: cancel"))
+		return b.String()
+	}
+
+	help := []string{"↑/↓: move", "m: move to another project", "a: move Droplets here"}
+	if r.pages() > 1 {
+		help = append(help, "←/→: page")
+	}
+	help = append(help, "r: refresh", "esc: back")
+	b.WriteString(helpStyle.Render(strings.Join(help, " • ")))
+
+	return b.String()
+}
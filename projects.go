@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+type projectItem struct {
+	godo.Project
+}
+
+func (p projectItem) Title() string { return p.Name }
+func (p projectItem) Description() string {
+	if p.IsDefault {
+		return p.Purpose + " · default"
+	}
+	return p.Purpose
+}
+func (p projectItem) FilterValue() string { return p.Name }
+func (p projectItem) Value() string       { return p.ID }
+
+// defaultProjectOption leaves the Droplets in the account's default
+// project, which is all that can be chosen when the projects can't be
+// listed.
+type defaultProjectOption struct{}
+
+func (defaultProjectOption) Title() string       { return "default" }
+func (defaultProjectOption) Description() string { return "leave the Droplets in the default project" }
+func (defaultProjectOption) FilterValue() string { return "default" }
+func (defaultProjectOption) Value() string       { return "" }
+
+type projectsMsg struct {
+	options   []option
+	defaultID string
+	err       error
+}
+
+// fetchProjects lists the projects on the account.
+func fetchProjects(client *godo.Client) tea.Cmd {
+	return func() tea.Msg {
+		projects, _, err := client.Projects.List(context.Background(), &godo.ListOptions{PerPage: 200})
+		if err != nil {
+			return projectsMsg{options: []option{defaultProjectOption{}}, err: err}
+		}
+
+		var msg projectsMsg
+		for _, p := range projects {
+			if p.IsDefault {
+				msg.defaultID = p.ID
+			}
+			msg.options = append(msg.options, projectItem{p})
+		}
+
+		return msg
+	}
+}
+
+// assignProject moves the Droplet into the given project.
+func assignProject(ctx context.Context, client *godo.Client, projectID string, droplet *godo.Droplet) error {
+	if projectID == "" {
+		return nil
+	}
+
+	_, _, err := client.Projects.AssignResources(ctx, projectID, droplet.URN())
+	return err
+}
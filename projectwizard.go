@@ -0,0 +1,286 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+// projectPurposes are the purposes the control panel offers for a project.
+// The API takes any, but these are what the control panel groups by.
+var projectPurposes = []string{
+	"Just trying out DigitalOcean",
+	"Class project / Educational purposes",
+	"Website or blog",
+	"Web Application",
+	"Service or API",
+	"Mobile Application",
+	"Machine learning / AI / Data processing",
+	"IoT",
+	"Operational / Developer tooling",
+}
+
+// projectEnvironments are the environments a project can be marked as.
+var projectEnvironments = []string{"Development", "Staging", "Production"}
+
+// projectStep is a step of the project wizard, in the order they're taken.
+type projectStep int
+
+const (
+	projectStepName projectStep = iota
+	projectStepDescription
+	projectStepPurpose
+	projectStepEnvironment
+	projectStepReview
+)
+
+// projectWizard walks through creating a project a step at a time. Esc goes
+// back a step.
+type projectWizard struct {
+	step projectStep
+
+	name         *textField
+	description  *textField
+	purposes     *selectField
+	environments *selectField
+}
+
+// openProjectWizard starts creating a project, asking for its name first.
+func (m model) openProjectWizard() (model, tea.Cmd) {
+	if m.blockChanges("projects", "created") {
+		return m, nil
+	}
+
+	w := &projectWizard{
+		name:         newOptionalTextField("Name: ", "e.g. staging"),
+		description:  newOptionalTextField("Description: ", "optional"),
+		purposes:     newSelectField("", "What's the project for?", ""),
+		environments: newSelectField("", "Choose the project's environment", ""),
+	}
+	w.name.CharLimit = 175
+	w.description.CharLimit = 255
+
+	purposes := make([]option, len(projectPurposes))
+	for i, p := range projectPurposes {
+		purposes[i] = valueOption(p)
+	}
+	environments := []option{noneOption("leave it unset")}
+	for _, e := range projectEnvironments {
+		environments = append(environments, valueOption(e))
+	}
+	cmds := []tea.Cmd{w.name.Focus(), w.purposes.SetOptions(purposes), w.environments.SetOptions(environments)}
+	for _, f := range []*selectField{w.purposes, w.environments} {
+		f.SetSize(m.width, m.height-1)
+	}
+
+	m.projects.create = w
+	m.notice = ""
+	m.formErr = ""
+	return m, tea.Batch(cmds...)
+}
+
+// picker returns the list the current step is chosen from, if it's chosen
+// from one.
+func (w *projectWizard) picker() *selectField {
+	switch w.step {
+	case projectStepPurpose:
+		return w.purposes
+	case projectStepEnvironment:
+		return w.environments
+	}
+	return nil
+}
+
+func (m model) updateProjectWizard(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.projects
+	w := l.create
+	if msg.String() == "ctrl+c" {
+		return m, m.quit()
+	}
+
+	if f := w.picker(); f != nil {
+		_, cmd := f.Update(msg)
+		if f.Opened() {
+			return m, cmd
+		}
+		if msg.String() == "esc" || f.selected == nil {
+			return m.projectWizardBack()
+		}
+		return m.projectWizardStep(w.step + 1)
+	}
+
+	switch w.step {
+	case projectStepReview:
+		switch msg.String() {
+		case "esc":
+			return m.projectWizardBack()
+		case "enter":
+			req := w.request()
+			l.create = nil
+			m.formErr = ""
+			l.creating = append(l.creating, req.Name)
+			return m, createProject(m.client, req)
+		}
+		return m, nil
+
+	case projectStepName:
+		switch msg.String() {
+		case "esc":
+			return m.projectWizardBack()
+		case "enter":
+			if strings.TrimSpace(w.name.Value()) == "" {
+				m.formErr = "the project needs a name"
+				return m, nil
+			}
+			m.formErr = ""
+			return m.projectWizardStep(projectStepDescription)
+		}
+		_, cmd := w.name.Update(msg)
+		return m, cmd
+
+	case projectStepDescription:
+		switch msg.String() {
+		case "esc":
+			return m.projectWizardBack()
+		case "enter":
+			return m.projectWizardStep(projectStepPurpose)
+		}
+		_, cmd := w.description.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+// projectWizardStep moves the wizard on to the step.
+func (m model) projectWizardStep(step projectStep) (tea.Model, tea.Cmd) {
+	w := m.projects.create
+	w.step = step
+
+	switch step {
+	case projectStepName:
+		return m, w.name.Focus()
+	case projectStepDescription:
+		return m, w.description.Focus()
+	}
+	if f := w.picker(); f != nil {
+		f.Open()
+	}
+	return m, nil
+}
+
+// projectWizardBack goes back a step, or stops creating the project from
+// the first.
+func (m model) projectWizardBack() (tea.Model, tea.Cmd) {
+	w := m.projects.create
+	m.formErr = ""
+	if w.step == projectStepName {
+		m.projects.create = nil
+		return m, nil
+	}
+	return m.projectWizardStep(w.step - 1)
+}
+
+// request returns the project described by the wizard's answers.
+func (w *projectWizard) request() *godo.CreateProjectRequest {
+	return &godo.CreateProjectRequest{
+		Name:        strings.TrimSpace(w.name.Value()),
+		Description: strings.TrimSpace(w.description.Value()),
+		Purpose:     w.purposes.Value(),
+		Environment: w.environments.Value(),
+	}
+}
+
+// projectCreatedMsg reports that a project has been created, or couldn't
+// be.
+type projectCreatedMsg struct {
+	name string
+	err  error
+}
+
+func createProject(client *godo.Client, req *godo.CreateProjectRequest) tea.Cmd {
+	return func() tea.Msg {
+		_, _, err := client.Projects.Create(context.Background(), req)
+		return projectCreatedMsg{name: req.Name, err: err}
+	}
+}
+
+// projectCreated refreshes the list once a project's been created.
+func (m model) projectCreated(msg projectCreatedMsg) (tea.Model, tea.Cmd) {
+	l := m.projects
+	if l != nil {
+		for i, name := range l.creating {
+			if name == msg.name {
+				l.creating = append(l.creating[:i:i], l.creating[i+1:]...)
+				break
+			}
+		}
+	}
+	if msg.err != nil {
+		m.formErr = fmt.Sprintf("couldn't create %s: %s", msg.name, msg.err)
+		return m, nil
+	}
+
+	cmd := m.toast("Created " + msg.name)
+	if l == nil {
+		return m, cmd
+	}
+	l.loading = true
+	return m, tea.Batch(cmd, fetchProjectList(m.client, l.page))
+}
+
+// projectPickerView returns the list being chosen from, if the screen's
+// choosing from one.
+func (m model) projectPickerView() (string, bool) {
+	l := m.projects
+	if w := l.create; w != nil {
+		if f := w.picker(); f != nil && f.Opened() {
+			return f.View(), true
+		}
+	}
+	if r := l.resources; r != nil {
+		if r.move != nil && r.move.Opened() {
+			return r.move.View(), true
+		}
+		if r.add != nil && r.add.Opened() {
+			return r.add.View(), true
+		}
+	}
+	return "", false
+}
+
+func (m model) projectWizardView() string {
+	w := m.projects.create
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s\n", focusedStyle.Render("New project"))
+	if w.step > projectStepName {
+		fmt.Fprintf(&b, "  Name: %s\n", strings.TrimSpace(w.name.Value()))
+	}
+	if d := strings.TrimSpace(w.description.Value()); w.step > projectStepDescription && d != "" {
+		fmt.Fprintf(&b, "  Description: %s\n", d)
+	}
+	if w.step > projectStepPurpose {
+		fmt.Fprintf(&b, "  Purpose: %s\n", w.purposes.Value())
+	}
+	if e := w.environments.Value(); w.step > projectStepEnvironment && e != "" {
+		fmt.Fprintf(&b, "  Environment: %s\n", e)
+	}
+	b.WriteString("\n")
+
+	switch w.step {
+	case projectStepReview:
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("It starts out empty; move resources into it from its resources."))
+		b.WriteString(helpStyle.Render("enter: create • esc: back"))
+	case projectStepName:
+		fmt.Fprintf(&b, "%s\n\n", w.name.View())
+		b.WriteString(helpStyle.Render("enter: next • esc: cancel"))
+	case projectStepDescription:
+		fmt.Fprintf(&b, "%s\n\n", w.description.View())
+		b.WriteString(helpStyle.Render("enter: next • esc: back"))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+// ptrTimeout is how long to wait for the reverse DNS lookup that confirms a
+// Droplet's PTR record.
+const ptrTimeout = 10 * time.Second
+
+// isFQDN reports whether the name looks like a fully qualified domain name,
+// i.e. a valid hostname with at least two labels ending in an alphabetic
+// TLD. DigitalOcean sets the PTR record of Droplets named like this.
+func isFQDN(name string) bool {
+	labels := strings.Split(strings.TrimSuffix(name, "."), ".")
+	if len(labels) < 2 || checkHostname(name) != "" {
+		return false
+	}
+	tld := labels[len(labels)-1]
+	if len(tld) < 2 {
+		return false
+	}
+	for _, r := range tld {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// nameHint explains the effect of the name, if it has one beyond naming the
+// Droplet.
+func (m model) nameHint() string {
+	names, err := dropletNames(m.fields[nameField].Value(), m.fields[countField].Value())
+	if err != nil || !isFQDN(names[0]) {
+		return ""
+	}
+	return "named as a domain, so its public IPs' reverse DNS (PTR) will point to its name"
+}
+
+// checkPTR looks up the PTR record of the Droplet's public IPv4 address,
+// returning what it points to. It's an error if that isn't the Droplet's
+// name.
+func checkPTR(ctx context.Context, droplet *godo.Droplet) (string, error) {
+	ip, err := droplet.PublicIPv4()
+	if err != nil || ip == "" {
+		return "", fmt.Errorf("%s has no public IPv4 address", droplet.Name)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ptrTimeout)
+	defer cancel()
+	names, err := net.DefaultResolver.LookupAddr(ctx, ip)
+	if err != nil {
+		return "", err
+	}
+
+	want := strings.TrimSuffix(droplet.Name, ".")
+	for _, n := range names {
+		if strings.EqualFold(strings.TrimSuffix(n, "."), want) {
+			return want, nil
+		}
+	}
+	if len(names) == 0 {
+		return "", fmt.Errorf("%s has no PTR record yet", ip)
+	}
+	return "", fmt.Errorf("%s points to %s, not %s", ip, strings.TrimSuffix(names[0], "."), want)
+}
+
+// ptrLabel describes the reverse DNS the Droplets will get, for review.
+func (m model) ptrLabel() string {
+	if !isFQDN(m.names[0]) {
+		return "none (name a Droplet as a domain to set it)"
+	}
+	return strings.Join(m.names, ", ")
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+// quotaMsg reports how many Droplets the account has against its limit.
+type quotaMsg struct {
+	count int
+	limit int
+	err   error
+}
+
+// checkQuota looks up the account's Droplet limit and current Droplet count.
+func checkQuota(client *godo.Client) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+
+		account, _, err := client.Account.Get(ctx)
+		if err != nil {
+			return quotaMsg{err: err}
+		}
+
+		_, resp, err := client.Droplets.List(ctx, &godo.ListOptions{PerPage: 1})
+		if err != nil {
+			return quotaMsg{err: err}
+		}
+
+		count := 0
+		if resp.Meta != nil {
+			count = resp.Meta.Total
+		}
+
+		return quotaMsg{count: count, limit: account.DropletLimit}
+	}
+}
+
+// exceeded reports whether creating n more Droplets would go over the limit.
+func (q quotaMsg) exceeded(n int) bool {
+	return q.err == nil && q.limit > 0 && q.count+n > q.limit
+}
+
+// warning describes the account's standing against its Droplet limit when
+// creating n more, or returns an empty string if there's plenty of room.
+func (q quotaMsg) warning(n int) string {
+	switch {
+	case q.err != nil:
+		return fmt.Sprintf("Couldn't check the Droplet limit: %s", q.err)
+	case q.exceeded(n):
+		return fmt.Sprintf("Creating %s would exceed the account's Droplet limit (%d of %d in use).", pluralize(n, "Droplet"), q.count, q.limit)
+	case q.limit > 0 && q.count+n >= q.limit*9/10:
+		return fmt.Sprintf("The account is close to its Droplet limit (%d of %d in use).", q.count, q.limit)
+	}
+	return ""
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// rateWarning is the share of the hourly API rate limit left at which the
+// status bar starts to warn.
+const rateWarning = 0.1
+
+// statusView reports how much of the API rate limit is left, as of the last
+// response. It's empty until the first response arrives.
+func (m model) statusView() string {
+	if m.client == nil {
+		return ""
+	}
+	rate := m.client.GetRate()
+	if rate.Limit == 0 {
+		return ""
+	}
+
+	v := fmt.Sprintf("API rate limit: %d/%d requests left, resets at %s", rate.Remaining, rate.Limit, rate.Reset.Local().Format("15:04"))
+	if float64(rate.Remaining) < float64(rate.Limit)*rateWarning {
+		return errorStyle.Render(v + " · nearly exhausted")
+	}
+	return helpStyle.Render(v)
+}
+
+// withStatusBar adds the status bar to the bottom of the screen below view.
+func (m model) withStatusBar(view string) string {
+	status := m.statusView()
+	if status == "" {
+		return view
+	}
+
+	lines := strings.Count(view, "\n") + 1
+	pad := 1
+	if m.height > lines+1 {
+		pad = m.height - lines
+	}
+	return view + strings.Repeat("\n", pad) + status
+}
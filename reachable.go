@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os/exec"
+	"strconv"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+const (
+	defaultSSHPort    = 22
+	defaultSSHTimeout = 5 * time.Minute
+	// dialInterval is how long to wait between attempts to connect.
+	dialInterval = 2 * time.Second
+	// pinAttempts is how many times to try scanning the host keys once SSH
+	// accepts connections.
+	pinAttempts = 3
+)
+
+// reachableMsg reports that SSH on the Droplet at index is accepting
+// connections, or stopped being waited on. The host keys are set if they've
+// been pinned, or pinErr says why not.
+type reachableMsg struct {
+	index    int
+	hostKeys []string
+	pinErr   error
+	err      error
+}
+
+// sshPort returns the port that SSH listens on in new Droplets.
+func (m model) sshPort() int {
+	if m.defaults.SSHPort != 0 {
+		return m.defaults.SSHPort
+	}
+	return defaultSSHPort
+}
+
+// waitForSSH polls the Droplet's SSH port until it accepts a connection,
+// since sshd is usually still starting when the Droplet becomes active.
+func (m model) waitForSSH(index int, droplet *godo.Droplet) tea.Cmd {
+	timeout := m.defaults.SSHTimeout
+	if timeout == 0 {
+		timeout = defaultSSHTimeout
+	}
+	port := strconv.Itoa(m.sshPort())
+
+	return func() tea.Msg {
+		ip, _ := droplet.PublicIPv4()
+		if ip == "" {
+			return reachableMsg{index: index, err: fmt.Errorf("%s has no public IPv4 address to connect to", droplet.Name)}
+		}
+		addr := net.JoinHostPort(ip, port)
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		var d net.Dialer
+		for {
+			conn, err := d.DialContext(ctx, "tcp", addr)
+			if err == nil {
+				conn.Close()
+				return m.pinWhenReachable(ctx, index, droplet)
+			}
+
+			select {
+			case <-ctx.Done():
+				return reachableMsg{index: index, err: fmt.Errorf("%s wasn't reachable within %s", addr, timeout)}
+			case <-time.After(dialInterval):
+			}
+		}
+	}
+}
+
+// pinWhenReachable pins the Droplet's host keys now that SSH is up, if it's
+// configured to. sshd may not be answering yet, so it tries a few times.
+func (m model) pinWhenReachable(ctx context.Context, index int, droplet *godo.Droplet) reachableMsg {
+	if m.hostKeyMode() != hostKeysPin {
+		return reachableMsg{index: index}
+	}
+
+	var err error
+	for i := 0; i < pinAttempts; i++ {
+		var keys []string
+		if keys, err = m.pinHostKeys(droplet); err == nil {
+			return reachableMsg{index: index, hostKeys: keys}
+		}
+		if errors.Is(err, exec.ErrNotFound) {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return reachableMsg{index: index, pinErr: err}
+		case <-time.After(dialInterval):
+		}
+	}
+	return reachableMsg{index: index, pinErr: err}
+}
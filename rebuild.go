@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+// rebuildFlow walks through rebuilding a Droplet from the list: choosing an
+// image and typing the Droplet's name to confirm, since its disk is erased.
+type rebuildFlow struct {
+	droplet godo.Droplet
+	images  *imagePicker
+	// name is set once an image has been chosen, asking for the Droplet's
+	// name to confirm.
+	image imageItem
+	name  *textField
+	err   error
+}
+
+// rebuildImagesMsg carries the images a Droplet can be rebuilt from.
+type rebuildImagesMsg struct {
+	id     int
+	images []imageItem
+	err    error
+}
+
+// fetchRebuildImages lists the images the Droplet can be rebuilt from: the
+// distributions, and the account's own images and snapshots that are in its
+// region.
+func fetchRebuildImages(client *godo.Client, droplet godo.Droplet) tea.Cmd {
+	return func() tea.Msg {
+		images, err := listImages(context.Background(), client)
+		if err != nil {
+			return rebuildImagesMsg{id: droplet.ID, err: err}
+		}
+
+		var usable []imageItem
+		for _, i := range images {
+			if i.private() && droplet.Region != nil && !contains(i.Regions, droplet.Region.Slug) {
+				continue
+			}
+			usable = append(usable, i)
+		}
+		return rebuildImagesMsg{id: droplet.ID, images: usable}
+	}
+}
+
+// openRebuild starts rebuilding the Droplet, fetching the images it can be
+// rebuilt from.
+func (m model) openRebuild(droplet godo.Droplet) (model, tea.Cmd) {
+	images := newImagePicker("")
+	images.SetSize(m.width, m.height-1)
+	m.list.rebuild = &rebuildFlow{droplet: droplet, images: images}
+	return m, fetchRebuildImages(m.client, droplet)
+}
+
+// setRebuildImages opens the image picker once the images have been
+// fetched.
+func (m model) setRebuildImages(msg rebuildImagesMsg) (tea.Model, tea.Cmd) {
+	if m.list == nil || m.list.rebuild == nil || m.list.rebuild.droplet.ID != msg.id {
+		return m, nil
+	}
+	r := m.list.rebuild
+	if msg.err != nil {
+		r.err = msg.err
+		return m, nil
+	}
+
+	cmd := r.images.SetImages(msg.images)
+	r.images.Open()
+	return m, cmd
+}
+
+func (m model) updateRebuild(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.list
+	r := l.rebuild
+	if msg.String() == "ctrl+c" {
+		return m, m.quit()
+	}
+
+	switch {
+	case r.err != nil || !r.images.Loaded():
+		if msg.String() == "esc" {
+			l.rebuild = nil
+		}
+		return m, nil
+
+	case r.images.Opened():
+		_, cmd := r.images.Update(msg)
+		if r.images.Opened() {
+			return m, cmd
+		}
+		image, ok := r.images.selected.(imageItem)
+		if !ok {
+			l.rebuild = nil
+			return m, cmd
+		}
+		r.image = image
+		r.name = newOptionalTextField("Type its name to rebuild it: ", r.droplet.Name)
+		r.name.CharLimit = 255
+		return m, tea.Batch(cmd, r.name.Focus())
+
+	case r.name != nil:
+		switch msg.String() {
+		case "esc":
+			// Back to the images.
+			r.name = nil
+			r.images.selected = nil
+			r.images.Open()
+			m.formErr = ""
+			return m, nil
+		case "enter":
+			if strings.TrimSpace(r.name.Model.Value()) != r.droplet.Name {
+				m.formErr = "the name doesn't match; type it exactly, or press esc to go back"
+				return m, nil
+			}
+			l.rebuild = nil
+			m.formErr = ""
+			l.busy[r.droplet.ID] = "rebuilding"
+			l.setRows()
+			return m, rebuildDroplet(m.client, r.droplet, r.image)
+		}
+		_, cmd := r.name.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+// rebuildDroplet rebuilds the Droplet from the image and waits until it's
+// active again, like a new one.
+func rebuildDroplet(client *godo.Client, droplet godo.Droplet, image imageItem) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+
+		var action *godo.Action
+		var err error
+		if image.Slug != "" {
+			action, _, err = client.DropletActions.RebuildByImageSlug(ctx, droplet.ID, image.Slug)
+		} else {
+			action, _, err = client.DropletActions.RebuildByImageID(ctx, droplet.ID, image.ID)
+		}
+		if err == nil {
+			err = waitForAction(ctx, client, action)
+		}
+		return dropletActionMsg{id: droplet.ID, name: droplet.Name, verb: "rebuild", done: "Rebuilt", err: err}
+	}
+}
+
+func (m model) rebuildView() string {
+	r := m.list.rebuild
+	var b strings.Builder
+
+	switch {
+	case r.err != nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(fmt.Sprintf("can't rebuild %s: %s", r.droplet.Name, r.err)))
+		b.WriteString(helpStyle.Render("esc: back"))
+	case !r.images.Loaded():
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading images..."))
+		b.WriteString(helpStyle.Render("esc: cancel"))
+	case r.images.Opened():
+		return r.images.View()
+	case r.name != nil:
+		fmt.Fprintf(&b, "%s\n", focusedStyle.Render(fmt.Sprintf("Rebuild %s from %s?", r.droplet.Name, r.image.Title())))
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("This erases everything on its disk and can't be undone. Its IP addresses are kept."))
+		if m.formErr != "" {
+			fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(m.formErr))
+		}
+		fmt.Fprintf(&b, "%s\n\n", r.name.View())
+		b.WriteString(helpStyle.Render("enter: rebuild • esc: back"))
+	}
+
+	return b.String()
+}
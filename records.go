@@ -0,0 +1,721 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+var (
+	openRecordsKey  = key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "records"))
+	createRecordKey = key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "add"))
+	editRecordKey   = key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "edit"))
+)
+
+// recordsPerPage is how many records the records table fetches at a time.
+const recordsPerPage = 50
+
+// minRecordTTL is the shortest TTL the API accepts, in seconds.
+const minRecordTTL = 30
+
+// recordTypes are the types of record that can be added and edited, with
+// what their data is.
+var recordTypes = []recordTypeOption{
+	{"A", "points a name at an IPv4 address"},
+	{"AAAA", "points a name at an IPv6 address"},
+	{"CNAME", "makes a name an alias of another hostname"},
+	{"TXT", "attaches text to a name, e.g. for SPF or domain verification"},
+	{"MX", "names a mail server for the domain, by priority"},
+	{"SRV", "names the host and port of a service, e.g. _sip._tcp"},
+}
+
+type recordTypeOption struct {
+	typ         string
+	description string
+}
+
+func (o recordTypeOption) Title() string       { return o.typ }
+func (o recordTypeOption) Description() string { return o.description }
+func (o recordTypeOption) FilterValue() string { return o.typ }
+func (o recordTypeOption) Value() string       { return o.typ }
+
+// editableRecord reports whether records of the type can be added and
+// edited here.
+func editableRecord(typ string) bool {
+	for _, t := range recordTypes {
+		if t.typ == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// recordsMsg carries a page of a domain's records, along with how many
+// there are in all.
+type recordsMsg struct {
+	domain  string
+	page    int
+	records []godo.DomainRecord
+	total   int
+	err     error
+}
+
+// fetchRecords lists a page of the domain's records, counting from 1.
+func fetchRecords(client *godo.Client, domain string, page int) tea.Cmd {
+	return func() tea.Msg {
+		records, resp, err := client.Domains.Records(context.Background(), domain, &godo.ListOptions{Page: page, PerPage: recordsPerPage})
+		if err != nil {
+			return recordsMsg{domain: domain, page: page, err: err}
+		}
+
+		total := len(records)
+		if resp.Meta != nil {
+			total = resp.Meta.Total
+		}
+		return recordsMsg{domain: domain, page: page, records: records, total: total}
+	}
+}
+
+// recordDoneMsg reports that a change to one of a domain's records has
+// finished. id is 0 for a record that's been added.
+type recordDoneMsg struct {
+	domain string
+	id     int
+	name   string
+	verb   string
+	done   string
+	err    error
+}
+
+// recordList is the table of a domain's records, shown on the domains screen
+// once a domain's been chosen.
+type recordList struct {
+	domain  string
+	table   table.Model
+	records []godo.DomainRecord
+	page    int
+	total   int
+	loading bool
+	err     error
+	// edit is set while adding or editing a record, and confirmDelete while
+	// asking to confirm deleting one.
+	edit          *recordEditor
+	confirmDelete *godo.DomainRecord
+	// busy holds the status to show for the records that are being changed,
+	// by ID, until they're done.
+	busy map[int]string
+}
+
+func newRecordList(domain string, width, height int) *recordList {
+	l := &recordList{
+		domain: domain,
+		page:   1,
+		busy:   make(map[int]string),
+		table: newListTable([]table.Column{
+			{Title: "Type", Width: 6},
+			{Title: "Name", Width: 24},
+			{Title: "Value", Width: 40},
+			{Title: "TTL", Width: 7},
+			{Title: "Priority", Width: 8},
+			{Title: "Status", Width: 10},
+		}),
+	}
+	l.SetSize(width, height)
+	return l
+}
+
+// SetSize fits the table to the screen, under the title and above the help.
+func (l *recordList) SetSize(width, height int) {
+	setTableSize(&l.table, width, height)
+}
+
+// pages returns how many pages of records there are.
+func (l *recordList) pages() int {
+	return pageCount(l.total, recordsPerPage)
+}
+
+// setRecords shows a page of the domain's records once it's been fetched.
+func (m model) setRecords(msg recordsMsg) (tea.Model, tea.Cmd) {
+	if m.domains == nil || m.domains.records == nil || m.domains.records.domain != msg.domain {
+		return m, nil
+	}
+	l := m.domains.records
+	// The last page was emptied, e.g. by deleting, so go back one.
+	if msg.err == nil && len(msg.records) == 0 && msg.page > 1 {
+		return m, fetchRecords(m.client, msg.domain, msg.page-1)
+	}
+
+	l.loading = false
+	l.err = msg.err
+	if msg.err != nil {
+		return m, nil
+	}
+	l.page, l.records, l.total = msg.page, msg.records, msg.total
+	l.setRows()
+	return m, nil
+}
+
+// setRows fills the table in from the records.
+func (l *recordList) setRows() {
+	rows := make([]table.Row, len(l.records))
+	for i, r := range l.records {
+		priority := ""
+		if r.Type == "MX" || r.Type == "SRV" {
+			priority = strconv.Itoa(r.Priority)
+		}
+		status := ""
+		if s, ok := l.busy[r.ID]; ok {
+			status = s + "…"
+		}
+		rows[i] = table.Row{r.Type, r.Name, recordValue(r), ttlLabel(r.TTL), priority, status}
+	}
+	l.table.SetRows(rows)
+	if l.table.Cursor() >= len(rows) {
+		l.table.GotoTop()
+	}
+}
+
+// recordValue describes what the record points at, including an SRV
+// record's port and weight.
+func recordValue(r godo.DomainRecord) string {
+	if r.Type == "SRV" {
+		return fmt.Sprintf("%s:%d, weight %d", r.Data, r.Port, r.Weight)
+	}
+	return r.Data
+}
+
+// ttlLabel returns the TTL in seconds, or blank for records like SOA that
+// don't report one.
+func ttlLabel(ttl int) string {
+	if ttl == 0 {
+		return ""
+	}
+	return strconv.Itoa(ttl)
+}
+
+// selected returns the record under the cursor, unless there are none or
+// it's busy.
+func (l *recordList) selected() (godo.DomainRecord, bool) {
+	i := l.table.Cursor()
+	if i < 0 || i >= len(l.records) {
+		return godo.DomainRecord{}, false
+	}
+	_, busy := l.busy[l.records[i].ID]
+	return l.records[i], !busy
+}
+
+// openRecords shows the records of the domain under the cursor.
+func (m model) openRecords() (model, tea.Cmd) {
+	domain, ok := m.domains.selected()
+	if !ok {
+		return m, nil
+	}
+	m.domains.records = newRecordList(domain.Name, m.width, m.height-1)
+	m.domains.records.loading = true
+	m.notice = ""
+	m.formErr = ""
+	return m, tea.Batch(fetchRecords(m.client, domain.Name, 1), m.spinner.Tick)
+}
+
+func (m model) updateRecords(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.domains.records
+	if l.edit != nil {
+		return m.updateRecordEditor(msg)
+	}
+	if l.confirmDelete != nil {
+		return m.updateRecordDelete(msg)
+	}
+
+	switch {
+	case msg.String() == "ctrl+c":
+		return m, m.quit()
+	case msg.String() == "esc":
+		// Back to the domains.
+		m.domains.records = nil
+		m.notice = ""
+		m.formErr = ""
+		return m, nil
+	case key.Matches(msg, screensKey):
+		return m.openScreens()
+	case key.Matches(msg, createRecordKey):
+		return m.openRecordEditor(nil)
+	case l.loading:
+		return m, nil
+	case key.Matches(msg, editRecordKey):
+		record, ok := l.selected()
+		if !ok {
+			return m, nil
+		}
+		return m.openRecordEditor(&record)
+	case key.Matches(msg, deleteKey):
+		return m.promptRecordDelete()
+	case key.Matches(msg, refreshListKey):
+		l.loading = true
+		return m, fetchRecords(m.client, l.domain, l.page)
+	case key.Matches(msg, nextPageKey) && l.page < l.pages():
+		l.loading = true
+		return m, fetchRecords(m.client, l.domain, l.page+1)
+	case key.Matches(msg, prevPageKey) && l.page > 1:
+		l.loading = true
+		return m, fetchRecords(m.client, l.domain, l.page-1)
+	}
+
+	var cmd tea.Cmd
+	l.table, cmd = l.table.Update(msg)
+	return m, cmd
+}
+
+// recordDone refreshes the records once a change to one has finished.
+func (m model) recordDone(msg recordDoneMsg) (tea.Model, tea.Cmd) {
+	var l *recordList
+	if m.domains != nil && m.domains.records != nil && m.domains.records.domain == msg.domain {
+		l = m.domains.records
+		delete(l.busy, msg.id)
+		l.setRows()
+	}
+	if msg.err != nil {
+		m.formErr = fmt.Sprintf("couldn't %s %s: %s", msg.verb, msg.name, msg.err)
+		return m, nil
+	}
+
+	cmd := m.toast(fmt.Sprintf("%s %s", msg.done, msg.name))
+	if l == nil {
+		return m, cmd
+	}
+	l.loading = true
+	return m, tea.Batch(cmd, fetchRecords(m.client, l.domain, l.page))
+}
+
+func (m model) recordsView() string {
+	l := m.domains.records
+	if l.edit != nil && l.edit.types != nil && l.edit.types.Opened() {
+		return l.edit.types.View()
+	}
+
+	var b strings.Builder
+
+	title := focusedStyle.Render(l.domain)
+	if l.total > 0 {
+		title += placeholderStyle.Render(fmt.Sprintf("  %s · page %d of %d", pluralize(l.total, "record"), l.page, l.pages()))
+	}
+	b.WriteString(title + "\n\n")
+
+	switch {
+	case l.loading && l.records == nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading records..."))
+	case l.err != nil && l.records == nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the records: "+l.err.Error()))
+	case len(l.records) == 0:
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("This domain has no records yet."))
+	default:
+		fmt.Fprintf(&b, "%s\n\n", l.table.View())
+	}
+
+	switch {
+	case l.loading && l.records != nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading..."))
+	case l.err != nil && l.records != nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the records: "+l.err.Error()))
+	}
+	if m.notice != "" {
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render(m.notice))
+	}
+	if m.formErr != "" {
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(m.formErr))
+	}
+
+	switch {
+	case l.edit != nil:
+		b.WriteString(m.recordEditorView())
+		return b.String()
+	case l.confirmDelete != nil:
+		b.WriteString(m.recordDeleteView())
+		b.WriteString(helpStyle.Render("y: delete • n: cancel"))
+		return b.String()
+	}
+
+	help := []string{"↑/↓: move", "c: add", "enter: edit", "d: delete"}
+	if l.pages() > 1 {
+		help = append(help, "←/→: page")
+	}
+	help = append(help, "r: refresh", "tab: screens", "esc: domains")
+	b.WriteString(helpStyle.Render(strings.Join(help, " • ")))
+
+	return b.String()
+}
+
+// recordEditor adds a record to the domain or edits one of its records. Its
+// fields depend on the record's type: MX records have a priority, and SRV
+// records a priority, a port and a weight too.
+type recordEditor struct {
+	// record is the record being edited, or nil when adding one. types is
+	// set while choosing the type of the record to add.
+	record *godo.DomainRecord
+	types  *selectField
+	typ    string
+
+	name     *textField
+	data     *textField
+	ttl      *textField
+	priority *textField
+	port     *textField
+	weight   *textField
+	focus    int
+}
+
+// openRecordEditor starts adding a record to the domain, choosing its type
+// first, or editing the record.
+func (m model) openRecordEditor(record *godo.DomainRecord) (model, tea.Cmd) {
+	verb := "added"
+	if record != nil {
+		verb = "changed"
+	}
+	if m.blockChanges("DNS records", verb) {
+		return m, nil
+	}
+
+	l := m.domains.records
+	m.notice = ""
+	m.formErr = ""
+	if record != nil {
+		if !editableRecord(record.Type) {
+			m.formErr = fmt.Sprintf("%s records can't be edited here", record.Type)
+			return m, nil
+		}
+		l.edit = &recordEditor{record: record}
+		return m, l.edit.setType(record.Type, *record)
+	}
+
+	opts := make([]option, len(recordTypes))
+	for i, t := range recordTypes {
+		opts[i] = t
+	}
+	types := newSelectField("", "Choose the type of record to add to "+l.domain, "")
+	types.SetSize(m.width, m.height-1)
+	cmd := types.SetOptions(opts)
+	types.Open()
+	l.edit = &recordEditor{types: types}
+	return m, cmd
+}
+
+// setType lays the editor's fields out for the type of record, filled in
+// from the record.
+func (e *recordEditor) setType(typ string, r godo.DomainRecord) tea.Cmd {
+	e.typ = typ
+	e.focus = 0
+	e.priority, e.port, e.weight = nil, nil, nil
+
+	name := r.Name
+	if name == "" {
+		name = "@"
+	}
+	e.name = newOptionalTextField("Name: ", "@ for the domain itself, or e.g. www")
+	e.name.CharLimit = 253
+	e.name.SetValue(name)
+
+	hints := map[string]string{
+		"A":     "an IPv4 address",
+		"AAAA":  "an IPv6 address",
+		"CNAME": "a hostname, e.g. www.example.com",
+		"TXT":   "the record's text",
+		"MX":    "the mail server's hostname",
+		"SRV":   "the service's hostname",
+	}
+	prompts := map[string]string{"CNAME": "Alias of: ", "MX": "Mail server: ", "SRV": "Target: "}
+	prompt, ok := prompts[typ]
+	if !ok {
+		prompt = "Value: "
+	}
+	e.data = newOptionalTextField(prompt, hints[typ])
+	e.data.CharLimit = 512
+	e.data.SetValue(r.Data)
+
+	ttl := r.TTL
+	if ttl == 0 {
+		ttl = dnsTTL
+	}
+	e.ttl = newOptionalTextField("TTL (seconds): ", "")
+	e.ttl.CharLimit = 6
+	e.ttl.SetValue(strconv.Itoa(ttl))
+
+	if typ == "MX" || typ == "SRV" {
+		priority := r.Priority
+		if r.ID == 0 {
+			priority = 10
+		}
+		e.priority = newOptionalTextField("Priority: ", "lower is tried first")
+		e.priority.CharLimit = 5
+		e.priority.SetValue(strconv.Itoa(priority))
+	}
+	if typ == "SRV" {
+		e.port = newOptionalTextField("Port: ", "e.g. 5060")
+		e.port.CharLimit = 5
+		if r.Port != 0 {
+			e.port.SetValue(strconv.Itoa(r.Port))
+		}
+		weight := r.Weight
+		if r.ID == 0 {
+			weight = 100
+		}
+		e.weight = newOptionalTextField("Weight: ", "shares traffic between equal priorities")
+		e.weight.CharLimit = 5
+		e.weight.SetValue(strconv.Itoa(weight))
+	}
+
+	return e.inputs()[0].Focus()
+}
+
+// inputs returns the editor's fields in the order they're shown.
+func (e *recordEditor) inputs() []*textField {
+	fields := []*textField{e.name, e.data, e.ttl}
+	for _, f := range []*textField{e.priority, e.port, e.weight} {
+		if f != nil {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// move focuses the next field, or the previous one, wrapping around.
+func (e *recordEditor) move(back bool) tea.Cmd {
+	fields := e.inputs()
+	fields[e.focus].Blur()
+	if back {
+		e.focus = (e.focus + len(fields) - 1) % len(fields)
+	} else {
+		e.focus = (e.focus + 1) % len(fields)
+	}
+	return fields[e.focus].Focus()
+}
+
+// request returns the record to send for the editor's fields, or an error
+// explaining what's wrong with them.
+func (e *recordEditor) request(domain string) (*godo.DomainRecordEditRequest, error) {
+	req := &godo.DomainRecordEditRequest{Type: e.typ}
+
+	req.Name = recordName(strings.TrimSuffix(strings.TrimSpace(e.name.Value()), "."), domain)
+	if req.Name == "" || strings.ContainsAny(req.Name, " \t") {
+		return nil, fmt.Errorf("the name is relative to %s, e.g. www, or @ for %s itself", domain, domain)
+	}
+
+	data := strings.TrimSpace(e.data.Value())
+	ip := net.ParseIP(data)
+	switch {
+	case data == "":
+		return nil, fmt.Errorf("%s records need %s", e.typ, e.data.Placeholder)
+	case e.typ == "A" && (ip == nil || ip.To4() == nil):
+		return nil, fmt.Errorf("%q isn't an IPv4 address", data)
+	case e.typ == "AAAA" && (ip == nil || ip.To4() != nil):
+		return nil, fmt.Errorf("%q isn't an IPv6 address", data)
+	case e.typ == "CNAME" || e.typ == "MX" || e.typ == "SRV":
+		if data != "@" {
+			host := strings.TrimSuffix(data, ".")
+			if err := checkHostname(host); err != "" {
+				return nil, fmt.Errorf("%q isn't a hostname", data)
+			}
+			// Hostnames are taken as full names rather than relative to
+			// the domain.
+			data = host + "."
+		}
+	}
+	req.Data = data
+
+	ttl, err := strconv.Atoi(strings.TrimSpace(e.ttl.Value()))
+	if err != nil || ttl < minRecordTTL {
+		return nil, fmt.Errorf("the TTL has to be a whole number of seconds, at least %d", minRecordTTL)
+	}
+	req.TTL = ttl
+
+	number := func(f *textField, what string, min int) (int, error) {
+		n, err := strconv.Atoi(strings.TrimSpace(f.Value()))
+		if err != nil || n < min || n > 65535 {
+			return 0, fmt.Errorf("the %s has to be a whole number from %d to 65535", what, min)
+		}
+		return n, nil
+	}
+	if e.priority != nil {
+		if req.Priority, err = number(e.priority, "priority", 0); err != nil {
+			return nil, err
+		}
+	}
+	if e.port != nil {
+		if req.Port, err = number(e.port, "port", 1); err != nil {
+			return nil, err
+		}
+	}
+	if e.weight != nil {
+		if req.Weight, err = number(e.weight, "weight", 0); err != nil {
+			return nil, err
+		}
+	}
+
+	return req, nil
+}
+
+func (m model) updateRecordEditor(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.domains.records
+	e := l.edit
+	if msg.String() == "ctrl+c" {
+		return m, m.quit()
+	}
+
+	if e.types != nil {
+		_, cmd := e.types.Update(msg)
+		if e.types.Opened() {
+			return m, cmd
+		}
+		if e.types.selected == nil {
+			l.edit = nil
+			return m, cmd
+		}
+		typ := e.types.Value()
+		e.types = nil
+		return m, tea.Batch(cmd, e.setType(typ, godo.DomainRecord{}))
+	}
+
+	switch msg.String() {
+	case "esc":
+		l.edit = nil
+		m.formErr = ""
+		return m, nil
+	case "tab", "down":
+		return m, e.move(false)
+	case "shift+tab", "up":
+		return m, e.move(true)
+	case "enter":
+		req, err := e.request(l.domain)
+		if err != nil {
+			m.formErr = err.Error()
+			return m, nil
+		}
+		l.edit = nil
+		m.formErr = ""
+		if e.record == nil {
+			return m, addRecord(m.client, l.domain, req)
+		}
+		l.busy[e.record.ID] = "saving"
+		l.setRows()
+		return m, editRecord(m.client, l.domain, e.record.ID, req)
+	}
+
+	_, cmd := e.inputs()[e.focus].Update(msg)
+	return m, cmd
+}
+
+// addRecord adds the record to the domain.
+func addRecord(client *godo.Client, domain string, req *godo.DomainRecordEditRequest) tea.Cmd {
+	return func() tea.Msg {
+		_, _, err := client.Domains.CreateRecord(context.Background(), domain, req)
+		return recordDoneMsg{domain: domain, name: recordLabel(req.Type, req.Name, domain), verb: "add", done: "Added", err: err}
+	}
+}
+
+// editRecord replaces the domain's record with the request.
+func editRecord(client *godo.Client, domain string, id int, req *godo.DomainRecordEditRequest) tea.Cmd {
+	return func() tea.Msg {
+		_, _, err := client.Domains.EditRecord(context.Background(), domain, id, req)
+		return recordDoneMsg{domain: domain, id: id, name: recordLabel(req.Type, req.Name, domain), verb: "save", done: "Saved", err: err}
+	}
+}
+
+// recordLabel names a record for messages, e.g. "the A record for
+// www.example.com".
+func recordLabel(typ, name, domain string) string {
+	return fmt.Sprintf("the %s record for %s", typ, recordFQDN(name, domain))
+}
+
+func (m model) recordEditorView() string {
+	l := m.domains.records
+	e := l.edit
+	if e.types != nil {
+		return ""
+	}
+	var b strings.Builder
+
+	title := fmt.Sprintf("Add %s %s record", article(e.typ), e.typ)
+	if e.record != nil {
+		title = fmt.Sprintf("Edit the %s record for %s", e.typ, recordFQDN(e.record.Name, l.domain))
+	}
+	fmt.Fprintf(&b, "%s\n\n", focusedStyle.Render(title))
+	for _, f := range e.inputs() {
+		fmt.Fprintf(&b, "%s\n", f.View())
+	}
+	b.WriteString("\n")
+	if e.typ == "CNAME" || e.typ == "MX" || e.typ == "SRV" {
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render(fmt.Sprintf("Hostnames are full names, so include the domain, e.g. mail.%s, or @ for %s itself.", l.domain, l.domain)))
+	}
+	b.WriteString(helpStyle.Render("tab: next field • enter: save • esc: cancel"))
+
+	return b.String()
+}
+
+// article returns "an" for record types pronounced with a leading vowel
+// sound, like A and MX, and "a" otherwise.
+func article(typ string) string {
+	if typ == "A" || typ == "AAAA" || typ == "MX" || typ == "SRV" {
+		return "an"
+	}
+	return "a"
+}
+
+// promptRecordDelete asks to confirm deleting the record under the cursor.
+func (m model) promptRecordDelete() (model, tea.Cmd) {
+	if m.blockChanges("DNS records", "deleted") {
+		return m, nil
+	}
+
+	l := m.domains.records
+	record, ok := l.selected()
+	if !ok {
+		return m, nil
+	}
+	if record.Type == "SOA" {
+		m.formErr = "the SOA record can't be deleted"
+		return m, nil
+	}
+	l.confirmDelete = &record
+	m.notice = ""
+	m.formErr = ""
+	return m, nil
+}
+
+func (m model) updateRecordDelete(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.domains.records
+	r := l.confirmDelete
+	switch msg.String() {
+	case "ctrl+c":
+		return m, m.quit()
+	case "y", "Y":
+		l.confirmDelete = nil
+		l.busy[r.ID] = "deleting"
+		l.setRows()
+		return m, deleteRecord(m.client, l.domain, *r)
+	case "n", "N", "esc":
+		l.confirmDelete = nil
+	}
+	return m, nil
+}
+
+// deleteRecord deletes the record from the domain.
+func deleteRecord(client *godo.Client, domain string, r godo.DomainRecord) tea.Cmd {
+	return func() tea.Msg {
+		_, err := client.Domains.DeleteRecord(context.Background(), domain, r.ID)
+		return recordDoneMsg{domain: domain, id: r.ID, name: recordLabel(r.Type, r.Name, domain), verb: "delete", done: "Deleted", err: err}
+	}
+}
+
+func (m model) recordDeleteView() string {
+	l := m.domains.records
+	r := l.confirmDelete
+
+	return fmt.Sprintf("%s\n%s\n\n",
+		focusedStyle.Render(fmt.Sprintf("Delete the %s record for %s → %s?", r.Type, recordFQDN(r.Name, l.domain), recordValue(*r))),
+		placeholderStyle.Render("It stops resolving once resolvers' cached copies expire, after its TTL."))
+}
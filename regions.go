@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+type regionItem struct {
+	godo.Region
+}
+
+func (r regionItem) Title() string       { return r.Name }
+func (r regionItem) Description() string { return r.Slug }
+func (r regionItem) FilterValue() string { return r.Name + " " + r.Slug }
+func (r regionItem) Value() string       { return r.Slug }
+
+type regionsMsg []option
+
+// fetchRegions lists the regions that currently accept new Droplets.
+func fetchRegions(client *godo.Client) tea.Cmd {
+	return func() tea.Msg {
+		regions, _, err := client.Regions.List(context.Background(), &godo.ListOptions{PerPage: 200})
+		if err != nil {
+			return dropletMsg(dropletErrorMsg(err))
+		}
+
+		var opts []option
+		for _, r := range regions {
+			if r.Available {
+				opts = append(opts, regionItem{r})
+			}
+		}
+
+		return regionsMsg(opts)
+	}
+}
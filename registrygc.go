@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+// gcPollInterval is how often a garbage collection is checked on while it's
+// followed.
+const gcPollInterval = 10 * time.Second
+
+// promptGarbageCollection asks to confirm garbage collecting the registry,
+// which is read-only until it's finished.
+func (m model) promptGarbageCollection() (model, tea.Cmd) {
+	l := m.registry
+	if l.registry == nil {
+		return m, nil
+	}
+	if m.blockChanges("the registry", "garbage collected") {
+		return m, nil
+	}
+	if l.gc != nil {
+		m.formErr = "a garbage collection is already running"
+		return m, nil
+	}
+	l.confirmGC = true
+	m.notice = ""
+	m.formErr = ""
+	return m, nil
+}
+
+func (m model) updateGarbageCollectionConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.registry
+	gcType := godo.GCTypeUnreferencedBlobsOnly
+	switch msg.String() {
+	case "ctrl+c":
+		return m, m.quit()
+	case "u", "U":
+		gcType = godo.GCTypeUntaggedManifestsAndUnreferencedBlobs
+	case "y", "Y":
+	case "n", "N", "esc":
+		l.confirmGC = false
+		return m, nil
+	default:
+		return m, nil
+	}
+	l.confirmGC = false
+	return m, startGarbageCollection(m.client, l.registry.Name, gcType)
+}
+
+// registryGCMsg carries the latest state of a garbage collection, once it's
+// started and then each time it's checked on.
+type registryGCMsg struct {
+	gc  *godo.GarbageCollection
+	err error
+}
+
+// startGarbageCollection starts garbage collecting the registry.
+func startGarbageCollection(client *godo.Client, registry string, gcType godo.GarbageCollectionType) tea.Cmd {
+	return func() tea.Msg {
+		gc, _, err := client.Registry.StartGarbageCollection(context.Background(), registry, &godo.StartGarbageCollectionRequest{Type: gcType})
+		return registryGCMsg{gc: gc, err: err}
+	}
+}
+
+// followGarbageCollection checks on the garbage collection after waiting for
+// wait. Once it's finished it's no longer the registry's active one, so it's
+// looked for among the past ones.
+func followGarbageCollection(client *godo.Client, registry, uuid string, wait time.Duration) tea.Cmd {
+	return func() tea.Msg {
+		time.Sleep(wait)
+
+		ctx := context.Background()
+		gc, resp, err := client.Registry.GetGarbageCollection(ctx, registry)
+		if err == nil && gc.UUID == uuid {
+			return registryGCMsg{gc: gc}
+		}
+		if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+			return registryGCMsg{err: err}
+		}
+
+		past, _, err := client.Registry.ListGarbageCollections(ctx, registry, &godo.ListOptions{PerPage: 20})
+		if err != nil {
+			return registryGCMsg{err: err}
+		}
+		for _, gc := range past {
+			if gc.UUID == uuid {
+				return registryGCMsg{gc: gc}
+			}
+		}
+		return registryGCMsg{err: fmt.Errorf("garbage collection %s went missing", uuid)}
+	}
+}
+
+// garbageCollectionUpdated shows a garbage collection's progress, following
+// it until it's finished.
+func (m model) garbageCollectionUpdated(msg registryGCMsg) (tea.Model, tea.Cmd) {
+	l := m.registry
+	if msg.err != nil {
+		if l != nil {
+			l.gc = nil
+		}
+		m.formErr = "couldn't garbage collect the registry: " + msg.err.Error()
+		return m, nil
+	}
+
+	gc := msg.gc
+	switch gc.Status {
+	case "succeeded":
+		cmd := m.toast(fmt.Sprintf("Garbage collection freed %s, deleting %s", formatSize(gc.FreedBytes), pluralize(int(gc.BlobsDeleted), "blob")))
+		if l == nil {
+			return m, cmd
+		}
+		l.gc = nil
+		l.loading = true
+		return m, tea.Batch(cmd, fetchRegistryList(m.client, l.page))
+	case "failed", "cancelled":
+		if l != nil {
+			l.gc = nil
+		}
+		m.formErr = "the garbage collection " + gc.Status
+		return m, nil
+	}
+
+	if l == nil {
+		// Left the screen since, so it's no longer shown.
+		return m, nil
+	}
+	l.gc = gc
+	return m, followGarbageCollection(m.client, l.registry.Name, gc.UUID, gcPollInterval)
+}
+
+// garbageCollectionView shows the running garbage collection's status, e.g.
+// "scanning manifests".
+func (m model) garbageCollectionView() string {
+	gc := m.registry.gc
+	if gc == nil {
+		return ""
+	}
+	line := "Garbage collecting"
+	if gc.Status != "" {
+		line += " (" + gc.Status + ")"
+	}
+	return fmt.Sprintf("%s  %s\n\n", m.spinner.View(), placeholderStyle.Render(line+"..."))
+}
+
+func (m model) garbageCollectionConfirmView() string {
+	return fmt.Sprintf("%s\n%s\n\n%s",
+		focusedStyle.Render(fmt.Sprintf("Garbage collect %s?", m.registry.registry.Name)),
+		errorStyle.Render("It's read-only until it's finished, so pushes fail in the meantime."),
+		helpStyle.Render("y: unreferenced blobs • u: untagged manifests too • n: cancel"))
+}
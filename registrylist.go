@@ -0,0 +1,316 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+var (
+	openTagsKey          = key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "tags"))
+	garbageCollectionKey = key.NewBinding(key.WithKeys("g"), key.WithHelp("g", "garbage collect"))
+)
+
+// repositoriesPerPage is how many repositories the registry screen fetches
+// at a time.
+const repositoriesPerPage = 20
+
+// registryHost is where the registry's images are pulled from.
+const registryHost = "registry.digitalocean.com"
+
+// registryListMsg carries the account's container registry and a page of
+// its repositories, along with how many there are in all and the garbage
+// collection that's running, if there is one. none is set if the account
+// has no registry.
+type registryListMsg struct {
+	registry *godo.Registry
+	none     bool
+	page     int
+	repos    []*godo.RepositoryV2
+	total    int
+	gc       *godo.GarbageCollection
+	err      error
+}
+
+// fetchRegistryList fetches the account's registry and a page of its
+// repositories, counting from 1.
+func fetchRegistryList(client *godo.Client, page int) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		registry, resp, err := client.Registry.Get(ctx)
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return registryListMsg{page: page, none: true}
+		}
+		if err != nil {
+			return registryListMsg{page: page, err: err}
+		}
+
+		repos, resp, err := client.Registry.ListRepositoriesV2(ctx, registry.Name, &godo.TokenListOptions{Page: page, PerPage: repositoriesPerPage})
+		if err != nil {
+			return registryListMsg{registry: registry, page: page, err: err}
+		}
+		total := len(repos)
+		if resp.Meta != nil {
+			total = resp.Meta.Total
+		}
+
+		// There's only an active garbage collection while one's running.
+		gc, resp, err := client.Registry.GetGarbageCollection(ctx, registry.Name)
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			gc, err = nil, nil
+		}
+		return registryListMsg{registry: registry, page: page, repos: repos, total: total, gc: gc, err: err}
+	}
+}
+
+// registryList is the screen listing the repositories in the account's
+// container registry, a page at a time.
+type registryList struct {
+	table    table.Model
+	registry *godo.Registry
+	none     bool
+	repos    []*godo.RepositoryV2
+	page     int
+	total    int
+	loading  bool
+	err      error
+	// tags is set while showing a repository's tags, and confirmGC while
+	// asking to confirm a garbage collection.
+	tags      *registryTags
+	confirmGC bool
+	// gc is the garbage collection being followed until it's finished.
+	gc *godo.GarbageCollection
+}
+
+func newRegistryList(width, height int) *registryList {
+	l := &registryList{
+		page: 1,
+		table: newListTable([]table.Column{
+			{Title: "Repository", Width: 28},
+			{Title: "Tags", Width: 6},
+			{Title: "Manifests", Width: 9},
+			{Title: "Latest", Width: 20},
+			{Title: "Size", Width: 10},
+			{Title: "Updated", Width: 12},
+		}),
+	}
+	l.SetSize(width, height)
+	return l
+}
+
+// SetSize fits the table to the screen, under the title and above the help.
+func (l *registryList) SetSize(width, height int) {
+	setTableSize(&l.table, width, height)
+	if l.tags != nil {
+		setTableSize(&l.tags.table, width, height)
+	}
+}
+
+// pages returns how many pages of repositories there are.
+func (l *registryList) pages() int {
+	return pageCount(l.total, repositoriesPerPage)
+}
+
+// setRegistryList shows a page of repositories once it's been fetched, and
+// follows a garbage collection if one's running.
+func (m model) setRegistryList(msg registryListMsg) (tea.Model, tea.Cmd) {
+	l := m.registry
+	if l == nil {
+		return m, nil
+	}
+	// The last page was emptied, e.g. by garbage collection, so go back one.
+	if msg.err == nil && !msg.none && len(msg.repos) == 0 && msg.page > 1 {
+		return m, fetchRegistryList(m.client, msg.page-1)
+	}
+
+	l.loading = false
+	l.err = msg.err
+	if msg.registry != nil {
+		l.registry = msg.registry
+	}
+	l.none = msg.none
+	if msg.err != nil {
+		return m, nil
+	}
+	l.page, l.repos, l.total = msg.page, msg.repos, msg.total
+	l.setRows()
+	if msg.gc != nil && l.gc == nil {
+		l.gc = msg.gc
+		return m, followGarbageCollection(m.client, l.registry.Name, msg.gc.UUID, gcPollInterval)
+	}
+	return m, nil
+}
+
+// setRows fills the table in from the repositories.
+func (l *registryList) setRows() {
+	rows := make([]table.Row, len(l.repos))
+	for i, r := range l.repos {
+		latest, size, updated := "", "", ""
+		if mf := r.LatestManifest; mf != nil {
+			latest = strings.Join(mf.Tags, ", ")
+			if latest == "" {
+				latest = shortDigest(mf.Digest)
+			}
+			size = formatSize(mf.CompressedSizeBytes)
+			updated = mf.UpdatedAt.Format("2006-01-02")
+		}
+		rows[i] = table.Row{r.Name, fmt.Sprint(r.TagCount), fmt.Sprint(r.ManifestCount), latest, size, updated}
+	}
+	l.table.SetRows(rows)
+	if l.table.Cursor() >= len(rows) {
+		l.table.GotoTop()
+	}
+}
+
+// selected returns the repository under the cursor, unless there are none.
+func (l *registryList) selected() (*godo.RepositoryV2, bool) {
+	i := l.table.Cursor()
+	if i < 0 || i >= len(l.repos) {
+		return nil, false
+	}
+	return l.repos[i], true
+}
+
+// shortDigest shortens a manifest's digest the way docker does, e.g.
+// "sha256:9c3b…" to its first 12 hex digits.
+func shortDigest(digest string) string {
+	hex := strings.TrimPrefix(digest, "sha256:")
+	if len(hex) > 12 {
+		hex = hex[:12]
+	}
+	return hex
+}
+
+// formatSize describes a number of bytes in the largest unit that keeps it
+// above 1, e.g. "1.5 GB".
+func formatSize(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGT"[exp])
+}
+
+// openRegistry shows the repositories in the account's registry, fetching
+// their first page.
+func (m model) openRegistry() (model, tea.Cmd) {
+	m.registry = newRegistryList(m.width, m.height-1)
+	m.registry.loading = true
+	m.notice = ""
+	m.formErr = ""
+	return m, tea.Batch(fetchRegistryList(m.client, 1), m.spinner.Tick)
+}
+
+func (m model) updateRegistry(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.registry
+	if l.tags != nil {
+		return m.updateRegistryTags(msg)
+	}
+	if l.confirmGC {
+		return m.updateGarbageCollectionConfirm(msg)
+	}
+
+	switch {
+	case msg.String() == "ctrl+c":
+		return m, m.quit()
+	case key.Matches(msg, closeListKeys):
+		return m.closeScreen()
+	case key.Matches(msg, screensKey):
+		return m.openScreens()
+	case l.loading:
+		return m, nil
+	case key.Matches(msg, openTagsKey):
+		return m.openRegistryTags()
+	case key.Matches(msg, garbageCollectionKey):
+		return m.promptGarbageCollection()
+	case key.Matches(msg, refreshListKey):
+		l.loading = true
+		return m, fetchRegistryList(m.client, l.page)
+	case key.Matches(msg, nextPageKey) && l.page < l.pages():
+		l.loading = true
+		return m, fetchRegistryList(m.client, l.page+1)
+	case key.Matches(msg, prevPageKey) && l.page > 1:
+		l.loading = true
+		return m, fetchRegistryList(m.client, l.page-1)
+	}
+
+	var cmd tea.Cmd
+	l.table, cmd = l.table.Update(msg)
+	return m, cmd
+}
+
+func (m model) registryView() string {
+	l := m.registry
+	if l.tags != nil {
+		return m.registryTagsView()
+	}
+
+	var b strings.Builder
+
+	title := focusedStyle.Render("Registry")
+	if r := l.registry; r != nil {
+		title = focusedStyle.Render(r.Name) + placeholderStyle.Render(fmt.Sprintf("  %s · %s used", r.Region, formatSize(r.StorageUsageBytes)))
+		if l.total > 0 {
+			title += placeholderStyle.Render(fmt.Sprintf(" · %s · page %d of %d", pluralize(l.total, "repository"), l.page, l.pages()))
+		}
+	}
+	b.WriteString(title + "\n\n")
+
+	switch {
+	case l.loading && l.registry == nil && !l.none:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading the registry..."))
+	case l.err != nil && l.registry == nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't get the registry: "+l.err.Error()))
+	case l.none:
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("This account has no container registry yet; create one under Container Registry in the control panel."))
+	case len(l.repos) == 0:
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render(fmt.Sprintf("There are no repositories yet; push with docker push %s/%s/<image>.", registryHost, l.registry.Name)))
+	default:
+		fmt.Fprintf(&b, "%s\n\n", l.table.View())
+	}
+
+	switch {
+	case l.loading && l.registry != nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading..."))
+	case l.err != nil && l.registry != nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the repositories: "+l.err.Error()))
+	}
+	b.WriteString(m.garbageCollectionView())
+	if m.notice != "" {
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render(m.notice))
+	}
+	if m.formErr != "" {
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(m.formErr))
+	}
+
+	if l.confirmGC {
+		b.WriteString(m.garbageCollectionConfirmView())
+		return b.String()
+	}
+
+	var help []string
+	if len(l.repos) > 0 {
+		help = append(help, "↑/↓: move", "enter: tags")
+	}
+	if l.registry != nil {
+		help = append(help, "g: garbage collect")
+	}
+	if l.pages() > 1 {
+		help = append(help, "←/→: page")
+	}
+	help = append(help, "r: refresh", "tab: screens", m.closeScreenHelp())
+	b.WriteString(helpStyle.Render(strings.Join(help, " • ")))
+
+	return b.String()
+}
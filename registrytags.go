@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+var copyImageKey = key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "copy image"))
+
+// tagsPerPage is how many tags the registry screen fetches at a time.
+const tagsPerPage = 20
+
+// registryTagsMsg carries a page of a repository's tags, along with how many
+// there are in all.
+type registryTagsMsg struct {
+	repo  string
+	page  int
+	tags  []*godo.RepositoryTag
+	total int
+	err   error
+}
+
+// fetchRegistryTags lists a page of the repository's tags, counting from 1.
+func fetchRegistryTags(client *godo.Client, registry, repo string, page int) tea.Cmd {
+	return func() tea.Msg {
+		tags, resp, err := client.Registry.ListRepositoryTags(context.Background(), registry, repo, &godo.ListOptions{Page: page, PerPage: tagsPerPage})
+		if err != nil {
+			return registryTagsMsg{repo: repo, page: page, err: err}
+		}
+
+		total := len(tags)
+		if resp.Meta != nil {
+			total = resp.Meta.Total
+		}
+		return registryTagsMsg{repo: repo, page: page, tags: tags, total: total}
+	}
+}
+
+// registryTags lists a repository's tags, a page at a time.
+type registryTags struct {
+	repo    string
+	table   table.Model
+	tags    []*godo.RepositoryTag
+	page    int
+	total   int
+	loading bool
+	err     error
+}
+
+// pages returns how many pages of tags there are.
+func (t *registryTags) pages() int {
+	return pageCount(t.total, tagsPerPage)
+}
+
+// image returns the reference the tag under the cursor is pulled by, e.g.
+// registry.digitalocean.com/acme/web:1.2.
+func (t *registryTags) image(registry string) (string, bool) {
+	i := t.table.Cursor()
+	if i < 0 || i >= len(t.tags) {
+		return "", false
+	}
+	return fmt.Sprintf("%s/%s/%s:%s", registryHost, registry, t.repo, t.tags[i].Tag), true
+}
+
+// openRegistryTags shows the tags of the repository under the cursor.
+func (m model) openRegistryTags() (model, tea.Cmd) {
+	l := m.registry
+	repo, ok := l.selected()
+	if !ok {
+		return m, nil
+	}
+
+	t := &registryTags{
+		repo:    repo.Name,
+		page:    1,
+		loading: true,
+		table: newListTable([]table.Column{
+			{Title: "Tag", Width: 28},
+			{Title: "Digest", Width: 14},
+			{Title: "Size", Width: 10},
+			{Title: "Updated", Width: 18},
+		}),
+	}
+	setTableSize(&t.table, m.width, m.height-1)
+	l.tags = t
+	m.notice = ""
+	m.formErr = ""
+	return m, fetchRegistryTags(m.client, l.registry.Name, repo.Name, 1)
+}
+
+// setRegistryTags shows a page of tags once it's been fetched.
+func (m model) setRegistryTags(msg registryTagsMsg) (tea.Model, tea.Cmd) {
+	if m.registry == nil || m.registry.tags == nil || m.registry.tags.repo != msg.repo {
+		return m, nil
+	}
+	t := m.registry.tags
+	t.loading = false
+	t.err = msg.err
+	if msg.err != nil {
+		return m, nil
+	}
+
+	t.page, t.tags, t.total = msg.page, msg.tags, msg.total
+	rows := make([]table.Row, len(t.tags))
+	for i, tag := range t.tags {
+		rows[i] = table.Row{tag.Tag, shortDigest(tag.ManifestDigest), formatSize(tag.CompressedSizeBytes), tag.UpdatedAt.Format("2006-01-02 15:04")}
+	}
+	t.table.SetRows(rows)
+	if t.table.Cursor() >= len(rows) {
+		t.table.GotoTop()
+	}
+	return m, nil
+}
+
+func (m model) updateRegistryTags(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.registry
+	t := l.tags
+	switch {
+	case msg.String() == "ctrl+c":
+		return m, m.quit()
+	case msg.String() == "esc":
+		l.tags = nil
+		m.formErr = ""
+		return m, nil
+	case t.loading:
+		return m, nil
+	case key.Matches(msg, copyImageKey):
+		if image, ok := t.image(l.registry.Name); ok {
+			return m, copyToClipboard(image)
+		}
+		return m, nil
+	case key.Matches(msg, refreshListKey):
+		t.loading = true
+		return m, fetchRegistryTags(m.client, l.registry.Name, t.repo, t.page)
+	case key.Matches(msg, nextPageKey) && t.page < t.pages():
+		t.loading = true
+		return m, fetchRegistryTags(m.client, l.registry.Name, t.repo, t.page+1)
+	case key.Matches(msg, prevPageKey) && t.page > 1:
+		t.loading = true
+		return m, fetchRegistryTags(m.client, l.registry.Name, t.repo, t.page-1)
+	}
+
+	var cmd tea.Cmd
+	t.table, cmd = t.table.Update(msg)
+	return m, cmd
+}
+
+func (m model) registryTagsView() string {
+	l := m.registry
+	t := l.tags
+	var b strings.Builder
+
+	title := focusedStyle.Render(t.repo)
+	if t.total > 0 {
+		title += placeholderStyle.Render(fmt.Sprintf("  %s · page %d of %d", pluralize(t.total, "tag"), t.page, t.pages()))
+	}
+	b.WriteString(title + "\n\n")
+
+	switch {
+	case t.loading && t.tags == nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading tags..."))
+	case t.err != nil && t.tags == nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the tags: "+t.err.Error()))
+	case len(t.tags) == 0:
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render(fmt.Sprintf("%s has no tags; only untagged manifests, which garbage collection can delete.", t.repo)))
+	default:
+		fmt.Fprintf(&b, "%s\n\n", t.table.View())
+	}
+
+	switch {
+	case t.loading && t.tags != nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading..."))
+	case t.err != nil && t.tags != nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the tags: "+t.err.Error()))
+	}
+	b.WriteString(m.garbageCollectionView())
+	if m.notice != "" {
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render(m.notice))
+	}
+	if m.formErr != "" {
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(m.formErr))
+	}
+
+	help := []string{"↑/↓: move", "y: copy image"}
+	if t.pages() > 1 {
+		help = append(help, "←/→: page")
+	}
+	help = append(help, "r: refresh", "esc: back")
+	b.WriteString(helpStyle.Render(strings.Join(help, " • ")))
+
+	return b.String()
+}
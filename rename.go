@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+var renameKey = key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "rename"))
+
+// renamePrompt edits a Droplet's name in the list.
+type renamePrompt struct {
+	droplet godo.Droplet
+	name    *textField
+}
+
+// openRename starts editing the Droplet's name, from its current one.
+func (m model) openRename(droplet godo.Droplet) (model, tea.Cmd) {
+	name := newTextField("New name: ", droplet.Name)
+	name.CharLimit = 253
+	name.SetValue(droplet.Name)
+	name.CursorEnd()
+	m.list.rename = &renamePrompt{droplet: droplet, name: name}
+	m.notice = ""
+	m.formErr = ""
+	return m, name.Focus()
+}
+
+func (m model) updateRename(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.list
+	p := l.rename
+	switch msg.String() {
+	case "ctrl+c":
+		return m, m.quit()
+	case "esc":
+		l.rename = nil
+		m.formErr = ""
+		return m, nil
+	case "enter":
+		name := strings.TrimSpace(p.name.Value())
+		if name == p.droplet.Name {
+			l.rename = nil
+			m.formErr = ""
+			return m, nil
+		}
+		if err := checkHostname(name); err != "" {
+			m.formErr = err
+			return m, nil
+		}
+		l.rename = nil
+		m.formErr = ""
+		l.busy[p.droplet.ID] = "renaming"
+		l.setRows()
+		return m, renameDroplet(m.client, p.droplet, name)
+	}
+
+	_, cmd := p.name.Update(msg)
+	return m, cmd
+}
+
+// renameDroplet renames the Droplet and waits for the action to complete.
+func renameDroplet(client *godo.Client, droplet godo.Droplet, name string) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+
+		action, _, err := client.DropletActions.Rename(ctx, droplet.ID, name)
+		if err == nil {
+			err = waitForAction(ctx, client, action)
+		}
+		return dropletActionMsg{id: droplet.ID, name: fmt.Sprintf("%s to %s", droplet.Name, name), verb: "rename", done: "Renamed", err: err}
+	}
+}
+
+func (m model) renameView() string {
+	return fmt.Sprintf("%s\n\n", m.list.rename.name.View())
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+	"github.com/digitalocean/godo/util"
+)
+
+// newReservedIP is the reserved IP field's value when a new IP should be
+// reserved for each Droplet.
+const newReservedIP = "new"
+
+type reservedIPItem struct {
+	godo.ReservedIP
+}
+
+func (r reservedIPItem) Title() string       { return r.IP }
+func (r reservedIPItem) Description() string { return "unassigned" }
+func (r reservedIPItem) FilterValue() string { return r.IP }
+func (r reservedIPItem) Value() string       { return r.IP }
+
+// newReservedIPOption reserves a new IP in the region.
+type newReservedIPOption string
+
+func (o newReservedIPOption) Title() string { return "new" }
+func (o newReservedIPOption) Description() string {
+	return "reserve a new IP in " + string(o)
+}
+func (o newReservedIPOption) FilterValue() string { return "new" }
+func (o newReservedIPOption) Value() string       { return newReservedIP }
+
+// reservedIPsMsg carries the unassigned reserved IPs. If they couldn't be
+// listed, ips is empty so that a new IP or none can still be chosen.
+type reservedIPsMsg struct {
+	ips []godo.ReservedIP
+	err error
+}
+
+// fetchReservedIPs lists the reserved IPs on the account that aren't
+// assigned to a Droplet.
+func fetchReservedIPs(client *godo.Client) tea.Cmd {
+	return func() tea.Msg {
+		ips, _, err := client.ReservedIPs.List(context.Background(), &godo.ListOptions{PerPage: 200})
+		if err != nil {
+			return reservedIPsMsg{ips: []godo.ReservedIP{}, err: err}
+		}
+
+		unassigned := []godo.ReservedIP{}
+		for _, ip := range ips {
+			if ip.Droplet == nil {
+				unassigned = append(unassigned, ip)
+			}
+		}
+
+		return reservedIPsMsg{ips: unassigned}
+	}
+}
+
+// reservedIPPicker is a selectField for the unassigned reserved IPs in the
+// chosen region, or a new one.
+type reservedIPPicker struct {
+	*selectField
+	ips    []godo.ReservedIP
+	region string
+}
+
+func newReservedIPPicker() *reservedIPPicker {
+	return &reservedIPPicker{
+		selectField: newSelectField("Reserved IP: ", "Choose a reserved IP", ""),
+	}
+}
+
+// SetReservedIPs replaces the full set of reserved IPs the picker chooses
+// from.
+func (p *reservedIPPicker) SetReservedIPs(ips []godo.ReservedIP) tea.Cmd {
+	p.ips = ips
+	return p.refresh()
+}
+
+// SetRegion restricts the picker to reserved IPs in the given region,
+// dropping the current choice if it belongs to another region.
+func (p *reservedIPPicker) SetRegion(region string) tea.Cmd {
+	if p.region == region {
+		return nil
+	}
+	p.region = region
+	if r, ok := p.selected.(reservedIPItem); ok && r.Region != nil && r.Region.Slug != region {
+		p.selected = nil
+	}
+
+	return p.refresh()
+}
+
+func (p *reservedIPPicker) refresh() tea.Cmd {
+	if p.ips == nil {
+		return nil
+	}
+
+	opts := []option{noneOption("don't assign a reserved IP"), newReservedIPOption(p.region)}
+	for _, ip := range p.ips {
+		if ip.Region != nil && ip.Region.Slug == p.region {
+			opts = append(opts, reservedIPItem{ip})
+		}
+	}
+
+	p.list.Title = "Choose a reserved IP in " + p.region
+
+	return p.SetOptions(opts)
+}
+
+func (p *reservedIPPicker) Update(msg tea.Msg) (field, tea.Cmd) {
+	_, cmd := p.selectField.Update(msg)
+	return p, cmd
+}
+
+// checkReservedIP returns an error if the reserved IP can't be assigned to
+// every Droplet being created.
+func checkReservedIP(ip string, names []string) error {
+	if ip != "" && ip != newReservedIP && len(names) > 1 {
+		return errors.New("a reserved IP can only be assigned when creating a single Droplet; choose new to reserve one for each")
+	}
+	return nil
+}
+
+// assignReservedIP assigns the reserved IP to the Droplet, reserving a new
+// one if ip is newReservedIP, and returns the IP assigned.
+func assignReservedIP(ctx context.Context, client *godo.Client, ip string, droplet *godo.Droplet) (string, error) {
+	switch ip {
+	case "":
+		return "", nil
+	case newReservedIP:
+		reserved, _, err := client.ReservedIPs.Create(ctx, &godo.ReservedIPCreateRequest{DropletID: droplet.ID})
+		if err != nil {
+			return "", err
+		}
+		return reserved.IP, nil
+	}
+
+	action, _, err := client.ReservedIPActions.Assign(ctx, ip, droplet.ID)
+	if err != nil {
+		return "", err
+	}
+	if err := util.WaitForActive(ctx, client, fmt.Sprintf("v2/actions/%d", action.ID)); err != nil {
+		return "", err
+	}
+	return ip, nil
+}
+
+// reservedIPLabel describes the reserved IPs that will be assigned, for
+// review.
+func (m model) reservedIPLabel() string {
+	switch ip := m.fields[reservedIPField].Value(); ip {
+	case "":
+		return "none"
+	case newReservedIP:
+		if len(m.names) > 1 {
+			return fmt.Sprintf("%d new in %s", len(m.names), m.droplet.Region)
+		}
+		return "new in " + m.droplet.Region
+	default:
+		return ip
+	}
+}
@@ -0,0 +1,644 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+var (
+	reserveIPKey = key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "reserve"))
+	assignIPKey  = key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "assign/unassign"))
+)
+
+// reservedIPsPerPage is how many reserved IPs the reserved IPs screen
+// fetches at a time.
+const reservedIPsPerPage = 20
+
+// reservedIPListMsg carries a page of the account's reserved IPs, along with
+// how many there are in all.
+type reservedIPListMsg struct {
+	page  int
+	ips   []godo.ReservedIP
+	total int
+	err   error
+}
+
+// fetchReservedIPList lists a page of the account's reserved IPs, counting
+// from 1.
+func fetchReservedIPList(client *godo.Client, page int) tea.Cmd {
+	return func() tea.Msg {
+		ips, resp, err := client.ReservedIPs.List(context.Background(), &godo.ListOptions{Page: page, PerPage: reservedIPsPerPage})
+		if err != nil {
+			return reservedIPListMsg{page: page, err: err}
+		}
+
+		total := len(ips)
+		if resp.Meta != nil {
+			total = resp.Meta.Total
+		}
+		return reservedIPListMsg{page: page, ips: ips, total: total}
+	}
+}
+
+// reservedIPDoneMsg reports that a change to a reserved IP has finished. ip
+// is blank for one that's been reserved, and region is where.
+type reservedIPDoneMsg struct {
+	ip     string
+	region string
+	name   string
+	verb   string
+	done   string
+	err    error
+}
+
+// reservedIPList is the screen listing the account's reserved IPs, a page at
+// a time.
+type reservedIPList struct {
+	table   table.Model
+	ips     []godo.ReservedIP
+	page    int
+	total   int
+	loading bool
+	err     error
+	// reserve is set while choosing the region to reserve an IP in, assign
+	// while assigning or unassigning one, and confirmRelease while asking to
+	// confirm releasing one.
+	reserve        *selectField
+	assign         *reservedIPAssignFlow
+	confirmRelease *godo.ReservedIP
+	// busy holds the status to show for the IPs that are being changed, and
+	// reserving the regions new IPs are being reserved in, until they're done.
+	busy      map[string]string
+	reserving []string
+}
+
+func newReservedIPList(width, height int) *reservedIPList {
+	l := &reservedIPList{
+		page: 1,
+		busy: make(map[string]string),
+		table: newListTable([]table.Column{
+			{Title: "IP", Width: 16},
+			{Title: "Region", Width: 8},
+			{Title: "Droplet", Width: 28},
+			{Title: "Status", Width: 12},
+		}),
+	}
+	l.SetSize(width, height)
+	return l
+}
+
+// SetSize fits the table to the screen, under the title and above the help.
+func (l *reservedIPList) SetSize(width, height int) {
+	setTableSize(&l.table, width, height)
+}
+
+// pages returns how many pages of reserved IPs there are.
+func (l *reservedIPList) pages() int {
+	return pageCount(l.total, reservedIPsPerPage)
+}
+
+// setReservedIPList shows a page of reserved IPs once it's been fetched.
+func (m model) setReservedIPList(msg reservedIPListMsg) (tea.Model, tea.Cmd) {
+	l := m.reservedIPs
+	if l == nil {
+		return m, nil
+	}
+	// The last page was emptied, e.g. by releasing, so go back one.
+	if msg.err == nil && len(msg.ips) == 0 && msg.page > 1 {
+		return m, fetchReservedIPList(m.client, msg.page-1)
+	}
+
+	l.loading = false
+	l.err = msg.err
+	if msg.err != nil {
+		return m, nil
+	}
+	l.page, l.ips, l.total = msg.page, msg.ips, msg.total
+	l.setRows()
+	return m, nil
+}
+
+// setRows fills the table in from the reserved IPs.
+func (l *reservedIPList) setRows() {
+	rows := make([]table.Row, len(l.ips))
+	for i, ip := range l.ips {
+		region := ""
+		if ip.Region != nil {
+			region = ip.Region.Slug
+		}
+		droplet := ""
+		if ip.Droplet != nil {
+			droplet = ip.Droplet.Name
+		}
+		status := ""
+		if s, ok := l.busy[ip.IP]; ok {
+			status = s + "…"
+		}
+		rows[i] = table.Row{ip.IP, region, droplet, status}
+	}
+	l.table.SetRows(rows)
+	if l.table.Cursor() >= len(rows) {
+		l.table.GotoTop()
+	}
+}
+
+// selected returns the reserved IP under the cursor, unless there are none
+// or it's busy.
+func (l *reservedIPList) selected() (godo.ReservedIP, bool) {
+	i := l.table.Cursor()
+	if i < 0 || i >= len(l.ips) {
+		return godo.ReservedIP{}, false
+	}
+	_, busy := l.busy[l.ips[i].IP]
+	return l.ips[i], !busy
+}
+
+// openReservedIPs shows the list of reserved IPs, fetching its first page.
+func (m model) openReservedIPs() (model, tea.Cmd) {
+	m.reservedIPs = newReservedIPList(m.width, m.height-1)
+	m.reservedIPs.loading = true
+	m.notice = ""
+	m.formErr = ""
+	return m, tea.Batch(fetchReservedIPList(m.client, 1), m.spinner.Tick)
+}
+
+func (m model) updateReservedIPs(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.reservedIPs
+	if l.reserve != nil {
+		return m.updateReserveIP(msg)
+	}
+	if l.assign != nil {
+		return m.updateReservedIPAssign(msg)
+	}
+	if l.confirmRelease != nil {
+		return m.updateReservedIPRelease(msg)
+	}
+
+	switch {
+	case msg.String() == "ctrl+c":
+		return m, m.quit()
+	case key.Matches(msg, closeListKeys):
+		return m.closeScreen()
+	case key.Matches(msg, screensKey):
+		return m.openScreens()
+	case key.Matches(msg, reserveIPKey):
+		return m.openReserveIP()
+	case l.loading:
+		return m, nil
+	case key.Matches(msg, assignIPKey):
+		return m.openReservedIPAssign()
+	case key.Matches(msg, deleteKey):
+		return m.promptReservedIPRelease()
+	case key.Matches(msg, refreshListKey):
+		l.loading = true
+		return m, fetchReservedIPList(m.client, l.page)
+	case key.Matches(msg, nextPageKey) && l.page < l.pages():
+		l.loading = true
+		return m, fetchReservedIPList(m.client, l.page+1)
+	case key.Matches(msg, prevPageKey) && l.page > 1:
+		l.loading = true
+		return m, fetchReservedIPList(m.client, l.page-1)
+	}
+
+	var cmd tea.Cmd
+	l.table, cmd = l.table.Update(msg)
+	return m, cmd
+}
+
+// reservedIPDone refreshes the list once a change to a reserved IP has
+// finished.
+func (m model) reservedIPDone(msg reservedIPDoneMsg) (tea.Model, tea.Cmd) {
+	l := m.reservedIPs
+	if l != nil {
+		delete(l.busy, msg.ip)
+		if msg.verb == "reserve" {
+			for i, region := range l.reserving {
+				if region == msg.region {
+					l.reserving = append(l.reserving[:i:i], l.reserving[i+1:]...)
+					break
+				}
+			}
+		}
+		l.setRows()
+	}
+	if msg.err != nil {
+		m.formErr = fmt.Sprintf("couldn't %s %s: %s", msg.verb, msg.name, msg.err)
+		return m, nil
+	}
+
+	cmd := m.toast(fmt.Sprintf("%s %s", msg.done, msg.name))
+	if l == nil {
+		return m, cmd
+	}
+	l.loading = true
+	return m, tea.Batch(cmd, fetchReservedIPList(m.client, l.page))
+}
+
+func (m model) reservedIPsView() string {
+	l := m.reservedIPs
+	if l.reserve != nil && l.reserve.Opened() {
+		return l.reserve.View()
+	}
+	if l.assign != nil && l.assign.droplets != nil && l.assign.droplets.Opened() {
+		return l.assign.droplets.View()
+	}
+
+	var b strings.Builder
+
+	title := focusedStyle.Render("Reserved IPs")
+	if l.total > 0 {
+		title += placeholderStyle.Render(fmt.Sprintf("  %s · page %d of %d", pluralize(l.total, "reserved IP"), l.page, l.pages()))
+	}
+	b.WriteString(title + "\n\n")
+
+	switch {
+	case l.loading && l.ips == nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading reserved IPs..."))
+	case l.err != nil && l.ips == nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the reserved IPs: "+l.err.Error()))
+	case len(l.ips) == 0:
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("There are no reserved IPs in this account yet."))
+	default:
+		fmt.Fprintf(&b, "%s\n\n", l.table.View())
+	}
+
+	switch {
+	case l.loading && l.ips != nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading..."))
+	case l.err != nil && l.ips != nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the reserved IPs: "+l.err.Error()))
+	}
+	b.WriteString(m.reservedIPsBusyView())
+	if m.notice != "" {
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render(m.notice))
+	}
+	if m.formErr != "" {
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(m.formErr))
+	}
+
+	switch {
+	case l.reserve != nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading regions..."))
+		b.WriteString(helpStyle.Render("esc: cancel"))
+		return b.String()
+	case l.assign != nil:
+		b.WriteString(m.reservedIPAssignView())
+		return b.String()
+	case l.confirmRelease != nil:
+		b.WriteString(m.reservedIPReleaseView())
+		b.WriteString(helpStyle.Render("y: release • n: cancel"))
+		return b.String()
+	}
+
+	help := []string{"↑/↓: move", "c: reserve", "a: assign/unassign", "d: release"}
+	if l.pages() > 1 {
+		help = append(help, "←/→: page")
+	}
+	help = append(help, "r: refresh", "tab: screens", m.closeScreenHelp())
+	b.WriteString(helpStyle.Render(strings.Join(help, " • ")))
+
+	return b.String()
+}
+
+// reservedIPsBusyView lists the reserved IPs that are being changed.
+func (m model) reservedIPsBusyView() string {
+	l := m.reservedIPs
+	var busy []string
+	for _, region := range l.reserving {
+		busy = append(busy, "reserving an IP in "+region)
+	}
+	for _, ip := range l.ips {
+		if s, ok := l.busy[ip.IP]; ok {
+			busy = append(busy, fmt.Sprintf("%s %s", s, ip.IP))
+		}
+	}
+	if len(busy) == 0 {
+		return ""
+	}
+	s := strings.Join(busy, ", ")
+	return fmt.Sprintf("%s  %s\n\n", m.spinner.View(), placeholderStyle.Render(strings.ToUpper(s[:1])+s[1:]+"..."))
+}
+
+// reservedIPRegionsMsg carries the regions that IPs can be reserved in.
+type reservedIPRegionsMsg struct {
+	regions []option
+	err     error
+}
+
+// fetchReservedIPRegions lists the regions that currently accept new
+// Droplets, which are the ones IPs can be reserved in.
+func fetchReservedIPRegions(client *godo.Client) tea.Cmd {
+	return func() tea.Msg {
+		regions, _, err := client.Regions.List(context.Background(), &godo.ListOptions{PerPage: 200})
+		if err != nil {
+			return reservedIPRegionsMsg{err: err}
+		}
+
+		var opts []option
+		for _, r := range regions {
+			if r.Available {
+				opts = append(opts, regionItem{r})
+			}
+		}
+		return reservedIPRegionsMsg{regions: opts}
+	}
+}
+
+// openReserveIP starts reserving a new IP, choosing its region once the
+// regions have been fetched.
+func (m model) openReserveIP() (model, tea.Cmd) {
+	if m.blockChanges("reserved IPs", "reserved") {
+		return m, nil
+	}
+
+	regions := newSelectField("", "Choose a region to reserve an IP in", "")
+	regions.SetSize(m.width, m.height-1)
+	m.reservedIPs.reserve = regions
+	m.notice = ""
+	m.formErr = ""
+	return m, fetchReservedIPRegions(m.client)
+}
+
+// setReservedIPRegions offers the regions once they've been fetched,
+// starting on the form's region.
+func (m model) setReservedIPRegions(msg reservedIPRegionsMsg) (tea.Model, tea.Cmd) {
+	if m.reservedIPs == nil || m.reservedIPs.reserve == nil {
+		return m, nil
+	}
+	l := m.reservedIPs
+	if msg.err != nil {
+		l.reserve = nil
+		m.formErr = "couldn't list the regions: " + msg.err.Error()
+		return m, nil
+	}
+
+	cmd := l.reserve.SetOptions(msg.regions)
+	l.reserve.Select(m.fields[regionField].Value())
+	l.reserve.selected = nil
+	l.reserve.Open()
+	return m, cmd
+}
+
+func (m model) updateReserveIP(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.reservedIPs
+	if msg.String() == "ctrl+c" {
+		return m, m.quit()
+	}
+	if !l.reserve.Opened() {
+		// Waiting for the regions.
+		if msg.String() == "esc" {
+			l.reserve = nil
+		}
+		return m, nil
+	}
+
+	_, cmd := l.reserve.Update(msg)
+	if l.reserve.Opened() {
+		return m, cmd
+	}
+	region := l.reserve.Value()
+	chosen := l.reserve.selected != nil
+	l.reserve = nil
+	if !chosen {
+		return m, cmd
+	}
+	l.reserving = append(l.reserving, region)
+	return m, tea.Batch(cmd, reserveIP(m.client, region))
+}
+
+// reserveIP reserves a new IP in the region, unassigned.
+func reserveIP(client *godo.Client, region string) tea.Cmd {
+	return func() tea.Msg {
+		ip, _, err := client.ReservedIPs.Create(context.Background(), &godo.ReservedIPCreateRequest{Region: region})
+		name := "an IP in " + region
+		if err == nil {
+			name = fmt.Sprintf("%s in %s", ip.IP, region)
+		}
+		return reservedIPDoneMsg{region: region, name: name, verb: "reserve", done: "Reserved", err: err}
+	}
+}
+
+// reservedIPAssignFlow assigns a reserved IP to a Droplet in its region, or
+// unassigns it from its Droplet after confirming.
+type reservedIPAssignFlow struct {
+	ip godo.ReservedIP
+	// droplets lists the Droplets it can be assigned to, unless it's
+	// already assigned, when unassign is set instead.
+	droplets *selectField
+	unassign bool
+	err      error
+}
+
+// assignDropletsMsg carries the Droplets a reserved IP can be assigned to.
+type assignDropletsMsg struct {
+	ip       string
+	droplets []godo.Droplet
+	err      error
+}
+
+// fetchAssignDroplets lists the Droplets in the reserved IP's region, which
+// are the only ones it can be assigned to.
+func fetchAssignDroplets(client *godo.Client, ip godo.ReservedIP) tea.Cmd {
+	return func() tea.Msg {
+		region := ""
+		if ip.Region != nil {
+			region = ip.Region.Slug
+		}
+		droplets, err := dropletsInRegion(context.Background(), client, region)
+		return assignDropletsMsg{ip: ip.IP, droplets: droplets, err: err}
+	}
+}
+
+// openReservedIPAssign assigns the reserved IP under the cursor to a
+// Droplet, or unassigns it if it's assigned.
+func (m model) openReservedIPAssign() (model, tea.Cmd) {
+	if m.blockChanges("reserved IPs", "assigned or unassigned") {
+		return m, nil
+	}
+
+	l := m.reservedIPs
+	ip, ok := l.selected()
+	if !ok {
+		return m, nil
+	}
+	m.notice = ""
+	m.formErr = ""
+	if ip.Droplet != nil {
+		l.assign = &reservedIPAssignFlow{ip: ip, unassign: true}
+		return m, nil
+	}
+
+	droplets := newSelectField("", "Assign "+ip.IP+" to", "")
+	droplets.SetSize(m.width, m.height-1)
+	l.assign = &reservedIPAssignFlow{ip: ip, droplets: droplets}
+	return m, fetchAssignDroplets(m.client, ip)
+}
+
+// setAssignDroplets offers the Droplets once they've been fetched.
+func (m model) setAssignDroplets(msg assignDropletsMsg) (tea.Model, tea.Cmd) {
+	if m.reservedIPs == nil || m.reservedIPs.assign == nil || m.reservedIPs.assign.ip.IP != msg.ip {
+		return m, nil
+	}
+	a := m.reservedIPs.assign
+	if msg.err == nil && len(msg.droplets) == 0 {
+		msg.err = errors.New("there are no Droplets in its region")
+	}
+	if msg.err != nil {
+		a.err = msg.err
+		return m, nil
+	}
+
+	opts := make([]option, len(msg.droplets))
+	for i, d := range msg.droplets {
+		opts[i] = dropletOption{d}
+	}
+	cmd := a.droplets.SetOptions(opts)
+	a.droplets.Open()
+	return m, cmd
+}
+
+func (m model) updateReservedIPAssign(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.reservedIPs
+	a := l.assign
+	if msg.String() == "ctrl+c" {
+		return m, m.quit()
+	}
+
+	switch {
+	case a.unassign:
+		switch msg.String() {
+		case "y", "Y":
+			l.assign = nil
+			l.busy[a.ip.IP] = "unassigning"
+			l.setRows()
+			return m, assignReservedIPTo(m.client, a.ip.IP, a.ip.Droplet.ID, a.ip.Droplet.Name, false)
+		case "n", "N", "esc":
+			l.assign = nil
+		}
+		return m, nil
+
+	case a.err != nil || !a.droplets.Loaded():
+		if msg.String() == "esc" {
+			l.assign = nil
+		}
+		return m, nil
+	}
+
+	_, cmd := a.droplets.Update(msg)
+	if a.droplets.Opened() {
+		return m, cmd
+	}
+	l.assign = nil
+	d, ok := a.droplets.selected.(dropletOption)
+	if !ok {
+		return m, cmd
+	}
+	l.busy[a.ip.IP] = "assigning"
+	l.setRows()
+	return m, tea.Batch(cmd, assignReservedIPTo(m.client, a.ip.IP, d.ID, d.Name, true))
+}
+
+// assignReservedIPTo assigns the reserved IP to the Droplet, or unassigns it
+// from the Droplet, and waits for the action to complete.
+func assignReservedIPTo(client *godo.Client, ip string, dropletID int, dropletName string, assign bool) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+
+		var action *godo.Action
+		var err error
+		if assign {
+			action, _, err = client.ReservedIPActions.Assign(ctx, ip, dropletID)
+		} else {
+			action, _, err = client.ReservedIPActions.Unassign(ctx, ip)
+		}
+		if err == nil {
+			err = waitForAction(ctx, client, action)
+		}
+		if assign {
+			return reservedIPDoneMsg{ip: ip, name: ip + " to " + dropletName, verb: "assign", done: "Assigned", err: err}
+		}
+		return reservedIPDoneMsg{ip: ip, name: ip + " from " + dropletName, verb: "unassign", done: "Unassigned", err: err}
+	}
+}
+
+func (m model) reservedIPAssignView() string {
+	a := m.reservedIPs.assign
+	var b strings.Builder
+
+	switch {
+	case a.unassign:
+		fmt.Fprintf(&b, "%s\n", focusedStyle.Render(fmt.Sprintf("Unassign %s from %s?", a.ip.IP, a.ip.Droplet.Name)))
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("Traffic to it stops reaching the Droplet. The IP stays reserved, and billed, until it's released."))
+		b.WriteString(helpStyle.Render("y: unassign • n: cancel"))
+	case a.err != nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(fmt.Sprintf("can't assign %s: %s", a.ip.IP, a.err)))
+		b.WriteString(helpStyle.Render("esc: back"))
+	case !a.droplets.Loaded():
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading Droplets..."))
+		b.WriteString(helpStyle.Render("esc: cancel"))
+	}
+
+	return b.String()
+}
+
+// promptReservedIPRelease asks to confirm releasing the reserved IP under
+// the cursor. Assigned IPs have to be unassigned first.
+func (m model) promptReservedIPRelease() (model, tea.Cmd) {
+	if m.blockChanges("reserved IPs", "released") {
+		return m, nil
+	}
+
+	l := m.reservedIPs
+	ip, ok := l.selected()
+	if !ok {
+		return m, nil
+	}
+	if ip.Droplet != nil {
+		m.formErr = fmt.Sprintf("%s is assigned to %s; press a to unassign it first", ip.IP, ip.Droplet.Name)
+		return m, nil
+	}
+	l.confirmRelease = &ip
+	m.notice = ""
+	m.formErr = ""
+	return m, nil
+}
+
+func (m model) updateReservedIPRelease(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.reservedIPs
+	ip := l.confirmRelease
+	switch msg.String() {
+	case "ctrl+c":
+		return m, m.quit()
+	case "y", "Y":
+		l.confirmRelease = nil
+		l.busy[ip.IP] = "releasing"
+		l.setRows()
+		return m, releaseReservedIP(m.client, ip.IP)
+	case "n", "N", "esc":
+		l.confirmRelease = nil
+	}
+	return m, nil
+}
+
+// releaseReservedIP gives the reserved IP up.
+func releaseReservedIP(client *godo.Client, ip string) tea.Cmd {
+	return func() tea.Msg {
+		_, err := client.ReservedIPs.Delete(context.Background(), ip)
+		return reservedIPDoneMsg{ip: ip, name: ip, verb: "release", done: "Released", err: err}
+	}
+}
+
+func (m model) reservedIPReleaseView() string {
+	ip := m.reservedIPs.confirmRelease
+
+	return fmt.Sprintf("%s\n%s\n\n",
+		focusedStyle.Render(fmt.Sprintf("Release %s?", ip.IP)),
+		errorStyle.Render("It goes back to the pool and can't be got back."))
+}
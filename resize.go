@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+// resizeFlow walks through resizing a Droplet from the list: choosing a size
+// it can move to, whether to grow the disk too, and confirming.
+type resizeFlow struct {
+	droplet godo.Droplet
+	sizes   *sizePicker
+	// disk is set while asking whether to grow the disk, when the size
+	// chosen has a bigger one.
+	disk       *selectField
+	size       godo.Size
+	resizeDisk bool
+	confirm    bool
+	err        error
+}
+
+// resizeSizesMsg carries the sizes a Droplet can be resized to.
+type resizeSizesMsg struct {
+	id    int
+	sizes []godo.Size
+	err   error
+}
+
+// fetchResizeSizes lists the sizes available in the Droplet's region that it
+// can be resized to. A disk can grow but never shrink, so sizes with a
+// smaller one than the Droplet's are left out.
+func fetchResizeSizes(client *godo.Client, droplet godo.Droplet) tea.Cmd {
+	return func() tea.Msg {
+		sizes, _, err := client.Sizes.List(context.Background(), &godo.ListOptions{PerPage: 200})
+		if err != nil {
+			return resizeSizesMsg{id: droplet.ID, err: err}
+		}
+
+		var fits []godo.Size
+		for _, s := range sizes {
+			if !s.Available || s.Slug == droplet.SizeSlug || s.Disk < droplet.Disk {
+				continue
+			}
+			if droplet.Region != nil && !contains(s.Regions, droplet.Region.Slug) {
+				continue
+			}
+			fits = append(fits, s)
+		}
+		sort.SliceStable(fits, func(i, j int) bool {
+			return fits[i].PriceMonthly < fits[j].PriceMonthly
+		})
+
+		return resizeSizesMsg{id: droplet.ID, sizes: fits}
+	}
+}
+
+// diskOption chooses whether a resize grows the disk.
+type diskOption struct {
+	title, description string
+	disk               bool
+}
+
+func (o diskOption) Title() string       { return o.title }
+func (o diskOption) Description() string { return o.description }
+func (o diskOption) FilterValue() string { return o.title }
+func (o diskOption) Value() string       { return o.title }
+
+// openResize starts resizing the Droplet, fetching the sizes it can move to.
+func (m model) openResize(droplet godo.Droplet) (model, tea.Cmd) {
+	sizes := newSizePicker("")
+	sizes.SetSize(m.width, m.height-1)
+	m.list.resize = &resizeFlow{droplet: droplet, sizes: sizes}
+	return m, fetchResizeSizes(m.client, droplet)
+}
+
+// setResizeSizes opens the size picker once the sizes have been fetched.
+func (m model) setResizeSizes(msg resizeSizesMsg) (tea.Model, tea.Cmd) {
+	if m.list == nil || m.list.resize == nil || m.list.resize.droplet.ID != msg.id {
+		return m, nil
+	}
+	r := m.list.resize
+	if msg.err == nil && len(msg.sizes) == 0 {
+		msg.err = fmt.Errorf("no size in its region has at least its %s disk", formatDisk(r.droplet.Disk))
+	}
+	if msg.err != nil {
+		r.err = msg.err
+		return m, nil
+	}
+
+	cmd := r.sizes.SetSizes(msg.sizes)
+	r.sizes.list.Title = fmt.Sprintf("Resize %s from %s", r.droplet.Name, r.droplet.SizeSlug)
+	r.sizes.Open()
+	return m, cmd
+}
+
+func (m model) updateResize(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.list
+	r := l.resize
+	if msg.String() == "ctrl+c" {
+		return m, m.quit()
+	}
+
+	switch {
+	case r.err != nil || !r.sizes.Loaded():
+		if msg.String() == "esc" {
+			l.resize = nil
+		}
+		return m, nil
+
+	case r.sizes.Opened():
+		_, cmd := r.sizes.Update(msg)
+		if r.sizes.Opened() {
+			return m, cmd
+		}
+		size, ok := r.sizes.Size()
+		if !ok || r.sizes.selected == nil {
+			l.resize = nil
+			return m, cmd
+		}
+		r.size = size
+		if size.Disk == r.droplet.Disk {
+			r.resizeDisk = false
+			r.confirm = true
+			return m, cmd
+		}
+		r.disk = newSelectField("", "Grow the disk too?", "")
+		r.disk.SetSize(m.width, m.height-1)
+		diskCmd := r.disk.SetOptions([]option{
+			diskOption{
+				title:       "CPU and RAM only",
+				description: fmt.Sprintf("keep the %s disk, so it can be resized back down later", formatDisk(r.droplet.Disk)),
+			},
+			diskOption{
+				title:       "CPU, RAM and disk",
+				description: fmt.Sprintf("grow the disk to %s; this is permanent, it can't shrink again", formatDisk(size.Disk)),
+				disk:        true,
+			},
+		})
+		r.disk.Open()
+		return m, tea.Batch(cmd, diskCmd)
+
+	case r.disk != nil && r.disk.Opened():
+		_, cmd := r.disk.Update(msg)
+		if r.disk.Opened() {
+			return m, cmd
+		}
+		o, ok := r.disk.selected.(diskOption)
+		if !ok {
+			// Back to the sizes.
+			r.disk = nil
+			r.sizes.selected = nil
+			r.sizes.Open()
+			return m, cmd
+		}
+		r.resizeDisk = o.disk
+		r.confirm = true
+		return m, cmd
+
+	case r.confirm:
+		switch msg.String() {
+		case "y", "Y":
+			l.resize = nil
+			l.busy[r.droplet.ID] = "resizing"
+			l.setRows()
+			return m, resizeDroplet(m.client, r.droplet, r.size.Slug, r.resizeDisk)
+		case "n", "N", "esc":
+			l.resize = nil
+		}
+	}
+	return m, nil
+}
+
+// resizeDroplet resizes the Droplet, powering it off first if need be and
+// on again afterwards if it was on.
+func resizeDroplet(client *godo.Client, droplet godo.Droplet, size string, disk bool) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		done := func(err error) tea.Msg {
+			return dropletActionMsg{id: droplet.ID, name: droplet.Name, verb: "resize", done: "Resized", err: err}
+		}
+
+		if isActive(droplet.Status) {
+			action, _, err := client.DropletActions.PowerOff(ctx, droplet.ID)
+			if err == nil {
+				err = waitForAction(ctx, client, action)
+			}
+			if err != nil {
+				return done(fmt.Errorf("powering off: %w", err))
+			}
+		}
+
+		action, _, err := client.DropletActions.Resize(ctx, droplet.ID, size, disk)
+		if err == nil {
+			err = waitForAction(ctx, client, action)
+		}
+		if err != nil {
+			return done(err)
+		}
+
+		if isActive(droplet.Status) {
+			action, _, err := client.DropletActions.PowerOn(ctx, droplet.ID)
+			if err == nil {
+				err = waitForAction(ctx, client, action)
+			}
+			if err != nil {
+				return done(fmt.Errorf("resized, but powering on again: %w", err))
+			}
+		}
+		return done(nil)
+	}
+}
+
+func (m model) resizeView() string {
+	r := m.list.resize
+	var b strings.Builder
+
+	switch {
+	case r.err != nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(fmt.Sprintf("can't resize %s: %s", r.droplet.Name, r.err)))
+		b.WriteString(helpStyle.Render("esc: back"))
+	case !r.sizes.Loaded():
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading sizes..."))
+		b.WriteString(helpStyle.Render("esc: cancel"))
+	case r.sizes.Opened():
+		return r.sizes.View()
+	case r.disk != nil && r.disk.Opened():
+		return r.disk.View()
+	case r.confirm:
+		what := "CPU and RAM"
+		if r.resizeDisk {
+			what = "CPU, RAM and disk"
+		}
+		fmt.Fprintf(&b, "%s\n\n", focusedStyle.Render(fmt.Sprintf("Resize %s (%s) from %s to %s?", r.droplet.Name, what, r.droplet.SizeSlug, r.size.Slug)))
+		fmt.Fprintf(&b, "%s\n", placeholderStyle.Render(sizeItem{r.size}.Description()))
+		if isActive(r.droplet.Status) {
+			fmt.Fprintf(&b, "%s\n", placeholderStyle.Render("It'll be powered off for the resize, then on again."))
+		}
+		if r.resizeDisk {
+			fmt.Fprintf(&b, "%s\n", errorStyle.Render(fmt.Sprintf("The disk grows to %s for good, which can't be undone.", formatDisk(r.size.Disk))))
+		}
+		b.WriteString("\n" + helpStyle.Render("y: resize • n: cancel"))
+	}
+
+	return b.String()
+}
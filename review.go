@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// updateReview handles the confirmation prompt shown before creating.
+func (m model) updateReview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, m.quit()
+
+	case "y", "Y":
+		if (m.quota.exceeded(len(m.names)) || m.readOnlyReason() != "") && !m.dryRun {
+			return m, nil
+		}
+
+		m.reviewing = false
+		m.edited = false
+		m.submitted = true
+		if m.dryRun {
+			return m.quitWith(m.dryRunOutput(), m.recordHistory())
+		}
+
+		m.creating = true
+		cmds := make([]tea.Cmd, 4)
+		cmds[0] = dropletCreate(m.client, m.droplet, m.names, m.volume)
+		cmds[1] = m.spinner.Tick
+		cmds[2] = m.recordHistory()
+		cmds[3] = clearDraft
+
+		return m, tea.Batch(cmds...)
+
+	case "n", "N", "esc", "backspace":
+		m.reviewing = false
+
+	case "e":
+		return m.openExport(), nil
+	}
+
+	return m, nil
+}
+
+func (m model) reviewView() string {
+	var b strings.Builder
+
+	title := "Review your Droplet"
+	if len(m.names) > 1 {
+		title = fmt.Sprintf("Review your %d Droplets", len(m.names))
+	}
+	if m.dryRun {
+		title += " (dry run)"
+	}
+	fmt.Fprintf(&b, "%s\n\n", focusedStyle.Render(title))
+
+	yesNo := func(f int) string {
+		if m.fields[f].(*toggleField).Checked() {
+			return "yes"
+		}
+		return "no"
+	}
+
+	volume := "none"
+	if m.volume != nil {
+		volume = fmt.Sprintf("%s (%d GB)", m.volume.Name, m.volume.SizeGigaBytes)
+	}
+
+	tags := strings.Join(m.droplet.Tags, ", ")
+	if tags == "" {
+		tags = "none"
+	}
+
+	userData := m.fields[userDataField].(*userDataEditor).summary()
+	if userData == "" {
+		userData = "none"
+	}
+
+	rows := [][2]string{
+		{"Name", strings.Join(m.names, ", ")},
+		{"Region", m.fields[regionField].(*selectField).Label()},
+		{"Size", m.fields[sizeField].(*sizePicker).Label()},
+		{"Image", m.fields[imageField].(*imagePicker).Label()},
+		{"SSH keys", m.fields[keysField].(*multiSelectField).Label()},
+		{"Tags", tags},
+		{"User data", userData},
+		{"Backups", yesNo(backupsField)},
+		{"Monitoring", yesNo(monitoringField)},
+		{"IPv6", yesNo(ipv6Field)},
+		{"VPC", m.fields[vpcField].(*vpcPicker).Label()},
+		{"Project", m.fields[projectField].(*selectField).Label()},
+		{"DNS", m.dnsLabel()},
+		{"Reverse DNS", m.ptrLabel()},
+		{"Reserved IP", m.reservedIPLabel()},
+		{"Firewalls", m.fields[firewallsField].(*multiSelectField).Label()},
+		{"Load balancer", m.fields[loadBalancerField].(*loadBalancerPicker).Label()},
+		{"Volume", volume},
+	}
+	if m.context != "" {
+		rows = append([][2]string{{"Context", m.context}}, rows...)
+	}
+	if team := m.team(); team != "" {
+		rows = append([][2]string{{"Team", team}}, rows...)
+	}
+	width := 0
+	for _, r := range rows {
+		if len(r[0]) > width {
+			width = len(r[0])
+		}
+	}
+	for _, r := range rows {
+		fmt.Fprintf(&b, "%s %s\n", focusedStyle.Render(fmt.Sprintf("%-*s", width+1, r[0]+":")), placeholderStyle.Render(r[1]))
+	}
+
+	if cost := m.costView(); cost != "" {
+		fmt.Fprintf(&b, "\n%s\n", cost)
+	}
+
+	if w := m.quota.warning(len(m.names)); w != "" {
+		fmt.Fprintf(&b, "\n%s\n", errorStyle.Render(w))
+	}
+
+	prompt := "Create? (y/N)"
+	switch {
+	case m.dryRun:
+		prompt = "Print the request without creating? (y/N)"
+	case m.quota.exceeded(len(m.names)):
+		prompt = "Press n to go back."
+	}
+	fmt.Fprintf(&b, "\n%s\n\n", focusedStyle.Render(prompt))
+	fmt.Fprintf(&b, "%s\n", helpStyle.Render("e: export"))
+
+	return b.String()
+}
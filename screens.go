@@ -0,0 +1,234 @@
+package main
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var screensKey = key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "screens"))
+
+// screen is one of the screens managing the account's resources, besides
+// the create form.
+type screen struct {
+	name        string
+	description string
+	// command starts the program on the screen, e.g. bubbletea-droplet
+	// volumes.
+	command string
+	open    func(model) (model, tea.Cmd)
+}
+
+func (s screen) Title() string       { return s.name }
+func (s screen) Description() string { return s.description }
+func (s screen) FilterValue() string { return s.name }
+func (s screen) Value() string       { return s.command }
+
+// screens lists the screens in the order they're offered in the screens
+// menu.
+var screens = []screen{
+	{
+		name:        "Droplets",
+		description: "list, act on and delete the account's Droplets",
+		command:     "list",
+		open:        model.openList,
+	},
+	{
+		name:        "Volumes",
+		description: "create, resize and delete block storage volumes",
+		command:     "volumes",
+		open:        model.openVolumes,
+	},
+	{
+		name:        "Domains",
+		description: "add and delete the domains whose DNS the account manages",
+		command:     "domains",
+		open:        model.openDomains,
+	},
+	{
+		name:        "Reserved IPs",
+		description: "reserve, assign, unassign and release reserved IPs",
+		command:     "reserved-ips",
+		open:        model.openReservedIPs,
+	},
+	{
+		name:        "Firewalls",
+		description: "create cloud firewalls, edit their rules and choose their Droplets",
+		command:     "firewalls",
+		open:        model.openFirewalls,
+	},
+	{
+		name:        "Load balancers",
+		description: "create load balancers for Droplets and delete them",
+		command:     "load-balancers",
+		open:        model.openLoadBalancers,
+	},
+	{
+		name:        "Kubernetes",
+		description: "create Kubernetes clusters and wait for them to run, and delete them",
+		command:     "kubernetes",
+		open:        model.openClusters,
+	},
+	{
+		name:        "Databases",
+		description: "show database clusters' connection details and copy them",
+		command:     "databases",
+		open:        model.openDatabases,
+	},
+	{
+		name:        "Apps",
+		description: "deploy App Platform apps from a spec and follow them until they're live",
+		command:     "apps",
+		open:        model.openApps,
+	},
+	{
+		name:        "Spaces",
+		description: "list the Spaces buckets in each region and create new ones",
+		command:     "spaces",
+		open:        model.openSpaces,
+	},
+	{
+		name:        "Registry",
+		description: "browse the container registry's repositories and tags, and garbage collect it",
+		command:     "registry",
+		open:        model.openRegistry,
+	},
+	{
+		name:        "Projects",
+		description: "list projects and their resources, create projects and move Droplets between them",
+		command:     "projects",
+		open:        model.openProjects,
+	},
+	{
+		name:        "SSH keys",
+		description: "list the account's SSH keys, upload public keys and delete them",
+		command:     "keys",
+		open:        model.openKeys,
+	},
+	{
+		name:        "VPCs",
+		description: "list VPCs and the resources in them, and create VPCs with their own IP ranges",
+		command:     "vpcs",
+		open:        model.openVPCs,
+	},
+	{
+		name:        "CDN endpoints",
+		description: "serve Spaces buckets from the edge, flush their caches and give them custom domains",
+		command:     "cdn",
+		open:        model.openCDNs,
+	},
+	{
+		name:        "Certificates",
+		description: "list TLS certificates, upload your own and request them from Let's Encrypt",
+		command:     "certificates",
+		open:        model.openCertificates,
+	},
+	{
+		name:        "Alert policies",
+		description: "list monitoring alert policies, and create them for CPU, memory or disk use",
+		command:     "alerts",
+		open:        model.openAlerts,
+	},
+	{
+		name:        "Functions",
+		description: "list Functions namespaces and the triggers that run their functions on a schedule",
+		command:     "functions",
+		open:        model.openFunctions,
+	},
+}
+
+// findScreen returns the screen started by the command, if there is one.
+func findScreen(command string) (screen, bool) {
+	for _, s := range screens {
+		if s.command == command {
+			return s, true
+		}
+	}
+	return screen{}, false
+}
+
+// openScreens shows the menu of screens to switch to.
+func (m model) openScreens() (model, tea.Cmd) {
+	opts := make([]option, len(screens))
+	for i, s := range screens {
+		opts[i] = s
+	}
+	m.screens = newSelectField("", "Screens", "")
+	m.screens.SetSize(m.width, m.height-1)
+	cmd := m.screens.SetOptions(opts)
+	m.screens.Open()
+	return m, cmd
+}
+
+// updateScreens handles the screens menu, switching to the chosen screen.
+func (m model) updateScreens(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "ctrl+c" {
+		return m, m.quit()
+	}
+
+	_, cmd := m.screens.Update(msg)
+	if m.screens.Opened() {
+		return m, cmd
+	}
+	s, ok := m.screens.selected.(screen)
+	m.screens = nil
+	if !ok {
+		return m, cmd
+	}
+
+	m.list = nil
+	m.volumes = nil
+	m.domains = nil
+	m.reservedIPs = nil
+	m.firewalls = nil
+	m.loadBalancers = nil
+	m.clusters = nil
+	m.databases = nil
+	m.apps = nil
+	m.spaces = nil
+	m.registry = nil
+	m.projects = nil
+	m.sshKeys = nil
+	m.vpcs = nil
+	m.cdns = nil
+	m.certificates = nil
+	m.alerts = nil
+	m.functions = nil
+	m, openCmd := s.open(m)
+	return m, tea.Batch(cmd, openCmd)
+}
+
+// closeScreen leaves the current screen for the form, or quits if the
+// program was started on a screen.
+func (m model) closeScreen() (tea.Model, tea.Cmd) {
+	if m.standalone {
+		return m, tea.Quit
+	}
+	m.list = nil
+	m.volumes = nil
+	m.domains = nil
+	m.reservedIPs = nil
+	m.firewalls = nil
+	m.loadBalancers = nil
+	m.clusters = nil
+	m.databases = nil
+	m.apps = nil
+	m.spaces = nil
+	m.registry = nil
+	m.projects = nil
+	m.sshKeys = nil
+	m.vpcs = nil
+	m.cdns = nil
+	m.certificates = nil
+	m.alerts = nil
+	m.functions = nil
+	m.formErr = ""
+	return m, nil
+}
+
+// closeScreenHelp describes the key that leaves the current screen.
+func (m model) closeScreenHelp() string {
+	if m.standalone {
+		return "q: quit"
+	}
+	return "esc: back"
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+// Monthly price caps cycled through with the price filter key. Zero means no
+// cap.
+var priceCaps = []float64{0, 5, 10, 25, 50, 100, 250, 500}
+
+var (
+	classKey = key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "class"))
+	priceKey = key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "max price"))
+)
+
+type sizeItem struct {
+	godo.Size
+}
+
+func (s sizeItem) Title() string { return s.Slug }
+func (s sizeItem) Description() string {
+	return fmt.Sprintf("%s · %s · %s disk · $%.2f/mo ($%.5f/hr) · %s",
+		pluralize(s.Vcpus, "vCPU"), formatMemory(s.Memory), formatDisk(s.Disk), s.PriceMonthly, s.PriceHourly, s.Size.Description)
+}
+func (s sizeItem) FilterValue() string { return s.Slug + " " + s.Size.Description }
+func (s sizeItem) Value() string       { return s.Slug }
+
+type sizesMsg []godo.Size
+
+// fetchSizes lists the Droplet sizes that are currently available.
+func fetchSizes(client *godo.Client) tea.Cmd {
+	return func() tea.Msg {
+		sizes, _, err := client.Sizes.List(context.Background(), &godo.ListOptions{PerPage: 200})
+		if err != nil {
+			return dropletMsg(dropletErrorMsg(err))
+		}
+
+		var available []godo.Size
+		for _, s := range sizes {
+			if s.Available {
+				available = append(available, s)
+			}
+		}
+		sort.SliceStable(available, func(i, j int) bool {
+			return available[i].PriceMonthly < available[j].PriceMonthly
+		})
+
+		return sizesMsg(available)
+	}
+}
+
+// sizePicker is a selectField for sizes that can additionally be narrowed
+// down by class and monthly price.
+type sizePicker struct {
+	*selectField
+	sizes   []godo.Size
+	region  string
+	classes []string
+	class   int
+	cap     int
+}
+
+func newSizePicker(fallback string) *sizePicker {
+	p := &sizePicker{
+		selectField: newSelectField("Size: ", "Choose a size", fallback),
+	}
+	p.list.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{classKey, priceKey}
+	}
+
+	return p
+}
+
+// SetSizes replaces the full set of sizes the picker chooses from.
+func (p *sizePicker) SetSizes(sizes []godo.Size) tea.Cmd {
+	p.sizes = sizes
+
+	seen := make(map[string]bool)
+	p.classes = []string{""}
+	for _, s := range sizes {
+		if s.Description != "" && !seen[s.Description] {
+			seen[s.Description] = true
+			p.classes = append(p.classes, s.Description)
+		}
+	}
+	p.class = 0
+
+	return p.refresh()
+}
+
+// SetRegion restricts the picker to sizes offered in the given region,
+// dropping the current choice if it isn't offered there.
+func (p *sizePicker) SetRegion(region string) tea.Cmd {
+	if p.region == region {
+		return nil
+	}
+	p.region = region
+	if s, ok := p.selected.(sizeItem); ok && !contains(s.Regions, region) {
+		p.selected = nil
+	}
+
+	return p.refresh()
+}
+
+// Size returns the full details of the chosen size, if known.
+func (p *sizePicker) Size() (godo.Size, bool) {
+	for _, s := range p.sizes {
+		if s.Slug == p.Value() {
+			return s, true
+		}
+	}
+	return godo.Size{}, false
+}
+
+func (p *sizePicker) refresh() tea.Cmd {
+	if p.sizes == nil {
+		return nil
+	}
+
+	class := p.classes[p.class]
+	max := priceCaps[p.cap]
+
+	var opts []option
+	for _, s := range p.sizes {
+		if p.region != "" && !contains(s.Regions, p.region) {
+			continue
+		}
+		if class != "" && s.Description != class {
+			continue
+		}
+		if max != 0 && s.PriceMonthly > max {
+			continue
+		}
+		opts = append(opts, sizeItem{s})
+	}
+
+	var filters []string
+	if p.region != "" {
+		filters = append(filters, p.region)
+	}
+	if class != "" {
+		filters = append(filters, class)
+	}
+	if max != 0 {
+		filters = append(filters, fmt.Sprintf("≤ $%.0f/mo", max))
+	}
+	p.list.Title = "Choose a size"
+	if len(filters) > 0 {
+		p.list.Title += " · " + strings.Join(filters, " · ")
+	}
+
+	return p.SetOptions(opts)
+}
+
+func (p *sizePicker) Update(msg tea.Msg) (field, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && p.open && !p.list.SettingFilter() {
+		switch {
+		case key.Matches(msg, classKey):
+			p.class = (p.class + 1) % len(p.classes)
+			return p, p.refresh()
+		case key.Matches(msg, priceKey):
+			p.cap = (p.cap + 1) % len(priceCaps)
+			return p, p.refresh()
+		}
+	}
+
+	_, cmd := p.selectField.Update(msg)
+	return p, cmd
+}
+
+// backupsPrice is the monthly surcharge for enabling weekly backups, which
+// cost 20% of the Droplet's price.
+func backupsPrice(s godo.Size) float64 {
+	return s.PriceMonthly * 0.2
+}
+
+func formatMemory(mb int) string {
+	if mb < 1024 {
+		return fmt.Sprintf("%d MB", mb)
+	}
+	return fmt.Sprintf("%g GB", float64(mb)/1024)
+}
+
+func formatDisk(gb int) string {
+	if gb >= 1000 && gb%1000 == 0 {
+		return fmt.Sprintf("%d TB", gb/1000)
+	}
+	return fmt.Sprintf("%d GB", gb)
+}
+
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+// snapshotPricePerGB is the monthly price of snapshot storage, per GB.
+const snapshotPricePerGB = 0.06
+
+// snapshotPrompt asks what to call a snapshot of a Droplet.
+type snapshotPrompt struct {
+	droplet godo.Droplet
+	name    *textField
+}
+
+// openSnapshot asks what to call the snapshot, suggesting the Droplet's name
+// and the date.
+func (m model) openSnapshot(droplet godo.Droplet) (model, tea.Cmd) {
+	name := newTextField("Snapshot name: ", droplet.Name+"-"+time.Now().Format("2006-01-02"))
+	name.CharLimit = 255
+	m.list.snapshot = &snapshotPrompt{droplet: droplet, name: name}
+	m.notice = ""
+	m.formErr = ""
+	return m, name.Focus()
+}
+
+func (m model) updateSnapshot(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.list
+	p := l.snapshot
+	switch msg.String() {
+	case "ctrl+c":
+		return m, m.quit()
+	case "esc":
+		l.snapshot = nil
+		return m, nil
+	case "enter":
+		l.snapshot = nil
+		l.busy[p.droplet.ID] = "snapshotting"
+		l.setRows()
+		return m, snapshotDroplet(m.client, p.droplet, strings.TrimSpace(p.name.Value()))
+	}
+
+	_, cmd := p.name.Update(msg)
+	return m, cmd
+}
+
+// snapshotDroplet takes a snapshot of the Droplet and waits for it to be
+// saved, which takes a few minutes per GB used.
+func snapshotDroplet(client *godo.Client, droplet godo.Droplet, name string) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+
+		action, _, err := client.DropletActions.Snapshot(ctx, droplet.ID, name)
+		if err == nil {
+			err = waitForAction(ctx, client, action)
+		}
+		return dropletActionMsg{id: droplet.ID, name: fmt.Sprintf("%s as %s", droplet.Name, name), verb: "snapshot", done: "Snapshotted", err: err}
+	}
+}
+
+func (m model) snapshotView() string {
+	p := m.list.snapshot
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s\n", placeholderStyle.Render(fmt.Sprintf("Snapshots cost $%.2f/GB a month for the space used, so up to $%.2f/mo for this %s disk.", snapshotPricePerGB, snapshotPricePerGB*float64(p.droplet.Disk), formatDisk(p.droplet.Disk))))
+	if isActive(p.droplet.Status) {
+		fmt.Fprintf(&b, "%s\n", errorStyle.Render("It's on, so anything being written meanwhile may be inconsistent in the snapshot; power it off first to be sure."))
+	}
+	fmt.Fprintf(&b, "\n%s\n\n", p.name.View())
+
+	return b.String()
+}
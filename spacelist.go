@@ -0,0 +1,423 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+var createBucketKey = key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "create"))
+
+// spacesListMsg carries the account's buckets in every Spaces region, along
+// with the regions they couldn't be listed in.
+type spacesListMsg struct {
+	buckets []spacesBucket
+	errs    map[string]error
+}
+
+// fetchSpacesList lists the buckets in each of the Spaces regions at once,
+// since each region has its own endpoint.
+func fetchSpacesList(c *spacesClient) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		var (
+			mu  sync.Mutex
+			wg  sync.WaitGroup
+			msg = spacesListMsg{errs: make(map[string]error)}
+		)
+		for _, region := range spacesRegions {
+			wg.Add(1)
+			go func(region string) {
+				defer wg.Done()
+				buckets, err := c.listBuckets(ctx, region)
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					msg.errs[region] = err
+					return
+				}
+				msg.buckets = append(msg.buckets, buckets...)
+			}(region)
+		}
+		wg.Wait()
+
+		sort.Slice(msg.buckets, func(i, j int) bool {
+			a, b := msg.buckets[i], msg.buckets[j]
+			if a.region != b.region {
+				return a.region < b.region
+			}
+			return a.Name < b.Name
+		})
+		return msg
+	}
+}
+
+// spaceList is the screen listing the account's Spaces buckets, region by
+// region.
+type spaceList struct {
+	// client is nil when there's no Spaces access key to sign requests
+	// with.
+	client  *spacesClient
+	table   table.Model
+	buckets []spacesBucket
+	loaded  bool
+	loading bool
+	// err is set when the buckets couldn't be listed in any region, and
+	// regionErrs when only some of them failed.
+	err        error
+	regionErrs map[string]error
+	// create is set while creating a bucket, and creating holds the names
+	// of the buckets being created.
+	create   *bucketCreateFlow
+	creating []string
+}
+
+func newSpaceList(client *spacesClient, width, height int) *spaceList {
+	l := &spaceList{
+		client: client,
+		table: newListTable([]table.Column{
+			{Title: "Name", Width: 32},
+			{Title: "Region", Width: 8},
+			{Title: "Endpoint", Width: 44},
+			{Title: "Created", Width: 12},
+		}),
+	}
+	l.SetSize(width, height)
+	return l
+}
+
+// SetSize fits the table to the screen, under the title and above the help.
+func (l *spaceList) SetSize(width, height int) {
+	setTableSize(&l.table, width, height)
+}
+
+// setSpacesList shows the buckets once they've been listed.
+func (m model) setSpacesList(msg spacesListMsg) (tea.Model, tea.Cmd) {
+	l := m.spaces
+	if l == nil {
+		return m, nil
+	}
+
+	l.loading = false
+	l.err, l.regionErrs = nil, nil
+	if len(msg.errs) == len(spacesRegions) {
+		// Most likely the key's wrong, which every region reports the same.
+		l.err = msg.errs[spacesRegions[0]]
+		return m, nil
+	}
+	if len(msg.errs) > 0 {
+		l.regionErrs = msg.errs
+	}
+	l.buckets, l.loaded = msg.buckets, true
+	l.setRows()
+	return m, nil
+}
+
+// setRows fills the table in from the buckets.
+func (l *spaceList) setRows() {
+	rows := make([]table.Row, len(l.buckets))
+	for i, b := range l.buckets {
+		endpoint := strings.TrimPrefix(spacesEndpoint(b.region), "https://")
+		rows[i] = table.Row{b.Name, b.region, b.Name + "." + endpoint, b.Created.Format("2006-01-02")}
+	}
+	l.table.SetRows(rows)
+	if l.table.Cursor() >= len(rows) {
+		l.table.GotoTop()
+	}
+}
+
+// openSpaces shows the list of buckets, unless there's no Spaces access key,
+// when it says how to add one.
+func (m model) openSpaces() (model, tea.Cmd) {
+	m.spaces = newSpaceList(newSpacesClient(m.defaults), m.width, m.height-1)
+	m.notice = ""
+	m.formErr = ""
+	if m.spaces.client == nil {
+		return m, nil
+	}
+	m.spaces.loading = true
+	return m, tea.Batch(fetchSpacesList(m.spaces.client), m.spinner.Tick)
+}
+
+func (m model) updateSpaces(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.spaces
+	if l.create != nil {
+		return m.updateBucketCreate(msg)
+	}
+
+	switch {
+	case msg.String() == "ctrl+c":
+		return m, m.quit()
+	case key.Matches(msg, closeListKeys):
+		return m.closeScreen()
+	case key.Matches(msg, screensKey):
+		return m.openScreens()
+	case l.client == nil:
+		return m, nil
+	case key.Matches(msg, createBucketKey):
+		return m.openBucketCreate()
+	case l.loading:
+		return m, nil
+	case key.Matches(msg, refreshListKey):
+		l.loading = true
+		return m, fetchSpacesList(l.client)
+	}
+
+	var cmd tea.Cmd
+	l.table, cmd = l.table.Update(msg)
+	return m, cmd
+}
+
+// bucketCreateFlow creates a bucket, asking for its name and then its
+// region.
+type bucketCreateFlow struct {
+	name    *textField
+	regions *selectField
+	// choosing is set once the name's been entered.
+	choosing bool
+}
+
+// spacesRegionsMsg carries the regions buckets can be created in.
+type spacesRegionsMsg struct {
+	regions []option
+	err     error
+}
+
+// fetchSpacesRegions describes the Spaces regions with the API's names for
+// them. Spaces outlives Droplets in some regions, so they're offered whether
+// or not they take new Droplets.
+func fetchSpacesRegions(client *godo.Client) tea.Cmd {
+	return func() tea.Msg {
+		regions, _, err := client.Regions.List(context.Background(), &godo.ListOptions{PerPage: 200})
+		if err != nil {
+			return spacesRegionsMsg{err: err}
+		}
+
+		bySlug := make(map[string]godo.Region)
+		for _, r := range regions {
+			bySlug[r.Slug] = r
+		}
+		var opts []option
+		for _, slug := range spacesRegions {
+			if r, ok := bySlug[slug]; ok {
+				opts = append(opts, regionItem{r})
+			} else {
+				opts = append(opts, valueOption(slug))
+			}
+		}
+		return spacesRegionsMsg{regions: opts}
+	}
+}
+
+// openBucketCreate starts creating a bucket, asking for its name while the
+// regions are fetched.
+func (m model) openBucketCreate() (model, tea.Cmd) {
+	if m.blockChanges("buckets", "created") {
+		return m, nil
+	}
+
+	f := &bucketCreateFlow{name: newOptionalTextField("Name: ", "e.g. my-assets")}
+	f.name.CharLimit = 63
+	f.regions = newSelectField("", "Choose a region for the bucket", "")
+	f.regions.SetSize(m.width, m.height-1)
+	m.spaces.create = f
+	m.notice = ""
+	m.formErr = ""
+	return m, tea.Batch(f.name.Focus(), fetchSpacesRegions(m.client))
+}
+
+// setSpacesRegions offers the regions once they've been fetched, starting
+// on the form's region if Spaces is in it.
+func (m model) setSpacesRegions(msg spacesRegionsMsg) (tea.Model, tea.Cmd) {
+	if m.spaces == nil || m.spaces.create == nil {
+		return m, nil
+	}
+	f := m.spaces.create
+	if msg.err != nil {
+		m.spaces.create = nil
+		m.formErr = "couldn't list the regions: " + msg.err.Error()
+		return m, nil
+	}
+	cmd := f.regions.SetOptions(msg.regions)
+	for _, region := range spacesRegions {
+		if region == m.fields[regionField].Value() {
+			f.regions.Select(region)
+		}
+	}
+	if f.choosing {
+		f.regions.Open()
+	}
+	return m, cmd
+}
+
+func (m model) updateBucketCreate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.spaces
+	f := l.create
+	if msg.String() == "ctrl+c" {
+		return m, m.quit()
+	}
+
+	if !f.choosing {
+		switch msg.String() {
+		case "esc":
+			l.create = nil
+			m.formErr = ""
+			return m, nil
+		case "enter":
+			name := strings.TrimSpace(f.name.Value())
+			if problem := checkBucketName(name); problem != "" {
+				m.formErr = problem
+				return m, nil
+			}
+			m.formErr = ""
+			f.choosing = true
+			if f.regions.Loaded() {
+				f.regions.Open()
+			}
+			return m, nil
+		}
+		_, cmd := f.name.Update(msg)
+		return m, cmd
+	}
+
+	if !f.regions.Opened() {
+		// Waiting for the regions.
+		if msg.String() == "esc" {
+			f.choosing = false
+		}
+		return m, nil
+	}
+	_, cmd := f.regions.Update(msg)
+	if f.regions.Opened() {
+		return m, cmd
+	}
+	if msg.String() == "esc" || f.regions.selected == nil {
+		// Backed out of the list, so back to the name.
+		f.choosing = false
+		return m, cmd
+	}
+	name, region := strings.TrimSpace(f.name.Value()), f.regions.Value()
+	l.create = nil
+	l.creating = append(l.creating, name)
+	return m, tea.Batch(cmd, createBucket(l.client, region, name))
+}
+
+// bucketCreatedMsg reports that a bucket has been created, or couldn't be.
+type bucketCreatedMsg struct {
+	name   string
+	region string
+	err    error
+}
+
+// createBucket creates a private bucket in the region.
+func createBucket(c *spacesClient, region, name string) tea.Cmd {
+	return func() tea.Msg {
+		err := c.createBucket(context.Background(), region, name)
+		return bucketCreatedMsg{name: name, region: region, err: err}
+	}
+}
+
+// bucketCreated refreshes the list once a bucket's been created.
+func (m model) bucketCreated(msg bucketCreatedMsg) (tea.Model, tea.Cmd) {
+	l := m.spaces
+	if l != nil {
+		for i, name := range l.creating {
+			if name == msg.name {
+				l.creating = append(l.creating[:i:i], l.creating[i+1:]...)
+				break
+			}
+		}
+	}
+	if msg.err != nil {
+		m.formErr = fmt.Sprintf("couldn't create %s: %s", msg.name, msg.err)
+		return m, nil
+	}
+
+	cmd := m.toast(fmt.Sprintf("Created %s in %s", msg.name, msg.region))
+	if l == nil {
+		return m, cmd
+	}
+	l.loading = true
+	return m, tea.Batch(cmd, fetchSpacesList(l.client))
+}
+
+func (m model) spacesView() string {
+	l := m.spaces
+	if f := l.create; f != nil && f.regions.Opened() {
+		return f.regions.View()
+	}
+
+	var b strings.Builder
+
+	title := focusedStyle.Render("Spaces")
+	if l.loaded {
+		title += placeholderStyle.Render("  " + pluralize(len(l.buckets), "bucket"))
+	}
+	b.WriteString(title + "\n\n")
+
+	switch {
+	case l.client == nil:
+		fmt.Fprintf(&b, "%s\n", placeholderStyle.Render("Spaces has its own access keys, separate from the API token. Create one under"))
+		fmt.Fprintf(&b, "%s\n", placeholderStyle.Render("API → Spaces Keys in the control panel, then set SPACES_ACCESS_KEY_ID and"))
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("SPACES_SECRET_ACCESS_KEY, or spaces-access-key-id and spaces-secret-access-key in the config file."))
+		b.WriteString(helpStyle.Render(strings.Join([]string{"tab: screens", m.closeScreenHelp()}, " • ")))
+		return b.String()
+	case l.loading && !l.loaded:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading buckets..."))
+	case l.err != nil && !l.loaded:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the buckets: "+l.err.Error()))
+	case len(l.buckets) == 0:
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("There are no buckets in this account yet."))
+	default:
+		fmt.Fprintf(&b, "%s\n\n", l.table.View())
+	}
+
+	switch {
+	case l.loading && l.loaded:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading..."))
+	case l.err != nil && l.loaded:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the buckets: "+l.err.Error()))
+	}
+	if len(l.regionErrs) > 0 {
+		var regions []string
+		for region := range l.regionErrs {
+			regions = append(regions, region)
+		}
+		sort.Strings(regions)
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(fmt.Sprintf("couldn't list the buckets in %s: %s", strings.Join(regions, ", "), l.regionErrs[regions[0]])))
+	}
+	if len(l.creating) > 0 {
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Creating "+strings.Join(l.creating, ", ")+"..."))
+	}
+	if m.notice != "" {
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render(m.notice))
+	}
+	if m.formErr != "" {
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(m.formErr))
+	}
+
+	if f := l.create; f != nil {
+		fmt.Fprintf(&b, "%s\n", focusedStyle.Render("Create a bucket"))
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("Its name is shared with everyone using Spaces in the region, and its files are private."))
+		fmt.Fprintf(&b, "%s\n\n", f.name.View())
+		if f.choosing {
+			fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading regions..."))
+			b.WriteString(helpStyle.Render("esc: back"))
+			return b.String()
+		}
+		b.WriteString(helpStyle.Render("enter: choose its region • esc: cancel"))
+		return b.String()
+	}
+
+	help := []string{"↑/↓: move", "c: create", "r: refresh", "tab: screens", m.closeScreenHelp()}
+	b.WriteString(helpStyle.Render(strings.Join(help, " • ")))
+
+	return b.String()
+}
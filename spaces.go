@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// spacesRegions are the regions Spaces buckets can be created in. Unlike
+// Droplets' regions, the API doesn't list them.
+var spacesRegions = []string{"ams3", "atl1", "blr1", "fra1", "lon1", "nyc3", "sfo2", "sfo3", "sgp1", "syd1", "tor1"}
+
+// spacesSigningRegion is the region requests are signed for. Spaces goes by
+// the endpoint's region instead, and accepts the one S3 tools default to.
+const spacesSigningRegion = "us-east-1"
+
+// spacesClient talks to the S3-compatible Spaces API, which is separate from
+// the DigitalOcean API and signs requests with a Spaces access key rather
+// than taking the API token.
+type spacesClient struct {
+	keyID  string
+	secret string
+	http   *http.Client
+}
+
+// newSpacesClient returns a client for the Spaces access key in
+// SPACES_ACCESS_KEY_ID and SPACES_SECRET_ACCESS_KEY, or else in the config
+// file. It returns nil if there isn't one.
+func newSpacesClient(cfg config) *spacesClient {
+	keyID, secret := os.Getenv("SPACES_ACCESS_KEY_ID"), os.Getenv("SPACES_SECRET_ACCESS_KEY")
+	if keyID == "" || secret == "" {
+		keyID, secret = cfg.SpacesKeyID, cfg.SpacesSecret
+	}
+	if keyID == "" || secret == "" {
+		return nil
+	}
+	return &spacesClient{keyID: keyID, secret: secret, http: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// spacesEndpoint returns the URL of a region's Spaces API.
+func spacesEndpoint(region string) string {
+	return fmt.Sprintf("https://%s.digitaloceanspaces.com", region)
+}
+
+// spacesBucket is a Spaces bucket, in the region it was listed in.
+type spacesBucket struct {
+	Name    string    `xml:"Name"`
+	Created time.Time `xml:"CreationDate"`
+	region  string
+}
+
+// spacesError is an error returned by the Spaces API, e.g.
+// BucketAlreadyExists.
+type spacesError struct {
+	status  int
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+func (e *spacesError) Error() string {
+	switch {
+	case e.Message != "":
+		return e.Message
+	case e.Code != "":
+		return e.Code
+	}
+	return fmt.Sprintf("the Spaces API returned %d %s", e.status, http.StatusText(e.status))
+}
+
+// listBuckets lists the account's buckets in the region.
+func (c *spacesClient) listBuckets(ctx context.Context, region string) ([]spacesBucket, error) {
+	var result struct {
+		Buckets []spacesBucket `xml:"Buckets>Bucket"`
+	}
+	if err := c.do(ctx, http.MethodGet, region, "/", nil, &result); err != nil {
+		return nil, err
+	}
+	for i := range result.Buckets {
+		result.Buckets[i].region = region
+	}
+	return result.Buckets, nil
+}
+
+// createBucket creates a private bucket in the region.
+func (c *spacesClient) createBucket(ctx context.Context, region, name string) error {
+	return c.do(ctx, http.MethodPut, region, "/"+name, http.Header{"X-Amz-Acl": {"private"}}, nil)
+}
+
+// do sends a signed request without a body to the region's endpoint,
+// decoding the XML response into v unless it's nil.
+func (c *spacesClient) do(ctx context.Context, method, region, path string, header http.Header, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, spacesEndpoint(region)+path, nil)
+	if err != nil {
+		return err
+	}
+	for k, vs := range header {
+		req.Header[k] = vs
+	}
+	c.sign(req, time.Now())
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= 300 {
+		e := &spacesError{status: resp.StatusCode}
+		// The body's empty for some errors, leaving the status.
+		_ = xml.Unmarshal(body, e)
+		return e
+	}
+	if v == nil {
+		return nil
+	}
+	return xml.Unmarshal(body, v)
+}
+
+// emptyPayloadHash is the SHA-256 of an empty body, which every request the
+// client sends has.
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// sign adds an AWS Signature Version 4 to the request, signing its host and
+// its x-amz- headers.
+func (c *spacesClient) sign(req *http.Request, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", emptyPayloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k, vs := range req.Header {
+		if k := strings.ToLower(k); strings.HasPrefix(k, "x-amz-") {
+			headers[k] = strings.TrimSpace(strings.Join(vs, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		fmt.Fprintf(&canonicalHeaders, "%s:%s\n", k, headers[k])
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonical := strings.Join([]string{
+		req.Method,
+		path,
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		emptyPayloadHash,
+	}, "\n")
+
+	scope := date + "/" + spacesSigningRegion + "/s3/aws4_request"
+	toSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex(canonical)}, "\n")
+
+	key := []byte("AWS4" + c.secret)
+	for _, part := range []string{date, spacesSigningRegion, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, toSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", c.keyID, scope, signedHeaders, signature))
+}
+
+// canonicalQuery returns the query sorted by key, escaped as Signature
+// Version 4 requires.
+func canonicalQuery(q url.Values) string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, k := range keys {
+		vs := q[k]
+		sort.Strings(vs)
+		for _, v := range vs {
+			pairs = append(pairs, awsEscape(k)+"="+awsEscape(v))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// awsEscape percent-encodes everything but the unreserved characters, with
+// spaces as %20 rather than +.
+func awsEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// origin returns the bucket's hostname, which is what a CDN endpoint serves
+// it from.
+func (b spacesBucket) origin() string {
+	return fmt.Sprintf("%s.%s.digitaloceanspaces.com", b.Name, b.region)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// spec describes a Droplet as it's entered in the form. Templates are saved
+// in this format.
+type spec struct {
+	Name         string   `yaml:"name,omitempty" json:"name,omitempty"`
+	Count        int      `yaml:"count,omitempty" json:"count,omitempty"`
+	Region       string   `yaml:"region,omitempty" json:"region,omitempty"`
+	Size         string   `yaml:"size,omitempty" json:"size,omitempty"`
+	Image        string   `yaml:"image,omitempty" json:"image,omitempty"`
+	SSHKeys      []string `yaml:"ssh-keys,omitempty" json:"ssh_keys,omitempty"`
+	Tags         []string `yaml:"tags,omitempty" json:"tags,omitempty"`
+	UserData     string   `yaml:"user-data,omitempty" json:"user_data,omitempty"`
+	Backups      bool     `yaml:"backups,omitempty" json:"backups,omitempty"`
+	Monitoring   bool     `yaml:"monitoring,omitempty" json:"monitoring,omitempty"`
+	IPv6         bool     `yaml:"ipv6,omitempty" json:"ipv6,omitempty"`
+	VPC          string   `yaml:"vpc,omitempty" json:"vpc,omitempty"`
+	Project      string   `yaml:"project,omitempty" json:"project,omitempty"`
+	Domain       string   `yaml:"domain,omitempty" json:"domain,omitempty"`
+	ReservedIP   string   `yaml:"reserved-ip,omitempty" json:"reserved_ip,omitempty"`
+	Firewalls    []string `yaml:"firewalls,omitempty" json:"firewalls,omitempty"`
+	LoadBalancer string   `yaml:"load-balancer,omitempty" json:"load_balancer,omitempty"`
+	VolumeName   string   `yaml:"volume-name,omitempty" json:"volume_name,omitempty"`
+	VolumeSize   int64    `yaml:"volume-size,omitempty" json:"volume_size,omitempty"`
+}
+
+// spec returns the values currently entered in the form. The VPC is left
+// out if it's the default, since that depends on the region.
+func (m model) spec() spec {
+	s := spec{
+		Name:         m.fields[nameField].Value(),
+		Region:       m.fields[regionField].Value(),
+		Size:         m.fields[sizeField].Value(),
+		Image:        m.fields[imageField].Value(),
+		SSHKeys:      m.fields[keysField].(*multiSelectField).Values(),
+		Tags:         parseTags(m.fields[tagsField].Value()),
+		UserData:     m.fields[userDataField].Value(),
+		Backups:      m.fields[backupsField].(*toggleField).Checked(),
+		Monitoring:   m.fields[monitoringField].(*toggleField).Checked(),
+		IPv6:         m.fields[ipv6Field].(*toggleField).Checked(),
+		Project:      m.projectID(),
+		Domain:       m.fields[domainField].Value(),
+		ReservedIP:   m.fields[reservedIPField].Value(),
+		Firewalls:    m.fields[firewallsField].(*multiSelectField).Values(),
+		LoadBalancer: m.fields[loadBalancerField].Value(),
+		VolumeName:   m.fields[volumeNameField].Value(),
+	}
+	s.Count, _ = strconv.Atoi(m.fields[countField].Value())
+	if vpc := m.fields[vpcField].(*vpcPicker); vpc.Value() != vpc.fallback {
+		s.VPC = vpc.Value()
+	}
+	s.VolumeSize, _ = strconv.ParseInt(m.fields[volumeSizeField].Value(), 10, 64)
+	if strings.TrimSpace(s.UserData) == "" {
+		s.UserData = ""
+	}
+
+	return s
+}
+
+// loadSpec fills in the form from the spec, replacing what was entered.
+// Anything the spec leaves out is reset to its default.
+func (m *model) loadSpec(s spec) tea.Cmd {
+	count := ""
+	if s.Count > 0 {
+		count = strconv.Itoa(s.Count)
+	}
+	volumeSize := ""
+	if s.VolumeSize > 0 {
+		volumeSize = strconv.FormatInt(s.VolumeSize, 10)
+	}
+
+	m.fields[nameField].(*textField).SetValue(s.Name)
+	m.fields[countField].(*textField).SetValue(count)
+	m.fields[regionField].(*selectField).Select(s.Region)
+	// The sizes and VPCs on offer depend on the region.
+	cmd := m.setRegion()
+	m.fields[sizeField].(*sizePicker).Select(s.Size)
+	m.fields[imageField].(*imagePicker).Select(s.Image)
+	m.fields[keysField].(*multiSelectField).CheckMatching(s.SSHKeys)
+	m.fields[tagsField].(*textField).SetValue(strings.Join(s.Tags, ","))
+	m.fields[userDataField].(*userDataEditor).editor.SetValue(s.UserData)
+	m.fields[backupsField].(*toggleField).checked = s.Backups
+	m.fields[monitoringField].(*toggleField).checked = s.Monitoring
+	m.fields[ipv6Field].(*toggleField).checked = s.IPv6
+	m.fields[vpcField].(*vpcPicker).Select(s.VPC)
+	m.fields[projectField].(*selectField).Select(s.Project)
+	m.fields[domainField].(*selectField).Select(s.Domain)
+	m.fields[reservedIPField].(*reservedIPPicker).Select(s.ReservedIP)
+	m.fields[firewallsField].(*multiSelectField).CheckMatching(s.Firewalls)
+	m.fields[loadBalancerField].(*loadBalancerPicker).Select(s.LoadBalancer)
+	m.fields[volumeNameField].(*textField).SetValue(s.VolumeName)
+	m.fields[volumeSizeField].(*textField).SetValue(volumeSize)
+
+	return cmd
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// readSpecFile reads a spec to fill the form in with from the file, or from
+// stdin if path is "-". JSON is read with the spec's snake_case field names,
+// anything else as YAML like the templates. Unknown fields are an error,
+// since a misspelt one would otherwise be silently left at its default.
+func readSpecFile(path string) (spec, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+		path = "stdin"
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return spec{}, err
+	}
+
+	var s spec
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		dec := json.NewDecoder(bytes.NewReader(trimmed))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&s)
+	} else if len(trimmed) > 0 {
+		dec := yaml.NewDecoder(bytes.NewReader(trimmed))
+		dec.KnownFields(true)
+		err = dec.Decode(&s)
+	}
+	if err != nil {
+		return spec{}, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// stdinPiped reports whether something is being piped to stdin, and so may
+// be a spec.
+func stdinPiped() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&(os.ModeNamedPipe|os.ModeCharDevice) == os.ModeNamedPipe
+}
+
+// loaded reports whether everything the form chooses from has loaded, along
+// with the account, so that it can be submitted without being shown.
+func (m model) loaded() bool {
+	if m.login != nil || (m.account.account == nil && m.account.err == nil) {
+		return false
+	}
+	for _, f := range m.fields {
+		if l, ok := f.(interface{ Loaded() bool }); ok && !l.Loaded() {
+			return false
+		}
+	}
+	return true
+}
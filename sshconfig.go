@@ -0,0 +1,137 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+var sshConfigKey = key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "add to ssh config"))
+
+// sshConfigMsg reports that a Host block has been written to the SSH config.
+type sshConfigMsg struct {
+	host string
+	err  error
+}
+
+// sshConfigPath returns the path to the user's SSH config.
+func sshConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".ssh", "config"), nil
+}
+
+// sshConfigMarkers returns the comments that the Host block for the named
+// Droplet is written between, so that it can be found and replaced later.
+func sshConfigMarkers(name string) (begin, end string) {
+	return "# BEGIN " + appName + " " + name, "# END " + appName + " " + name
+}
+
+// sshConfigBlock returns the Host block for logging into the Droplet.
+func (m model) sshConfigBlock(droplet *godo.Droplet) (string, error) {
+	ip, _ := droplet.PublicIPv4()
+	if ip == "" {
+		return "", errors.New("the Droplet has no public IPv4 address")
+	}
+	user := m.defaults.SSHUser
+	if user == "" {
+		user = defaultSSHUser
+	}
+
+	begin, end := sshConfigMarkers(droplet.Name)
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\nHost %s\n", begin, droplet.Name)
+	fmt.Fprintf(&b, "    HostName %s\n", ip)
+	fmt.Fprintf(&b, "    User %s\n", user)
+	if port := m.sshPort(); port != defaultSSHPort {
+		fmt.Fprintf(&b, "    Port %d\n", port)
+	}
+	if m.defaults.SSHIdentity != "" {
+		fmt.Fprintf(&b, "    IdentityFile %s\n", m.defaults.SSHIdentity)
+	}
+	// Unless they're in the user's own known_hosts, point ssh at the keys
+	// that were pinned.
+	if path, err := knownHostsPath(); err == nil && m.hostKeyMode() == hostKeysPin && !m.defaults.SeedKnownHosts {
+		fmt.Fprintf(&b, "    UserKnownHostsFile %s\n", path)
+		fmt.Fprintf(&b, "    StrictHostKeyChecking yes\n")
+	}
+	fmt.Fprintf(&b, "%s\n", end)
+
+	return b.String(), nil
+}
+
+// replaceSSHConfigBlock returns the config with the named Droplet's Host
+// block replaced, or appended if it isn't there yet.
+func replaceSSHConfigBlock(config, name, block string) string {
+	begin, end := sshConfigMarkers(name)
+
+	var kept []string
+	skipping, removed := false, false
+	for _, line := range strings.SplitAfter(config, "\n") {
+		switch strings.TrimSpace(line) {
+		case begin:
+			skipping = true
+			continue
+		case end:
+			skipping, removed = false, true
+			continue
+		}
+		// Drop the blank line that separated the old block from the next.
+		if removed && strings.TrimSpace(line) == "" {
+			removed = false
+			continue
+		}
+		removed = false
+		if !skipping && line != "" {
+			kept = append(kept, line)
+		}
+	}
+
+	config = strings.Join(kept, "")
+	if config != "" && !strings.HasSuffix(config, "\n") {
+		config += "\n"
+	}
+	if config != "" && !strings.HasSuffix(config, "\n\n") {
+		config += "\n"
+	}
+	return config + block
+}
+
+// writeSSHConfig adds a Host block for the Droplet to the user's SSH config,
+// replacing the one written for a Droplet of the same name before.
+func (m model) writeSSHConfig(droplet *godo.Droplet) tea.Cmd {
+	block, err := m.sshConfigBlock(droplet)
+
+	return func() tea.Msg {
+		if err != nil {
+			return sshConfigMsg{err: err}
+		}
+		path, err := sshConfigPath()
+		if err != nil {
+			return sshConfigMsg{err: err}
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return sshConfigMsg{err: err}
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			return sshConfigMsg{err: err}
+		}
+		config := replaceSSHConfigBlock(string(data), droplet.Name, block)
+		if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+			return sshConfigMsg{err: err}
+		}
+
+		return sshConfigMsg{host: droplet.Name}
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+type keyItem struct {
+	godo.Key
+}
+
+func (k keyItem) Title() string       { return k.Name }
+func (k keyItem) Description() string { return k.Fingerprint }
+func (k keyItem) FilterValue() string { return k.Name }
+func (k keyItem) Value() string       { return strconv.Itoa(k.ID) }
+
+type keysMsg []option
+
+// fetchKeys lists the SSH keys on the account.
+func fetchKeys(client *godo.Client) tea.Cmd {
+	return func() tea.Msg {
+		keys, _, err := client.Keys.List(context.Background(), &godo.ListOptions{PerPage: 200})
+		if err != nil {
+			return dropletMsg(dropletErrorMsg(err))
+		}
+
+		opts := make([]option, len(keys))
+		for i, k := range keys {
+			opts[i] = keyItem{k}
+		}
+
+		return keysMsg(opts)
+	}
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+const (
+	// defaultSSHUser is who to log in as unless the config says otherwise.
+	defaultSSHUser = "root"
+	// toastTimeout is how long a toast stays on the screen.
+	toastTimeout = 3 * time.Second
+)
+
+var (
+	sshKey          = key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "ssh"))
+	nextDropletKey  = key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "next Droplet"))
+	quitCreatedKeys = key.NewBinding(key.WithKeys("q", "esc", "enter", "ctrl+c"), key.WithHelp("q", "quit"))
+)
+
+// toastExpiredMsg asks for the toast to be cleared, unless it's since been
+// replaced.
+type toastExpiredMsg string
+
+// sshDoneMsg reports that an SSH session has ended.
+type sshDoneMsg struct {
+	err error
+}
+
+// sshCommand returns the command that logs into the Droplet as the
+// configured user and key, with any extra options, running the remote command
+// if one is given.
+func (m model) sshCommand(droplet *godo.Droplet, options []string, remote ...string) (*exec.Cmd, error) {
+	ip, _ := droplet.PublicIPv4()
+	if ip == "" {
+		return nil, errors.New("the Droplet has no public IPv4 address")
+	}
+
+	user := m.defaults.SSHUser
+	if user == "" {
+		user = defaultSSHUser
+	}
+
+	args := append(m.hostKeyOptions(), options...)
+	if port := m.sshPort(); port != defaultSSHPort {
+		args = append(args, "-p", strconv.Itoa(port))
+	}
+	if m.defaults.SSHIdentity != "" {
+		args = append(args, "-i", m.defaults.SSHIdentity)
+	}
+	args = append(args, user+"@"+ip)
+	args = append(args, remote...)
+
+	return exec.Command("ssh", args...), nil
+}
+
+// ssh hands the terminal over to an SSH session on the chosen Droplet,
+// returning to the success screen when it ends.
+func (m model) ssh() tea.Cmd {
+	if m.hostKeyMode() == hostKeysPin && m.pending[m.chosen].hostKeys == nil {
+		return m.pinForSSH(m.chosen)
+	}
+	cmd, err := m.sshCommand(m.pending[m.chosen].droplet, nil)
+	if err != nil {
+		return func() tea.Msg { return sshDoneMsg{err: err} }
+	}
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return sshDoneMsg{err: err}
+	})
+}
+
+// toast shows the notice for a few seconds.
+func (m *model) toast(notice string) tea.Cmd {
+	m.notice = notice
+	return tea.Tick(toastTimeout, func(time.Time) tea.Msg {
+		return toastExpiredMsg(notice)
+	})
+}
+
+// checkCreated moves on to the success screen once every Droplet is done.
+func (m model) checkCreated() (tea.Model, tea.Cmd) {
+	for _, p := range m.pending {
+		if !p.done {
+			return m, nil
+		}
+	}
+	return m.created()
+}
+
+// created shows the success screen once every Droplet is done, unless none
+// of them could be created.
+func (m model) created() (tea.Model, tea.Cmd) {
+	m.creating = false
+	m.chosen = -1
+	m.nextDroplet()
+	if m.chosen == -1 {
+		err := m.pending[0].err
+		if len(m.pending) > 1 {
+			err = fmt.Errorf("%d of %d Droplets failed", len(m.pending), len(m.pending))
+		}
+		return m.quitWith(createSummary(m.pending), m.logCreation(err))
+	}
+
+	m.succeeded = true
+	cmds := []tea.Cmd{m.logCreation(nil)}
+	if m.defaults.Inventory != "" {
+		cmds = append(cmds, m.writeInventory(m.defaults.Inventory))
+	}
+	m, cmd := m.runSetup(uploadStep)
+	return m, tea.Batch(append(cmds, cmd)...)
+}
+
+// The steps that set up the new Droplets once they're created, in the order
+// they run. Each runs once the one before has finished, since the files
+// uploaded may be used by the bootstrap script, which may set up the app the
+// health check looks for. With a health check, the post-create hook runs for
+// each Droplet as soon as it passes instead.
+const (
+	uploadStep = iota
+	bootstrapStep
+	healthStep
+	hookStep
+)
+
+// runSetup starts the first configured setup step from step on.
+func (m model) runSetup(step int) (model, tea.Cmd) {
+	switch {
+	case step <= uploadStep && len(m.defaults.Uploads) > 0:
+		return m.startUpload()
+	case step <= bootstrapStep && m.defaults.Bootstrap != "":
+		return m.startBootstrap()
+	case step <= healthStep && m.defaults.HealthCheck != "":
+		return m.startHealthChecks()
+	case step <= hookStep && m.defaults.PostCreate != "":
+		return m.startHooks()
+	}
+	return m, nil
+}
+
+// waitsForSSH reports whether the Droplets have to be reachable over SSH
+// before they're reported as created, either because it was asked for or
+// because a setup step needs it.
+func (m model) waitsForSSH() bool {
+	return m.defaults.WaitForSSH || m.defaults.Bootstrap != "" || len(m.defaults.Uploads) > 0
+}
+
+// nextDroplet chooses the next Droplet that was created successfully, for
+// the actions on the success screen.
+func (m *model) nextDroplet() {
+	for i := 1; i <= len(m.pending); i++ {
+		n := (m.chosen + i) % len(m.pending)
+		if m.pending[n].err == nil {
+			m.chosen = n
+			return
+		}
+	}
+}
+
+// updateCreated handles the actions offered on the success screen.
+func (m model) updateCreated(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.confirmSSHConfig {
+		m.confirmSSHConfig = false
+		switch msg.String() {
+		case "y", "Y":
+			return m, m.writeSSHConfig(m.pending[m.chosen].droplet)
+		case "ctrl+c":
+			return m.quitWith(createSummary(m.pending))
+		}
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(msg, sshKey):
+		m.formErr = ""
+		return m, m.ssh()
+	case key.Matches(msg, nextDropletKey):
+		m.nextDroplet()
+	case key.Matches(msg, copyIPKey):
+		m.formErr = ""
+		return m, copyIP(m.pending[m.chosen].droplet)
+	case key.Matches(msg, consoleKey):
+		m.formErr = ""
+		return m, openConsole(m.pending[m.chosen].droplet)
+	case key.Matches(msg, controlPanelKey):
+		m.formErr = ""
+		return m, openInControlPanel(m.pending[m.chosen].droplet)
+	case key.Matches(msg, exportKey):
+		return m.openExport(), nil
+	case key.Matches(msg, sshConfigKey):
+		m.formErr = ""
+		m.notice = ""
+		m.confirmSSHConfig = true
+	case key.Matches(msg, printJSONKey):
+		m.output = outputJSON
+		return m.quitWith(createSummary(m.pending))
+	case key.Matches(msg, quitCreatedKeys):
+		return m.quitWith(createSummary(m.pending))
+	}
+
+	return m, nil
+}
+
+func (m model) createdView() string {
+	var b strings.Builder
+
+	b.WriteString(createSummary(m.pending))
+	if m.notice != "" {
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render(m.notice))
+	}
+	if m.formErr != "" {
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(m.formErr))
+	}
+
+	name := m.pending[m.chosen].droplet.Name
+	if m.confirmSSHConfig {
+		path, _ := sshConfigPath()
+		fmt.Fprintf(&b, "%s\n\n", focusedStyle.Render(fmt.Sprintf("Add Host %s to %s? (y/N)", name, path)))
+		b.WriteString(helpStyle.Render("y: add • n: cancel"))
+
+		return b.String()
+	}
+
+	help := []string{"s: ssh into " + name, "y: copy IP", "c: add to ssh config", "o: open in control panel", "w: web console", "e: export"}
+	if len(m.pending) > 1 {
+		help = append(help, "tab: next Droplet")
+	}
+	help = append(help, "j: quit and print JSON", "q: quit")
+	b.WriteString(helpStyle.Render(strings.Join(help, " • ")))
+
+	return b.String()
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"os"
+	"os/user"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// parseTags splits a comma or space separated list of tags, dropping empty
+// and duplicate entries.
+func parseTags(s string) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, t := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' }) {
+		if !seen[t] {
+			seen[t] = true
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
+
+// defaultAutoTags are added to every Droplet when auto-tag is set in the
+// config without auto-tags.
+var defaultAutoTags = []string{"created-by:{app}", "created-by-user:{user}", "created-at:{time}"}
+
+// invalidTagChars matches what can't appear in a tag.
+var invalidTagChars = regexp.MustCompile(`[^A-Za-z0-9_:-]`)
+
+// autoTags returns the tags from the config to add to every Droplet, with
+// their placeholders filled in:
+//
+//	{app}   bubbletea-droplet
+//	{user}  the local username
+//	{date}  today's date as YYYYMMDD
+//	{time}  the current UTC time as YYYYMMDDhhmmss
+func (m model) autoTags() []string {
+	templates := m.defaults.AutoTags
+	if len(templates) == 0 && m.defaults.AutoTag {
+		templates = defaultAutoTags
+	}
+	if len(templates) == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	r := strings.NewReplacer(
+		"{app}", appName,
+		"{user}", localUsername(),
+		"{date}", now.Format("20060102"),
+		"{time}", now.Format("20060102150405"),
+	)
+
+	tags := make([]string, len(templates))
+	for i, t := range templates {
+		tags[i] = invalidTagChars.ReplaceAllString(r.Replace(t), "-")
+	}
+	return tags
+}
+
+// localUsername returns the name of the user running the program, or
+// "unknown" if it can't be found.
+func localUsername() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return "unknown"
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var teamStyle = focusedStyle.Copy().Bold(true)
+
+// teamsMsg maps context names to the team each one's token belongs to.
+type teamsMsg map[string]string
+
+// fetchTeams looks up the team behind each context's token. A token is
+// scoped to a single team, so the contexts double as the list of teams to
+// choose from. Contexts that can't be looked up are left out.
+func fetchTeams(contexts []authContext, apiURL string) tea.Cmd {
+	return func() tea.Msg {
+		teams := make(teamsMsg)
+		for _, c := range contexts {
+			client, err := newClient(c.token, apiURL)
+			if err != nil {
+				continue
+			}
+			account, _, err := client.Account.Get(context.Background())
+			if err != nil || account.Team == nil {
+				continue
+			}
+			teams[c.name] = account.Team.Name
+		}
+		return teams
+	}
+}
+
+// team returns the name of the team that the Droplet will be created under,
+// or an empty string if it isn't known yet.
+func (m model) team() string {
+	if m.account.account == nil || m.account.account.Team == nil {
+		return ""
+	}
+	return m.account.account.Team.Name
+}
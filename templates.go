@@ -0,0 +1,193 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	saveTemplateKey = key.NewBinding(key.WithKeys("ctrl+s"), key.WithHelp("ctrl+s", "save template"))
+	loadTemplateKey = key.NewBinding(key.WithKeys("ctrl+t"), key.WithHelp("ctrl+t", "load template"))
+)
+
+type templateItem string
+
+func (t templateItem) Title() string       { return string(t) }
+func (t templateItem) Description() string { return "" }
+func (t templateItem) FilterValue() string { return string(t) }
+func (t templateItem) Value() string       { return string(t) }
+
+// templatesMsg lists the saved templates.
+type templatesMsg struct {
+	options []option
+	err     error
+}
+
+// templateMsg carries a template loaded from disk.
+type templateMsg struct {
+	name string
+	spec spec
+	err  error
+}
+
+// templateSavedMsg reports the result of saving a template.
+type templateSavedMsg struct {
+	name string
+	err  error
+}
+
+// templatesDir returns the directory templates are saved in.
+func templatesDir() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "templates"), nil
+}
+
+// checkTemplateName makes sure the name is usable as a file name.
+func checkTemplateName(name string) error {
+	if name == "" || strings.HasPrefix(name, ".") || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("%q isn't a valid template name", name)
+	}
+	return nil
+}
+
+// fetchTemplates lists the saved templates by name.
+func fetchTemplates() tea.Cmd {
+	return func() tea.Msg {
+		dir, err := templatesDir()
+		if err != nil {
+			return templatesMsg{err: err}
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return templatesMsg{err: err}
+		}
+
+		var names []string
+		for _, e := range entries {
+			if name := strings.TrimSuffix(e.Name(), ".yaml"); !e.IsDir() && name != e.Name() {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+
+		opts := make([]option, len(names))
+		for i, n := range names {
+			opts[i] = templateItem(n)
+		}
+		return templatesMsg{options: opts}
+	}
+}
+
+// loadTemplate reads the named template.
+func loadTemplate(name string) tea.Cmd {
+	return func() tea.Msg {
+		dir, err := templatesDir()
+		if err != nil {
+			return templateMsg{err: err}
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, name+".yaml"))
+		if err != nil {
+			return templateMsg{err: err}
+		}
+
+		var s spec
+		if err := yaml.Unmarshal(data, &s); err != nil {
+			return templateMsg{err: fmt.Errorf("reading template %s: %w", name, err)}
+		}
+		return templateMsg{name: name, spec: s}
+	}
+}
+
+// saveTemplate writes the spec as the named template, replacing any existing
+// one.
+func saveTemplate(name string, s spec) tea.Cmd {
+	return func() tea.Msg {
+		dir, err := templatesDir()
+		if err != nil {
+			return templateSavedMsg{err: err}
+		}
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return templateSavedMsg{err: err}
+		}
+
+		data, err := yaml.Marshal(s)
+		if err != nil {
+			return templateSavedMsg{err: err}
+		}
+		if err := os.WriteFile(filepath.Join(dir, name+".yaml"), data, 0o600); err != nil {
+			return templateSavedMsg{err: err}
+		}
+		return templateSavedMsg{name: name}
+	}
+}
+
+func newTemplatePicker() *selectField {
+	f := newSelectField("Template: ", "Load a template", "")
+	f.list.SetShowStatusBar(false)
+	return f
+}
+
+// updateTemplates handles the template picker and the prompt for a template
+// name, returning false if neither is showing.
+func (m model) updateTemplates(msg tea.KeyMsg) (tea.Model, tea.Cmd, bool) {
+	if m.templateName != nil {
+		switch msg.String() {
+		case "ctrl+c":
+			return m, m.quit(), true
+		case "esc":
+			m.templateName = nil
+			return m, nil, true
+		case "enter":
+			name := strings.TrimSpace(m.templateName.Model.Value())
+			if err := checkTemplateName(name); err != nil {
+				m.formErr = err.Error()
+				return m, nil, true
+			}
+			m.templateName = nil
+			return m, saveTemplate(name, m.spec()), true
+		}
+
+		_, cmd := m.templateName.Update(msg)
+		return m, cmd, true
+	}
+
+	if m.templates.Opened() {
+		if msg.String() == "ctrl+c" {
+			return m, m.quit(), true
+		}
+		_, cmd := m.templates.Update(msg)
+		if !m.templates.Opened() && m.templates.selected != nil {
+			return m, loadTemplate(m.templates.Value()), true
+		}
+		return m, cmd, true
+	}
+
+	return m, nil, false
+}
+
+func (m model) templateNameView() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s\n\n", focusedStyle.Render("Save the form as a template"))
+	fmt.Fprintf(&b, "%s\n\n", m.templateName.View())
+	if m.formErr != "" {
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(m.formErr))
+	}
+	b.WriteString(helpStyle.Render("enter: save • esc: cancel"))
+
+	return b.String()
+}
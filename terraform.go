@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/digitalocean/godo"
+)
+
+var terraformLabelChars = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+// terraformLabel turns a Droplet or volume name into a resource label.
+func terraformLabel(name string) string {
+	label := terraformLabelChars.ReplaceAllString(name, "_")
+	if label == "" || (label[0] >= '0' && label[0] <= '9') {
+		label = "_" + label
+	}
+	return label
+}
+
+// hclString quotes s as an HCL string, escaping template sequences so they
+// aren't interpolated.
+func hclString(s string) string {
+	q := strconv.Quote(s)
+	q = strings.ReplaceAll(q, "${", "$${")
+	return strings.ReplaceAll(q, "%{", "%%{")
+}
+
+func hclList(values []string) string {
+	return "[" + strings.Join(values, ", ") + "]"
+}
+
+// terraformExport renders the create request as digitalocean_droplet
+// resources, one per Droplet, along with the volume to attach.
+func (m model) terraformExport() string {
+	var b strings.Builder
+
+	req := m.droplet
+	volumeLabel := ""
+	if m.volume != nil {
+		volumeLabel = terraformLabel(m.volume.Name)
+		writeHCLBlock(&b, "digitalocean_volume", volumeLabel, [][2]string{
+			{"name", hclString(m.volume.Name)},
+			{"region", hclString(m.volume.Region)},
+			{"size", strconv.FormatInt(m.volume.SizeGigaBytes, 10)},
+		}, "")
+	}
+
+	for _, name := range m.names {
+		attrs := [][2]string{
+			{"name", hclString(name)},
+			{"region", hclString(req.Region)},
+			{"size", hclString(req.Size)},
+			{"image", hclString(imageRef(req.Image))},
+		}
+		if keys := sshKeyRefs(req.SSHKeys); len(keys) > 0 {
+			attrs = append(attrs, [2]string{"ssh_keys", hclList(keys)})
+		}
+		if len(req.Tags) > 0 {
+			tags := make([]string, len(req.Tags))
+			for i, t := range req.Tags {
+				tags[i] = hclString(t)
+			}
+			attrs = append(attrs, [2]string{"tags", hclList(tags)})
+		}
+		if req.VPCUUID != "" {
+			attrs = append(attrs, [2]string{"vpc_uuid", hclString(req.VPCUUID)})
+		}
+		if req.Backups {
+			attrs = append(attrs, [2]string{"backups", "true"})
+		}
+		if req.Monitoring {
+			attrs = append(attrs, [2]string{"monitoring", "true"})
+		}
+		if req.IPv6 {
+			attrs = append(attrs, [2]string{"ipv6", "true"})
+		}
+		if volumeLabel != "" {
+			attrs = append(attrs, [2]string{"volume_ids", fmt.Sprintf("[digitalocean_volume.%s.id]", volumeLabel)})
+		}
+
+		writeHCLBlock(&b, "digitalocean_droplet", terraformLabel(name), attrs, req.UserData)
+
+		if domain := m.fields[domainField].Value(); domain != "" {
+			writeHCLBlock(&b, "digitalocean_record", terraformLabel(name), [][2]string{
+				{"domain", hclString(domain)},
+				{"type", hclString("A")},
+				{"name", hclString(recordName(name, domain))},
+				{"value", fmt.Sprintf("digitalocean_droplet.%s.ipv4_address", terraformLabel(name))},
+				{"ttl", strconv.Itoa(dnsTTL)},
+			}, "")
+		}
+
+		dropletID := fmt.Sprintf("digitalocean_droplet.%s.id", terraformLabel(name))
+		switch ip := m.fields[reservedIPField].Value(); ip {
+		case "":
+		case newReservedIP:
+			writeHCLBlock(&b, "digitalocean_reserved_ip", terraformLabel(name), [][2]string{
+				{"region", hclString(req.Region)},
+				{"droplet_id", dropletID},
+			}, "")
+		default:
+			writeHCLBlock(&b, "digitalocean_reserved_ip_assignment", terraformLabel(name), [][2]string{
+				{"ip_address", hclString(ip)},
+				{"droplet_id", dropletID},
+			}, "")
+		}
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// writeHCLBlock writes a resource block with its attributes aligned the way
+// terraform fmt does, followed by the user data as a heredoc if there is any.
+func writeHCLBlock(b *strings.Builder, kind, label string, attrs [][2]string, userData string) {
+	width := 0
+	for _, a := range attrs {
+		if len(a[0]) > width {
+			width = len(a[0])
+		}
+	}
+
+	fmt.Fprintf(b, "resource %q %q {\n", kind, label)
+	for _, a := range attrs {
+		fmt.Fprintf(b, "  %-*s = %s\n", width, a[0], a[1])
+	}
+	if userData != "" {
+		data := strings.ReplaceAll(strings.ReplaceAll(userData, "${", "$${"), "%{", "%%{")
+		if !strings.HasSuffix(data, "\n") {
+			data += "\n"
+		}
+		fmt.Fprintf(b, "\n  user_data = <<-EOT\n%sEOT\n", data)
+	}
+	b.WriteString("}\n\n")
+}
+
+// imageRef returns the slug or ID that the image was chosen by.
+func imageRef(image godo.DropletCreateImage) string {
+	if image.Slug != "" {
+		return image.Slug
+	}
+	return strconv.Itoa(image.ID)
+}
+
+// sshKeyRefs returns the IDs or fingerprints that the keys were chosen by,
+// as HCL values.
+func sshKeyRefs(keys []godo.DropletCreateSSHKey) []string {
+	refs := make([]string, len(keys))
+	for i, k := range keys {
+		if k.Fingerprint != "" {
+			refs[i] = hclString(k.Fingerprint)
+		} else {
+			refs[i] = strconv.Itoa(k.ID)
+		}
+	}
+	return refs
+}
@@ -0,0 +1,342 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pkg/sftp"
+)
+
+// upload is a local file or directory to copy to new Droplets, and where to
+// put it. A directory is copied with everything in it.
+type upload struct {
+	From string `yaml:"from"`
+	To   string `yaml:"to"`
+}
+
+// parseUpload parses an upload given on the command line as from:to.
+func parseUpload(s string) (upload, error) {
+	i := strings.LastIndex(s, ":")
+	if i <= 0 || i == len(s)-1 {
+		return upload{}, fmt.Errorf("upload %q must be given as local:remote", s)
+	}
+	return upload{From: s[:i], To: s[i+1:]}, nil
+}
+
+// uploadFile is a single file or directory to create on the Droplets.
+type uploadFile struct {
+	local, remote string
+	mode          fs.FileMode
+	size          int64
+}
+
+// uploadFiles lists everything to create on each Droplet for the uploads,
+// directories before what's in them.
+func uploadFiles(uploads []upload) ([]uploadFile, error) {
+	var files []uploadFile
+	for _, u := range uploads {
+		err := filepath.WalkDir(u.From, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			if !info.Mode().IsRegular() && !info.IsDir() {
+				return nil
+			}
+
+			rel, err := filepath.Rel(u.From, p)
+			if err != nil {
+				return err
+			}
+			files = append(files, uploadFile{
+				local:  p,
+				remote: path.Join(u.To, filepath.ToSlash(rel)),
+				mode:   info.Mode(),
+				size:   info.Size(),
+			})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// uploadRun is the files being copied to the new Droplets, with how far
+// along it is.
+type uploadRun struct {
+	files []uploadFile
+	// count is how many of the files aren't directories, and total how
+	// many bytes are sent to all the Droplets together.
+	count    int
+	total    int64
+	sent     int64
+	progress progress.Model
+	// file is the last file that was sent to, for display.
+	file string
+	// out carries the progress and exit of every run, and running is how
+	// many haven't exited yet.
+	out     chan tea.Msg
+	running int
+	err     error
+}
+
+// uploadProgressMsg reports that n more bytes of the file have been sent.
+type uploadProgressMsg struct {
+	file string
+	n    int64
+}
+
+// uploadDoneMsg reports that copying to the Droplet at index has finished.
+type uploadDoneMsg struct {
+	index int
+	err   error
+}
+
+// startUpload copies the configured files to every Droplet that was
+// created, over SFTP.
+func (m model) startUpload() (model, tea.Cmd) {
+	run := &uploadRun{
+		progress: progress.New(progress.WithDefaultGradient(), progress.WithWidth(uploadBarWidth(m.width))),
+		out:      make(chan tea.Msg, 64),
+	}
+	m.upload = run
+
+	files, err := uploadFiles(m.defaults.Uploads)
+	if err != nil {
+		run.err = err
+		for i := range m.pending {
+			if m.pending[i].err == nil {
+				m.pending[i].uploadErr = err
+			}
+		}
+		return m, nil
+	}
+	run.files = files
+	var size int64
+	for _, f := range files {
+		if !f.mode.IsDir() {
+			run.count++
+			size += f.size
+		}
+	}
+
+	var cmds []tea.Cmd
+	for i, p := range m.pending {
+		if p.err != nil {
+			continue
+		}
+		m.pending[i].uploaded = true
+		run.running++
+		run.total += size
+		cmds = append(cmds, m.runUpload(i, run.out))
+	}
+
+	return m, tea.Batch(append(cmds, run.listen())...)
+}
+
+// runUpload copies the files to the Droplet at index, through the SFTP
+// subsystem of an SSH session so that the usual SSH config and agent apply.
+func (m model) runUpload(index int, out chan<- tea.Msg) tea.Cmd {
+	droplet := m.pending[index].droplet
+	files := m.upload.files
+
+	return func() tea.Msg {
+		done := func(err error) tea.Msg {
+			out <- uploadDoneMsg{index: index, err: err}
+			return nil
+		}
+
+		options := append(append([]string(nil), bootstrapSSHOptions...), "-s")
+		cmd, err := m.sshCommand(droplet, options, "sftp")
+		if err != nil {
+			return done(err)
+		}
+		w, err := cmd.StdinPipe()
+		if err != nil {
+			return done(err)
+		}
+		r, err := cmd.StdoutPipe()
+		if err != nil {
+			return done(err)
+		}
+		if err := cmd.Start(); err != nil {
+			return done(err)
+		}
+		client, err := sftp.NewClientPipe(r, w)
+		if err != nil {
+			cmd.Wait()
+			return done(err)
+		}
+
+		err = sendFiles(client, files, out)
+		client.Close()
+		if waitErr := cmd.Wait(); err == nil {
+			err = waitErr
+		}
+		return done(err)
+	}
+}
+
+// sendFiles creates each of the files on the other end of the client,
+// reporting progress to out as it goes.
+func sendFiles(client *sftp.Client, files []uploadFile, out chan<- tea.Msg) error {
+	for _, f := range files {
+		if f.mode.IsDir() {
+			if err := client.MkdirAll(f.remote); err != nil {
+				return fmt.Errorf("%s: %w", f.remote, err)
+			}
+			continue
+		}
+		if err := client.MkdirAll(path.Dir(f.remote)); err != nil {
+			return fmt.Errorf("%s: %w", path.Dir(f.remote), err)
+		}
+		if err := sendFile(client, f, out); err != nil {
+			return fmt.Errorf("%s: %w", f.remote, err)
+		}
+	}
+	return nil
+}
+
+func sendFile(client *sftp.Client, f uploadFile, out chan<- tea.Msg) error {
+	src, err := os.Open(f.local)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := client.Create(f.remote)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, &progressReader{r: src, file: f.remote, out: out}); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	return client.Chmod(f.remote, f.mode.Perm())
+}
+
+// progressReader reports how much of the file has been read.
+type progressReader struct {
+	r    io.Reader
+	file string
+	out  chan<- tea.Msg
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.out <- uploadProgressMsg{file: p.file, n: int64(n)}
+	}
+	return n, err
+}
+
+// uploadBarWidth is how wide the progress bar is on a screen of the width.
+func uploadBarWidth(width int) int {
+	if width > 14 {
+		return width - 4
+	}
+	return 40
+}
+
+// listen waits for the next message from the running uploads.
+func (r *uploadRun) listen() tea.Cmd {
+	return func() tea.Msg {
+		return <-r.out
+	}
+}
+
+func (m model) updateUpload(msg tea.Msg) (tea.Model, tea.Cmd) {
+	run := m.upload
+
+	switch msg := msg.(type) {
+	case uploadProgressMsg:
+		run.sent += msg.n
+		run.file = msg.file
+		return m, run.listen()
+
+	case uploadDoneMsg:
+		m.pending[msg.index].uploadErr = msg.err
+		if msg.err != nil && run.err == nil {
+			run.err = msg.err
+		}
+		run.running--
+		if run.running > 0 {
+			return m, run.listen()
+		}
+		if run.err == nil {
+			m.upload = nil
+			return m.runSetup(bootstrapStep)
+		}
+		return m, nil
+
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		run.progress.Width = uploadBarWidth(msg.Width)
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return m.quitWith(createSummary(m.pending))
+		case "enter", "esc", "q":
+			// Carry on with the rest of the setup on the Droplets that
+			// the files made it to, see settingUp.
+			if run.running == 0 {
+				m.upload = nil
+				return m.runSetup(bootstrapStep)
+			}
+		}
+	}
+
+	return m, nil
+}
+
+func (m model) uploadView() string {
+	var b strings.Builder
+
+	run := m.upload
+	switch {
+	case run.running > 0:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render(fmt.Sprintf("Uploading %d files...", run.count)))
+	case run.err != nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("Couldn't upload the files: "+run.err.Error()))
+	default:
+		fmt.Fprintf(&b, "%s\n\n", focusedStyle.Render("Uploaded the files"))
+	}
+
+	percent := 1.0
+	if run.total > 0 {
+		percent = float64(run.sent) / float64(run.total)
+	}
+	fmt.Fprintf(&b, "  %s\n", run.progress.ViewAs(percent))
+	fmt.Fprintf(&b, "  %s\n\n", placeholderStyle.Render(run.file))
+
+	if run.running > 0 {
+		b.WriteString(helpStyle.Render("ctrl+c: quit"))
+	} else {
+		b.WriteString(helpStyle.Render("enter: continue"))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/filepicker"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxUserDataSize is the largest user data the API accepts, in bytes.
+const maxUserDataSize = 64 * 1024
+
+var openFileKey = key.NewBinding(key.WithKeys("ctrl+o"), key.WithHelp("ctrl+o", "load from file"))
+
+// userDataEditor is a form row that opens a full screen editor for the
+// Droplet's cloud-init user data. Esc returns to the form.
+type userDataEditor struct {
+	prompt  string
+	editor  textarea.Model
+	files   filepicker.Model
+	library *selectField
+	focused bool
+	open    bool
+	picking bool
+	err     error
+	// templated is set while the user data came from a template whose
+	// placeholders haven't all been replaced.
+	templated bool
+}
+
+type userDataMsg struct {
+	data string
+	err  error
+}
+
+// loadUserData reads the user data from a local file.
+func loadUserData(path string) tea.Cmd {
+	return func() tea.Msg {
+		info, err := os.Stat(path)
+		if err != nil {
+			return userDataMsg{err: err}
+		}
+		if info.Size() > maxUserDataSize {
+			return userDataMsg{err: fmt.Errorf("%s is %s; user data is limited to %s", path, formatBytes(int(info.Size())), formatBytes(maxUserDataSize))}
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return userDataMsg{err: err}
+		}
+
+		return userDataMsg{data: string(data)}
+	}
+}
+
+func newUserDataEditor() *userDataEditor {
+	t := textarea.New()
+	t.Placeholder = "#cloud-config"
+	t.CharLimit = 0
+	t.MaxHeight = 0
+	t.Cursor.Style = cursorStyle
+	t.FocusedStyle.Prompt = focusedStyle
+	t.FocusedStyle.LineNumber = blurredStyle
+	t.FocusedStyle.CursorLineNumber = focusedStyle
+
+	fp := filepicker.New()
+	fp.Styles.Cursor = focusedStyle
+	fp.Styles.Selected = focusedStyle
+	fp.Styles.Directory = focusedStyle.Copy().Bold(true)
+	// Esc leaves the file picker rather than moving to the parent directory.
+	fp.KeyMap.Back = key.NewBinding(key.WithKeys("h", "backspace", "left"), key.WithHelp("h", "back"))
+
+	return &userDataEditor{
+		prompt:  "User data: ",
+		editor:  t,
+		files:   fp,
+		library: newCloudInitLibrary(),
+	}
+}
+
+func (f *userDataEditor) Focus() tea.Cmd {
+	f.focused = true
+	return nil
+}
+
+func (f *userDataEditor) Blur() {
+	f.focused = false
+}
+
+func (f *userDataEditor) Open() {
+	f.open = true
+	f.editor.Focus()
+}
+
+func (f *userDataEditor) Opened() bool {
+	return f.open
+}
+
+func (f *userDataEditor) SetSize(width, height int) {
+	f.editor.SetWidth(width)
+	// Leave room for the title and help lines.
+	f.editor.SetHeight(height - 4)
+	f.library.SetSize(width, height)
+}
+
+// SetValue replaces the user data, e.g. with a template from the library,
+// noting the placeholders that are left to fill in by hand.
+func (f *userDataEditor) SetValue(data string, unfilled []string) {
+	f.editor.SetValue(data)
+	f.setUnfilled(unfilled)
+}
+
+// setUnfilled notes the template placeholders that are left to replace.
+func (f *userDataEditor) setUnfilled(unfilled []string) {
+	f.err = nil
+	f.templated = len(unfilled) > 0
+	if f.templated {
+		f.err = fmt.Errorf("replace %s before creating", strings.Join(unfilled, ", "))
+	}
+}
+
+func (f *userDataEditor) Update(msg tea.Msg) (field, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case userDataMsg:
+		f.err = msg.err
+		if msg.err == nil {
+			f.editor.SetValue(msg.data)
+			f.templated = false
+		}
+		return f, nil
+
+	case tea.KeyMsg:
+		if !f.open {
+			return f, nil
+		}
+
+		if f.library.Opened() {
+			f.library.Update(msg)
+			if f.library.Opened() || f.library.selected == nil {
+				return f, nil
+			}
+			return f, pickCloudInit(f.library.Value())
+		}
+
+		if f.picking {
+			if msg.String() == "esc" {
+				f.picking = false
+				return f, nil
+			}
+
+			f.files, cmd = f.files.Update(msg)
+			if ok, path := f.files.DidSelectFile(msg); ok {
+				f.picking = false
+				return f, loadUserData(path)
+			}
+			return f, cmd
+		}
+
+		switch {
+		case msg.String() == "esc":
+			f.open = false
+			f.editor.Blur()
+			return f, nil
+		case key.Matches(msg, openFileKey):
+			f.picking = true
+			f.err = nil
+			return f, f.files.Init()
+		case key.Matches(msg, libraryKey):
+			f.library.selected = nil
+			f.library.Open()
+			return f, nil
+		}
+
+		before := f.Value()
+		f.editor, cmd = f.editor.Update(msg)
+		if f.Value() != before {
+			// Editing by hand moves on from a file that failed to load,
+			// and may fill in what the template left.
+			if f.templated {
+				f.setUnfilled(cloudInitPlaceholder.FindAllString(f.Value(), -1))
+			} else {
+				f.err = nil
+			}
+		}
+		return f, cmd
+	}
+
+	// The file picker reads directories in the background, so pass other
+	// messages through even while it isn't showing.
+	f.files, cmd = f.files.Update(msg)
+	if f.open {
+		var editorCmd tea.Cmd
+		f.editor, editorCmd = f.editor.Update(msg)
+		cmd = tea.Batch(cmd, editorCmd)
+	}
+	return f, cmd
+}
+
+func (f *userDataEditor) View() string {
+	if f.open && f.library.Opened() {
+		return f.library.View()
+	}
+
+	if f.open && f.picking {
+		var b strings.Builder
+		fmt.Fprintf(&b, "%s\n\n", focusedStyle.Render("Load user data from "+f.files.CurrentDirectory))
+		fmt.Fprintf(&b, "%s\n", f.files.View())
+		b.WriteString(helpStyle.Render("enter: open/select • h: parent directory • esc: cancel"))
+
+		return b.String()
+	}
+
+	if f.open {
+		var b strings.Builder
+		fmt.Fprintf(&b, "%s  %s\n\n", focusedStyle.Render("Cloud-init user data"), f.sizeView())
+		fmt.Fprintf(&b, "%s\n\n", f.editor.View())
+		if f.err != nil {
+			fmt.Fprintf(&b, "%s\n", errorStyle.Render(f.err.Error()))
+		}
+		b.WriteString(helpStyle.Render("esc: done • ctrl+o: load from file • ctrl+l: template library"))
+
+		return b.String()
+	}
+
+	prompt := noStyle
+	value := placeholderStyle
+	if f.focused {
+		prompt = focusedStyle
+		value = focusedStyle
+	}
+
+	v := f.summary()
+	switch {
+	case v == "":
+		v = "none"
+		value = placeholderStyle
+	case len(f.Value()) > maxUserDataSize:
+		v += " exceeds " + formatBytes(maxUserDataSize)
+		value = errorStyle
+	}
+
+	return prompt.Render(f.prompt) + value.Render(v)
+}
+
+// sizeView shows how much of the user data limit is in use.
+func (f *userDataEditor) sizeView() string {
+	size := len(f.Value())
+	v := fmt.Sprintf("%s / %s", formatBytes(size), formatBytes(maxUserDataSize))
+	if size > maxUserDataSize {
+		return errorStyle.Render(v)
+	}
+	return helpStyle.Render(v)
+}
+
+// validate returns why the user data can't be sent, if it can't.
+func (f *userDataEditor) validate() string {
+	if size := len(f.Value()); size > maxUserDataSize {
+		return fmt.Sprintf("user data is %s over the %s limit", formatBytes(size-maxUserDataSize), formatBytes(maxUserDataSize))
+	}
+	if f.err != nil {
+		return f.err.Error()
+	}
+	return ""
+}
+
+// summary describes the user data in a single line for the form.
+func (f *userDataEditor) summary() string {
+	if strings.TrimSpace(f.Value()) == "" {
+		return ""
+	}
+
+	lines := f.editor.LineCount()
+	first := strings.SplitN(f.Value(), "\n", 2)[0]
+	if lines == 1 {
+		return first
+	}
+	return fmt.Sprintf("%s (%d lines)", first, lines)
+}
+
+func (f *userDataEditor) Value() string {
+	return f.editor.Value()
+}
+
+func formatBytes(n int) string {
+	if n < 1024 {
+		return fmt.Sprintf("%d B", n)
+	}
+	return fmt.Sprintf("%.1f KB", float64(n)/1024)
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// validateSlugs checks the chosen region, size and image against the
+// catalogs fetched from the API, returning an error message per field.
+// Catalogs that haven't loaded yet are skipped.
+func (m model) validateSlugs() map[int]string {
+	errs := make(map[int]string)
+
+	region := m.fields[regionField].(*selectField)
+	if !region.loading {
+		if msg := checkSlug("region", region.Value(), optionValues(region.options())); msg != "" {
+			errs[regionField] = msg
+		}
+	}
+
+	size := m.fields[sizeField].(*sizePicker)
+	if size.sizes != nil {
+		var slugs, inRegion []string
+		for _, s := range size.sizes {
+			slugs = append(slugs, s.Slug)
+			if contains(s.Regions, region.Value()) {
+				inRegion = append(inRegion, s.Slug)
+			}
+		}
+		if msg := checkSlug("size", size.Value(), slugs); msg != "" {
+			errs[sizeField] = msg
+		} else if !contains(inRegion, size.Value()) {
+			errs[sizeField] = fmt.Sprintf("size %s is not available in %s", size.Value(), region.Value())
+		}
+	}
+
+	image := m.fields[imageField].(*imagePicker)
+	if image.images != nil {
+		var values []string
+		for _, i := range image.images {
+			values = append(values, i.Value())
+		}
+		if msg := checkSlug("image", image.Value(), values); msg != "" {
+			errs[imageField] = msg
+		} else if msg := checkImage(image.Value(), image.images, region.Value(), size); msg != "" {
+			errs[imageField] = msg
+		}
+	}
+
+	return errs
+}
+
+// checkImage returns an error message if the account's own image or
+// snapshot can't be used in the region, or needs a bigger disk than the
+// size has.
+func checkImage(value string, images []imageItem, region string, size *sizePicker) string {
+	for _, i := range images {
+		if i.Value() != value || !i.private() {
+			continue
+		}
+		if !contains(i.Regions, region) {
+			return fmt.Sprintf("image %s is not available in %s; it's in %s", i.Name, region, strings.Join(i.Regions, ", "))
+		}
+		if s, ok := size.Size(); ok && s.Disk < i.MinDiskSize {
+			return fmt.Sprintf("image %s needs at least a %s disk, but size %s has %s", i.Name, formatDisk(i.MinDiskSize), s.Slug, formatDisk(s.Disk))
+		}
+	}
+	return ""
+}
+
+// checkSlug returns an error message if value isn't one of the valid slugs,
+// suggesting the closest match.
+func checkSlug(kind, value string, valid []string) string {
+	if contains(valid, value) {
+		return ""
+	}
+
+	msg := fmt.Sprintf("%s %s not found", kind, value)
+	if s := closest(value, valid); s != "" {
+		msg += fmt.Sprintf(" — did you mean %s?", s)
+	}
+	return msg
+}
+
+// closest returns the candidate with the smallest edit distance to s, as long
+// as it's near enough to plausibly be a typo.
+func closest(s string, candidates []string) string {
+	best, bestDist := "", len(s)/2+1
+	for _, c := range candidates {
+		if d := levenshtein(s, c); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+func optionValues(opts []option) []string {
+	values := make([]string, len(opts))
+	for i, o := range opts {
+		values[i] = o.Value()
+	}
+	return values
+}
+
+// validateName checks each Droplet name the form would create is a valid
+// hostname. If the count isn't valid yet, only the first name is checked.
+func (m model) validateName() string {
+	name := m.fields[nameField].Value()
+	names, err := dropletNames(name, m.fields[countField].Value())
+	if err != nil {
+		names = []string{name}
+		if strings.Contains(name, "%") {
+			names[0] = fmt.Sprintf(name, 1)
+		}
+	}
+
+	for _, n := range names {
+		if msg := checkHostname(n); msg != "" {
+			return msg
+		}
+	}
+	return ""
+}
+
+// checkHostname returns an error message unless name is a valid RFC 1123
+// hostname, which is what the API requires of Droplet names.
+func checkHostname(name string) string {
+	switch {
+	case len(name) > 253:
+		return fmt.Sprintf("name %q is longer than 253 characters", name)
+	case strings.HasSuffix(name, "."):
+		return fmt.Sprintf("name %q must not end with a dot", name)
+	}
+
+	for _, label := range strings.Split(name, ".") {
+		switch {
+		case label == "":
+			return fmt.Sprintf("name %q must not contain empty labels", name)
+		case len(label) > 63:
+			return fmt.Sprintf("name %q has a label longer than 63 characters", name)
+		case strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-"):
+			return fmt.Sprintf("name %q has a label that starts or ends with a hyphen", name)
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-') {
+				return fmt.Sprintf("name %q may only contain letters, numbers, hyphens and dots", name)
+			}
+		}
+	}
+
+	return ""
+}
+
+// checkBucketName returns why name can't be a Spaces bucket's name, or an
+// empty string if it can. Bucket names are part of their URLs, so they're
+// lower case.
+func checkBucketName(name string) string {
+	switch {
+	case len(name) < 3 || len(name) > 63:
+		return fmt.Sprintf("bucket name %q must be 3 to 63 characters long", name)
+	case strings.HasPrefix(name, "-") || strings.HasSuffix(name, "-"):
+		return fmt.Sprintf("bucket name %q must not start or end with a hyphen", name)
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-') {
+			return fmt.Sprintf("bucket name %q may only contain lower case letters, numbers and hyphens", name)
+		}
+	}
+	return ""
+}
+
+// checkVPCRange returns why cidr can't be a VPC's IP range, or an empty
+// string if it can. VPCs take private IPv4 ranges from /16 to /24, and an
+// empty range leaves the API to choose one.
+func checkVPCRange(cidr string) string {
+	if cidr == "" {
+		return ""
+	}
+	ip, network, err := net.ParseCIDR(cidr)
+	if err != nil || ip.To4() == nil {
+		return fmt.Sprintf("%q isn't an IPv4 range, like 10.10.10.0/24", cidr)
+	}
+	if !ip.IsPrivate() {
+		return fmt.Sprintf("%s isn't a private range; use one in 10.0.0.0/8, 172.16.0.0/12 or 192.168.0.0/16", cidr)
+	}
+	if ones, _ := network.Mask.Size(); ones < 16 || ones > 24 {
+		return fmt.Sprintf("%s is a /%d; VPCs are between /16 and /24", cidr, ones)
+	}
+	if !ip.Equal(network.IP) {
+		return fmt.Sprintf("%s doesn't start its range; did you mean %s?", cidr, network)
+	}
+	return ""
+}
@@ -0,0 +1,381 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+var (
+	createVolumeKey = key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "create"))
+	resizeVolumeKey = key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "resize"))
+)
+
+// volumesPerPage is how many volumes the volumes screen fetches at a time.
+const volumesPerPage = 20
+
+// volumesMsg carries a page of the account's volumes, along with how many
+// there are in all and the names of the Droplets they're attached to.
+type volumesMsg struct {
+	page     int
+	volumes  []godo.Volume
+	droplets map[int]string
+	total    int
+	err      error
+}
+
+// fetchVolumes lists a page of the account's volumes, counting from 1.
+func fetchVolumes(client *godo.Client, page int) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+
+		volumes, resp, err := client.Storage.ListVolumes(ctx, &godo.ListVolumeParams{
+			ListOptions: &godo.ListOptions{Page: page, PerPage: volumesPerPage},
+		})
+		if err != nil {
+			return volumesMsg{page: page, err: err}
+		}
+
+		total := len(volumes)
+		if resp.Meta != nil {
+			total = resp.Meta.Total
+		}
+		return volumesMsg{page: page, volumes: volumes, droplets: attachedDroplets(ctx, client, volumes), total: total}
+	}
+}
+
+// attachedDroplets looks up the names of the Droplets the volumes are
+// attached to, by ID. Droplets that can't be looked up are left out.
+func attachedDroplets(ctx context.Context, client *godo.Client, volumes []godo.Volume) map[int]string {
+	names := make(map[int]string)
+	for _, v := range volumes {
+		for _, id := range v.DropletIDs {
+			if _, ok := names[id]; ok {
+				continue
+			}
+			if droplet, _, err := client.Droplets.Get(ctx, id); err == nil {
+				names[id] = droplet.Name
+			}
+		}
+	}
+	return names
+}
+
+// volumeDoneMsg reports that a change to a volume has finished. verb and
+// done describe it, e.g. "resize" and "Resized".
+type volumeDoneMsg struct {
+	id   string
+	name string
+	verb string
+	done string
+	err  error
+}
+
+// volumeList is the screen listing the account's volumes, a page at a time.
+type volumeList struct {
+	table    table.Model
+	volumes  []godo.Volume
+	droplets map[int]string
+	page     int
+	total    int
+	loading  bool
+	err      error
+	// create is set while creating a volume, confirmDelete while asking to
+	// confirm deleting one, resize while choosing one's new size and attach
+	// while attaching or detaching one.
+	create        *volumeCreate
+	confirmDelete *volumeDeletePrompt
+	resize        *volumeResizePrompt
+	attach        *volumeAttachFlow
+	// busy holds the status to show for the volumes that are being changed,
+	// by ID, and creating the names of those being created, until they're
+	// done.
+	busy     map[string]string
+	creating []string
+}
+
+func newVolumeList(width, height int) *volumeList {
+	l := &volumeList{
+		page: 1,
+		busy: make(map[string]string),
+		table: newListTable([]table.Column{
+			{Title: "Name", Width: 28},
+			{Title: "Region", Width: 8},
+			{Title: "Size", Width: 8},
+			{Title: "Price", Width: 10},
+			{Title: "Status", Width: 10},
+			{Title: "Droplet", Width: 24},
+		}),
+	}
+	l.SetSize(width, height)
+	return l
+}
+
+// SetSize fits the table to the screen, under the title and above the help.
+func (l *volumeList) SetSize(width, height int) {
+	setTableSize(&l.table, width, height)
+	if l.create != nil && l.create.regions != nil {
+		l.create.regions.SetSize(width, height)
+	}
+	if l.attach != nil && l.attach.droplets != nil {
+		l.attach.droplets.SetSize(width, height)
+	}
+}
+
+// pages returns how many pages of volumes there are.
+func (l *volumeList) pages() int {
+	return pageCount(l.total, volumesPerPage)
+}
+
+// setVolumes shows a page of volumes once it's been fetched.
+func (m model) setVolumes(msg volumesMsg) (tea.Model, tea.Cmd) {
+	l := m.volumes
+	if l == nil {
+		return m, nil
+	}
+	// The last page was emptied, e.g. by deleting, so go back one.
+	if msg.err == nil && len(msg.volumes) == 0 && msg.page > 1 {
+		return m, fetchVolumes(m.client, msg.page-1)
+	}
+
+	l.loading = false
+	l.err = msg.err
+	if msg.err != nil {
+		return m, nil
+	}
+	l.page, l.volumes, l.droplets, l.total = msg.page, msg.volumes, msg.droplets, msg.total
+	l.setRows()
+	return m, nil
+}
+
+// setRows fills the table in from the volumes.
+func (l *volumeList) setRows() {
+	rows := make([]table.Row, len(l.volumes))
+	for i, v := range l.volumes {
+		region := ""
+		if v.Region != nil {
+			region = v.Region.Slug
+		}
+		status := "available"
+		if len(v.DropletIDs) > 0 {
+			status = "attached"
+		}
+		if s, ok := l.busy[v.ID]; ok {
+			status = s + "…"
+		}
+		rows[i] = table.Row{v.Name, region, fmt.Sprintf("%d GB", v.SizeGigaBytes), fmt.Sprintf("$%.2f/mo", volumePrice(v.SizeGigaBytes)), status, l.attachedTo(v)}
+	}
+	l.table.SetRows(rows)
+	if l.table.Cursor() >= len(rows) {
+		l.table.GotoTop()
+	}
+}
+
+// attachedTo names the Droplets the volume is attached to.
+func (l *volumeList) attachedTo(v godo.Volume) string {
+	var names []string
+	for _, id := range v.DropletIDs {
+		names = append(names, l.dropletName(id))
+	}
+	return strings.Join(names, ", ")
+}
+
+// dropletName names the Droplet a volume is attached to, falling back to its
+// ID.
+func (l *volumeList) dropletName(id int) string {
+	if name, ok := l.droplets[id]; ok {
+		return name
+	}
+	return strconv.Itoa(id)
+}
+
+// selected returns the volume under the cursor, unless there are none or
+// it's busy.
+func (l *volumeList) selected() (godo.Volume, bool) {
+	i := l.table.Cursor()
+	if i < 0 || i >= len(l.volumes) {
+		return godo.Volume{}, false
+	}
+	_, busy := l.busy[l.volumes[i].ID]
+	return l.volumes[i], !busy
+}
+
+// openVolumes shows the list of volumes, fetching its first page.
+func (m model) openVolumes() (model, tea.Cmd) {
+	m.volumes = newVolumeList(m.width, m.height-1)
+	m.volumes.loading = true
+	m.notice = ""
+	m.formErr = ""
+	return m, tea.Batch(fetchVolumes(m.client, 1), m.spinner.Tick)
+}
+
+func (m model) updateVolumes(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.volumes
+	if l.create != nil {
+		return m.updateVolumeCreate(msg)
+	}
+	if l.confirmDelete != nil {
+		return m.updateVolumeDelete(msg)
+	}
+	if l.resize != nil {
+		return m.updateVolumeResize(msg)
+	}
+	if l.attach != nil {
+		return m.updateVolumeAttach(msg)
+	}
+
+	switch {
+	case msg.String() == "ctrl+c":
+		return m, m.quit()
+	case key.Matches(msg, closeListKeys):
+		return m.closeScreen()
+	case key.Matches(msg, screensKey):
+		return m.openScreens()
+	case key.Matches(msg, createVolumeKey):
+		return m.openVolumeCreate()
+	case l.loading:
+		return m, nil
+	case key.Matches(msg, deleteKey):
+		return m.promptVolumeDelete()
+	case key.Matches(msg, resizeVolumeKey):
+		return m.openVolumeResize()
+	case key.Matches(msg, attachKey):
+		return m.openVolumeAttach()
+	case key.Matches(msg, refreshListKey):
+		l.loading = true
+		return m, fetchVolumes(m.client, l.page)
+	case key.Matches(msg, nextPageKey) && l.page < l.pages():
+		l.loading = true
+		return m, fetchVolumes(m.client, l.page+1)
+	case key.Matches(msg, prevPageKey) && l.page > 1:
+		l.loading = true
+		return m, fetchVolumes(m.client, l.page-1)
+	}
+
+	var cmd tea.Cmd
+	l.table, cmd = l.table.Update(msg)
+	return m, cmd
+}
+
+// volumeDone refreshes the list once a change to a volume has finished.
+func (m model) volumeDone(msg volumeDoneMsg) (tea.Model, tea.Cmd) {
+	l := m.volumes
+	if l != nil {
+		delete(l.busy, msg.id)
+		if msg.verb == "create" {
+			for i, name := range l.creating {
+				if name == msg.name {
+					l.creating = append(l.creating[:i:i], l.creating[i+1:]...)
+					break
+				}
+			}
+		}
+		l.setRows()
+	}
+	if msg.err != nil {
+		m.formErr = fmt.Sprintf("couldn't %s %s: %s", msg.verb, msg.name, msg.err)
+		return m, nil
+	}
+
+	cmd := m.toast(fmt.Sprintf("%s %s", msg.done, msg.name))
+	if l == nil {
+		return m, cmd
+	}
+	l.loading = true
+	return m, tea.Batch(cmd, fetchVolumes(m.client, l.page))
+}
+
+func (m model) volumesView() string {
+	l := m.volumes
+	if l.create != nil && l.create.regions != nil && l.create.regions.Opened() {
+		return l.create.regions.View()
+	}
+	if l.attach != nil && l.attach.droplets != nil && l.attach.droplets.Opened() {
+		return l.attach.droplets.View()
+	}
+
+	var b strings.Builder
+
+	title := focusedStyle.Render("Volumes")
+	if l.total > 0 {
+		title += placeholderStyle.Render(fmt.Sprintf("  %s · page %d of %d", pluralize(l.total, "volume"), l.page, l.pages()))
+	}
+	b.WriteString(title + "\n\n")
+
+	switch {
+	case l.loading && l.volumes == nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading volumes..."))
+	case l.err != nil && l.volumes == nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the volumes: "+l.err.Error()))
+	case len(l.volumes) == 0:
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("There are no volumes in this account yet."))
+	default:
+		fmt.Fprintf(&b, "%s\n\n", l.table.View())
+	}
+
+	switch {
+	case l.loading && l.volumes != nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading..."))
+	case l.err != nil && l.volumes != nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the volumes: "+l.err.Error()))
+	}
+	b.WriteString(m.volumesBusyView())
+	if m.notice != "" {
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render(m.notice))
+	}
+	if m.formErr != "" {
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(m.formErr))
+	}
+
+	switch {
+	case l.create != nil:
+		b.WriteString(m.volumeCreateView())
+		return b.String()
+	case l.confirmDelete != nil:
+		b.WriteString(m.volumeDeleteView())
+		b.WriteString(helpStyle.Render("enter: delete • esc: cancel"))
+		return b.String()
+	case l.resize != nil:
+		b.WriteString(m.volumeResizeView())
+		b.WriteString(helpStyle.Render("enter: resize • esc: cancel"))
+		return b.String()
+	case l.attach != nil:
+		b.WriteString(m.volumeAttachView())
+		return b.String()
+	}
+
+	help := []string{"↑/↓: move", "c: create", "a: attach/detach", "s: resize", "d: delete"}
+	if l.pages() > 1 {
+		help = append(help, "←/→: page")
+	}
+	help = append(help, "r: refresh", "tab: screens", m.closeScreenHelp())
+	b.WriteString(helpStyle.Render(strings.Join(help, " • ")))
+
+	return b.String()
+}
+
+// volumesBusyView describes what the volumes screen is waiting on, if
+// anything.
+func (m model) volumesBusyView() string {
+	l := m.volumes
+	var busy []string
+	for _, name := range l.creating {
+		busy = append(busy, "creating "+name)
+	}
+	for _, v := range l.volumes {
+		if s, ok := l.busy[v.ID]; ok {
+			busy = append(busy, fmt.Sprintf("%s %s", s, v.Name))
+		}
+	}
+	if len(busy) == 0 {
+		return ""
+	}
+	s := strings.Join(busy, ", ")
+	return fmt.Sprintf("%s  %s\n\n", m.spinner.View(), placeholderStyle.Render(strings.ToUpper(s[:1])+s[1:]+"..."))
+}
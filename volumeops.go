@@ -0,0 +1,361 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+// volumeCreate walks through creating a volume from the volumes screen: its
+// name, its region and its size.
+type volumeCreate struct {
+	name *textField
+	// named is set once the name's been given, while choosing the region
+	// from regions, and size once the region's been chosen.
+	named   bool
+	regions *selectField
+	region  string
+	size    *textField
+}
+
+// volumeRegionsMsg carries the regions that volumes can be created in.
+type volumeRegionsMsg struct {
+	regions []option
+	err     error
+}
+
+// fetchVolumeRegions lists the regions that currently accept new volumes.
+func fetchVolumeRegions(client *godo.Client) tea.Cmd {
+	return func() tea.Msg {
+		regions, _, err := client.Regions.List(context.Background(), &godo.ListOptions{PerPage: 200})
+		if err != nil {
+			return volumeRegionsMsg{err: err}
+		}
+
+		var opts []option
+		for _, r := range regions {
+			if r.Available && contains(r.Features, "storage") {
+				opts = append(opts, regionItem{r})
+			}
+		}
+		return volumeRegionsMsg{regions: opts}
+	}
+}
+
+// openVolumeCreate starts creating a volume, fetching the regions it can be
+// created in meanwhile.
+func (m model) openVolumeCreate() (model, tea.Cmd) {
+	if m.blockChanges("volumes", "created") {
+		return m, nil
+	}
+
+	name := newTextField("Name: ", "")
+	name.CharLimit = 64
+	regions := newSelectField("", "Choose a region for the volume", "")
+	regions.SetSize(m.width, m.height-1)
+	m.volumes.create = &volumeCreate{name: name, regions: regions}
+	m.notice = ""
+	m.formErr = ""
+	return m, tea.Batch(name.Focus(), fetchVolumeRegions(m.client))
+}
+
+// setVolumeRegions fills the region step in once the regions have been
+// fetched, starting on the form's region.
+func (m model) setVolumeRegions(msg volumeRegionsMsg) (tea.Model, tea.Cmd) {
+	if m.volumes == nil || m.volumes.create == nil {
+		return m, nil
+	}
+	c := m.volumes.create
+	if msg.err != nil {
+		m.volumes.create = nil
+		m.formErr = "couldn't list the regions: " + msg.err.Error()
+		return m, nil
+	}
+
+	cmd := c.regions.SetOptions(msg.regions)
+	c.regions.Select(m.fields[regionField].Value())
+	c.regions.selected = nil
+	if c.named {
+		c.regions.Open()
+	}
+	return m, cmd
+}
+
+func (m model) updateVolumeCreate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.volumes
+	c := l.create
+	if msg.String() == "ctrl+c" {
+		return m, m.quit()
+	}
+
+	switch {
+	case c.regions.Opened():
+		_, cmd := c.regions.Update(msg)
+		if c.regions.Opened() {
+			return m, cmd
+		}
+		if c.regions.selected == nil {
+			// Back to the name.
+			c.named = false
+			return m, tea.Batch(cmd, c.name.Focus())
+		}
+		c.region = c.regions.Value()
+		c.size = newTextField("Size (GB): ", "100")
+		c.size.CharLimit = 5
+		return m, tea.Batch(cmd, c.size.Focus())
+
+	case c.size != nil:
+		switch msg.String() {
+		case "esc":
+			// Back to the regions.
+			c.size = nil
+			c.regions.selected = nil
+			c.regions.Open()
+			m.formErr = ""
+			return m, nil
+		case "enter":
+			name := strings.TrimSpace(c.name.Value())
+			req, err := volumeRequest(name, strings.TrimSpace(c.size.Value()), c.region)
+			if err != nil {
+				m.formErr = err.Error()
+				return m, nil
+			}
+			l.create = nil
+			m.formErr = ""
+			l.creating = append(l.creating, name)
+			return m, createVolume(m.client, req)
+		}
+		_, cmd := c.size.Update(msg)
+		return m, cmd
+
+	case c.named:
+		// Waiting for the regions.
+		if msg.String() == "esc" {
+			c.named = false
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc":
+		l.create = nil
+		m.formErr = ""
+		return m, nil
+	case "enter":
+		if err := checkVolumeName(strings.TrimSpace(c.name.Value())); err != "" {
+			m.formErr = err
+			return m, nil
+		}
+		m.formErr = ""
+		c.named = true
+		c.name.Blur()
+		c.regions.Open()
+		return m, nil
+	}
+	_, cmd := c.name.Update(msg)
+	return m, cmd
+}
+
+// createVolume creates the volume.
+func createVolume(client *godo.Client, req *godo.VolumeCreateRequest) tea.Cmd {
+	return func() tea.Msg {
+		volume, _, err := client.Storage.CreateVolume(context.Background(), req)
+		id := ""
+		if volume != nil {
+			id = volume.ID
+		}
+		return volumeDoneMsg{id: id, name: req.Name, verb: "create", done: "Created", err: err}
+	}
+}
+
+func (m model) volumeCreateView() string {
+	c := m.volumes.create
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s\n\n", focusedStyle.Render("Create a volume"))
+	fmt.Fprintf(&b, "%s\n", c.name.View())
+	switch {
+	case c.size != nil:
+		fmt.Fprintf(&b, "%s %s\n", noStyle.Render("Region:"), placeholderStyle.Render(c.region))
+		fmt.Fprintf(&b, "%s\n", c.size.View())
+		if gb, err := strconv.ParseInt(strings.TrimSpace(c.size.Value()), 10, 64); err == nil && gb > 0 {
+			fmt.Fprintf(&b, "%s\n", placeholderStyle.Render(fmt.Sprintf("$%.2f/mo, at $%.2f/GB a month", volumePrice(gb), volumePricePerGB)))
+		}
+		b.WriteString("\n" + helpStyle.Render("enter: create • esc: back"))
+	case c.named:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading regions..."))
+		b.WriteString(helpStyle.Render("esc: back"))
+	default:
+		b.WriteString("\n" + helpStyle.Render("enter: next • esc: cancel"))
+	}
+
+	return b.String()
+}
+
+// volumeDeletePrompt asks for a volume's name before deleting it.
+type volumeDeletePrompt struct {
+	volume godo.Volume
+	name   *textField
+}
+
+// promptVolumeDelete asks to confirm deleting the volume under the cursor.
+// Attached volumes have to be detached first.
+func (m model) promptVolumeDelete() (model, tea.Cmd) {
+	if m.blockChanges("volumes", "deleted") {
+		return m, nil
+	}
+
+	l := m.volumes
+	volume, ok := l.selected()
+	if !ok {
+		return m, nil
+	}
+	if len(volume.DropletIDs) > 0 {
+		m.formErr = fmt.Sprintf("%s is attached to %s; press a to detach it first", volume.Name, l.attachedTo(volume))
+		return m, nil
+	}
+	name := newOptionalTextField("Type its name to delete it: ", volume.Name)
+	name.CharLimit = 64
+	l.confirmDelete = &volumeDeletePrompt{volume: volume, name: name}
+	m.notice = ""
+	m.formErr = ""
+	return m, name.Focus()
+}
+
+func (m model) updateVolumeDelete(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.volumes
+	p := l.confirmDelete
+	switch msg.String() {
+	case "ctrl+c":
+		return m, m.quit()
+	case "esc":
+		l.confirmDelete = nil
+		m.formErr = ""
+		return m, nil
+	case "enter":
+		if strings.TrimSpace(p.name.Model.Value()) != p.volume.Name {
+			m.formErr = "the name doesn't match; type it exactly, or press esc to cancel"
+			return m, nil
+		}
+		l.confirmDelete = nil
+		m.formErr = ""
+		l.busy[p.volume.ID] = "deleting"
+		l.setRows()
+		return m, deleteVolume(m.client, p.volume)
+	}
+
+	_, cmd := p.name.Update(msg)
+	return m, cmd
+}
+
+// deleteVolume deletes the volume.
+func deleteVolume(client *godo.Client, volume godo.Volume) tea.Cmd {
+	return func() tea.Msg {
+		_, err := client.Storage.DeleteVolume(context.Background(), volume.ID)
+		return volumeDoneMsg{id: volume.ID, name: volume.Name, verb: "delete", done: "Deleted", err: err}
+	}
+}
+
+func (m model) volumeDeleteView() string {
+	p := m.volumes.confirmDelete
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s\n", focusedStyle.Render(fmt.Sprintf("Delete %s (%d GB)?", p.volume.Name, p.volume.SizeGigaBytes)))
+	fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("This destroys the volume and everything on it, and can't be undone. Its snapshots are kept."))
+	fmt.Fprintf(&b, "%s\n\n", p.name.View())
+
+	return b.String()
+}
+
+// volumeResizePrompt asks for a volume's new size.
+type volumeResizePrompt struct {
+	volume godo.Volume
+	size   *textField
+}
+
+// openVolumeResize asks for the new size of the volume under the cursor.
+func (m model) openVolumeResize() (model, tea.Cmd) {
+	if m.blockChanges("volumes", "resized") {
+		return m, nil
+	}
+
+	l := m.volumes
+	volume, ok := l.selected()
+	if !ok {
+		return m, nil
+	}
+	size := newOptionalTextField("New size (GB): ", strconv.FormatInt(volume.SizeGigaBytes, 10))
+	size.CharLimit = 5
+	l.resize = &volumeResizePrompt{volume: volume, size: size}
+	m.notice = ""
+	m.formErr = ""
+	return m, size.Focus()
+}
+
+func (m model) updateVolumeResize(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.volumes
+	p := l.resize
+	switch msg.String() {
+	case "ctrl+c":
+		return m, m.quit()
+	case "esc":
+		l.resize = nil
+		m.formErr = ""
+		return m, nil
+	case "enter":
+		gb, err := strconv.ParseInt(strings.TrimSpace(p.size.Value()), 10, 64)
+		switch {
+		case err != nil || gb < 1:
+			m.formErr = "volume size must be a whole number of GB"
+			return m, nil
+		case gb <= p.volume.SizeGigaBytes:
+			m.formErr = fmt.Sprintf("volumes can only grow, so it has to be over %d GB", p.volume.SizeGigaBytes)
+			return m, nil
+		}
+		l.resize = nil
+		m.formErr = ""
+		l.busy[p.volume.ID] = "resizing"
+		l.setRows()
+		return m, resizeVolume(m.client, p.volume, int(gb))
+	}
+
+	_, cmd := p.size.Update(msg)
+	return m, cmd
+}
+
+// resizeVolume grows the volume and waits for the action to complete. The
+// filesystem on it still has to be grown from the Droplet afterwards.
+func resizeVolume(client *godo.Client, volume godo.Volume, gb int) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+
+		region := ""
+		if volume.Region != nil {
+			region = volume.Region.Slug
+		}
+		action, _, err := client.StorageActions.Resize(ctx, volume.ID, gb, region)
+		if err == nil {
+			err = waitForAction(ctx, client, action)
+		}
+		return volumeDoneMsg{id: volume.ID, name: fmt.Sprintf("%s to %d GB", volume.Name, gb), verb: "resize", done: "Resized", err: err}
+	}
+}
+
+func (m model) volumeResizeView() string {
+	p := m.volumes.resize
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s\n", focusedStyle.Render(fmt.Sprintf("Resize %s from %d GB?", p.volume.Name, p.volume.SizeGigaBytes)))
+	fmt.Fprintf(&b, "%s\n", placeholderStyle.Render("Volumes can grow but never shrink. Grow the filesystem on it afterwards, e.g. with resize2fs."))
+	fmt.Fprintf(&b, "\n%s\n", p.size.View())
+	if gb, err := strconv.ParseInt(strings.TrimSpace(p.size.Value()), 10, 64); err == nil && gb > p.volume.SizeGigaBytes {
+		fmt.Fprintf(&b, "%s\n", placeholderStyle.Render(fmt.Sprintf("$%.2f/mo → $%.2f/mo", volumePrice(p.volume.SizeGigaBytes), volumePrice(gb))))
+	}
+	b.WriteString("\n")
+
+	return b.String()
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/digitalocean/godo"
+)
+
+// volumePricePerGB is the monthly price of block storage per GiB.
+const volumePricePerGB = 0.10
+
+// volumeRequest builds the request for the volume to attach to the new
+// Droplet, returning nil if none was asked for.
+func volumeRequest(name, size, region string) (*godo.VolumeCreateRequest, error) {
+	if name == "" && size == "" {
+		return nil, nil
+	}
+	if name == "" {
+		return nil, errors.New("a volume needs a name")
+	}
+
+	gb, err := strconv.ParseInt(size, 10, 64)
+	if err != nil || gb < 1 {
+		return nil, fmt.Errorf("volume size must be a whole number of GB")
+	}
+
+	return &godo.VolumeCreateRequest{
+		Region:         region,
+		Name:           name,
+		SizeGigaBytes:  gb,
+		FilesystemType: "ext4",
+	}, nil
+}
+
+// checkVolumeName returns an error message if the name isn't one a volume
+// can have: up to 64 lowercase letters, numbers and dashes, starting with a
+// letter.
+func checkVolumeName(name string) string {
+	switch {
+	case name == "":
+		return "a volume needs a name"
+	case len(name) > 64:
+		return fmt.Sprintf("name %q is longer than 64 characters", name)
+	case name[0] < 'a' || name[0] > 'z':
+		return fmt.Sprintf("name %q has to start with a lowercase letter", name)
+	}
+	for _, r := range name {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+			return fmt.Sprintf("name %q can only have lowercase letters, numbers and dashes", name)
+		}
+	}
+	return ""
+}
+
+// volumePrice is the monthly price of a volume of the given size.
+func volumePrice(gb int64) float64 {
+	return float64(gb) * volumePricePerGB
+}
+
+// mountName is the directory under /mnt that DigitalOcean's images mount a
+// volume on, by replacing dashes with underscores in its name.
+func mountName(volume string) string {
+	return strings.ReplaceAll(volume, "-", "_")
+}
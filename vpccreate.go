@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+// vpcCreateFlow creates a VPC, asking for its name, then its region and then
+// its IP range.
+type vpcCreateFlow struct {
+	name    *textField
+	regions *selectField
+	// choosing is set once the name's been entered, and ipRange once the
+	// region's been chosen.
+	choosing bool
+	ipRange  *textField
+}
+
+// vpcRegionsMsg carries the regions VPCs can be created in.
+type vpcRegionsMsg struct {
+	regions []option
+	err     error
+}
+
+// fetchVPCRegions lists the regions a VPC can be created in, which are the
+// ones that take new Droplets.
+func fetchVPCRegions(client *godo.Client) tea.Cmd {
+	return func() tea.Msg {
+		regions, _, err := client.Regions.List(context.Background(), &godo.ListOptions{PerPage: 200})
+		if err != nil {
+			return vpcRegionsMsg{err: err}
+		}
+
+		var opts []option
+		for _, r := range regions {
+			if r.Available {
+				opts = append(opts, regionItem{r})
+			}
+		}
+		return vpcRegionsMsg{regions: opts}
+	}
+}
+
+// openVPCCreate starts creating a VPC, asking for its name while the regions
+// are fetched.
+func (m model) openVPCCreate() (model, tea.Cmd) {
+	if m.blockChanges("VPCs", "created") {
+		return m, nil
+	}
+
+	f := &vpcCreateFlow{name: newOptionalTextField("Name: ", "e.g. staging-network")}
+	f.name.CharLimit = 255
+	f.regions = newSelectField("", "Choose a region for the VPC", "")
+	f.regions.SetSize(m.width, m.height-1)
+	m.vpcs.create = f
+	m.notice = ""
+	m.formErr = ""
+	return m, tea.Batch(f.name.Focus(), fetchVPCRegions(m.client))
+}
+
+// setVPCRegions offers the regions once they've been fetched, starting on
+// the form's region.
+func (m model) setVPCRegions(msg vpcRegionsMsg) (tea.Model, tea.Cmd) {
+	if m.vpcs == nil || m.vpcs.create == nil {
+		return m, nil
+	}
+	f := m.vpcs.create
+	if msg.err != nil {
+		m.vpcs.create = nil
+		m.formErr = "couldn't list the regions: " + msg.err.Error()
+		return m, nil
+	}
+	cmd := f.regions.SetOptions(msg.regions)
+	f.regions.Select(m.fields[regionField].Value())
+	if f.choosing && f.ipRange == nil {
+		f.regions.Open()
+	}
+	return m, cmd
+}
+
+func (m model) updateVPCCreate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.vpcs
+	f := l.create
+	if msg.String() == "ctrl+c" {
+		return m, m.quit()
+	}
+
+	if f.ipRange != nil {
+		switch msg.String() {
+		case "esc":
+			// Back to the regions.
+			f.ipRange = nil
+			m.formErr = ""
+			f.regions.Open()
+			return m, nil
+		case "enter":
+			ipRange := strings.TrimSpace(f.ipRange.Value())
+			if problem := checkVPCRange(ipRange); problem != "" {
+				m.formErr = problem
+				return m, nil
+			}
+			req := &godo.VPCCreateRequest{
+				Name:       strings.TrimSpace(f.name.Value()),
+				RegionSlug: f.regions.Value(),
+				IPRange:    ipRange,
+			}
+			l.create = nil
+			m.formErr = ""
+			l.creating = append(l.creating, req.Name)
+			return m, createVPC(m.client, req)
+		}
+		_, cmd := f.ipRange.Update(msg)
+		return m, cmd
+	}
+
+	if !f.choosing {
+		switch msg.String() {
+		case "esc":
+			l.create = nil
+			m.formErr = ""
+			return m, nil
+		case "enter":
+			if strings.TrimSpace(f.name.Value()) == "" {
+				m.formErr = "the VPC needs a name"
+				return m, nil
+			}
+			m.formErr = ""
+			f.choosing = true
+			if f.regions.Loaded() {
+				f.regions.Open()
+			}
+			return m, nil
+		}
+		_, cmd := f.name.Update(msg)
+		return m, cmd
+	}
+
+	if !f.regions.Opened() {
+		// Waiting for the regions.
+		if msg.String() == "esc" {
+			f.choosing = false
+		}
+		return m, nil
+	}
+	_, cmd := f.regions.Update(msg)
+	if f.regions.Opened() {
+		return m, cmd
+	}
+	if msg.String() == "esc" || f.regions.selected == nil {
+		// Backed out of the list, so back to the name.
+		f.choosing = false
+		return m, cmd
+	}
+	f.name.Blur()
+	f.ipRange = newOptionalTextField("IP range (optional): ", "e.g. 10.20.0.0/16, or leave it to be chosen")
+	f.ipRange.CharLimit = 18
+	return m, tea.Batch(cmd, f.ipRange.Focus())
+}
+
+// vpcCreatedMsg reports that a VPC has been created, or couldn't be.
+type vpcCreatedMsg struct {
+	name string
+	vpc  *godo.VPC
+	err  error
+}
+
+func createVPC(client *godo.Client, req *godo.VPCCreateRequest) tea.Cmd {
+	return func() tea.Msg {
+		vpc, _, err := client.VPCs.Create(context.Background(), req)
+		return vpcCreatedMsg{name: req.Name, vpc: vpc, err: err}
+	}
+}
+
+// vpcCreated refreshes the list once a VPC's been created, offering it in
+// the form's VPCs straight away.
+func (m model) vpcCreated(msg vpcCreatedMsg) (tea.Model, tea.Cmd) {
+	l := m.vpcs
+	if l != nil {
+		for i, name := range l.creating {
+			if name == msg.name {
+				l.creating = append(l.creating[:i:i], l.creating[i+1:]...)
+				break
+			}
+		}
+	}
+	if msg.err != nil {
+		m.formErr = fmt.Sprintf("couldn't create %s: %s", msg.name, msg.err)
+		return m, nil
+	}
+
+	cmds := []tea.Cmd{m.toast(fmt.Sprintf("Created %s in %s (%s)", msg.vpc.Name, msg.vpc.RegionSlug, msg.vpc.IPRange))}
+	if p := m.fields[vpcField].(*vpcPicker); p.vpcs != nil {
+		cmds = append(cmds, p.SetVPCs(append(p.vpcs[:len(p.vpcs):len(p.vpcs)], msg.vpc)))
+	}
+	if l != nil {
+		l.loading = true
+		cmds = append(cmds, fetchVPCList(m.client, l.page))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+func (m model) vpcCreateView() string {
+	f := m.vpcs.create
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s\n\n", focusedStyle.Render("New VPC"))
+	fmt.Fprintf(&b, "%s\n", f.name.View())
+	switch {
+	case f.ipRange != nil:
+		fmt.Fprintf(&b, "Region: %s\n", f.regions.Value())
+		fmt.Fprintf(&b, "%s\n\n", f.ipRange.View())
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("The range can't be changed later, and mustn't overlap the account's other VPCs."))
+		b.WriteString(helpStyle.Render("enter: create • esc: back"))
+	case f.choosing:
+		fmt.Fprintf(&b, "\n%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading regions..."))
+		b.WriteString(helpStyle.Render("esc: back"))
+	default:
+		b.WriteString("\n" + helpStyle.Render("enter: next • esc: cancel"))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+var (
+	createVPCKey   = key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "create"))
+	openMembersKey = key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "members"))
+)
+
+// vpcsPerPage is how many VPCs the VPCs screen fetches at a time.
+const vpcsPerPage = 20
+
+// vpcListMsg carries a page of the account's VPCs, along with how many
+// there are in all.
+type vpcListMsg struct {
+	page  int
+	vpcs  []*godo.VPC
+	total int
+	err   error
+}
+
+// fetchVPCList lists a page of the account's VPCs, counting from 1.
+func fetchVPCList(client *godo.Client, page int) tea.Cmd {
+	return func() tea.Msg {
+		vpcs, resp, err := client.VPCs.List(context.Background(), &godo.ListOptions{Page: page, PerPage: vpcsPerPage})
+		if err != nil {
+			return vpcListMsg{page: page, err: err}
+		}
+
+		total := len(vpcs)
+		if resp.Meta != nil {
+			total = resp.Meta.Total
+		}
+		return vpcListMsg{page: page, vpcs: vpcs, total: total}
+	}
+}
+
+// vpcList is the screen listing the account's VPCs in every region, a page
+// at a time.
+type vpcList struct {
+	table   table.Model
+	vpcs    []*godo.VPC
+	page    int
+	total   int
+	loading bool
+	err     error
+	// create is set while creating a VPC, and members while showing the
+	// resources in one.
+	create  *vpcCreateFlow
+	members *vpcMembers
+	// creating holds the names of the VPCs being created.
+	creating []string
+}
+
+func newVPCList(width, height int) *vpcList {
+	l := &vpcList{
+		page: 1,
+		table: newListTable([]table.Column{
+			{Title: "Name", Width: 32},
+			{Title: "Region", Width: 8},
+			{Title: "IP range", Width: 18},
+			{Title: "Description", Width: 36},
+		}),
+	}
+	l.SetSize(width, height)
+	return l
+}
+
+// SetSize fits the table to the screen, under the title and above the help.
+func (l *vpcList) SetSize(width, height int) {
+	setTableSize(&l.table, width, height)
+	if l.members != nil {
+		setTableSize(&l.members.table, width, height)
+	}
+}
+
+// pages returns how many pages of VPCs there are.
+func (l *vpcList) pages() int {
+	return pageCount(l.total, vpcsPerPage)
+}
+
+// setVPCList shows a page of VPCs once it's been fetched.
+func (m model) setVPCList(msg vpcListMsg) (tea.Model, tea.Cmd) {
+	l := m.vpcs
+	if l == nil {
+		return m, nil
+	}
+	// The last page was emptied, e.g. by deleting elsewhere, so go back one.
+	if msg.err == nil && len(msg.vpcs) == 0 && msg.page > 1 {
+		return m, fetchVPCList(m.client, msg.page-1)
+	}
+
+	l.loading = false
+	l.err = msg.err
+	if msg.err != nil {
+		return m, nil
+	}
+	l.page, l.vpcs, l.total = msg.page, msg.vpcs, msg.total
+	l.setRows()
+	return m, nil
+}
+
+// setRows fills the table in from the VPCs.
+func (l *vpcList) setRows() {
+	rows := make([]table.Row, len(l.vpcs))
+	for i, v := range l.vpcs {
+		name := v.Name
+		if v.Default {
+			name += " (default)"
+		}
+		rows[i] = table.Row{name, v.RegionSlug, v.IPRange, v.Description}
+	}
+	l.table.SetRows(rows)
+	if l.table.Cursor() >= len(rows) {
+		l.table.GotoTop()
+	}
+}
+
+// selected returns the VPC under the cursor, unless there are none.
+func (l *vpcList) selected() (*godo.VPC, bool) {
+	i := l.table.Cursor()
+	if i < 0 || i >= len(l.vpcs) {
+		return nil, false
+	}
+	return l.vpcs[i], true
+}
+
+// openVPCs shows the list of VPCs, fetching its first page.
+func (m model) openVPCs() (model, tea.Cmd) {
+	m.vpcs = newVPCList(m.width, m.height-1)
+	m.vpcs.loading = true
+	m.notice = ""
+	m.formErr = ""
+	return m, tea.Batch(fetchVPCList(m.client, 1), m.spinner.Tick)
+}
+
+func (m model) updateVPCs(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.vpcs
+	if l.create != nil {
+		return m.updateVPCCreate(msg)
+	}
+	if l.members != nil {
+		return m.updateVPCMembers(msg)
+	}
+
+	switch {
+	case msg.String() == "ctrl+c":
+		return m, m.quit()
+	case key.Matches(msg, closeListKeys):
+		return m.closeScreen()
+	case key.Matches(msg, screensKey):
+		return m.openScreens()
+	case key.Matches(msg, createVPCKey):
+		return m.openVPCCreate()
+	case l.loading:
+		return m, nil
+	case key.Matches(msg, openMembersKey):
+		return m.openVPCMembers()
+	case key.Matches(msg, refreshListKey):
+		l.loading = true
+		return m, fetchVPCList(m.client, l.page)
+	case key.Matches(msg, nextPageKey) && l.page < l.pages():
+		l.loading = true
+		return m, fetchVPCList(m.client, l.page+1)
+	case key.Matches(msg, prevPageKey) && l.page > 1:
+		l.loading = true
+		return m, fetchVPCList(m.client, l.page-1)
+	}
+
+	var cmd tea.Cmd
+	l.table, cmd = l.table.Update(msg)
+	return m, cmd
+}
+
+func (m model) vpcsView() string {
+	l := m.vpcs
+	if f := l.create; f != nil && f.regions.Opened() {
+		return f.regions.View()
+	}
+	if l.members != nil {
+		return m.vpcMembersView()
+	}
+
+	var b strings.Builder
+
+	title := focusedStyle.Render("VPCs")
+	if l.total > 0 {
+		title += placeholderStyle.Render(fmt.Sprintf("  %s · page %d of %d", pluralize(l.total, "VPC"), l.page, l.pages()))
+	}
+	b.WriteString(title + "\n\n")
+
+	switch {
+	case l.loading && l.vpcs == nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading VPCs..."))
+	case l.err != nil && l.vpcs == nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the VPCs: "+l.err.Error()))
+	case len(l.vpcs) == 0:
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("There are no VPCs in this account yet; each region gets a default one with its first Droplet."))
+	default:
+		fmt.Fprintf(&b, "%s\n\n", l.table.View())
+	}
+
+	switch {
+	case l.loading && l.vpcs != nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading..."))
+	case l.err != nil && l.vpcs != nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the VPCs: "+l.err.Error()))
+	}
+	if len(l.creating) > 0 {
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Creating "+strings.Join(l.creating, ", ")+"..."))
+	}
+	if m.notice != "" {
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render(m.notice))
+	}
+	if m.formErr != "" {
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render(m.formErr))
+	}
+
+	if l.create != nil {
+		b.WriteString(m.vpcCreateView())
+		return b.String()
+	}
+
+	help := []string{"↑/↓: move", "enter: members", "c: create"}
+	if l.pages() > 1 {
+		help = append(help, "←/→: page")
+	}
+	help = append(help, "r: refresh", "tab: screens", m.closeScreenHelp())
+	b.WriteString(helpStyle.Render(strings.Join(help, " • ")))
+
+	return b.String()
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+// vpcMembersPerPage is how many of a VPC's members are fetched at a time.
+const vpcMembersPerPage = 20
+
+// vpcMembersMsg carries a page of the resources in a VPC, along with how
+// many there are in all.
+type vpcMembersMsg struct {
+	vpcID   string
+	page    int
+	members []*godo.VPCMember
+	total   int
+	err     error
+}
+
+// fetchVPCMembers lists a page of the resources in the VPC, counting from 1.
+func fetchVPCMembers(client *godo.Client, vpcID string, page int) tea.Cmd {
+	return func() tea.Msg {
+		members, resp, err := client.VPCs.ListMembers(context.Background(), vpcID, nil, &godo.ListOptions{Page: page, PerPage: vpcMembersPerPage})
+		if err != nil {
+			return vpcMembersMsg{vpcID: vpcID, page: page, err: err}
+		}
+
+		total := len(members)
+		if resp.Meta != nil {
+			total = resp.Meta.Total
+		}
+		return vpcMembersMsg{vpcID: vpcID, page: page, members: members, total: total}
+	}
+}
+
+// vpcMembers lists the resources in a VPC, a page at a time.
+type vpcMembers struct {
+	vpc     *godo.VPC
+	table   table.Model
+	members []*godo.VPCMember
+	page    int
+	total   int
+	loading bool
+	err     error
+}
+
+// pages returns how many pages of members there are.
+func (v *vpcMembers) pages() int {
+	return pageCount(v.total, vpcMembersPerPage)
+}
+
+// openVPCMembers shows the resources in the VPC under the cursor.
+func (m model) openVPCMembers() (model, tea.Cmd) {
+	l := m.vpcs
+	vpc, ok := l.selected()
+	if !ok {
+		return m, nil
+	}
+
+	v := &vpcMembers{
+		vpc:     vpc,
+		page:    1,
+		loading: true,
+		table: newListTable([]table.Column{
+			{Title: "Kind", Width: 20},
+			{Title: "Name", Width: 40},
+			{Title: "Created", Width: 12},
+		}),
+	}
+	setTableSize(&v.table, m.width, m.height-1)
+	l.members = v
+	m.notice = ""
+	m.formErr = ""
+	return m, fetchVPCMembers(m.client, vpc.ID, 1)
+}
+
+// setVPCMembers shows a page of members once it's been fetched.
+func (m model) setVPCMembers(msg vpcMembersMsg) (tea.Model, tea.Cmd) {
+	if m.vpcs == nil || m.vpcs.members == nil || m.vpcs.members.vpc.ID != msg.vpcID {
+		return m, nil
+	}
+	v := m.vpcs.members
+	// The last page was emptied, e.g. by deleting a Droplet, so go back one.
+	if msg.err == nil && len(msg.members) == 0 && msg.page > 1 {
+		return m, fetchVPCMembers(m.client, msg.vpcID, msg.page-1)
+	}
+
+	v.loading = false
+	v.err = msg.err
+	if msg.err != nil {
+		return m, nil
+	}
+	v.page, v.members, v.total = msg.page, msg.members, msg.total
+	rows := make([]table.Row, len(v.members))
+	for i, member := range v.members {
+		kind := ""
+		if parts := strings.SplitN(member.URN, ":", 3); len(parts) == 3 {
+			var ok bool
+			if kind, ok = resourceKinds[parts[1]]; !ok {
+				kind = parts[1]
+			}
+		}
+		created := ""
+		if !member.CreatedAt.IsZero() {
+			created = member.CreatedAt.Format("2006-01-02")
+		}
+		rows[i] = table.Row{kind, member.Name, created}
+	}
+	v.table.SetRows(rows)
+	if v.table.Cursor() >= len(rows) {
+		v.table.GotoTop()
+	}
+	return m, nil
+}
+
+func (m model) updateVPCMembers(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.vpcs
+	v := l.members
+
+	switch {
+	case msg.String() == "ctrl+c":
+		return m, m.quit()
+	case msg.String() == "esc":
+		l.members = nil
+		return m, nil
+	case v.loading:
+		return m, nil
+	case key.Matches(msg, refreshListKey):
+		v.loading = true
+		return m, fetchVPCMembers(m.client, v.vpc.ID, v.page)
+	case key.Matches(msg, nextPageKey) && v.page < v.pages():
+		v.loading = true
+		return m, fetchVPCMembers(m.client, v.vpc.ID, v.page+1)
+	case key.Matches(msg, prevPageKey) && v.page > 1:
+		v.loading = true
+		return m, fetchVPCMembers(m.client, v.vpc.ID, v.page-1)
+	}
+
+	var cmd tea.Cmd
+	v.table, cmd = v.table.Update(msg)
+	return m, cmd
+}
+
+func (m model) vpcMembersView() string {
+	v := m.vpcs.members
+	var b strings.Builder
+
+	title := focusedStyle.Render(v.vpc.Name)
+	title += placeholderStyle.Render(fmt.Sprintf("  %s · %s", v.vpc.RegionSlug, v.vpc.IPRange))
+	if v.total > 0 {
+		title += placeholderStyle.Render(fmt.Sprintf(" · %s · page %d of %d", pluralize(v.total, "member"), v.page, v.pages()))
+	}
+	b.WriteString(title + "\n\n")
+
+	switch {
+	case v.loading && v.members == nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading members..."))
+	case v.err != nil && v.members == nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the VPC's members: "+v.err.Error()))
+	case len(v.members) == 0:
+		fmt.Fprintf(&b, "%s\n\n", placeholderStyle.Render("Nothing is in this VPC yet; choose it in the form to create a Droplet in it."))
+	default:
+		fmt.Fprintf(&b, "%s\n\n", v.table.View())
+	}
+
+	switch {
+	case v.loading && v.members != nil:
+		fmt.Fprintf(&b, "%s  %s\n\n", m.spinner.View(), placeholderStyle.Render("Loading..."))
+	case v.err != nil && v.members != nil:
+		fmt.Fprintf(&b, "%s\n\n", errorStyle.Render("couldn't list the VPC's members: "+v.err.Error()))
+	}
+
+	help := []string{"↑/↓: move"}
+	if v.pages() > 1 {
+		help = append(help, "←/→: page")
+	}
+	help = append(help, "r: refresh", "esc: back")
+	b.WriteString(helpStyle.Render(strings.Join(help, " • ")))
+
+	return b.String()
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/digitalocean/godo"
+)
+
+type vpcItem struct {
+	*godo.VPC
+}
+
+func (v vpcItem) Title() string { return v.Name }
+func (v vpcItem) Description() string {
+	if v.Default {
+		return v.IPRange + " · default"
+	}
+	return v.IPRange
+}
+func (v vpcItem) FilterValue() string { return v.Name + " " + v.IPRange }
+func (v vpcItem) Value() string       { return v.ID }
+
+// vpcsMsg carries the account's VPCs. If they couldn't be listed, vpcs is
+// empty, leaving the Droplets to go in their region's default VPC.
+type vpcsMsg struct {
+	vpcs []*godo.VPC
+	err  error
+}
+
+// fetchVPCs lists the VPCs on the account across all regions.
+func fetchVPCs(client *godo.Client) tea.Cmd {
+	return func() tea.Msg {
+		vpcs, _, err := client.VPCs.List(context.Background(), &godo.ListOptions{PerPage: 200})
+		if err != nil {
+			return vpcsMsg{vpcs: []*godo.VPC{}, err: err}
+		}
+
+		return vpcsMsg{vpcs: vpcs}
+	}
+}
+
+// vpcPicker is a selectField for the VPCs in the chosen region. Until one is
+// picked it falls back to the region's default VPC.
+type vpcPicker struct {
+	*selectField
+	vpcs   []*godo.VPC
+	region string
+}
+
+func newVPCPicker() *vpcPicker {
+	p := &vpcPicker{
+		selectField: newSelectField("VPC: ", "Choose a VPC", ""),
+	}
+	p.hideValue = true
+
+	return p
+}
+
+// SetVPCs replaces the full set of VPCs the picker chooses from.
+func (p *vpcPicker) SetVPCs(vpcs []*godo.VPC) tea.Cmd {
+	p.vpcs = vpcs
+	return p.refresh()
+}
+
+// SetRegion restricts the picker to VPCs in the given region, dropping the
+// current choice if it belongs to another region.
+func (p *vpcPicker) SetRegion(region string) tea.Cmd {
+	if p.region == region {
+		return nil
+	}
+	p.region = region
+	if v, ok := p.selected.(vpcItem); ok && v.RegionSlug != region {
+		p.selected = nil
+	}
+
+	return p.refresh()
+}
+
+func (p *vpcPicker) refresh() tea.Cmd {
+	if p.vpcs == nil {
+		return nil
+	}
+
+	p.fallback = ""
+	var opts []option
+	for _, v := range p.vpcs {
+		if v.RegionSlug != p.region {
+			continue
+		}
+		if v.Default {
+			p.fallback = v.ID
+		}
+		opts = append(opts, vpcItem{v})
+	}
+	if len(opts) == 0 {
+		opts = append(opts, noneOption("use the region's default VPC"))
+	}
+
+	p.list.Title = "Choose a VPC in " + p.region
+
+	return p.SetOptions(opts)
+}
+
+func (p *vpcPicker) Update(msg tea.Msg) (field, tea.Cmd) {
+	_, cmd := p.selectField.Update(msg)
+	return p, cmd
+}